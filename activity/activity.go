@@ -0,0 +1,99 @@
+// Package implementing methods: flickr.activity.*
+package activity
+
+import (
+	"fmt"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Maximum per_page accepted by flickr.activity.userPhotos
+const MaxUserPhotosPerPage = 50
+
+// Types of the items of UserPhotos
+const (
+	ItemPhoto    = "photo"
+	ItemPhotoset = "photoset"
+)
+
+// Types of activity events
+const (
+	EventComment  = "comment"
+	EventNote     = "note"
+	EventFavorite = "fave"
+)
+
+// Something that happened on an item, e.g. a comment left on it
+type Event struct {
+	Type string `xml:"type,attr"`
+	// ID of the comment or note, empty for other events
+	CommentId string `xml:"commentid,attr"`
+	NoteId    string `xml:"noteid,attr"`
+	// NSID and name of the user who triggered the event
+	User     string `xml:"user,attr"`
+	Username string `xml:"username,attr"`
+	// Unix timestamp
+	DateAdded string `xml:"dateadded,attr"`
+	// Text of comments and notes
+	Text string `xml:",chardata"`
+}
+
+// A photo or photoset of the calling user with recent activity
+type Item struct {
+	// ItemPhoto or ItemPhotoset
+	Type   string `xml:"type,attr"`
+	Id     string `xml:"id,attr"`
+	Owner  string `xml:"owner,attr"`
+	Secret string `xml:"secret,attr"`
+	Server string `xml:"server,attr"`
+	// Number of comments before and during the timeframe
+	CommentsOld int     `xml:"commentsold,attr"`
+	CommentsNew int     `xml:"commentsnew,attr"`
+	Title       string  `xml:"title"`
+	Events      []Event `xml:"activity>event"`
+}
+
+type UserPhotosResponse struct {
+	flickr.BasicResponse
+	Items struct {
+		Page    int    `xml:"page,attr"`
+		Pages   int    `xml:"pages,attr"`
+		PerPage int    `xml:"perpage,attr"`
+		Total   int    `xml:"total,attr"`
+		Items   []Item `xml:"item"`
+	} `xml:"items"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r UserPhotosResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the recent activity on the photos and photosets of the calling user.
+// timeframe is a number of days or hours, e.g. "2d" or "4h", an empty timeframe
+// returns the activity since the last login of the user.
+// This method requires authentication with 'read' permission.
+func UserPhotos(client *flickr.FlickrClient, timeframe string, page, perPage int) (*UserPhotosResponse, error) {
+	if perPage < 0 || perPage > MaxUserPhotosPerPage {
+		return nil, flickErr.NewError(flickErr.InvalidParamsError,
+			fmt.Sprintf("per_page must be between 0 (Flickr default) and %d, got %d", MaxUserPhotosPerPage, perPage))
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.activity.userPhotos")
+	if timeframe != "" {
+		client.Args.Set("timeframe", timeframe)
+	}
+	if page > 0 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+	client.OAuthSign()
+
+	response := &UserPhotosResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
@@ -0,0 +1,52 @@
+package activity
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const userPhotos = `<rsp stat="ok"><items page="1" pages="1" perpage="50" total="2">
+  <item type="photoset" id="395" owner="12037949754@N01" primary="6521" secret="5a3cc65d72" server="2" commentsold="1" commentsnew="1" views="33" photos="7" more="0">
+    <title>A set of photos</title>
+    <activity>
+      <event type="comment" commentid="11-395-72157" user="12037949754@N01" username="Bees" dateadded="1144086424">yay</event>
+    </activity>
+  </item>
+  <item type="photo" id="10289" owner="12037949754@N01" secret="37d2dae4a2" server="1" commentsold="1" commentsnew="0" notesold="0" notesnew="1" views="47" faves="0" more="0">
+    <title>A photo</title>
+    <activity>
+      <event type="note" noteid="11-10289-1" user="12037949754@N01" username="Bees" dateadded="1144086335">nice</event>
+    </activity>
+  </item>
+</items></rsp>`
+
+func TestUserPhotos(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.activity.userPhotos": userPhotos,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := UserPhotos(fclient, "2d", 1, 50)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(resp.Items.Items), 2)
+	set := resp.Items.Items[0]
+	flickr.Expect(t, set.Type, ItemPhotoset)
+	flickr.Expect(t, set.Title, "A set of photos")
+	flickr.Expect(t, set.CommentsNew, 1)
+	flickr.Expect(t, len(set.Events), 1)
+	flickr.Expect(t, set.Events[0].Type, EventComment)
+	flickr.Expect(t, set.Events[0].CommentId, "11-395-72157")
+	flickr.Expect(t, set.Events[0].Text, "yay")
+	flickr.Expect(t, resp.Items.Items[1].Events[0].NoteId, "11-10289-1")
+
+	args := calls.Last("flickr.activity.userPhotos")
+	flickr.Expect(t, args.Get("timeframe"), "2d")
+	flickr.Expect(t, args.Get("per_page"), "50")
+
+	_, err = UserPhotos(fclient, "2d", 1, 51)
+	flickr.Expect(t, err != nil, true)
+	flickr.Expect(t, len(calls.Methods()), 1)
+}
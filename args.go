@@ -0,0 +1,61 @@
+package flickr
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How a date param is sent to Flickr, methods take either Unix timestamps (e.g.
+// min_upload_date) or MySQL datetimes (e.g. min_taken_date)
+type DateFormat int
+
+const (
+	UnixDate DateFormat = iota
+	MySQLDate
+)
+
+// Layout of MySQL datetimes
+const mysqlDateLayout = "2006-01-02 15:04:05"
+
+// Set a date param. Unix timestamps are absolute while MySQL datetimes are sent as
+// the wall clock of t, in its own location, since Flickr compares them with the
+// dates taken found in EXIF which have no time zone. Zero times are not sent.
+func (c *FlickrClient) SetTime(name string, t time.Time, format DateFormat) {
+	if t.IsZero() {
+		return
+	}
+	if format == MySQLDate {
+		c.Args.Set(name, t.Format(mysqlDateLayout))
+	} else {
+		c.Args.Set(name, strconv.FormatInt(t.Unix(), 10))
+	}
+}
+
+// Set a boolean param, sent as "1" or "0"
+func (c *FlickrClient) SetBool(name string, b bool) {
+	if b {
+		c.Args.Set(name, "1")
+	} else {
+		c.Args.Set(name, "0")
+	}
+}
+
+// Set an integer param
+func (c *FlickrClient) SetInt(name string, n int) {
+	c.Args.Set(name, strconv.Itoa(n))
+}
+
+// Set a decimal param, e.g. a latitude, with as many digits as needed and no more
+func (c *FlickrClient) SetFloat(name string, f float64) {
+	c.Args.Set(name, strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// Set a param holding a comma separated list, e.g. photo IDs. Empty lists are not
+// sent. Tags, which may contain spaces, must be formatted with FormatTags instead.
+func (c *FlickrClient) SetCSV(name string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	c.Args.Set(name, strings.Join(values, ","))
+}
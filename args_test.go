@@ -0,0 +1,48 @@
+package flickr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetTime(t *testing.T) {
+	c := GetTestClient()
+	c.Init()
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	date := time.Date(2024, 6, 1, 14, 30, 5, 0, loc)
+
+	c.SetTime("min_upload_date", date, UnixDate)
+	Expect(t, c.Args.Get("min_upload_date"), "1717245005")
+
+	c.SetTime("min_taken_date", date, MySQLDate)
+	Expect(t, c.Args.Get("min_taken_date"), "2024-06-01 14:30:05")
+
+	c.SetTime("max_taken_date", time.Time{}, MySQLDate)
+	_, found := c.Args["max_taken_date"]
+	Expect(t, found, false)
+}
+
+func TestSetBoolIntCSV(t *testing.T) {
+	c := GetTestClient()
+	c.Init()
+
+	c.SetBool("is_public", true)
+	c.SetBool("is_friend", false)
+	Expect(t, c.Args.Get("is_public"), "1")
+	Expect(t, c.Args.Get("is_friend"), "0")
+
+	c.SetInt("per_page", 500)
+	Expect(t, c.Args.Get("per_page"), "500")
+
+	c.SetFloat("lat", 45.5)
+	c.SetFloat("lon", -73)
+	Expect(t, c.Args.Get("lat"), "45.5")
+	Expect(t, c.Args.Get("lon"), "-73")
+
+	c.SetCSV("photo_ids", []string{"1", "2", "3"})
+	Expect(t, c.Args.Get("photo_ids"), "1,2,3")
+
+	c.SetCSV("tags", nil)
+	_, found := c.Args["tags"]
+	Expect(t, found, false)
+}
@@ -25,6 +25,11 @@ type CheckTokenResponse struct {
 	} `xml:"oauth"`
 }
 
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r CheckTokenResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
 // Returns the credentials attached to an OAuth authentication token.
 // This method does not require user authentication, but the request must be api-signed.
 func CheckToken(client *flickr.FlickrClient, oauthToken string) (*CheckTokenResponse, error) {
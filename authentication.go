@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	flickErr "gopkg.in/masci/flickr.v2/error"
 )
@@ -30,10 +31,11 @@ func ParseRequestToken(response string) (*RequestToken, error) {
 
 	ret := &RequestToken{}
 
-	oauth_problem := val.Get("oauth_problem")
-	if oauth_problem != "" {
-		ret.OAuthProblem = oauth_problem
-		return ret, flickErr.NewError(flickErr.RequestTokenError, oauth_problem)
+	if problem := parseOAuthProblem(val); problem != nil {
+		ret.OAuthProblem = string(problem.Problem)
+		err := flickErr.NewError(flickErr.RequestTokenError, problem.Error())
+		err.Cause = problem
+		return ret, err
 	}
 
 	confirmed, _ := strconv.ParseBool(val.Get("oauth_callback_confirmed"))
@@ -69,10 +71,11 @@ func ParseOAuthToken(response string) (*OAuthToken, error) {
 
 	ret := &OAuthToken{}
 
-	oauth_problem := val.Get("oauth_problem")
-	if oauth_problem != "" {
-		ret.OAuthProblem = oauth_problem
-		return ret, flickErr.NewError(flickErr.OAuthTokenError, oauth_problem)
+	if problem := parseOAuthProblem(val); problem != nil {
+		ret.OAuthProblem = string(problem.Problem)
+		err := flickErr.NewError(flickErr.OAuthTokenError, problem.Error())
+		err.Cause = problem
+		return ret, err
 	}
 
 	ret.OAuthToken = val.Get("oauth_token")
@@ -95,18 +98,40 @@ func GetRequestToken(client *FlickrClient) (*RequestToken, error) {
 	// we don't have token secret at this stage, pass an empty string
 	client.Sign("")
 
-	res, err := client.HTTPClient.Get(client.GetUrl())
+	var tok *RequestToken
+	err := client.retryOnSkew("", func() error {
+		body, date, err := client.getOAuthResponse()
+		if err != nil {
+			return err
+		}
+		tok, err = ParseRequestToken(body)
+		return withServerTime(err, date)
+	})
+	return tok, err
+}
+
+// Perform a request of the OAuth flow, returns the response body and the time of
+// its Date header
+func (c *FlickrClient) getOAuthResponse() (string, time.Time, error) {
+	res, err := c.get()
 	if err != nil {
-		return nil, err
+		return "", time.Time{}, err
 	}
 
 	defer res.Body.Close()
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return "", time.Time{}, err
 	}
+	return string(body), serverTime(res), nil
+}
 
-	return ParseRequestToken(string(body))
+// Set the ServerTime of the OAuthError carried by err, if any
+func withServerTime(err error, date time.Time) error {
+	if oauthErr := AsOAuthError(err); oauthErr != nil {
+		oauthErr.ServerTime = date
+	}
+	return err
 }
 
 // Returns the URL users need to reach to grant permission to our application
@@ -131,19 +156,19 @@ func GetAccessToken(client *FlickrClient, reqToken *RequestToken, oauthVerifier
 	// use the request token for signing
 	client.Sign(reqToken.OauthTokenSecret)
 
-	res, err := client.HTTPClient.Get(client.GetUrl())
-	if err != nil {
-		return nil, err
-	}
-
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
+	var accessTok *OAuthToken
+	err := client.retryOnSkew(reqToken.OauthTokenSecret, func() error {
+		body, date, err := client.getOAuthResponse()
+		if err != nil {
+			return err
+		}
+		accessTok, err = ParseOAuthToken(body)
+		return withServerTime(err, date)
+	})
+	if accessTok == nil {
 		return nil, err
 	}
 
-	accessTok, err := ParseOAuthToken(string(body))
-
 	// set client params for convenience
 	client.OAuthToken = accessTok.OAuthToken
 	client.OAuthTokenSecret = accessTok.OAuthTokenSecret
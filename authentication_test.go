@@ -1,6 +1,7 @@
 package flickr
 
 import (
+	"strings"
 	"testing"
 
 	flickErr "gopkg.in/masci/flickr.v2/error"
@@ -118,3 +119,44 @@ func TestGetAccessToken(t *testing.T) {
 	Expect(t, fclient.OAuthToken, "72157626318069415-087bfc7b5816092c")
 	Expect(t, fclient.OAuthTokenSecret, "a202d1f853ec69de")
 }
+
+func TestParseOAuthProblem(t *testing.T) {
+	_, err := ParseRequestToken("oauth_problem=timestamp_refused&oauth_acceptable_timestamps=1500000000-1500000300")
+	oauthErr := AsOAuthError(err)
+	if oauthErr == nil {
+		t.Fatal("err does not carry an OAuthError")
+	}
+	Expect(t, oauthErr.Problem, ProblemTimestampRefused)
+	Expect(t, oauthErr.MinTimestamp, int64(1500000000))
+	Expect(t, oauthErr.MaxTimestamp, int64(1500000300))
+	Expect(t, strings.Contains(err.Error(), "synchronize"), true)
+	Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.RequestTokenError)
+
+	_, err = ParseOAuthToken("oauth_problem=parameter_absent&oauth_parameters_absent=oauth_nonce%26oauth_verifier&oauth_problem_advice=missing%20params")
+	oauthErr = AsOAuthError(err)
+	Expect(t, oauthErr.Problem, ProblemParameterAbsent)
+	Expect(t, oauthErr.Advice, "missing params")
+	Expect(t, len(oauthErr.ParametersAbsent), 2)
+	Expect(t, oauthErr.ParametersAbsent[1], "oauth_verifier")
+	Expect(t, oauthErr.Hint(), "add the missing OAuth parameters to the request: oauth_nonce, oauth_verifier")
+	Expect(t, oauthErr.NeedsReauthorization(), false)
+
+	_, err = ParseOAuthToken("oauth_problem=token_rejected")
+	Expect(t, AsOAuthError(err).NeedsReauthorization(), true)
+
+	// unknown problems are kept as is
+	_, err = ParseOAuthToken("oauth_problem=foo")
+	Expect(t, AsOAuthError(err).Error(), "foo")
+	Expect(t, AsOAuthError(nil) == nil, true)
+}
+
+func TestOAuthProblemApiCall(t *testing.T) {
+	fclient := GetTestClient()
+	server, client := FlickrMock(200, "oauth_problem=signature_invalid", "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	err := DoGet(fclient, &BasicResponse{})
+	Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.ApiError)
+	Expect(t, AsOAuthError(err).Problem, ProblemSignatureInvalid)
+}
@@ -0,0 +1,48 @@
+package flickr
+
+import (
+	"net/http"
+)
+
+// AuthProvider replaces the built-in OAuth 1.0a and api_sig signing, e.g. when calls
+// are routed through a gateway that authenticates them on behalf of the client.
+// When a client has an AuthProvider, OAuthSign, ApiSign and UploadSign leave the
+// request params untouched and SignRequest is called on every HTTP request right
+// before it's sent, retries included. The provider is responsible for everything
+// Flickr or the gateway needs, the api_key param included.
+type AuthProvider interface {
+	SignRequest(req *http.Request) error
+}
+
+// Adapter to use an ordinary function as AuthProvider
+type AuthProviderFunc func(req *http.Request) error
+
+// Implement AuthProvider
+func (f AuthProviderFunc) SignRequest(req *http.Request) error {
+	return f(req)
+}
+
+// An AuthProvider adding a fixed set of headers to every request, e.g. the
+// credentials expected by a gateway
+type HeaderAuthProvider struct {
+	Header http.Header
+}
+
+// Implement AuthProvider
+func (p *HeaderAuthProvider) SignRequest(req *http.Request) error {
+	for name, values := range p.Header {
+		req.Header.Del(name)
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	return nil
+}
+
+// Sign the requests performed by the client with provider instead of the built-in
+// signers
+func WithAuthProvider(provider AuthProvider) ClientOption {
+	return func(c *FlickrClient) {
+		c.AuthProvider = provider
+	}
+}
@@ -0,0 +1,61 @@
+package flickr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestAuthProvider(t *testing.T) {
+	requests := []*http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		requests = append(requests, r)
+		fmt.Fprintln(w, `<rsp stat="ok"><photoid>42</photoid></rsp>`)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	provider := &HeaderAuthProvider{Header: http.Header{"X-Gateway-Token": {"secret"}}}
+	client := NewFlickrClient("apikey", "apisecret", WithAuthProvider(provider))
+	client.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+
+	client.Init()
+	client.Args.Set("method", "flickr.test.null")
+	client.OAuthSign()
+	Expect(t, len(client.Args), 1)
+	Expect(t, DoGet(client, &FooResponse{}), nil)
+
+	client.Init()
+	client.ApiSign()
+	Expect(t, len(client.Args), 0)
+
+	_, err := UploadReader(client, bytes.NewBufferString("jpeg"), "a.jpg", nil)
+	Expect(t, err, nil)
+
+	Expect(t, len(requests), 2)
+	for _, r := range requests {
+		Expect(t, r.Header.Get("X-Gateway-Token"), "secret")
+		Expect(t, r.Form.Get("oauth_signature"), "")
+		Expect(t, r.Form.Get("api_sig"), "")
+	}
+	Expect(t, requests[0].Form.Get("method"), "flickr.test.null")
+}
+
+func TestAuthProviderError(t *testing.T) {
+	server, httpClient := FlickrMock(200, `<rsp stat="ok"></rsp>`, "")
+	defer server.Close()
+
+	denied := errors.New("gateway unavailable")
+	client := NewFlickrClient("apikey", "apisecret", WithAuthProvider(AuthProviderFunc(func(req *http.Request) error {
+		return denied
+	})))
+	client.HTTPClient = httpClient
+	client.Init()
+
+	Expect(t, DoGet(client, &FooResponse{}), denied)
+}
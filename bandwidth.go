@@ -0,0 +1,120 @@
+package flickr
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// BandwidthLimiter caps the throughput of uploads with a token bucket refilled at
+// Rate bytes per second, so that long running jobs (e.g. backup daemons) don't
+// saturate the upstream link. Uploads sharing a limiter share its bandwidth: use one
+// limiter per client to cap the client, a limiter in UploadParams to cap a job.
+// A BandwidthLimiter is safe for concurrent use.
+type BandwidthLimiter struct {
+	// Bytes per second, zero or negative means no limit
+	Rate int
+	// Bytes that can be sent at once after a pause, defaults to Rate
+	Burst int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	// replaced in tests
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// Create a limiter capping uploads to bytesPerSec
+func NewBandwidthLimiter(bytesPerSec int) *BandwidthLimiter {
+	return &BandwidthLimiter{Rate: bytesPerSec}
+}
+
+// Cap the uploads performed by the client to bytesPerSec, see BandwidthLimiter
+func WithUploadLimit(bytesPerSec int) ClientOption {
+	return func(c *FlickrClient) {
+		c.UploadLimiter = NewBandwidthLimiter(bytesPerSec)
+	}
+}
+
+func (l *BandwidthLimiter) clock() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+func (l *BandwidthLimiter) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+// Take n bytes from the bucket, blocking until the bucket was refilled enough
+func (l *BandwidthLimiter) wait(n int) {
+	if l.Rate <= 0 || n <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	now := l.clock()
+	burst := float64(l.burst())
+	if l.last.IsZero() {
+		l.tokens = burst
+	} else {
+		l.tokens += now.Sub(l.last).Seconds() * float64(l.Rate)
+		if l.tokens > burst {
+			l.tokens = burst
+		}
+	}
+	l.last = now
+	// the bucket may go negative, later callers wait for the debt to be paid
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / float64(l.Rate) * float64(time.Second))
+	}
+	sleep := l.sleep
+	l.mu.Unlock()
+
+	if delay > 0 {
+		if sleep == nil {
+			sleep = time.Sleep
+		}
+		sleep(delay)
+	}
+}
+
+// A reader consuming the bucket of a limiter
+type limitedReader struct {
+	r       io.Reader
+	limiter *BandwidthLimiter
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	// never read more than a burst at once, so throughput stays smooth
+	if burst := lr.limiter.burst(); burst > 0 && len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := lr.r.Read(p)
+	lr.limiter.wait(n)
+	return n, err
+}
+
+// Wrap r so that reading from it doesn't exceed the rate of the limiter, a nil
+// limiter returns r as it is
+func (l *BandwidthLimiter) Reader(r io.Reader) io.Reader {
+	if l == nil || l.Rate <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, limiter: l}
+}
+
+// Wrap a request body, closing the result closes body
+func (l *BandwidthLimiter) readCloser(body io.ReadCloser) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{l.Reader(body), body}
+}
@@ -0,0 +1,68 @@
+package flickr
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+// Install a fake clock on l, sleeping advances it. Returns the total time slept.
+func fakeLimiterClock(l *BandwidthLimiter) *time.Duration {
+	now := time.Unix(1500000000, 0)
+	slept := new(time.Duration)
+	l.now = func() time.Time { return now }
+	l.sleep = func(d time.Duration) {
+		*slept += d
+		now = now.Add(d)
+	}
+	return slept
+}
+
+func TestBandwidthLimiter(t *testing.T) {
+	l := NewBandwidthLimiter(1000)
+	slept := fakeLimiterClock(l)
+
+	data, err := ioutil.ReadAll(l.Reader(bytes.NewReader(make([]byte, 3000))))
+	Expect(t, err, nil)
+	Expect(t, len(data), 3000)
+	// the first second worth of data is sent at once
+	Expect(t, *slept, 2*time.Second)
+
+	// idle time refills the bucket up to the burst only
+	l.now = func() time.Time { return time.Unix(1500000100, 0) }
+	*slept = 0
+	ioutil.ReadAll(l.Reader(bytes.NewReader(make([]byte, 1000))))
+	Expect(t, *slept, time.Duration(0))
+}
+
+func TestBandwidthLimiterDisabled(t *testing.T) {
+	r := bytes.NewReader(nil)
+	var l *BandwidthLimiter
+	Expect(t, l.Reader(r), r)
+	Expect(t, NewBandwidthLimiter(0).Reader(r), r)
+}
+
+func TestUploadLimit(t *testing.T) {
+	fclient := NewFlickrClient("apikey", "apisecret", WithUploadLimit(100))
+	clientSlept := fakeLimiterClock(fclient.UploadLimiter)
+	server, client := FlickrMock(200, `<?xml version="1.0" encoding="utf-8" ?><rsp stat="ok"><photoid>42</photoid></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := UploadReader(fclient, bytes.NewReader(make([]byte, 500)), "a.jpg", nil)
+	Expect(t, err, nil)
+	Expect(t, resp.ID, "42")
+	// the multipart body is larger than the photo
+	Expect(t, *clientSlept > 4*time.Second, true)
+
+	// a job limiter overrides the one of the client
+	params := NewUploadParams()
+	params.Limiter = NewBandwidthLimiter(1000)
+	jobSlept := fakeLimiterClock(params.Limiter)
+	*clientSlept = 0
+	_, err = UploadReader(fclient, bytes.NewReader(make([]byte, 500)), "a.jpg", params)
+	Expect(t, err, nil)
+	Expect(t, *clientSlept, time.Duration(0))
+	Expect(t, *jobSlept > 0, true)
+}
@@ -0,0 +1,114 @@
+package flickr
+
+import (
+	"fmt"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Code returned by the photo methods for photos that don't exist or the caller can't
+// see anymore, e.g. deleted or blocked after being listed
+const PhotoNotFound = 1
+
+// Options shared by helpers processing many items at once
+type BatchOptions struct {
+	// Stop at the first failing item instead of processing all of them
+	FailFast bool
+}
+
+// An item that failed in a batch operation along with its error, the error is
+// usually a *flickErr.Error
+type ItemError struct {
+	Item string
+	Err  error
+}
+
+// Implement error interface
+func (e ItemError) Error() string {
+	return e.Item + ": " + e.Err.Error()
+}
+
+// A photo referenced by a list response that wasn't found when processed, e.g.
+// because it was deleted or blocked in the meantime
+type Tombstone struct {
+	Id string
+	// Last known metadata of the photo: the entry of the list it was found in, e.g. a
+	// photos.SearchPhoto, nil when the helper was given bare IDs
+	Last interface{}
+	// The error returned by Flickr
+	Err error
+}
+
+// Return whether err reports a photo that doesn't exist anymore
+func IsPhotoNotFound(err error) bool {
+	e, ok := err.(*flickErr.Error)
+	return ok && e.ErrorCode == flickErr.ApiError && e.ApiCode == PhotoNotFound
+}
+
+// Outcome of a helper processing many items: instead of failing on the first error,
+// successes, per-item failures and warnings are collected so callers get partial results
+type BatchResult struct {
+	// Items processed successfully
+	Succeeded []string
+	// Items that failed
+	Failed []ItemError
+	// Non fatal issues, e.g. items skipped because there was nothing to do
+	Warnings []string
+	// Photos that disappeared before they could be processed, they are neither
+	// succeeded nor failed
+	Tombstones []Tombstone
+	// Action chosen for each item by the helpers picking one among several, e.g.
+	// "skipped" or "replaced", keyed by item
+	Actions map[string]string
+}
+
+func NewBatchResult() *BatchResult {
+	return &BatchResult{Succeeded: []string{}, Failed: []ItemError{}, Warnings: []string{}, Tombstones: []Tombstone{}, Actions: map[string]string{}}
+}
+
+// Record the action chosen for an item, whatever its outcome
+func (r *BatchResult) SetAction(item, action string) {
+	r.Actions[item] = action
+}
+
+// Record the outcome of an item, err is nil on success. Returns the error to stop
+// the batch with according to opts: the item error when FailFast is set, nil otherwise.
+func (r *BatchResult) Add(item string, err error, opts BatchOptions) error {
+	if err == nil {
+		r.Succeeded = append(r.Succeeded, item)
+		return nil
+	}
+	r.Failed = append(r.Failed, ItemError{Item: item, Err: err})
+	if opts.FailFast {
+		return err
+	}
+	return nil
+}
+
+// Same as Add for items that are photos: photos Flickr reports as not found are
+// tombstoned along with last, their last known metadata, and never stop the batch
+func (r *BatchResult) AddPhoto(id string, last interface{}, err error, opts BatchOptions) error {
+	if IsPhotoNotFound(err) {
+		r.Tombstones = append(r.Tombstones, Tombstone{Id: id, Last: last, Err: err})
+		return nil
+	}
+	return r.Add(id, err, opts)
+}
+
+// Record a warning
+func (r *BatchResult) Warn(format string, a ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, a...))
+}
+
+// Return whether some item failed
+func (r *BatchResult) HasFailures() bool {
+	return len(r.Failed) > 0
+}
+
+// Return the first failure, nil if every item succeeded
+func (r *BatchResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	return r.Failed[0]
+}
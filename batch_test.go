@@ -0,0 +1,42 @@
+package flickr
+
+import (
+	"errors"
+	"testing"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestBatchResult(t *testing.T) {
+	r := NewBatchResult()
+	Expect(t, r.HasFailures(), false)
+	Expect(t, r.Err(), nil)
+
+	Expect(t, r.Add("a", nil, BatchOptions{}), nil)
+	boom := errors.New("boom")
+	Expect(t, r.Add("b", boom, BatchOptions{}), nil)
+	Expect(t, r.Add("c", boom, BatchOptions{FailFast: true}), boom)
+	r.Warn("skipped %s", "d")
+
+	Expect(t, len(r.Succeeded), 1)
+	Expect(t, len(r.Failed), 2)
+	Expect(t, r.HasFailures(), true)
+	Expect(t, r.Err().Error(), "b: boom")
+	Expect(t, r.Warnings[0], "skipped d")
+}
+
+func TestBatchResultTombstones(t *testing.T) {
+	r := NewBatchResult()
+	notFound := flickErr.NewError(flickErr.ApiError, "Photo not found")
+	notFound.ApiCode = PhotoNotFound
+	Expect(t, IsPhotoNotFound(notFound), true)
+	Expect(t, IsPhotoNotFound(errors.New("boom")), false)
+
+	Expect(t, r.AddPhoto("1", "last", notFound, BatchOptions{FailFast: true}), nil)
+	Expect(t, r.AddPhoto("2", nil, nil, BatchOptions{}), nil)
+	Expect(t, len(r.Tombstones), 1)
+	Expect(t, r.Tombstones[0].Id, "1")
+	Expect(t, r.Tombstones[0].Last, "last")
+	Expect(t, len(r.Succeeded), 1)
+	Expect(t, r.HasFailures(), false)
+}
@@ -0,0 +1,141 @@
+package flickr
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// A function converting a response body declared in a charset other than UTF-8 to
+// UTF-8, see xml.Decoder.CharsetReader
+type CharsetReader func(charset string, input io.Reader) (io.Reader, error)
+
+// Convert the responses declared in a charset other than UTF-8 with fn instead of
+// DefaultCharsetReader
+func WithCharsetReader(fn CharsetReader) ClientOption {
+	return func(c *FlickrClient) {
+		c.CharsetReader = fn
+	}
+}
+
+// Characters of bytes 0x80-0x9F in Windows-1252, which replaces the C1 control
+// characters of ISO-8859-1 with printable ones. Undefined bytes are kept as control
+// characters like ISO-8859-1 does.
+var cp1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+}
+
+// Return the character of a Windows-1252 byte, a superset of ISO-8859-1 in practice
+func latin1Rune(b byte) rune {
+	if b >= 0x80 && b < 0xA0 {
+		return cp1252[b-0x80]
+	}
+	return rune(b)
+}
+
+// Charsets decoded as Windows-1252 by DefaultCharsetReader
+var latin1Charsets = map[string]bool{
+	"iso-8859-1":   true,
+	"iso8859-1":    true,
+	"iso_8859-1":   true,
+	"latin1":       true,
+	"latin-1":      true,
+	"windows-1252": true,
+	"cp1252":       true,
+	"us-ascii":     true,
+	"ascii":        true,
+}
+
+// The CharsetReader used when the client has none: ISO-8859-1 and Windows-1252 are
+// converted to UTF-8, any other charset is assumed to be mis-declared UTF-8, so
+// the response is decoded anyway instead of failing as a whole.
+func DefaultCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	if latin1Charsets[strings.ToLower(charset)] {
+		return &transformReader{r: input, fn: decodeLatin1}, nil
+	}
+	return &transformReader{r: input, fn: repairUTF8}, nil
+}
+
+// Convert Windows-1252 bytes to UTF-8
+func decodeLatin1(in []byte, final bool) ([]byte, []byte) {
+	out := make([]byte, 0, len(in))
+	for _, b := range in {
+		out = utf8.AppendRune(out, latin1Rune(b))
+	}
+	return out, nil
+}
+
+// Replace the bytes of in that are not valid UTF-8 with the characters they stand
+// for in Windows-1252, the usual culprit of mis-declared encodings. Unless final,
+// an incomplete sequence at the end of in is returned to be completed by the next
+// chunk.
+func repairUTF8(in []byte, final bool) ([]byte, []byte) {
+	if utf8.Valid(in) {
+		return in, nil
+	}
+	out := make([]byte, 0, len(in)+8)
+	for i := 0; i < len(in); {
+		r, size := utf8.DecodeRune(in[i:])
+		if r == utf8.RuneError && size <= 1 {
+			if !final && !utf8.FullRune(in[i:]) {
+				return out, in[i:]
+			}
+			out = utf8.AppendRune(out, latin1Rune(in[i]))
+			i++
+			continue
+		}
+		out = append(out, in[i:i+size]...)
+		i += size
+	}
+	return out, nil
+}
+
+// A reader converting the bytes of r in chunks with fn, which returns the converted
+// bytes and the tail of its input to be converted along with the next chunk
+type transformReader struct {
+	r    io.Reader
+	fn   func(in []byte, final bool) ([]byte, []byte)
+	rest []byte
+	out  []byte
+	err  error
+}
+
+func (t *transformReader) Read(p []byte) (int, error) {
+	for len(t.out) == 0 {
+		if t.err != nil {
+			return 0, t.err
+		}
+		chunk := make([]byte, 4096)
+		n, err := t.r.Read(chunk)
+		t.err = err
+		t.out, t.rest = t.fn(append(t.rest, chunk[:n]...), err != nil)
+	}
+	n := copy(p, t.out)
+	t.out = t.out[n:]
+	return n, nil
+}
+
+// Match the encoding of an XML declaration
+var xmlEncoding = regexp.MustCompile(`^\s*<\?xml[^>]*encoding\s*=\s*["']([^"']+)["']`)
+
+// Return a decoder of a response body tolerating bad encodings: bodies declared as
+// UTF-8, or not declared at all, get their invalid bytes repaired as Windows-1252,
+// other charsets are converted with charsetReader, DefaultCharsetReader if nil.
+func newResponseDecoder(body io.Reader, charsetReader CharsetReader) *xml.Decoder {
+	if charsetReader == nil {
+		charsetReader = DefaultCharsetReader
+	}
+	reader := bufio.NewReader(body)
+	head, _ := reader.Peek(256)
+	var input io.Reader = reader
+	if m := xmlEncoding.FindSubmatch(head); m == nil || strings.EqualFold(string(m[1]), "utf-8") {
+		input = &transformReader{r: reader, fn: repairUTF8}
+	}
+	decoder := xml.NewDecoder(input)
+	decoder.CharsetReader = charsetReader
+	return decoder
+}
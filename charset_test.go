@@ -0,0 +1,92 @@
+package flickr
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type titleResponse struct {
+	BasicResponse
+	Title string `xml:"photo>title"`
+}
+
+func parseTitle(body []byte, charsetReader CharsetReader) (*titleResponse, error) {
+	res := &http.Response{Body: ioutil.NopCloser(bytes.NewReader(body))}
+	r := &titleResponse{}
+	err := parseApiResponse(res, r, charsetReader)
+	return r, err
+}
+
+func TestParseLatin1(t *testing.T) {
+	body := []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\" ?><rsp stat=\"ok\"><photo><title>Caf\xe9 \x93Milano\x94</title></photo></rsp>")
+	r, err := parseTitle(body, nil)
+	Expect(t, err, nil)
+	Expect(t, r.Title, "Café “Milano”")
+}
+
+func TestParseMisdeclaredUTF8(t *testing.T) {
+	// declared (or defaulting to) UTF-8 but the title is latin-1
+	body := []byte("<?xml version=\"1.0\" encoding=\"utf-8\" ?><rsp stat=\"ok\"><photo><title>Caf\xe9 in M\xfcnchen, caff\xc3\xa8</title></photo></rsp>")
+	r, err := parseTitle(body, nil)
+	Expect(t, err, nil)
+	Expect(t, r.Title, "Café in München, caffè")
+
+	r, err = parseTitle(body[len(`<?xml version="1.0" encoding="utf-8" ?>`):], nil)
+	Expect(t, err, nil)
+	Expect(t, r.Title, "Café in München, caffè")
+
+	// unknown charsets are decoded as UTF-8
+	body = []byte("<?xml version=\"1.0\" encoding=\"x-unknown\" ?><rsp stat=\"ok\"><photo><title>caff\xc3\xa8</title></photo></rsp>")
+	r, err = parseTitle(body, nil)
+	Expect(t, err, nil)
+	Expect(t, r.Title, "caffè")
+}
+
+func TestCustomCharsetReader(t *testing.T) {
+	refused := errors.New("unsupported charset")
+	strict := func(charset string, input io.Reader) (io.Reader, error) {
+		return nil, refused
+	}
+	body := []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\" ?><rsp stat=\"ok\"><photo><title>Caf\xe9</title></photo></rsp>")
+	r, err := parseTitle(body, strict)
+	Expect(t, err != nil, true)
+	Expect(t, r.ErrorCode(), -1)
+
+	client := NewFlickrClient("apikey", "apisecret", WithCharsetReader(strict))
+	Expect(t, client.CharsetReader != nil, true)
+}
+
+func TestRepairUTF8Chunks(t *testing.T) {
+	// multi-byte sequences split across reads are kept
+	input := "caff\xc3\xa8 \xe9t\xe9 \xe2\x82\xac"
+	reader := &transformReader{r: iotest.OneByteReader(strings.NewReader(input)), fn: repairUTF8}
+	out, err := ioutil.ReadAll(reader)
+	Expect(t, err, nil)
+	Expect(t, string(out), "caffè été €")
+
+	// a truncated sequence at the end is repaired too
+	out, _ = ioutil.ReadAll(&transformReader{r: strings.NewReader("abc\xc3"), fn: repairUTF8})
+	Expect(t, string(out), "abcÃ")
+}
+
+func TestStreamLatin1(t *testing.T) {
+	body := "<?xml version=\"1.0\" encoding=\"windows-1252\" ?><rsp stat=\"ok\"><photos page=\"1\" pages=\"1\" perpage=\"2\" total=\"2\"><photo title=\"\x93one\x94\" /><photo title=\"caf\xe9\" /></photos></rsp>"
+	titles := []string{}
+	_, err := parseApiStream(strings.NewReader(body), "photo", func(d *xml.Decoder, start *xml.StartElement) error {
+		var p struct {
+			Title string `xml:"title,attr"`
+		}
+		err := d.DecodeElement(&p, start)
+		titles = append(titles, p.Title)
+		return err
+	}, nil)
+	Expect(t, err, nil)
+	Expect(t, strings.Join(titles, ","), "“one”,café")
+}
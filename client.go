@@ -2,15 +2,18 @@ package flickr
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha1"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -48,17 +51,98 @@ type FlickrClient struct {
 	OAuthTokenSecret string
 	// User flickr ID
 	Id string
+	// Maximum duration of a single API call, including reading the response body.
+	// Zero means no timeout other than the one configured on HTTPClient
+	CallTimeout time.Duration
+	// Optional callbacks invoked around every API call, see TraceHook
+	TraceHook *TraceHook
+	// Optional policy retrying calls failing because of temporary problems
+	RetryPolicy *RetryPolicy
+	// Optional set of api keys used in turn to sign unauthenticated calls, see KeyRing
+	KeyRing *KeyRing
+	// Optional tracker of the requests performed in the last hour, see QuotaTracker
+	QuotaTracker *QuotaTracker
+	// Optional cap on the bandwidth used by uploads, see BandwidthLimiter
+	UploadLimiter *BandwidthLimiter
+	// Optional signer replacing the built-in ones, see AuthProvider
+	AuthProvider AuthProvider
+	// Optional merger of identical concurrent read calls, see Coalescer
+	Coalescer *Coalescer
+	// Optional converter of responses declared in charsets other than UTF-8,
+	// DefaultCharsetReader when nil
+	CharsetReader CharsetReader
+	// Optional writer receiving the details of every signature computed, see
+	// WithSignatureDebug
+	SignatureDebug io.Writer
+	// Optional queue keeping the write calls failing because of the network, see
+	// OfflineQueue
+	OfflineQueue *OfflineQueue
+	// Optional callback receiving the response fields no struct picks up, see
+	// WithSchemaDrift
+	OnSchemaDrift DriftFunc
+	// Optional limits of the account uploads are checked against before they're
+	// sent, see UploadLimits
+	UploadLimits *UploadLimits
+	// Optional offset between the local clock and the clock of Flickr, learned when a
+	// timestamp is refused, see ClockSkew
+	ClockSkew *ClockSkew
+	// Optional caches invalidated when the file of a photo changes, see PhotoCache
+	PhotoCaches []PhotoCache
+	// Optional decoders replacing the default one for some API methods, see
+	// WithDecoder
+	Decoders map[string]ResponseDecoder
+	// Optional pacer of the requests shared by the clients of a process, see Scheduler
+	Scheduler *Scheduler
+	// Name the calls of the client are accounted to in the Scheduler metrics, see
+	// ForSubsystem
+	Subsystem string
+}
+
+// A function configuring optional features of a FlickrClient
+type ClientOption func(*FlickrClient)
+
+// Set a default timeout for every API call performed by the client
+func WithCallTimeout(timeout time.Duration) ClientOption {
+	return func(c *FlickrClient) {
+		c.CallTimeout = timeout
+	}
 }
 
 // Create a Flickr client, apiKey and apiSecret are mandatory
-func NewFlickrClient(apiKey string, apiSecret string) *FlickrClient {
-	return &FlickrClient{
+func NewFlickrClient(apiKey string, apiSecret string, opts ...ClientOption) *FlickrClient {
+	c := &FlickrClient{
 		ApiKey:     apiKey,
 		ApiSecret:  apiSecret,
 		HTTPClient: &http.Client{},
 		HTTPVerb:   "GET",
 		Args:       url.Values{},
+		ClockSkew:  &ClockSkew{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Return a copy of the client sharing credentials and HTTPClient, changes to
+// the copy (Args, tokens, etc) do not affect the original client
+func (c *FlickrClient) Clone() *FlickrClient {
+	ret := *c
+	ret.Args = url.Values{}
+	for k, v := range c.Args {
+		ret.Args[k] = append([]string(nil), v...)
 	}
+	return &ret
+}
+
+// Return a copy of the client whose calls time out after the given duration,
+// use it to override CallTimeout for a single request:
+//
+//	photos.GetInfo(client.WithTimeout(5*time.Second), id, "")
+func (c *FlickrClient) WithTimeout(timeout time.Duration) *FlickrClient {
+	ret := c.Clone()
+	ret.CallTimeout = timeout
+	return ret
 }
 
 // Sign the next request performed by the FlickrClient
@@ -73,12 +157,16 @@ func (c *FlickrClient) SetOAuthDefaults() {
 	c.Args.Add("oauth_version", "1.0")
 	c.Args.Add("oauth_signature_method", "HMAC-SHA1")
 	c.Args.Add("oauth_nonce", generateNonce())
-	c.Args.Add("oauth_timestamp", fmt.Sprintf("%d", time.Now().Unix()))
+	c.Args.Add("oauth_timestamp", fmt.Sprintf("%d", c.now().Unix()))
 }
 
 // Sign the request with a default set of OAuth parameters, needed to authorize
 // users for certain writing/destructive operations.
+// Does nothing when the client has an AuthProvider.
 func (c *FlickrClient) OAuthSign() {
+	if c.AuthProvider != nil {
+		return
+	}
 	c.SetOAuthDefaults()
 	c.Args.Set("oauth_token", c.OAuthToken)
 	c.Args.Set("oauth_consumer_key", c.ApiKey)
@@ -88,12 +176,22 @@ func (c *FlickrClient) OAuthSign() {
 }
 
 // Specific signing process for API calls: not the same as OAuth sign, used
-// for requests that don't need user authorizations.
+// for requests that don't need user authorizations. If the client has a KeyRing,
+// the request is signed with the next key of the ring unless it carries an OAuth
+// token, which is bound to the client ApiKey.
+// Does nothing when the client has an AuthProvider.
 func (c *FlickrClient) ApiSign() {
-	c.Args.Set("api_key", c.ApiKey)
+	if c.AuthProvider != nil {
+		return
+	}
+	key := ApiKey{Key: c.ApiKey, Secret: c.ApiSecret}
+	if c.KeyRing != nil && len(c.KeyRing.Keys) > 0 && c.Args.Get("oauth_token") == "" {
+		key = c.KeyRing.pick()
+	}
+	c.Args.Set("api_key", key.Key)
 	// the "api_sig" param must not be included in the signing process
 	c.Args.Del("api_sig")
-	c.Args.Set("api_sig", c.getApiSignature(c.ApiSecret))
+	c.Args.Set("api_sig", c.getApiSignature(key.Secret))
 }
 
 // Evaluate the complete URL to make requests (base url + params)
@@ -106,17 +204,208 @@ func (c *FlickrClient) ClearArgs() {
 	c.Args = url.Values{}
 }
 
-// Reset Args and set the default endpoint
+// Reset Args and set the default endpoint and HTTP verb, calls sent with POST set
+// the verb after Init
 func (c *FlickrClient) Init() {
 	c.ClearArgs()
 	c.EndpointUrl = API_ENDPOINT
+	c.HTTPVerb = "GET"
+}
+
+// Perform an HTTP request with the given http.Client, applying the client CallTimeout
+// and AuthProvider. Failures to get a response carry a NetError, see diagnoseNetError.
+func (c *FlickrClient) do(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	if c.AuthProvider != nil {
+		if err := c.AuthProvider.SignRequest(req); err != nil {
+			return nil, err
+		}
+	}
+
+	// transports may rewrite the URL, report the host the request was meant for
+	host := req.URL.Host
+	start := time.Now()
+	if c.CallTimeout <= 0 {
+		res, err := httpClient.Do(req)
+		if err != nil {
+			return nil, diagnoseNetError(host, err, time.Since(start))
+		}
+		return res, nil
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), c.CallTimeout)
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, diagnoseNetError(host, err, time.Since(start))
+	}
+	// the timeout must cover reading the body too, release the context on Close
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// Build the request of an attempt of a call from the client Args, retry is true for
+// the attempts following the first one
+type requestBuilder func(retry bool) (*http.Request, error)
+
+// Perform an HTTP request and parse its response with parse, invoking the client
+// TraceHook around every attempt and retrying according to the RetryPolicy. Retries
+// are built again with build, signed afresh.
+// Identical read calls in flight are merged when the client has a Coalescer.
+func (c *FlickrClient) roundTrip(httpClient *http.Client, build requestBuilder, parse func(*http.Response) error) error {
+	req, err := build(false)
+	if err != nil {
+		return err
+	}
+	if c.Coalescer != nil {
+		if key, ok := coalesceKey(c, req.Method); ok {
+			return c.Coalescer.do(req.Context(), key, func(parse func(*http.Response) error) error {
+				return c.send(httpClient, req, build, parse)
+			}, parse)
+		}
+	}
+	return c.send(httpClient, req, build, parse)
+}
+
+// Same as roundTrip, never coalescing the call
+func (c *FlickrClient) send(httpClient *http.Client, req *http.Request, build requestBuilder, parse func(*http.Response) error) error {
+	attempt := func(req *http.Request) (*http.Response, error) {
+		if c.Scheduler != nil {
+			if err := c.Scheduler.Wait(req.Context(), c.Subsystem); err != nil {
+				return nil, err
+			}
+		}
+		if c.QuotaTracker != nil {
+			c.QuotaTracker.record()
+		}
+		ctx, end := c.startTrace(req.Context(), req)
+		res, err := c.do(httpClient, req.WithContext(ctx))
+		if err == nil {
+			err = parse(res)
+		}
+		end(err)
+		if c.KeyRing != nil {
+			c.KeyRing.record(c.Args.Get("api_key"), res, err)
+		}
+		return res, err
+	}
+
+	if c.RetryPolicy == nil {
+		_, err := attempt(req)
+		return err
+	}
+	// breakers are tracked per API method, uploads and OAuth calls per endpoint
+	key := c.Args.Get("method")
+	if key == "" {
+		key = c.EndpointUrl
+	}
+	return c.RetryPolicy.run(key, req, attempt, func() (*http.Request, error) {
+		return build(true)
+	})
+}
+
+// Refresh the OAuth nonce and timestamp of the Args and sign them again with
+// tokenSecret, for a new attempt of the same call
+func (c *FlickrClient) resignOAuth(tokenSecret string) {
+	c.Args.Set("oauth_timestamp", strconv.FormatInt(c.now().Unix(), 10))
+	c.Args.Set("oauth_nonce", generateNonce())
+	c.Sign(tokenSecret)
+}
+
+// Sign the Args again for a new attempt of an API call, the way they were signed for
+// the first one: Flickr refuses a nonce used already, OAuth requests get a fresh
+// nonce and timestamp, and requests signed with an api key go through ApiSign again
+// so that a KeyRing can switch key. Requests signed by an AuthProvider are left
+// alone, it signs every attempt.
+func (c *FlickrClient) resign() {
+	switch {
+	case c.AuthProvider != nil:
+	case c.Args.Get("oauth_signature") != "":
+		c.resignOAuth(c.OAuthTokenSecret)
+	case c.Args.Get("api_sig") != "":
+		c.ApiSign()
+	}
+}
+
+// Perform a GET request to the URL built from EndpointUrl and Args
+func (c *FlickrClient) get() (*http.Response, error) {
+	req, err := http.NewRequest("GET", c.GetUrl(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(c.HTTPClient, req)
+}
+
+// Same as get, parsing the response with parse and invoking the TraceHook
+func (c *FlickrClient) getAndParse(parse func(*http.Response) error) error {
+	return c.roundTrip(c.HTTPClient, func(retry bool) (*http.Request, error) {
+		if retry {
+			c.resign()
+		}
+		return http.NewRequest("GET", c.GetUrl(), nil)
+	}, parse)
+}
+
+// An io.ReadCloser releasing a context when closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// Percent-encode a string as mandated by OAuth 1.0a (RFC 5849, section 3.6):
+// unreserved characters (ALPHA, DIGIT, '-', '.', '_', '~') are left as they are,
+// every other byte of the UTF-8 encoded string becomes %XX with uppercase hex digits.
+// Unlike url.QueryEscape, spaces are encoded as %20 and '*' is encoded as well.
+func oauthEncode(s string) string {
+	const hex = "0123456789ABCDEF"
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9',
+			b == '-', b == '.', b == '_', b == '~':
+			buf.WriteByte(b)
+		default:
+			buf.WriteByte('%')
+			buf.WriteByte(hex[b>>4])
+			buf.WriteByte(hex[b&0x0F])
+		}
+	}
+	return buf.String()
+}
+
+// Normalize request params for signing (RFC 5849, section 3.4.1.3.2): names and
+// values are encoded, sorted by name then by value and joined with '=' and '&'
+func (c *FlickrClient) getNormalizedParams() string {
+	pairs := make([][2]string, 0, len(c.Args))
+	for k, values := range c.Args {
+		for _, v := range values {
+			pairs = append(pairs, [2]string{oauthEncode(k), oauthEncode(v)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	ret := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		ret = append(ret, p[0]+"="+p[1])
+	}
+	return strings.Join(ret, "&")
 }
 
 // Get the base string to compose the signature
 func (c *FlickrClient) getSigningBaseString() string {
-	request_url := url.QueryEscape(c.EndpointUrl)
-	flickr_encoded := strings.Replace(c.Args.Encode(), "+", "%20", -1)
-	query := url.QueryEscape(flickr_encoded)
+	request_url := oauthEncode(c.EndpointUrl)
+	query := oauthEncode(c.getNormalizedParams())
 
 	ret := fmt.Sprintf("%s&%s&%s", c.HTTPVerb, request_url, query)
 	return ret
@@ -124,7 +413,8 @@ func (c *FlickrClient) getSigningBaseString() string {
 
 // Compute the signature of a signed request
 func (c *FlickrClient) getSignature(token_secret string) string {
-	key := fmt.Sprintf("%s&%s", url.QueryEscape(c.ApiSecret), url.QueryEscape(token_secret))
+	start := time.Now()
+	key := fmt.Sprintf("%s&%s", oauthEncode(c.ApiSecret), oauthEncode(token_secret))
 	base_string := c.getSigningBaseString()
 
 	mac := hmac.New(sha1.New, []byte(key))
@@ -132,12 +422,16 @@ func (c *FlickrClient) getSignature(token_secret string) string {
 
 	ret := base64.StdEncoding.EncodeToString(mac.Sum(nil))
 
+	if c.SignatureDebug != nil {
+		c.dumpSignature("oauth", base_string, secretShape("consumer secret", c.ApiSecret)+"&"+secretShape("token secret", token_secret), ret, time.Since(start))
+	}
 	return ret
 }
 
 // Sign API requests. This method differs from the signing process needed for
 // OAuth authenticated requests.
 func (c *FlickrClient) getApiSignature(token_secret string) string {
+	start := time.Now()
 	var buf bytes.Buffer
 	buf.WriteString(token_secret)
 
@@ -157,5 +451,10 @@ func (c *FlickrClient) getApiSignature(token_secret string) string {
 	base := buf.String()
 
 	data := []byte(base)
-	return fmt.Sprintf("%x", md5.Sum(data))
+	ret := fmt.Sprintf("%x", md5.Sum(data))
+	if c.SignatureDebug != nil {
+		// the base string starts with the secret, dump only its length
+		c.dumpSignature("api", secretShape("api secret", token_secret)+base[len(token_secret):], secretShape("api secret", token_secret), ret, time.Since(start))
+	}
+	return ret
 }
@@ -1,7 +1,13 @@
 package flickr
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetSigningBaseString(t *testing.T) {
@@ -33,6 +39,38 @@ func TestSign(t *testing.T) {
 	Expect(t, signed, expected)
 }
 
+func TestSignatureDebug(t *testing.T) {
+	var dump strings.Builder
+	c := GetTestClient()
+	WithSignatureDebug(&dump)(c)
+	base := c.getSigningBaseString()
+
+	c.Sign("token12345secret")
+	out := dump.String()
+	Expect(t, strings.Contains(out, "--- oauth signature of GET http://www.flickr.com/services/oauth/request_token\n"), true)
+	Expect(t, strings.Contains(out, "base string: "+base+"\n"), true)
+	Expect(t, strings.Contains(out, "signing key: <consumer secret, 16 chars>&<token secret, 16 chars>\n"), true)
+	Expect(t, strings.Contains(out, "signature: dXyfrCetFSTpzD3djSrkFhj0MIQ=\n"), true)
+	Expect(t, strings.Contains(out, "token12345secret"), false)
+	Expect(t, strings.Contains(out, c.ApiSecret), false)
+
+	dump.Reset()
+	client := NewFlickrClient("1234567890", "SECRET", WithSignatureDebug(&dump))
+	client.Args.Set("method", "flickr.test.echo")
+	client.ApiSign()
+	out = dump.String()
+	Expect(t, strings.Contains(out, "--- api signature of GET flickr.test.echo\n"), true)
+	Expect(t, strings.Contains(out, "base string: <api secret, 6 chars>api_key1234567890methodflickr.test.echo\n"), true)
+	Expect(t, strings.Contains(out, "SECRET"), false)
+}
+
+func BenchmarkSign(b *testing.B) {
+	c := GetTestClient()
+	for i := 0; i < b.N; i++ {
+		c.Sign("token12345secret")
+	}
+}
+
 func TestClearArgs(t *testing.T) {
 	c := GetTestClient()
 	c.SetOAuthDefaults()
@@ -86,7 +124,88 @@ func TestInit(t *testing.T) {
 	client := GetTestClient()
 	client.Args.Set("foo", "bar")
 	client.EndpointUrl = ""
+	client.HTTPVerb = "POST"
 	client.Init()
 	Expect(t, len(client.Args), 0)
 	Expect(t, client.EndpointUrl != "", true)
+	// a call following a POST is signed as a GET unless it sets the verb again
+	Expect(t, client.HTTPVerb, "GET")
+}
+
+func TestCallTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprintln(w, `<?xml version="1.0" encoding="utf-8" ?><rsp stat="ok"></rsp>`)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	client := NewFlickrClient("apikey", "apisecret", WithCallTimeout(5*time.Millisecond))
+	Expect(t, client.CallTimeout, 5*time.Millisecond)
+	client.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+	client.Init()
+
+	err := DoGet(client, &FooResponse{})
+	Expect(t, err != nil, true)
+
+	// per-request override does not touch the original client
+	err = DoGet(client.WithTimeout(time.Second), &FooResponse{})
+	Expect(t, err, nil)
+	Expect(t, client.CallTimeout, 5*time.Millisecond)
+}
+
+func TestClone(t *testing.T) {
+	client := GetTestClient()
+	clone := client.Clone()
+	clone.Args.Set("oauth_version", "2.0")
+	clone.OAuthToken = "token"
+	Expect(t, client.Args.Get("oauth_version"), "1.0")
+	Expect(t, client.OAuthToken, "")
+	Expect(t, clone.ApiSecret, client.ApiSecret)
+	Expect(t, clone.HTTPClient, client.HTTPClient)
+}
+
+func TestOAuthEncode(t *testing.T) {
+	// test cases from http://wiki.oauth.net/TestCases
+	cases := map[string]string{
+		"abcABC123": "abcABC123",
+		"-._~":      "-._~",
+		"%":         "%25",
+		"+":         "%2B",
+		"&=*":       "%26%3D%2A",
+		"\n":        "%0A",
+		" ":         "%20",
+		"\x7F":      "%7F",
+		"\u0080":    "%C2%80",
+		"、":         "%E3%80%81",
+		"":          "",
+		"a b*c~d":   "a%20b%2Ac~d",
+		"!'()":      "%21%27%28%29",
+		"/?#[]@":    "%2F%3F%23%5B%5D%40",
+		"caffè":     "caff%C3%A8",
+		"😀":         "%F0%9F%98%80",
+	}
+	for in, expected := range cases {
+		Expect(t, oauthEncode(in), expected)
+	}
+}
+
+func TestGetNormalizedParams(t *testing.T) {
+	c := GetTestClient()
+	c.ClearArgs()
+	c.Args.Set("b", "x y")
+	c.Args.Add("a", "2")
+	c.Args.Add("a", "1")
+	c.Args.Set("a*", "~")
+	c.Args.Set("c", "")
+
+	Expect(t, c.getNormalizedParams(), "a=1&a=2&a%2A=~&b=x%20y&c=")
+}
+
+func TestSignSpecialChars(t *testing.T) {
+	c := GetTestClient()
+	c.Args.Set("title", "A title * with ~ spaces")
+
+	ret := c.getSigningBaseString()
+	Expect(t, strings.HasSuffix(ret, "%26title%3DA%2520title%2520%252A%2520with%2520~%2520spaces"), true)
 }
@@ -0,0 +1,103 @@
+package flickr
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ClockSkew holds the offset between the local clock and the clock of Flickr, added
+// to the oauth_timestamp of the requests. It's learned when Flickr refuses a
+// timestamp, from the timestamps it reports as acceptable or else from the Date
+// header of its response. Clones of a client share it, it's safe for concurrent use.
+type ClockSkew struct {
+	// offset in nanoseconds
+	offset int64
+}
+
+// Return the offset to add to the local clock
+func (s *ClockSkew) Offset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.offset))
+}
+
+// Set the offset to add to the local clock, e.g. one persisted by a previous run
+func (s *ClockSkew) SetOffset(offset time.Duration) {
+	atomic.StoreInt64(&s.offset, int64(offset))
+}
+
+// Return the current time on the clock of Flickr
+func (s *ClockSkew) Now() time.Time {
+	return time.Now().Add(s.Offset())
+}
+
+// Learn the offset from an OAuthError refusing a timestamp, returns false when the
+// error gives no hint about the time of Flickr
+func (s *ClockSkew) learn(e *OAuthError) bool {
+	var server time.Time
+	switch {
+	case e.MaxTimestamp > 0:
+		server = time.Unix(e.MinTimestamp+(e.MaxTimestamp-e.MinTimestamp)/2, 0)
+	case !e.ServerTime.IsZero():
+		server = e.ServerTime
+	default:
+		return false
+	}
+	s.SetOffset(time.Until(server))
+	return true
+}
+
+// Correct the oauth_timestamp of the requests with skew, shared by the clients
+// given the same ClockSkew. NewFlickrClient sets a fresh one, pass nil to disable
+// the correction.
+func WithClockSkew(skew *ClockSkew) ClientOption {
+	return func(c *FlickrClient) {
+		c.ClockSkew = skew
+	}
+}
+
+// Return the time used for oauth_timestamp, corrected by the ClockSkew of the client
+func (c *FlickrClient) now() time.Time {
+	if c.ClockSkew != nil {
+		return c.ClockSkew.Now()
+	}
+	return time.Now()
+}
+
+// Return the time of the Date header of a response, zero if missing or invalid
+func serverTime(res *http.Response) time.Time {
+	if res == nil {
+		return time.Time{}
+	}
+	date, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+// Perform an OAuth signed call, retrying it once with a corrected oauth_timestamp
+// when Flickr refuses the timestamp and the client has a ClockSkew. tokenSecret is
+// the secret the request was signed with.
+func (c *FlickrClient) retryOnSkew(tokenSecret string, call func() error) error {
+	return c.retryOnSkewWith(func() bool {
+		c.resignOAuth(tokenSecret)
+		return true
+	}, call)
+}
+
+// Same as retryOnSkew, resign prepares the Args for the retry and reports whether
+// the call can be performed again at all
+func (c *FlickrClient) retryOnSkewWith(resign func() bool, call func() error) error {
+	err := call()
+	if c.ClockSkew == nil || c.Args.Get("oauth_timestamp") == "" {
+		return err
+	}
+	oauthErr := AsOAuthError(err)
+	if oauthErr == nil || oauthErr.Problem != ProblemTimestampRefused || !c.ClockSkew.learn(oauthErr) {
+		return err
+	}
+	if !resign() {
+		return err
+	}
+	return call()
+}
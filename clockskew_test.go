@@ -0,0 +1,116 @@
+package flickr
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Serve requests checking their oauth_timestamp against a clock late by an hour
+func skewedServer(advice bool, calls *int) (*httptest.Server, *http.Client) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		now := time.Now().Add(-time.Hour)
+		ts, _ := strconv.ParseInt(r.FormValue("oauth_timestamp"), 10, 64)
+		if ts < now.Unix()-300 || ts > now.Unix()+300 {
+			w.Header().Set("Date", now.UTC().Format(http.TimeFormat))
+			fmt.Fprint(w, "oauth_problem=timestamp_refused")
+			if advice {
+				fmt.Fprintf(w, "&oauth_acceptable_timestamps=%d-%d", now.Unix()-300, now.Unix()+300)
+			}
+			return
+		}
+		if r.URL.Path == "/services/oauth/request_token" {
+			fmt.Fprint(w, "oauth_callback_confirmed=true&oauth_token=token&oauth_token_secret=secret")
+			return
+		}
+		fmt.Fprint(w, `<rsp stat="ok"></rsp>`)
+	}))
+	u, _ := url.Parse(server.URL)
+	return server, &http.Client{Transport: RewriteTransport{URL: u}}
+}
+
+func skewedCall(client *FlickrClient) error {
+	client.Init()
+	client.Args.Set("method", "flickr.test.login")
+	client.OAuthSign()
+	return DoGet(client, &BasicResponse{})
+}
+
+func TestClockSkew(t *testing.T) {
+	for _, advice := range []bool{false, true} {
+		calls := 0
+		server, httpClient := skewedServer(advice, &calls)
+		client := NewFlickrClient("key", "secret")
+		client.HTTPClient = httpClient
+
+		Expect(t, skewedCall(client), nil)
+		Expect(t, calls, 2)
+		offset := client.ClockSkew.Offset()
+		Expect(t, offset > -time.Hour-5*time.Second && offset < -time.Hour+5*time.Second, true)
+
+		// the following calls use the corrected clock right away
+		Expect(t, skewedCall(client.Clone()), nil)
+		Expect(t, calls, 3)
+		server.Close()
+	}
+}
+
+func TestClockSkewDisabled(t *testing.T) {
+	calls := 0
+	server, httpClient := skewedServer(false, &calls)
+	defer server.Close()
+	client := NewFlickrClient("key", "secret", WithClockSkew(nil))
+	client.HTTPClient = httpClient
+
+	err := skewedCall(client)
+	Expect(t, AsOAuthError(err).Problem, ProblemTimestampRefused)
+	Expect(t, AsOAuthError(err).ServerTime.IsZero(), false)
+	Expect(t, calls, 1)
+}
+
+func TestClockSkewRequestToken(t *testing.T) {
+	calls := 0
+	server, httpClient := skewedServer(false, &calls)
+	defer server.Close()
+	client := NewFlickrClient("key", "secret")
+	client.HTTPClient = httpClient
+
+	tok, err := GetRequestToken(client)
+	Expect(t, err, nil)
+	Expect(t, tok.OauthToken, "token")
+	Expect(t, calls, 2)
+}
+
+func TestClockSkewUpload(t *testing.T) {
+	calls := 0
+	server, httpClient := skewedServer(false, &calls)
+	defer server.Close()
+	client := NewFlickrClient("key", "secret")
+
+	_, err := UploadReaderWithClient(client, bytes.NewReader([]byte("jpeg")), "a.jpg", nil, httpClient)
+	Expect(t, err, nil)
+	Expect(t, calls, 2)
+
+	// the offset is known now, following uploads are signed with the corrected clock
+	_, err = UploadReaderWithClient(client.Clone(), bytes.NewReader([]byte("jpeg")), "a.jpg", nil, httpClient)
+	Expect(t, err, nil)
+	Expect(t, calls, 3)
+}
+
+func TestClockSkewUploadUnseekable(t *testing.T) {
+	calls := 0
+	server, httpClient := skewedServer(false, &calls)
+	defer server.Close()
+	client := NewFlickrClient("key", "secret")
+
+	// a streamed file can't be sent twice
+	_, err := UploadReaderWithClient(client, bytes.NewBufferString("jpeg"), "a.jpg", nil, httpClient)
+	Expect(t, AsOAuthError(err).Problem, ProblemTimestampRefused)
+	Expect(t, calls, 1)
+}
@@ -0,0 +1,315 @@
+// Command flickr is a command line client for the Flickr API built on top of the
+// library, it also serves as a reference integration.
+//
+// Credentials are read from the same env vars used by the examples:
+// FLICKRGO_API_KEY and FLICKRGO_API_SECRET are always required, commands acting
+// on behalf of a user need FLICKRGO_OAUTH_TOKEN and FLICKRGO_OAUTH_TOKEN_SECRET,
+// which can be obtained with "flickr auth".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/groups"
+	"gopkg.in/masci/flickr.v2/photos"
+	"gopkg.in/masci/flickr.v2/photosets"
+)
+
+const usage = `Usage: flickr <command> [arguments]
+
+Commands:
+  auth                              authorize the application and print the OAuth tokens
+  upload [flags] file...            upload files, reading title, description and tags from their metadata
+  download [-dir dir] photo_id...   download the original files of photos
+  search [flags]                    search photos
+  album list [user_id]              list the albums of a user
+  album photos album_id             list the photos of an album
+  album create title photo_id       create an album with a primary photo
+  album add album_id photo_id...    add photos to an album
+  group info url|alias|id           show information about a group
+  group join group_id               join a group
+  group leave group_id              leave a group
+  group add group_id photo_id...    add photos to a group pool
+
+Run "flickr <command> -h" for the flags of a command.
+`
+
+// Print the error and exit
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "flickr:", flickr.Redact(err.Error()))
+	os.Exit(1)
+}
+
+// Create the API client from env vars, auth tells whether user tokens are required
+func newClient(auth bool) *flickr.FlickrClient {
+	apik := os.Getenv("FLICKRGO_API_KEY")
+	apisec := os.Getenv("FLICKRGO_API_SECRET")
+	if apik == "" || apisec == "" {
+		fmt.Fprintln(os.Stderr, "Please set FLICKRGO_API_KEY and FLICKRGO_API_SECRET env vars")
+		os.Exit(1)
+	}
+	client := flickr.NewFlickrClient(apik, apisec)
+	client.OAuthToken = os.Getenv("FLICKRGO_OAUTH_TOKEN")
+	client.OAuthTokenSecret = os.Getenv("FLICKRGO_OAUTH_TOKEN_SECRET")
+	client.Id = os.Getenv("FLICKRGO_USER_ID")
+	if auth && (client.OAuthToken == "" || client.OAuthTokenSecret == "") {
+		fmt.Fprintln(os.Stderr, "Please set FLICKRGO_OAUTH_TOKEN and FLICKRGO_OAUTH_TOKEN_SECRET env vars, "+
+			"run \"flickr auth\" to get them")
+		os.Exit(1)
+	}
+	return client
+}
+
+// Write a response as JSON or one of its lists as CSV, according to format
+func output(w io.Writer, format string, response interface{}, list interface{}) {
+	switch format {
+	case "json":
+		data, err := flickr.MarshalResponse(response)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Fprintln(w, string(data))
+	case "csv":
+		if err := flickr.WriteCSV(w, list); err != nil {
+			fail(err)
+		}
+	default:
+		fail(fmt.Errorf("unknown format %q, use json or csv", format))
+	}
+}
+
+// Parse the flags of a command, exiting if the number of positional arguments
+// is lower than min
+func parse(fs *flag.FlagSet, args []string, min int, synopsis string) []string {
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: flickr %s\n", synopsis)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() < min {
+		fs.Usage()
+		os.Exit(2)
+	}
+	return fs.Args()
+}
+
+func runAuth(args []string) {
+	parse(flag.NewFlagSet("auth", flag.ExitOnError), args, 0, "auth")
+	client := newClient(false)
+
+	tok, err := flickr.GetRequestToken(client)
+	if err != nil {
+		fail(err)
+	}
+	url, err := flickr.GetAuthorizeUrl(client, tok)
+	if err != nil {
+		fail(err)
+	}
+
+	var oauthVerifier string
+	fmt.Fprintln(os.Stderr, "Open your browser at this url:", url)
+	fmt.Fprint(os.Stderr, "Then, insert the code: ")
+	fmt.Scanln(&oauthVerifier)
+
+	accessTok, err := flickr.GetAccessToken(client, tok, oauthVerifier)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Printf("export FLICKRGO_OAUTH_TOKEN=%s\n", accessTok.OAuthToken)
+	fmt.Printf("export FLICKRGO_OAUTH_TOKEN_SECRET=%s\n", accessTok.OAuthTokenSecret)
+	fmt.Printf("export FLICKRGO_USER_ID=%s\n", accessTok.UserNsid)
+}
+
+func runUpload(args []string) {
+	fs := flag.NewFlagSet("upload", flag.ExitOnError)
+	title := fs.String("title", "", "title of the photos, defaults to the one in the file metadata")
+	public := fs.Bool("public", false, "make the photos public")
+	hidden := fs.Bool("hidden", false, "hide the photos from public searches")
+	album := fs.String("album", "", "ID of an album to add the photos to")
+	files := parse(fs, args, 1, "upload [flags] file...")
+	client := newClient(true)
+
+	for _, path := range files {
+		params, err := flickr.NewUploadParamsFromFile(path)
+		if err != nil {
+			fail(err)
+		}
+		if *title != "" {
+			params.Title = *title
+		}
+		params.IsPublic = *public
+		params.Hidden = flickr.VisibleInSearch
+		if *hidden {
+			params.Hidden = flickr.HiddenFromSearch
+		}
+
+		resp, err := flickr.UploadFile(client, path, params)
+		if err != nil {
+			fail(fmt.Errorf("%s: %s", path, err))
+		}
+		if *album != "" {
+			if _, err := photosets.AddPhoto(client, *album, resp.ID); err != nil {
+				fail(err)
+			}
+		}
+		fmt.Println(resp.ID, path)
+	}
+}
+
+func runDownload(args []string) {
+	fs := flag.NewFlagSet("download", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory to save the files into")
+	ids := parse(fs, args, 1, "download [-dir dir] photo_id...")
+	client := newClient(false)
+
+	for _, id := range ids {
+		path, err := photos.SaveOriginal(client, id, *dir)
+		if err != nil {
+			fail(fmt.Errorf("%s: %s", id, err))
+		}
+		fmt.Println(path)
+	}
+}
+
+func runSearch(args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	params := &photos.SearchParams{}
+	var tags string
+	fs.StringVar(&params.UserId, "user", "", "ID of the owner of the photos, \"me\" for the calling user")
+	fs.StringVar(&params.Text, "text", "", "free text search")
+	fs.StringVar(&tags, "tags", "", "comma separated list of tags")
+	fs.StringVar(&params.BBox, "bbox", "", "geo bounding box, min_lon,min_lat,max_lon,max_lat")
+	fs.StringVar(&params.Sort, "sort", "", "sort order, e.g. date-posted-desc")
+	fs.StringVar(&params.Extras, "extras", "", "comma separated list of extra fields")
+	fs.IntVar(&params.PerPage, "per-page", 100, "number of photos per page")
+	fs.IntVar(&params.Page, "page", 1, "page of results")
+	format := fs.String("format", "csv", "output format, json or csv")
+	parse(fs, args, 0, "search [flags]")
+	if tags != "" {
+		params.Tags = []string{tags}
+	}
+
+	client := newClient(false)
+	// searches without user tokens only return public photos
+	params.Anonymous = client.OAuthToken == ""
+	resp, err := photos.Search(client, params)
+	if err != nil {
+		fail(err)
+	}
+	output(os.Stdout, *format, resp, resp.Photos.Items)
+}
+
+func runAlbum(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("album "+args[0], flag.ExitOnError)
+	format := fs.String("format", "csv", "output format, json or csv")
+	switch args[0] {
+	case "list":
+		rest := parse(fs, args[1:], 0, "album list [-format json|csv] [user_id]")
+		client := newClient(false)
+		userId := client.Id
+		if len(rest) > 0 {
+			userId = rest[0]
+		}
+		resp, err := photosets.GetList(client, client.OAuthToken != "", userId, 1)
+		if err != nil {
+			fail(err)
+		}
+		output(os.Stdout, *format, resp, resp.Photosets.Items)
+	case "photos":
+		rest := parse(fs, args[1:], 1, "album photos [-format json|csv] album_id")
+		client := newClient(false)
+		resp, err := photosets.GetPhotos(client, client.OAuthToken != "", rest[0], "", 1)
+		if err != nil {
+			fail(err)
+		}
+		output(os.Stdout, *format, resp, resp.Photoset.Photos)
+	case "create":
+		rest := parse(fs, args[1:], 2, "album create title primary_photo_id")
+		resp, err := photosets.Create(newClient(true), rest[0], "", rest[1])
+		if err != nil {
+			fail(err)
+		}
+		fmt.Println(resp.Set.Id)
+	case "add":
+		rest := parse(fs, args[1:], 2, "album add album_id photo_id...")
+		client := newClient(true)
+		for _, id := range rest[1:] {
+			if _, err := photosets.AddPhoto(client, rest[0], id); err != nil {
+				fail(fmt.Errorf("%s: %s", id, err))
+			}
+		}
+	default:
+		fail(fmt.Errorf("unknown album command %q", args[0]))
+	}
+}
+
+func runGroup(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("group "+args[0], flag.ExitOnError)
+	switch args[0] {
+	case "info":
+		rest := parse(fs, args[1:], 1, "group info url|alias|group_id")
+		resp, err := groups.LookupGroup(newClient(false), rest[0])
+		if err != nil {
+			fail(err)
+		}
+		output(os.Stdout, "json", resp, nil)
+	case "join":
+		acceptRules := fs.Bool("accept-rules", false, "accept the rules of the group")
+		rest := parse(fs, args[1:], 1, "group join [-accept-rules] group_id")
+		if _, err := groups.Join(newClient(true), rest[0], *acceptRules); err != nil {
+			fail(err)
+		}
+	case "leave":
+		deletePhotos := fs.Bool("delete-photos", false, "remove your photos from the group pool")
+		rest := parse(fs, args[1:], 1, "group leave [-delete-photos] group_id")
+		if _, err := groups.Leave(newClient(true), rest[0], *deletePhotos); err != nil {
+			fail(err)
+		}
+	case "add":
+		rest := parse(fs, args[1:], 2, "group add group_id photo_id...")
+		client := newClient(true)
+		for _, id := range rest[1:] {
+			if _, err := groups.AddPhoto(client, rest[0], id); err != nil {
+				fail(fmt.Errorf("%s: %s", id, err))
+			}
+		}
+	default:
+		fail(fmt.Errorf("unknown group command %q", args[0]))
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func([]string){
+		"auth":     runAuth,
+		"upload":   runUpload,
+		"download": runDownload,
+		"search":   runSearch,
+		"album":    runAlbum,
+		"group":    runGroup,
+	}
+	run, found := commands[os.Args[1]]
+	if !found {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	run(os.Args[2:])
+}
@@ -0,0 +1,146 @@
+package flickr
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Params changing at every call that don't make two calls different
+var volatileArgs = []string{"oauth_nonce", "oauth_timestamp", "oauth_signature", "api_sig"}
+
+// Coalescer merges identical read calls performed concurrently: while a call is in
+// flight, the same call (same method, params and credentials) performed by other
+// goroutines waits for its response instead of hitting the API again. Only read
+// methods (get*, search, lookup*) are coalesced, every caller parses its own copy
+// of the response. Bodies of coalesced calls are read in memory, streaming included.
+// Calls of clients with an AuthProvider are never coalesced: the credentials it adds
+// to requests can't be told apart.
+// Share a Coalescer among the clients (and clones) that should merge their calls.
+// A Coalescer is safe for concurrent use.
+type Coalescer struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+// A call in flight, the response is available once done is closed
+type coalescedCall struct {
+	done chan struct{}
+	res  *recordedResponse
+	err  error
+	// whether the context of the caller performing the call was done
+	canceled bool
+	// number of callers waiting for the response, guarded by Coalescer.mu
+	dups int
+}
+
+// A response read in memory so that it can be parsed many times
+type recordedResponse struct {
+	statusCode    int
+	contentLength int64
+	header        http.Header
+	body          []byte
+}
+
+func (r *recordedResponse) replay() *http.Response {
+	return &http.Response{
+		StatusCode:    r.statusCode,
+		ContentLength: r.contentLength,
+		Header:        r.header,
+		Body:          ioutil.NopCloser(bytes.NewReader(r.body)),
+	}
+}
+
+func NewCoalescer() *Coalescer {
+	return &Coalescer{calls: map[string]*coalescedCall{}}
+}
+
+// Merge the identical read calls performed concurrently by the client, see Coalescer
+func WithCoalescer(coalescer *Coalescer) ClientOption {
+	return func(c *FlickrClient) {
+		c.Coalescer = coalescer
+	}
+}
+
+// Whether an API method only reads data
+func readMethod(method string) bool {
+	name := method[strings.LastIndex(method, ".")+1:]
+	return strings.HasPrefix(name, "get") || strings.HasPrefix(name, "lookup") || name == "search"
+}
+
+// Return the key identifying the call about to be performed by the client, false if
+// the call must not be coalesced
+func coalesceKey(c *FlickrClient, verb string) (string, bool) {
+	if c.AuthProvider != nil || !readMethod(c.Args.Get("method")) {
+		return "", false
+	}
+	args := url.Values{}
+	for k, v := range c.Args {
+		args[k] = v
+	}
+	for _, name := range volatileArgs {
+		args.Del(name)
+	}
+	// Encode sorts params by name
+	return verb + " " + c.EndpointUrl + "?" + args.Encode(), true
+}
+
+// Perform the call with perform unless an identical call is in flight, then parse
+// the response with parse. Only the first caller performs the request, parsing it
+// with parse as it would without coalescing, the others parse a copy of the same
+// response or get the same error if no response was received. A caller waiting for
+// a call in flight gives up when its own ctx is done; if the caller performing the
+// call gives up instead, one of the callers still waiting performs it again.
+func (co *Coalescer) do(ctx context.Context, key string, perform func(parse func(*http.Response) error) error, parse func(*http.Response) error) error {
+	for {
+		co.mu.Lock()
+		call, found := co.calls[key]
+		if !found {
+			break
+		}
+		call.dups++
+		co.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			co.mu.Lock()
+			call.dups--
+			co.mu.Unlock()
+			return ctx.Err()
+		}
+		if call.res != nil {
+			return parse(call.res.replay())
+		}
+		if !call.canceled || ctx.Err() != nil {
+			return call.err
+		}
+		// the first caller to get here performs the call, the others wait for it
+	}
+	call := &coalescedCall{done: make(chan struct{})}
+	co.calls[key] = call
+	co.mu.Unlock()
+
+	call.err = perform(func(res *http.Response) error {
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+		// the last attempt wins when the call is retried
+		call.res = &recordedResponse{
+			statusCode: res.StatusCode, contentLength: res.ContentLength, header: res.Header, body: body,
+		}
+		return parse(call.res.replay())
+	})
+	call.canceled = ctx.Err() != nil
+
+	co.mu.Lock()
+	delete(co.calls, key)
+	co.mu.Unlock()
+	close(call.done)
+	return call.err
+}
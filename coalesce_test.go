@@ -0,0 +1,224 @@
+package flickr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadMethod(t *testing.T) {
+	Expect(t, readMethod("flickr.photos.getInfo"), true)
+	Expect(t, readMethod("flickr.photos.search"), true)
+	Expect(t, readMethod("flickr.urls.lookupGroup"), true)
+	Expect(t, readMethod("flickr.photos.delete"), false)
+	Expect(t, readMethod("flickr.photos.setMeta"), false)
+	Expect(t, readMethod(""), false)
+}
+
+func TestCoalesceKey(t *testing.T) {
+	signed := func(photoId string) *FlickrClient {
+		client := GetTestClient()
+		client.Init()
+		client.Args.Set("method", "flickr.photos.getInfo")
+		client.Args.Set("photo_id", photoId)
+		client.OAuthSign()
+		return client
+	}
+	first, ok := coalesceKey(signed("1"), "POST")
+	Expect(t, ok, true)
+
+	// nonces, timestamps and signatures don't matter
+	second, _ := coalesceKey(signed("1"), "POST")
+	Expect(t, first, second)
+
+	other, _ := coalesceKey(signed("2"), "POST")
+	Expect(t, other != first, true)
+
+	get, _ := coalesceKey(signed("1"), "GET")
+	Expect(t, get != first, true)
+
+	client := signed("1")
+	client.Args.Set("method", "flickr.photos.delete")
+	_, ok = coalesceKey(client, "POST")
+	Expect(t, ok, false)
+
+	// the credentials of an AuthProvider are not part of the Args
+	client = signed("1")
+	client.AuthProvider = AuthProviderFunc(func(req *http.Request) error { return nil })
+	_, ok = coalesceKey(client, "POST")
+	Expect(t, ok, false)
+}
+
+func TestCoalescer(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+		fmt.Fprintln(w, `<rsp stat="ok"><foo>bar</foo></rsp>`)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	coalescer := NewCoalescer()
+	client := NewFlickrClient("apikey", "apisecret", WithCoalescer(coalescer))
+	client.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+
+	const callers = 5
+	responses := make([]*FooResponse, callers)
+	errs := make([]error, callers)
+	wg := sync.WaitGroup{}
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := client.Clone()
+			c.Init()
+			c.Args.Set("method", "flickr.test.getFoo")
+			c.OAuthSign()
+			responses[i] = &FooResponse{}
+			errs[i] = DoGet(c, responses[i])
+		}(i)
+	}
+
+	// wait for every caller to join the call in flight
+	waitDups(coalescer, callers-1)
+	close(release)
+	wg.Wait()
+
+	Expect(t, atomic.LoadInt32(&hits), int32(1))
+	for i := 0; i < callers; i++ {
+		Expect(t, errs[i], nil)
+		Expect(t, responses[i].Foo, "bar")
+	}
+	Expect(t, len(coalescer.calls), 0)
+
+	// calls are not cached once completed
+	c := client.Clone()
+	c.Init()
+	c.Args.Set("method", "flickr.test.getFoo")
+	Expect(t, DoGet(c, &FooResponse{}), nil)
+	Expect(t, atomic.LoadInt32(&hits), int32(2))
+}
+
+// Wait for n callers to wait for the call in flight
+func waitDups(coalescer *Coalescer, n int) {
+	for {
+		coalescer.mu.Lock()
+		dups := -1
+		for _, call := range coalescer.calls {
+			dups = call.dups
+		}
+		coalescer.mu.Unlock()
+		if dups == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Serve getFoo calls once release is closed, unless they are canceled before
+func blockingFooServer(hits *int32, release chan struct{}) (*httptest.Server, *FlickrClient) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		fmt.Fprintln(w, `<rsp stat="ok"><foo>bar</foo></rsp>`)
+	}))
+	u, _ := url.Parse(server.URL)
+	client := NewFlickrClient("apikey", "apisecret", WithCoalescer(NewCoalescer()))
+	client.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+	return server, client
+}
+
+// Perform a coalesced getFoo call bound to ctx
+func getFooContext(ctx context.Context, client *FlickrClient) (*FooResponse, error) {
+	c := client.Clone()
+	c.Init()
+	c.Args.Set("method", "flickr.test.getFoo")
+	c.OAuthSign()
+	resp := &FooResponse{}
+	err := c.roundTrip(c.HTTPClient, func(retry bool) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", c.GetUrl(), nil)
+	}, func(res *http.Response) error {
+		return c.parseResponse(res, resp)
+	})
+	return resp, err
+}
+
+func TestCoalescerWaiterCanceled(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server, client := blockingFooServer(&hits, release)
+	defer server.Close()
+
+	done := make(chan error)
+	go func() {
+		_, err := getFooContext(context.Background(), client)
+		done <- err
+	}()
+	for atomic.LoadInt32(&hits) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// a waiting caller gives up on its own context, the call goes on
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		waitDups(client.Coalescer, 1)
+		cancel()
+	}()
+	_, err := getFooContext(ctx, client)
+	Expect(t, err, context.Canceled)
+	waitDups(client.Coalescer, 0)
+
+	close(release)
+	Expect(t, <-done, nil)
+	Expect(t, atomic.LoadInt32(&hits), int32(1))
+}
+
+func TestCoalescerPerformerCanceled(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server, client := blockingFooServer(&hits, release)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := getFooContext(ctx, client)
+		done <- err
+	}()
+	for atomic.LoadInt32(&hits) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the caller performing the call gives up, the waiting caller performs it again
+	type result struct {
+		resp *FooResponse
+		err  error
+	}
+	waiter := make(chan result)
+	go func() {
+		resp, err := getFooContext(context.Background(), client)
+		waiter <- result{resp, err}
+	}()
+	waitDups(client.Coalescer, 1)
+	cancel()
+	Expect(t, <-done != nil, true)
+
+	close(release)
+	res := <-waiter
+	Expect(t, res.err, nil)
+	Expect(t, res.resp.Foo, "bar")
+	Expect(t, atomic.LoadInt32(&hits), int32(2))
+	Expect(t, len(client.Coalescer.calls), 0)
+}
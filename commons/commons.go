@@ -0,0 +1,57 @@
+// Package implementing methods: flickr.commons.*
+package commons
+
+import (
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A link of an institution
+type InstitutionURL struct {
+	// "site", "license" or "flickr"
+	Type string `xml:"type,attr"`
+	URL  string `xml:",chardata"`
+}
+
+// An institution taking part in Flickr Commons, the photos it shares have no known
+// copyright restrictions, see photos.LicenseNoKnownCopyright
+type Institution struct {
+	// NSID of the Flickr account of the institution
+	Nsid string `xml:"nsid,attr"`
+	// Unix timestamp of the day the institution joined the Commons
+	DateLaunch string           `xml:"date_launch,attr"`
+	Name       string           `xml:"name"`
+	Urls       []InstitutionURL `xml:"urls>url"`
+}
+
+// Return the link of the given type ("site", "license" or "flickr"), empty if the
+// institution has none
+func (i *Institution) URL(kind string) string {
+	for _, u := range i.Urls {
+		if u.Type == kind {
+			return u.URL
+		}
+	}
+	return ""
+}
+
+type InstitutionsResponse struct {
+	flickr.BasicResponse
+	Institutions []Institution `xml:"institutions>institution"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r InstitutionsResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the institutions taking part in Flickr Commons
+// This method does not require authentication.
+func GetInstitutions(client *flickr.FlickrClient) (*InstitutionsResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.commons.getInstitutions")
+	client.ApiSign()
+
+	response := &InstitutionsResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
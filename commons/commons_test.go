@@ -0,0 +1,41 @@
+package commons
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestGetInstitutions(t *testing.T) {
+	body := `<rsp stat="ok">
+  <institutions>
+    <institution nsid="8623220@N02" date_launch="1200470400">
+      <name>The Library of Congress</name>
+      <urls>
+        <url type="site">http://www.loc.gov/</url>
+        <url type="license">http://www.loc.gov/rr/print/195_copr.html#noknown</url>
+        <url type="flickr">http://flickr.com/photos/library_of_congress/</url>
+      </urls>
+    </institution>
+    <institution nsid="24785917@N03" date_launch="1211155200">
+      <name>Powerhouse Museum Collection</name>
+    </institution>
+  </institutions>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.commons.getInstitutions": body,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetInstitutions(fclient)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.commons.getInstitutions").Get("oauth_token"), "")
+	flickr.Expect(t, len(resp.Institutions), 2)
+	loc := resp.Institutions[0]
+	flickr.Expect(t, loc.Nsid, "8623220@N02")
+	flickr.Expect(t, loc.Name, "The Library of Congress")
+	flickr.Expect(t, loc.URL("license"), "http://www.loc.gov/rr/print/195_copr.html#noknown")
+	flickr.Expect(t, resp.Institutions[1].URL("site"), "")
+}
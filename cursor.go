@@ -0,0 +1,69 @@
+package flickr
+
+import (
+	"path/filepath"
+)
+
+// A Cursor tracks the progress of an incremental sync (e.g. a nightly job processing
+// new uploads) so that each run only handles what changed since the previous one.
+// Persist it with Save or SaveTo between runs and pass it back to the sync helpers: if a run is
+// interrupted, the next one resumes after the last page fully processed.
+type Cursor struct {
+	// Unix timestamp, items at or after it are processed. Zero processes everything
+	Since int64 `json:"since"`
+	// Number of pages of the current run already processed, zero if no run is in progress
+	Page int `json:"page"`
+	// Most recent timestamp seen by the current run, becomes Since once the run completes
+	Latest int64 `json:"latest"`
+}
+
+// Load a cursor from a JSON file, a missing file yields a zero cursor
+func LoadCursor(path string) (*Cursor, error) {
+	return LoadCursorFrom(NewFileStore(filepath.Dir(path)), filepath.Base(path))
+}
+
+// Load a cursor stored under key, a missing key yields a zero cursor
+func LoadCursorFrom(store Store, key string) (*Cursor, error) {
+	c := &Cursor{}
+	if _, err := LoadJSON(store, key, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Write the cursor to a JSON file
+func (c *Cursor) Save(path string) error {
+	return c.SaveTo(NewFileStore(filepath.Dir(path)), filepath.Base(path))
+}
+
+// Store the cursor as JSON under key
+func (c *Cursor) SaveTo(store Store, key string) error {
+	return SaveJSON(store, key, c)
+}
+
+// Return the page the current run must fetch next
+func (c *Cursor) NextPage() int {
+	return c.Page + 1
+}
+
+// Record the timestamp of an item processed by the current run
+func (c *Cursor) Seen(timestamp int64) {
+	if timestamp > c.Latest {
+		c.Latest = timestamp
+	}
+}
+
+// Mark the page returned by NextPage as fully processed
+func (c *Cursor) PageDone() {
+	c.Page++
+}
+
+// Mark the current run as completed, the next run only processes items at or after
+// the most recent timestamp seen
+func (c *Cursor) Complete() {
+	if c.Latest > c.Since {
+		c.Since = c.Latest
+	}
+	c.Page = 0
+	c.Latest = 0
+}
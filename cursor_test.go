@@ -0,0 +1,56 @@
+package flickr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	c := &Cursor{Since: 100}
+	Expect(t, c.NextPage(), 1)
+	c.Seen(150)
+	c.Seen(120)
+	c.PageDone()
+	Expect(t, c.NextPage(), 2)
+	Expect(t, c.Since, int64(100))
+
+	c.Complete()
+	Expect(t, *c, Cursor{Since: 150})
+
+	// a run without new items keeps the previous position
+	c.PageDone()
+	c.Complete()
+	Expect(t, *c, Cursor{Since: 150})
+}
+
+func TestCursorPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cursor")
+	Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cursor.json")
+
+	c, err := LoadCursor(path)
+	Expect(t, err, nil)
+	Expect(t, *c, Cursor{})
+
+	c = &Cursor{Since: 10, Page: 2, Latest: 20}
+	Expect(t, c.Save(path), nil)
+	loaded, err := LoadCursor(path)
+	Expect(t, err, nil)
+	Expect(t, *loaded, *c)
+}
+
+func TestCursorStore(t *testing.T) {
+	s := NewMemoryStore()
+	c, err := LoadCursorFrom(s, "cursors/nightly")
+	Expect(t, err, nil)
+	Expect(t, *c, Cursor{})
+
+	c = &Cursor{Since: 10, Page: 2, Latest: 20}
+	Expect(t, c.SaveTo(s, "cursors/nightly"), nil)
+	loaded, err := LoadCursorFrom(s, "cursors/nightly")
+	Expect(t, err, nil)
+	Expect(t, *loaded, *c)
+}
@@ -0,0 +1,43 @@
+package flickr
+
+import (
+	"bytes"
+)
+
+// A function decoding the body of a response into r in place of the default XML
+// decoding. Failures reported by Flickr must be reflected on r, e.g. by decoding the
+// stat attribute into its BasicResponse, the returned error is for bodies that can't
+// be decoded at all, which are then handled like the raw text OAuth errors.
+type ResponseDecoder func(body []byte, r FlickrResponse) error
+
+// Decode a response body the way DoGet and DoPost do by default, e.g. from a
+// ResponseDecoder fixing the body of a method before decoding it
+func DecodeResponse(body []byte, r FlickrResponse) error {
+	return newResponseDecoder(bytes.NewReader(body), nil).Decode(r)
+}
+
+// Decode the responses of an API method, e.g. "flickr.photos.getInfo" or "upload"
+// for uploads and replacements, with decode instead of the default XML decoding. Meant to work around
+// responses whose XML differs from what the response struct expects without waiting
+// for a fix of the struct. Can be given once per method, the last one wins. The
+// decoders are shared with the clones of the client.
+func WithDecoder(method string, decode ResponseDecoder) ClientOption {
+	return func(c *FlickrClient) {
+		if c.Decoders == nil {
+			c.Decoders = map[string]ResponseDecoder{}
+		}
+		c.Decoders[method] = decode
+	}
+}
+
+// Return the decoder registered for the method of the current call, nil if none
+func (c *FlickrClient) decoder() ResponseDecoder {
+	if len(c.Decoders) == 0 {
+		return nil
+	}
+	method := c.Args.Get("method")
+	if method == "" {
+		method = "upload"
+	}
+	return c.Decoders[method]
+}
@@ -0,0 +1,49 @@
+package flickr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestWithDecoder(t *testing.T) {
+	// the title comes as an attribute instead of an element
+	body := `<rsp stat="ok"><photos page="1"><photo id="1" title="One" /></photos></rsp>`
+	server, client := FlickrMock(200, body, "text/xml")
+	defer server.Close()
+
+	fix := func(body []byte, r FlickrResponse) error {
+		body = bytes.Replace(body, []byte(` title="One" />`), []byte(`><title>One</title></photo>`), 1)
+		return DecodeResponse(body, r)
+	}
+	fclient := NewFlickrClient("key", "secret", WithDecoder("flickr.test.fixed", fix))
+	fclient.HTTPClient = client
+
+	fclient.Args.Set("method", "flickr.test.fixed")
+	resp := &driftResponse{}
+	Expect(t, DoGet(fclient, resp), nil)
+	Expect(t, resp.Photos.Items[0].Title, "One")
+
+	// other methods keep the default decoding
+	fclient.Args.Set("method", "flickr.test.other")
+	resp = &driftResponse{}
+	Expect(t, DoGet(fclient, resp), nil)
+	Expect(t, resp.Photos.Items[0].Title, "")
+}
+
+func TestWithDecoderFailure(t *testing.T) {
+	server, client := FlickrMock(200, `not xml`, "text/plain")
+	defer server.Close()
+
+	fclient := NewFlickrClient("key", "secret", WithDecoder("flickr.test.broken", func(body []byte, r FlickrResponse) error {
+		return errors.New("can't decode")
+	}))
+	fclient.HTTPClient = client
+	fclient.Args.Set("method", "flickr.test.broken")
+
+	err := DoGet(fclient, &BasicResponse{})
+	Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.ApiError)
+	Expect(t, err.(*flickErr.Error).ApiCode, -1)
+}
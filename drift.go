@@ -0,0 +1,242 @@
+package flickr
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// An attribute or an element of a response that no field of the response struct
+// picks up, e.g. a field Flickr added since the struct was written
+type SchemaDrift struct {
+	// API method of the call, "upload" for uploads
+	Method string
+	// Path of the element from the root, e.g. "rsp>photos>photo", followed by
+	// "@name" for attributes: "rsp>photos>photo@views"
+	Path string
+	// Value of the first occurrence of the attribute, empty for elements
+	Sample string
+}
+
+// A function receiving the schema drifts found in responses
+type DriftFunc func(drift SchemaDrift)
+
+// Decode responses in strict mode: the attributes and elements no field of the
+// response struct picks up are reported to fn, once per path and response. Meant for
+// maintainers looking for new Flickr fields, responses are read twice.
+func WithSchemaDrift(fn DriftFunc) ClientOption {
+	return func(c *FlickrClient) {
+		c.OnSchemaDrift = fn
+	}
+}
+
+// Parse a response with the decoder registered for the method, or else with
+// parseApiResponse looking for schema drift when the client has an OnSchemaDrift
+// callback. Failed responses are not checked.
+func (c *FlickrClient) parseResponse(res *http.Response, r FlickrResponse) error {
+	if decode := c.decoder(); decode != nil {
+		return parseResponseWith(res, r, decode)
+	}
+	if c.OnSchemaDrift == nil {
+		return parseApiResponse(res, r, c.CharsetReader)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err := parseApiResponse(res, r, c.CharsetReader); err != nil {
+		return err
+	}
+
+	method := c.Args.Get("method")
+	if method == "" {
+		method = "upload"
+	}
+	findSchemaDrift(bytes.NewReader(body), reflect.TypeOf(r), c.CharsetReader, func(path, sample string) {
+		c.OnSchemaDrift(SchemaDrift{Method: method, Path: path, Sample: sample})
+	})
+	return nil
+}
+
+// The attributes and child elements a struct type picks up
+type schemaNode struct {
+	attrs    map[string]bool
+	children map[string]*schemaNode
+	// the type has an ",any,attr" field
+	anyAttr bool
+	// the type has an ",any" field or decodes itself, its content is not checked
+	anyElement bool
+}
+
+func newSchemaNode() *schemaNode {
+	return &schemaNode{attrs: map[string]bool{}, children: map[string]*schemaNode{}}
+}
+
+var (
+	xmlUnmarshalerType  = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	schemaCacheMu sync.Mutex
+	// schemas of the types already seen, by type
+	schemaCache = map[reflect.Type]*schemaNode{}
+)
+
+// Return the schema of a type, following the rules of encoding/xml for the struct
+// tags. Types decoding themselves accept anything, the other non-struct types hold
+// text only.
+func schemaOf(t reflect.Type) *schemaNode {
+	schemaCacheMu.Lock()
+	defer schemaCacheMu.Unlock()
+	return buildSchema(t)
+}
+
+// Same as schemaOf, must be called with the lock held
+func buildSchema(t reflect.Type) *schemaNode {
+	for t.Kind() == reflect.Ptr || (t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8) {
+		t = t.Elem()
+	}
+	if node, found := schemaCache[t]; found {
+		return node
+	}
+	node := newSchemaNode()
+	// cached before the fields are walked to cope with recursive types
+	schemaCache[t] = node
+	switch {
+	case reflect.PtrTo(t).Implements(xmlUnmarshalerType):
+		node.anyElement = true
+		node.anyAttr = true
+	case t.Kind() != reflect.Struct || reflect.PtrTo(t).Implements(textUnmarshalerType):
+	default:
+		addFields(node, t)
+	}
+	return node
+}
+
+// Add the attributes and elements picked up by the fields of a struct type to node
+func addFields(node *schemaNode, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("xml")
+		if tag == "-" || f.Name == "XMLName" {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addFields(node, ft)
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+		flags := map[string]bool{}
+		for _, o := range strings.Split(opts, ",") {
+			flags[o] = true
+		}
+		switch {
+		case flags["attr"] && flags["any"]:
+			node.anyAttr = true
+		case flags["attr"]:
+			if name == "" {
+				name = f.Name
+			}
+			node.attrs[name] = true
+		case flags["any"]:
+			node.anyElement = true
+		case flags["chardata"], flags["cdata"], flags["comment"], flags["innerxml"]:
+			// innerxml copies the raw content without picking up any field
+		default:
+			if name == "" {
+				name = f.Name
+			}
+			parent := node
+			parts := strings.Split(name, ">")
+			for _, part := range parts[:len(parts)-1] {
+				child := parent.children[part]
+				if child == nil {
+					child = newSchemaNode()
+					parent.children[part] = child
+				}
+				parent = child
+			}
+			if _, found := parent.children[parts[len(parts)-1]]; !found {
+				parent.children[parts[len(parts)-1]] = buildSchema(f.Type)
+			}
+		}
+	}
+}
+
+// Walk an XML document and call report for the attributes and elements the schema
+// of t doesn't pick up. Only the topmost unknown element of a subtree is reported.
+func findSchemaDrift(body io.Reader, t reflect.Type, charsetReader CharsetReader, report func(path, sample string)) {
+	root := schemaOf(t)
+	seen := map[string]bool{}
+	emit := func(path, sample string) {
+		if !seen[path] {
+			seen[path] = true
+			report(path, sample)
+		}
+	}
+
+	decoder := newResponseDecoder(body, charsetReader)
+	// schemas of the open elements, nil for the ones that are not checked
+	var stack []*schemaNode
+	var names []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return
+		}
+		switch tok := token.(type) {
+		case xml.StartElement:
+			var node *schemaNode
+			names = append(names, tok.Name.Local)
+			path := strings.Join(names, ">")
+			switch {
+			case len(stack) == 0:
+				node = root
+			case stack[len(stack)-1] == nil || stack[len(stack)-1].anyElement:
+			default:
+				if node = stack[len(stack)-1].children[tok.Name.Local]; node == nil {
+					emit(path, "")
+				}
+			}
+			if node != nil && !node.anyAttr {
+				for _, a := range tok.Attr {
+					if a.Name.Space == "xmlns" || a.Name.Local == "xmlns" {
+						continue
+					}
+					if !node.attrs[a.Name.Local] {
+						emit(path+"@"+a.Name.Local, a.Value)
+					}
+				}
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+				names = names[:len(names)-1]
+			}
+		}
+	}
+}
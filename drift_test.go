@@ -0,0 +1,67 @@
+package flickr
+
+import (
+	"testing"
+)
+
+type driftPhoto struct {
+	Id    string `xml:"id,attr"`
+	Title string `xml:"title"`
+}
+
+type driftResponse struct {
+	BasicResponse
+	Photos struct {
+		Page  int          `xml:"page,attr"`
+		Items []driftPhoto `xml:"photo"`
+	} `xml:"photos"`
+	Tags []string `xml:"tags>tag"`
+}
+
+func TestSchemaDrift(t *testing.T) {
+	body := `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photos page="1" pages="3">
+    <photo id="1" views="12"><title>One</title><location><county>Kent</county></location></photo>
+    <photo id="2" views="7"><title>Two</title></photo>
+  </photos>
+  <tags><tag>cat</tag><tag>dog</tag></tags>
+</rsp>`
+	server, client := FlickrMock(200, body, "text/xml")
+	defer server.Close()
+
+	drifts := []SchemaDrift{}
+	fclient := NewFlickrClient("key", "secret", WithSchemaDrift(func(d SchemaDrift) {
+		drifts = append(drifts, d)
+	}))
+	fclient.HTTPClient = client
+	fclient.Args.Set("method", "flickr.test.drift")
+
+	resp := &driftResponse{}
+	err := DoGet(fclient, resp)
+	Expect(t, err, nil)
+	// the response is decoded as usual
+	Expect(t, len(resp.Photos.Items), 2)
+	Expect(t, resp.Photos.Items[1].Title, "Two")
+	Expect(t, len(resp.Tags), 2)
+
+	Expect(t, len(drifts), 3)
+	Expect(t, drifts[0], SchemaDrift{Method: "flickr.test.drift", Path: "rsp>photos@pages", Sample: "3"})
+	Expect(t, drifts[1], SchemaDrift{Method: "flickr.test.drift", Path: "rsp>photos>photo@views", Sample: "12"})
+	Expect(t, drifts[2], SchemaDrift{Method: "flickr.test.drift", Path: "rsp>photos>photo>location"})
+}
+
+func TestSchemaDriftFailedResponse(t *testing.T) {
+	server, client := FlickrMock(200, `<rsp stat="fail"><err code="1" msg="nope"/></rsp>`, "text/xml")
+	defer server.Close()
+
+	called := false
+	fclient := NewFlickrClient("key", "secret", WithSchemaDrift(func(d SchemaDrift) {
+		called = true
+	}))
+	fclient.HTTPClient = client
+
+	err := DoGet(fclient, &BasicResponse{})
+	Expect(t, err != nil, true)
+	Expect(t, called, false)
+}
@@ -0,0 +1,226 @@
+// Package finding likely duplicates in a photo library by comparing perceptual
+// hashes of the thumbnails of the photos
+package dupes
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/bits"
+	"sort"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+	"gopkg.in/masci/flickr.v2/photos"
+)
+
+// A Hasher computes a 64 bits perceptual hash of an image: similar images must get
+// hashes differing by few bits
+type Hasher interface {
+	Hash(img image.Image) uint64
+}
+
+// Difference hash: the image is reduced to 9x8 gray levels and every bit tells
+// whether a cell is brighter than its right neighbour. Robust to scaling, color
+// and compression changes.
+type DHash struct{}
+
+// Implement Hasher
+func (DHash) Hash(img image.Image) uint64 {
+	cells := grayCells(img, 9, 8)
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if cells[y*9+x] > cells[y*9+x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// Reduce an image to w x h cells holding the average luminance of their pixels
+func grayCells(img image.Image, w, h int) []float64 {
+	b := img.Bounds()
+	sums := make([]float64, w*h)
+	counts := make([]int, w*h)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		cy := (y - b.Min.Y) * h / b.Dy()
+		for x := b.Min.X; x < b.Max.X; x++ {
+			cx := (x - b.Min.X) * w / b.Dx()
+			r, g, bl, _ := img.At(x, y).RGBA()
+			sums[cy*w+cx] += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)
+			counts[cy*w+cx]++
+		}
+	}
+	for i := range sums {
+		if counts[i] > 0 {
+			sums[i] /= float64(counts[i])
+		}
+	}
+	return sums
+}
+
+// Return the similarity of two hashes, from 0 (every bit differs) to 1 (same hash)
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
+
+// A photo along with the hash of its thumbnail
+type HashedPhoto struct {
+	Photo photos.SearchPhoto
+	Hash  uint64
+}
+
+// A photo of a DuplicateGroup
+type Candidate struct {
+	HashedPhoto
+	// Similarity with the first photo of the group
+	Similarity float64
+}
+
+// Photos that are likely duplicates of each other, for human review
+type DuplicateGroup struct {
+	// In the order the photos were given to Cluster, i.e. most recent uploads first
+	// for FindDuplicates
+	Photos []Candidate
+	// Lowest similarity between two photos of the group
+	Score float64
+}
+
+// Group the photos whose hashes are at least minSimilarity similar, directly or
+// through other photos of the group. Photos without duplicates are left out.
+// Groups are sorted by decreasing score.
+func Cluster(list []HashedPhoto, minSimilarity float64) []DuplicateGroup {
+	// union-find over the pairs of similar photos
+	parent := make([]int, len(list))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range list {
+		for j := i + 1; j < len(list); j++ {
+			if Similarity(list[i].Hash, list[j].Hash) >= minSimilarity {
+				if ri, rj := find(i), find(j); ri != rj {
+					parent[rj] = ri
+				}
+			}
+		}
+	}
+
+	members := map[int][]int{}
+	roots := []int{}
+	for i := range list {
+		root := find(i)
+		if members[root] == nil {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+
+	groups := []DuplicateGroup{}
+	for _, root := range roots {
+		idx := members[root]
+		if len(idx) < 2 {
+			continue
+		}
+		first := list[idx[0]].Hash
+		group := DuplicateGroup{Score: 1}
+		for n, i := range idx {
+			group.Photos = append(group.Photos, Candidate{HashedPhoto: list[i], Similarity: Similarity(first, list[i].Hash)})
+			for _, j := range idx[n+1:] {
+				if s := Similarity(list[i].Hash, list[j].Hash); s < group.Score {
+					group.Score = s
+				}
+			}
+		}
+		groups = append(groups, group)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Score > groups[j].Score
+	})
+	return groups
+}
+
+// Options of FindDuplicates
+type Options struct {
+	// Hash function of the thumbnails, DHash when nil
+	Hasher Hasher
+	// Minimum similarity of duplicates, see Similarity. 0.9 when zero
+	MinSimilarity float64
+	// How the thumbnails are picked and where they are downloaded, a memory cache is
+	// used when Cache is nil. Use a DirThumbnailCache to avoid downloading them again
+	// on the next runs.
+	Thumbnails photos.ThumbnailOptions
+}
+
+// Walk the library of a user, download a thumbnail of every photo and group the
+// photos whose thumbnails look alike, e.g. the same file uploaded twice or
+// re-edited versions of a photo. Videos are left out. Photos whose thumbnail can't
+// be downloaded or decoded are reported in the BatchResult.
+// This method requires authentication to access private photos.
+func FindDuplicates(client *flickr.FlickrClient, userId string, opts Options) ([]DuplicateGroup, *flickr.BatchResult, error) {
+	if opts.Hasher == nil {
+		opts.Hasher = DHash{}
+	}
+	if opts.MinSimilarity == 0 {
+		opts.MinSimilarity = 0.9
+	}
+	if opts.Thumbnails.Cache == nil {
+		opts.Thumbnails.Cache = photos.NewMemoryThumbnailCache()
+	}
+
+	params := photos.SearchParams{UserId: userId, Media: photos.MediaPhotos, PerPage: flickr.MaxPerPage}
+	var resp *photos.SearchResponse
+	list := []photos.SearchPhoto{}
+	it := flickr.NewPageIterator(func(page int) (*flickr.ListInfo, error) {
+		params.Page = page
+		var err error
+		if resp, err = photos.Search(client, &params); err != nil {
+			return nil, err
+		}
+		return &flickr.ListInfo{Page: page, Pages: resp.Photos.Pages, PerPage: resp.Photos.Perpage, Total: resp.Photos.Total}, nil
+	}, flickr.PageIteratorOptions{Dedupe: true})
+	for it.Next() {
+		for _, p := range resp.Photos.Items {
+			if it.Keep(p.Id) {
+				list = append(list, p)
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, flickr.NewBatchResult(), err
+	}
+
+	thumbs, result, err := photos.PrefetchThumbnails(client, list, opts.Thumbnails)
+	if err != nil {
+		return nil, result, err
+	}
+	hashed := []HashedPhoto{}
+	for _, p := range list {
+		t := thumbs[p.Id]
+		if t == nil {
+			continue
+		}
+		data, _ := opts.Thumbnails.Cache.Get(t.CacheName)
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			err = flickErr.NewError(flickErr.DownloadError, "thumbnail of photo "+p.Id+" can't be decoded: "+err.Error())
+			if err := result.Add(p.Id, err, opts.Thumbnails.BatchOptions); err != nil {
+				return nil, result, err
+			}
+			continue
+		}
+		hashed = append(hashed, HashedPhoto{Photo: p, Hash: opts.Hasher.Hash(img)})
+	}
+	return Cluster(hashed, opts.MinSimilarity), result, nil
+}
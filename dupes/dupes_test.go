@@ -0,0 +1,86 @@
+package dupes
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Encode a w x h horizontal gradient, reversed if asked
+func gradient(w, h int, reversed bool) []byte {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 250 / w)
+			if reversed {
+				v = 250 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v + uint8(y%3)})
+		}
+	}
+	buf := &bytes.Buffer{}
+	png.Encode(buf, img)
+	return buf.Bytes()
+}
+
+func TestCluster(t *testing.T) {
+	list := []HashedPhoto{
+		{Hash: 0xFF00},
+		{Hash: 0x0F0F0F0F0F0F0F0F},
+		{Hash: 0xFF01},
+		{Hash: 0xFF03},
+	}
+	list[0].Photo.Id, list[1].Photo.Id, list[2].Photo.Id, list[3].Photo.Id = "a", "b", "c", "d"
+	groups := Cluster(list, 0.98)
+	flickr.Expect(t, len(groups), 1)
+	flickr.Expect(t, len(groups[0].Photos), 3)
+	flickr.Expect(t, groups[0].Photos[2].Photo.Id, "d")
+	flickr.Expect(t, groups[0].Photos[2].Similarity, 1-2.0/64)
+	flickr.Expect(t, groups[0].Score, 1-2.0/64)
+}
+
+func TestFindDuplicates(t *testing.T) {
+	files := map[string][]byte{
+		"1": gradient(100, 75, false),
+		"2": gradient(320, 240, false),
+		"3": gradient(100, 75, true),
+		"4": []byte("not an image"),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("method") {
+		case "flickr.photos.search":
+			fmt.Fprint(w, `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="4">
+  <photo id="1" /><photo id="2" /><photo id="3" /><photo id="4" />
+</photos></rsp>`)
+		case "flickr.photos.getSizes":
+			id := r.FormValue("photo_id")
+			fmt.Fprintf(w, `<rsp stat="ok"><sizes><size label="Thumbnail" width="100" height="75" source="https://live.staticflickr.com/1/%s_a_t.png" media="photo" /></sizes></rsp>`, id)
+		default:
+			id := strings.Split(strings.TrimPrefix(r.URL.Path, "/1/"), "_")[0]
+			w.Write(files[id])
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	client := flickr.GetTestClient()
+	client.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	groups, result, err := FindDuplicates(client, "me", Options{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(groups), 1)
+	flickr.Expect(t, len(groups[0].Photos), 2)
+	flickr.Expect(t, groups[0].Photos[0].Photo.Id, "1")
+	flickr.Expect(t, groups[0].Photos[1].Photo.Id, "2")
+	flickr.Expect(t, groups[0].Score >= 0.9, true)
+	flickr.Expect(t, len(result.Failed), 1)
+	flickr.Expect(t, result.Failed[0].Item, "4")
+}
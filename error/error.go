@@ -6,20 +6,43 @@ package error
 // along with the HTTP Response
 
 const (
-	ApiError          = 10
-	RequestTokenError = 20
-	OAuthTokenError   = 30
+	ApiError              = 10
+	RequestTokenError     = 20
+	OAuthTokenError       = 30
+	VerificationError     = 40
+	FileTooLargeError     = 50
+	InvalidParamsError    = 60
+	DownloadError         = 70
+	CircuitOpenError      = 80
+	RulesNotAcceptedError = 90
+	UnsupportedMediaError = 100
+	ExifHiddenError       = 110
+	OfflineQueuedError    = 120
 )
 
 var errors = map[int]string{
-	ApiError:          "Flickr API returned an error: ",
-	RequestTokenError: "An error occurred during token request: ",
-	OAuthTokenError:   "An error occurred while getting the OAuth token: ",
+	ApiError:              "Flickr API returned an error: ",
+	RequestTokenError:     "An error occurred during token request: ",
+	OAuthTokenError:       "An error occurred while getting the OAuth token: ",
+	VerificationError:     "Uploaded file failed verification: ",
+	FileTooLargeError:     "File exceeds the allowed size: ",
+	InvalidParamsError:    "Invalid request parameters: ",
+	DownloadError:         "Unable to download file: ",
+	CircuitOpenError:      "Too many consecutive failures, calls suspended: ",
+	RulesNotAcceptedError: "Group rules were not accepted: ",
+	UnsupportedMediaError: "File format not supported by Flickr: ",
+	ExifHiddenError:       "The owner of the photo does not share its EXIF data: ",
+	OfflineQueuedError:    "Network unavailable, call queued to be sent later: ",
 }
 
 type Error struct {
 	ErrorCode int
 	Message   string
+	// For ApiError, the code of the error returned by Flickr
+	ApiCode int
+	// Detailed cause of the error when known, e.g. the *flickr.OAuthError of
+	// OAuth failures, returned by Unwrap
+	Cause error
 }
 
 // Implement error interface
@@ -27,6 +50,11 @@ func (e Error) Error() string {
 	return e.Message
 }
 
+// Return the cause of the error for errors.Is and errors.As, nil if unknown
+func (e Error) Unwrap() error {
+	return e.Cause
+}
+
 func NewError(errorCode int, message string) *Error {
 	return &Error{
 		ErrorCode: errorCode,
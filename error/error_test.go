@@ -1,6 +1,7 @@
 package error
 
 import (
+	stderrors "errors"
 	"testing"
 )
 
@@ -12,3 +13,15 @@ func TestError(t *testing.T) {
 
 	}
 }
+
+func TestErrorUnwrap(t *testing.T) {
+	cause := stderrors.New("cause")
+	e := NewError(RequestTokenError, "token_rejected")
+	if stderrors.Unwrap(e) != nil {
+		t.Error("Expected no cause")
+	}
+	e.Cause = cause
+	if !stderrors.Is(e, cause) {
+		t.Errorf("Expected %v to wrap %v", e, cause)
+	}
+}
@@ -0,0 +1,259 @@
+package flickr
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attributes holding unix timestamps, exported as RFC 3339 dates
+var timestampAttributes = map[string]bool{
+	"dateupload":   true,
+	"dateuploaded": true,
+	"lastupdate":   true,
+	"dateadded":    true,
+	"datecreate":   true,
+	"posted":       true,
+	"date_create":  true,
+	"date_update":  true,
+}
+
+var (
+	xmlNameType       = reflect.TypeOf(xml.Name{})
+	basicResponseType = reflect.TypeOf(BasicResponse{})
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// A named value of an exported struct, values are either scalars, []exportField
+// for nested structs or []interface{} for lists
+type exportField struct {
+	name  string
+	value interface{}
+}
+
+// Return the name a struct field is exported with, the one used in the XML response
+// when available. Returns false for fields that must not be exported.
+func exportName(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" || f.Type == xmlNameType {
+		return "", false
+	}
+	tag := f.Tag.Get("xml")
+	if tag == "-" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "innerxml" || opt == "comment" {
+			return "", false
+		}
+	}
+	name := parts[0]
+	if i := strings.LastIndex(name, ">"); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name, true
+}
+
+// Convert a value to its exported form. Flickr sends many numbers and flags as
+// strings: "0"/"1" flags (is*, can*, has*) become booleans and unix timestamps
+// become dates, IDs and secrets are left as they are.
+func exportValue(name string, v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s := v.String()
+		if strings.HasPrefix(name, "is") || strings.HasPrefix(name, "can") || strings.HasPrefix(name, "has") {
+			if s == "0" || s == "1" {
+				return s == "1"
+			}
+		}
+		if timestampAttributes[name] {
+			if ts, err := strconv.ParseInt(s, 10, 64); err == nil && ts > 0 {
+				return time.Unix(ts, 0).UTC()
+			}
+		}
+		return s
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if timestampAttributes[name] && v.Int() > 0 {
+			return time.Unix(v.Int(), 0).UTC()
+		}
+		return v.Interface()
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface()
+		}
+		return exportStruct(v)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		ret := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ret = append(ret, exportValue(name, v.Index(i)))
+		}
+		return ret
+	}
+	return v.Interface()
+}
+
+// Convert a struct to a list of fields, embedded structs are flattened and the
+// fields of BasicResponse (status and error) are left out
+func exportStruct(v reflect.Value) []exportField {
+	ret := []exportField{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if f.Type != basicResponseType {
+				ret = append(ret, exportStruct(v.Field(i))...)
+			}
+			continue
+		}
+		name, ok := exportName(f)
+		if !ok {
+			continue
+		}
+		ret = append(ret, exportField{name, exportValue(name, v.Field(i))})
+	}
+	return ret
+}
+
+// Encode an exported value as JSON, keeping struct fields in declaration order
+func encodeExported(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case []exportField:
+		buf.WriteByte('{')
+		for i, f := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			name, _ := json.Marshal(f.name)
+			buf.Write(name)
+			buf.WriteByte(':')
+			if err := encodeExported(buf, f.value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeExported(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+// Encode a response (or any struct) as JSON for consumption by other tools.
+// Fields are named after the XML attributes and elements of the Flickr response,
+// "0"/"1" flags are converted to booleans and unix timestamps to RFC 3339 dates.
+// The status and error of the response are left out.
+func MarshalResponse(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := encodeExported(buf, exportValue("", reflect.ValueOf(v))); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Flatten exported fields into CSV columns, nested structs are prefixed with the
+// name of their parent ("visibility.ispublic") and lists are joined with ";"
+func flattenExported(prefix string, fields []exportField, header *[]string, row *[]string) {
+	for _, f := range fields {
+		name := prefix + f.name
+		if nested, ok := f.value.([]exportField); ok {
+			flattenExported(name+".", nested, header, row)
+			continue
+		}
+		*header = append(*header, name)
+		*row = append(*row, formatExported(f.value))
+	}
+}
+
+// Format an exported scalar value for a CSV cell
+func formatExported(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if _, ok := item.([]exportField); ok {
+				// lists of structs don't fit in a cell
+				continue
+			}
+			items = append(items, formatExported(item))
+		}
+		return strings.Join(items, ";")
+	}
+	return fmt.Sprint(value)
+}
+
+// Write a list of structs (e.g. the photos or groups of a list response) as CSV,
+// one row per item preceded by a header. Columns and values are the same as the
+// ones produced by MarshalResponse.
+func WriteCSV(w io.Writer, list interface{}) error {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return invalidParams("WriteCSV expects a list of structs, got %T", list)
+	}
+	elem := v.Type().Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return invalidParams("WriteCSV expects a list of structs, got %T", list)
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{}
+	flattenExported("", exportStruct(reflect.Zero(elem)), &header, &[]string{})
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		row := []string{}
+		fields, ok := exportValue("", v.Index(i)).([]exportField)
+		if !ok {
+			// nil pointer
+			row = make([]string, len(header))
+		} else {
+			flattenExported("", fields, &[]string{}, &row)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
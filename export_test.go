@@ -0,0 +1,68 @@
+package flickr
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+type exportItem struct {
+	Id         string `xml:"id,attr"`
+	IsPublic   string `xml:"ispublic,attr"`
+	DateUpload string `xml:"dateupload,attr"`
+	Views      int    `xml:"views,attr"`
+	Title      string `xml:"title"`
+	Tags       []string
+	Visibility struct {
+		IsFamily bool `xml:"isfamily,attr"`
+	} `xml:"visibility"`
+}
+
+type exportResponse struct {
+	BasicResponse
+	Photos struct {
+		Total int          `xml:"total,attr"`
+		Items []exportItem `xml:"photo"`
+	} `xml:"photos"`
+}
+
+const exportBody = `<rsp stat="ok"><photos total="2">
+  <photo id="0042" ispublic="1" dateupload="1089839628" views="7"><title>beach, sunset</title><visibility isfamily="1" /></photo>
+  <photo id="43" ispublic="0" dateupload="" views="0"><title>spam</title></photo>
+</photos></rsp>`
+
+func TestMarshalResponse(t *testing.T) {
+	resp := &exportResponse{}
+	Expect(t, xml.Unmarshal([]byte(exportBody), resp), nil)
+	resp.Photos.Items[0].Tags = []string{"beach", "sea"}
+
+	data, err := MarshalResponse(resp)
+	Expect(t, err, nil)
+	Expect(t, json.Valid(data), true)
+	Expect(t, string(data), `{"photos":{"total":2,"photo":[`+
+		`{"id":"0042","ispublic":true,"dateupload":"2004-07-14T21:13:48Z","views":7,"title":"beach, sunset","tags":["beach","sea"],"visibility":{"isfamily":true}},`+
+		`{"id":"43","ispublic":false,"dateupload":"","views":0,"title":"spam","tags":[],"visibility":{"isfamily":false}}]}}`)
+}
+
+func TestWriteCSV(t *testing.T) {
+	resp := &exportResponse{}
+	Expect(t, xml.Unmarshal([]byte(exportBody), resp), nil)
+	resp.Photos.Items[0].Tags = []string{"beach", "sea"}
+
+	buf := &bytes.Buffer{}
+	Expect(t, WriteCSV(buf, resp.Photos.Items), nil)
+	Expect(t, buf.String(), "id,ispublic,dateupload,views,title,tags,visibility.isfamily\n"+
+		"0042,true,2004-07-14T21:13:48Z,7,\"beach, sunset\",beach;sea,true\n"+
+		"43,false,,0,spam,,false\n")
+
+	// an empty list still gets a header
+	buf.Reset()
+	Expect(t, WriteCSV(buf, []*exportItem{}), nil)
+	Expect(t, buf.String(), "id,ispublic,dateupload,views,title,tags,visibility.isfamily\n")
+
+	err := WriteCSV(buf, "nope")
+	Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+}
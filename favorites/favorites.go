@@ -0,0 +1,137 @@
+// Package implementing methods: flickr.favorites.*
+package favorites
+
+import (
+	"context"
+	"encoding/xml"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+	"gopkg.in/masci/flickr.v2/photos"
+)
+
+// Code returned by flickr.favorites.remove for photos that are not in the favorites
+// of the calling user, photos that don't exist included
+const NotAFavorite = 1
+
+// A photo in the favorites list of a user
+type Favorite struct {
+	photos.SearchPhoto
+	// Unix timestamp of the moment the photo was faved
+	DateFaved string `xml:"date_faved,attr"`
+}
+
+// Parameters of GetList, zero values are not sent to Flickr
+type ListParams struct {
+	// The calling user when empty
+	UserId string
+	// Unix timestamps, only photos faved at or after MinFaveDate and at or before
+	// MaxFaveDate are returned
+	MinFaveDate int64
+	MaxFaveDate int64
+	// comma separated list of extra fields to fetch for each photo
+	Extras string
+}
+
+// Remove a photo from the favorites of the calling user.
+// This method requires authentication with 'write' permission.
+func Remove(client *flickr.FlickrClient, photoId string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.favorites.remove")
+	client.Args.Set("photo_id", photoId)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Return whether err reports a photo that is not a favorite of the calling user
+func IsNotAFavorite(err error) bool {
+	e, ok := err.(*flickErr.Error)
+	return ok && e.ErrorCode == flickErr.ApiError && e.ApiCode == NotAFavorite
+}
+
+// Fetch a page of favorites, calling fn for every photo as soon as it's decoded
+func streamList(client *flickr.FlickrClient, params *ListParams, page int, fn func(*Favorite) error) (*flickr.ListInfo, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.favorites.getList")
+	if params.UserId != "" {
+		client.Args.Set("user_id", params.UserId)
+	}
+	if params.MinFaveDate > 0 {
+		client.SetTime("min_fave_date", time.Unix(params.MinFaveDate, 0), flickr.UnixDate)
+	}
+	if params.MaxFaveDate > 0 {
+		client.SetTime("max_fave_date", time.Unix(params.MaxFaveDate, 0), flickr.UnixDate)
+	}
+	if params.Extras != "" {
+		client.Args.Set("extras", params.Extras)
+	}
+	client.SetInt("per_page", flickr.MaxPerPage)
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	client.OAuthSign()
+
+	return flickr.DoGetStream(client, "photo", func(d *xml.Decoder, start *xml.StartElement) error {
+		fave := &Favorite{}
+		if err := d.DecodeElement(fave, start); err != nil {
+			return err
+		}
+		return fn(fave)
+	})
+}
+
+// Walk the favorites of a user, most recently faved first, calling fn for every
+// photo as soon as it's decoded so that lists of thousands of favorites are never
+// held in memory. Photos shifted to the next page by favorites added during the walk
+// are only reported once, see flickr.PageIterator. Removing favorites from fn would
+// shift the following ones to the pages already walked, collect the IDs and pass
+// them to RemoveMany once the walk is over. Returning an error from fn stops the walk.
+// This method requires authentication with 'read' permission.
+func GetList(client *flickr.FlickrClient, params ListParams, fn func(*Favorite) error) (flickr.PaginationStats, error) {
+	var fnErr error
+	var it *flickr.PageIterator
+	it = flickr.NewPageIterator(func(page int) (*flickr.ListInfo, error) {
+		return streamList(client, &params, page, func(fave *Favorite) error {
+			if !it.Keep(fave.Id) {
+				return nil
+			}
+			fnErr = fn(fave)
+			return fnErr
+		})
+	}, flickr.PageIteratorOptions{Dedupe: true})
+	for it.Next() {
+	}
+	if fnErr != nil {
+		return it.Stats(), fnErr
+	}
+	return it.Stats(), it.Err()
+}
+
+// Remove many photos from the favorites of the calling user, e.g. to prune stale
+// favorites found with GetList. Photos that are not favorites, or that don't exist
+// anymore since Flickr reports both with the same code, count as removed with a
+// warning. Once ctx is done no more photos are removed and ctx.Err() is returned
+// along with the partial result.
+// This method requires authentication with 'write' permission.
+func RemoveMany(ctx context.Context, client *flickr.FlickrClient, photoIds []string, opts flickr.BatchOptions) (*flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	for _, id := range photoIds {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		_, err := Remove(client, id)
+		if IsNotAFavorite(err) {
+			result.Warn("photo %s was not a favorite", id)
+			err = nil
+		}
+		if err := result.Add(id, err, opts); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package favorites
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestGetList(t *testing.T) {
+	pages := map[string]string{
+		"": `<rsp stat="ok"><photos page="1" pages="2" perpage="2" total="3">
+  <photo id="3" owner="a" secret="x" server="1" title="three" date_faved="300" />
+  <photo id="2" owner="a" secret="x" server="1" title="two" date_faved="200" />
+</photos></rsp>`,
+		// a favorite was added after the first page, photo 2 shifted to this one
+		"2": `<rsp stat="ok"><photos page="2" pages="2" perpage="2" total="4">
+  <photo id="2" owner="a" secret="x" server="1" title="two" date_faved="200" />
+  <photo id="1" owner="a" secret="x" server="1" title="one" date_faved="100" />
+</photos></rsp>`,
+	}
+	var minDates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		minDates = append(minDates, r.FormValue("min_fave_date"))
+		w.Write([]byte(pages[r.FormValue("page")]))
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	client := flickr.GetTestClient()
+	client.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	ids := []string{}
+	stats, err := GetList(client, ListParams{MinFaveDate: 50}, func(f *Favorite) error {
+		ids = append(ids, f.Id+"@"+f.DateFaved)
+		return nil
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(ids), 3)
+	flickr.Expect(t, ids[2], "1@100")
+	flickr.Expect(t, stats, flickr.PaginationStats{Pages: 2, Items: 4, Duplicates: 1, TotalChanged: true})
+	flickr.Expect(t, minDates[0], "50")
+
+	stop := errors.New("stop")
+	_, err = GetList(client, ListParams{}, func(f *Favorite) error {
+		return stop
+	})
+	flickr.Expect(t, err, stop)
+}
+
+func TestRemoveMany(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("photo_id") {
+		case "2":
+			w.Write([]byte(`<rsp stat="fail"><err code="1" msg="Photo not in favorites" /></rsp>`))
+		case "3":
+			w.Write([]byte(`<rsp stat="fail"><err code="2" msg="Cannot remove photo from that user's favorites" /></rsp>`))
+		case "4":
+			w.Write([]byte(`<rsp stat="fail"><err code="105" msg="Service currently unavailable" /></rsp>`))
+		default:
+			w.Write([]byte(`<rsp stat="ok"></rsp>`))
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	client := flickr.GetTestClient()
+	client.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	result, err := RemoveMany(context.Background(), client, []string{"1", "2", "3", "4"}, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Succeeded), 2)
+	flickr.Expect(t, result.Succeeded[1], "2")
+	flickr.Expect(t, len(result.Warnings), 1)
+	flickr.Expect(t, len(result.Tombstones), 0)
+	flickr.Expect(t, len(result.Failed), 2)
+	flickr.Expect(t, result.Failed[0].Item, "3")
+	flickr.Expect(t, result.Failed[1].Item, "4")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = RemoveMany(ctx, client, []string{"1"}, flickr.BatchOptions{})
+	flickr.Expect(t, err, context.Canceled)
+	flickr.Expect(t, len(result.Succeeded), 0)
+}
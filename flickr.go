@@ -4,11 +4,13 @@ package flickr
 import (
 	"bytes"
 	"mime/multipart"
+	"net/http"
 )
 
 const (
 	API_ENDPOINT      = "https://api.flickr.com/services/rest"
 	UPLOAD_ENDPOINT   = "https://up.flickr.com/services/upload/"
+	REPLACE_ENDPOINT  = "https://up.flickr.com/services/replace/"
 	AUTHORIZE_URL     = "https://www.flickr.com/services/oauth/authorize"
 	REQUEST_TOKEN_URL = "https://www.flickr.com/services/oauth/request_token"
 	ACCESS_TOKEN_URL  = "https://www.flickr.com/services/oauth/access_token"
@@ -16,45 +18,82 @@ const (
 
 // Perform a GET request to the Flickr API with the configured FlickrClient passed as first
 // parameter. Results will be unmarshalled to fill in a FlickrResponse struct passed as
-// second parameter.
+// second parameter. OAuth signed calls refused because of the clock are retried once
+// with a corrected timestamp, see ClockSkew.
 func DoGet(client *FlickrClient, r FlickrResponse) error {
-	res, err := client.HTTPClient.Get(client.GetUrl())
-	if err != nil {
-		return err
-	}
-
-	return parseApiResponse(res, r)
+	return client.retryOnSkew(client.OAuthTokenSecret, func() error {
+		return client.getAndParse(func(res *http.Response) error {
+			return client.parseResponse(res, r)
+		})
+	})
 }
 
 // Perform a POST request to the Flickr API with the configured FlickrClient, the
 // request body and the body content type. Results will be unmarshalled in a FlickrResponse
-// struct.
+// struct. The body is sent again as is when the call is retried, see RetryPolicy.
 func DoPostBody(client *FlickrClient, body *bytes.Buffer, bodyType string, r FlickrResponse) error {
-	res, err := client.HTTPClient.Post(client.EndpointUrl, bodyType, body)
-	if err != nil {
-		return err
-	}
+	data := body.Bytes()
+	return postBody(client, func(retry bool) (*bytes.Buffer, string, error) {
+		return bytes.NewBuffer(data), bodyType, nil
+	}, r)
+}
 
-	return parseApiResponse(res, r)
+// Perform a POST request whose body and content type are returned by build, called
+// again for every retry
+func postBody(client *FlickrClient, build func(retry bool) (*bytes.Buffer, string, error), r FlickrResponse) error {
+	return client.roundTrip(client.HTTPClient, func(retry bool) (*http.Request, error) {
+		body, bodyType, err := build(retry)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest("POST", client.EndpointUrl, body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", bodyType)
+		return req, nil
+	}, func(res *http.Response) error {
+		return client.parseResponse(res, r)
+	})
 }
 
 // Perform a POST request to the Flickr API with the configured FlickrClient,
-// dumping client Args into the request Body.
+// dumping client Args into the request Body. When the client has an OfflineQueue,
+// write calls failing because of the network are queued, see OfflineQueue, read
+// calls (get*, lookup* and search methods) return the network error. Calls refused
+// because of the clock are retried as with DoGet.
 func DoPost(client *FlickrClient, r FlickrResponse) error {
-	// instance an empty request body
-	body := &bytes.Buffer{}
-	// multipart writer to fill the body
-	writer := multipart.NewWriter(body)
-	// dump params
-	for key, val := range client.Args {
-		_ = writer.WriteField(key, val[0])
+	if client.OfflineQueue != nil && client.EndpointUrl == API_ENDPOINT && !readMethod(client.Args.Get("method")) {
+		return client.OfflineQueue.post(client, r)
 	}
-	err := writer.Close()
-	if err != nil {
-		return err
-	}
-	// evaluate the content type and the boundary
-	contentType := writer.FormDataContentType()
+	return doPost(client, r)
+}
+
+// Same as DoPost, ignoring the OfflineQueue of the client
+func doPost(client *FlickrClient, r FlickrResponse) error {
+	return client.retryOnSkew(client.OAuthTokenSecret, func() error {
+		return postArgs(client, r)
+	})
+}
 
-	return DoPostBody(client, body, contentType, r)
+// Post the client Args as a multipart body, signed again for every retry
+func postArgs(client *FlickrClient, r FlickrResponse) error {
+	return postBody(client, func(retry bool) (*bytes.Buffer, string, error) {
+		if retry {
+			client.resign()
+		}
+		// instance an empty request body
+		body := &bytes.Buffer{}
+		// multipart writer to fill the body
+		writer := multipart.NewWriter(body)
+		// dump params
+		for key, val := range client.Args {
+			_ = writer.WriteField(key, val[0])
+		}
+		if err := writer.Close(); err != nil {
+			return nil, "", err
+		}
+		// the content type carries the boundary
+		return body, writer.FormDataContentType(), nil
+	}, r)
 }
@@ -2,6 +2,10 @@ package flickr
 
 import (
 	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 )
 
@@ -36,3 +40,35 @@ func TestDoPost(t *testing.T) {
 	params := []string{"fooArg"}
 	AssertParamsInBody(t, fclient, params)
 }
+
+func TestResponseMeta(t *testing.T) {
+	challenge := "<html>Checking your browser</html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.Header().Set("Server", "cloudflare")
+			w.Header().Set("Content-Length", fmt.Sprint(len(challenge)))
+			w.WriteHeader(503)
+			fmt.Fprint(w, challenge)
+			return
+		}
+		fmt.Fprint(w, `<rsp stat="ok"></rsp>`)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+
+	resp := &FooResponse{}
+	err := DoGet(fclient, resp)
+	Expect(t, err, nil)
+	Expect(t, resp.HTTP.StatusCode, 200)
+
+	// the details are available when the body can't be parsed
+	resp = &FooResponse{}
+	err = DoPost(fclient, resp)
+	Expect(t, err != nil, true)
+	Expect(t, resp.HTTP.StatusCode, 503)
+	Expect(t, resp.HTTP.ContentLength, int64(len(challenge)))
+	Expect(t, resp.HTTP.Header.Get("Server"), "cloudflare")
+	Expect(t, resp.ErrorMsg(), challenge)
+}
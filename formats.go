@@ -0,0 +1,83 @@
+package flickr
+
+import (
+	"mime"
+	"strings"
+)
+
+// Details about a file format Flickr reports as "originalformat"
+type MediaFormat struct {
+	// File extension, including the leading dot
+	Extension string
+	// MIME type of the file
+	MimeType string
+	// Whether the format is a video container
+	Video bool
+}
+
+var mediaFormats = map[string]MediaFormat{
+	"jpg":  {".jpg", "image/jpeg", false},
+	"jpeg": {".jpg", "image/jpeg", false},
+	"png":  {".png", "image/png", false},
+	"gif":  {".gif", "image/gif", false},
+	"tif":  {".tiff", "image/tiff", false},
+	"tiff": {".tiff", "image/tiff", false},
+	"webp": {".webp", "image/webp", false},
+	"heic": {".heic", "image/heic", false},
+	"mp4":  {".mp4", "video/mp4", true},
+	"m4v":  {".m4v", "video/x-m4v", true},
+	"mov":  {".mov", "video/quicktime", true},
+	"avi":  {".avi", "video/x-msvideo", true},
+	"wmv":  {".wmv", "video/x-ms-wmv", true},
+	"mpg":  {".mpg", "video/mpeg", true},
+	"mpeg": {".mpg", "video/mpeg", true},
+	"3gp":  {".3gp", "video/3gpp", true},
+	"m2ts": {".m2ts", "video/mp2t", true},
+	"mts":  {".mts", "video/mp2t", true},
+	"ogg":  {".ogv", "video/ogg", true},
+	"ogv":  {".ogv", "video/ogg", true},
+}
+
+// Return details about a format string as returned by Flickr in the "originalformat"
+// attribute (case insensitive). The second value is false for unknown formats.
+func GetMediaFormat(format string) (MediaFormat, bool) {
+	f, found := mediaFormats[strings.ToLower(strings.TrimPrefix(format, "."))]
+	return f, found
+}
+
+// Return the file extension (with the leading dot) matching the format string,
+// unknown formats get the format itself as extension so no information is lost
+func FormatExtension(format string) string {
+	if f, found := GetMediaFormat(format); found {
+		return f.Extension
+	}
+	if format == "" {
+		return ""
+	}
+	return "." + strings.ToLower(format)
+}
+
+// Return the MIME type matching the format string, "application/octet-stream"
+// if the format is unknown
+func FormatMimeType(format string) string {
+	if f, found := GetMediaFormat(format); found {
+		return f.MimeType
+	}
+	return "application/octet-stream"
+}
+
+// Return the file extension (with the leading dot) of a MIME type, e.g. the
+// Content-Type of a download, "" if it's not one of the formats known. Formats
+// sharing a MIME type get the first extension in lexical order.
+func mimeTypeExtension(mimeType string) string {
+	if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = mediaType
+	}
+	ext := ""
+	for _, f := range mediaFormats {
+		if f.MimeType == mimeType && (ext == "" || f.Extension < ext) {
+			ext = f.Extension
+		}
+	}
+	return ext
+}
@@ -0,0 +1,44 @@
+package flickr
+
+import (
+	"testing"
+)
+
+func TestGetMediaFormat(t *testing.T) {
+	f, found := GetMediaFormat("JPG")
+	Expect(t, found, true)
+	Expect(t, f.Extension, ".jpg")
+	Expect(t, f.MimeType, "image/jpeg")
+	Expect(t, f.Video, false)
+
+	f, found = GetMediaFormat("mov")
+	Expect(t, found, true)
+	Expect(t, f.Video, true)
+
+	_, found = GetMediaFormat("foo")
+	Expect(t, found, false)
+}
+
+func TestFormatExtension(t *testing.T) {
+	Expect(t, FormatExtension("jpg"), ".jpg")
+	Expect(t, FormatExtension("tif"), ".tiff")
+	Expect(t, FormatExtension("mp4"), ".mp4")
+	Expect(t, FormatExtension("Foo"), ".foo")
+	Expect(t, FormatExtension(""), "")
+}
+
+func TestFormatMimeType(t *testing.T) {
+	Expect(t, FormatMimeType("png"), "image/png")
+	Expect(t, FormatMimeType("gif"), "image/gif")
+	Expect(t, FormatMimeType("mov"), "video/quicktime")
+	Expect(t, FormatMimeType("foo"), "application/octet-stream")
+}
+
+func TestMimeTypeExtension(t *testing.T) {
+	Expect(t, mimeTypeExtension("image/jpeg"), ".jpg")
+	Expect(t, mimeTypeExtension("image/jpeg; charset=binary"), ".jpg")
+	Expect(t, mimeTypeExtension("video/mp4"), ".mp4")
+	Expect(t, mimeTypeExtension("video/quicktime"), ".mov")
+	Expect(t, mimeTypeExtension("video/mp2t"), ".m2ts")
+	Expect(t, mimeTypeExtension("text/plain"), "")
+}
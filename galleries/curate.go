@@ -0,0 +1,116 @@
+package galleries
+
+import (
+	"strconv"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+	"gopkg.in/masci/flickr.v2/photos"
+)
+
+// A photo proposed for a gallery, set Comment to the text shown next to the photo
+// before passing it to AddSelection
+type Candidate struct {
+	Photo   photos.SearchPhoto
+	Comment string
+}
+
+// Return the IDs of the photos of a gallery
+func galleryPhotoIds(client *flickr.FlickrClient, galleryId string) (map[string]bool, error) {
+	ids := map[string]bool{}
+	var resp *PhotosResponse
+	it := flickr.NewPageIterator(func(page int) (*flickr.ListInfo, error) {
+		var err error
+		if resp, err = GetPhotos(client, galleryId, "", page); err != nil {
+			return nil, err
+		}
+		return &flickr.ListInfo{Page: page, Pages: resp.Photos.Pages, PerPage: resp.Photos.Perpage, Total: resp.Photos.Total}, nil
+	}, flickr.PageIteratorOptions{})
+	for it.Next() {
+		for _, p := range resp.Photos.Items {
+			it.Keep(p.Id)
+			ids[p.Id] = true
+		}
+	}
+	return ids, it.Err()
+}
+
+// Search photos matching params that could be added to a gallery: photos already in
+// the gallery and photos of its owner, which galleries can't hold, are skipped. At
+// most limit candidates are returned, fewer when the gallery would go beyond
+// MaxPhotos; a zero limit fills the gallery. Candidates come in the search order,
+// set params.Sort to pick the best ones first (e.g. "interestingness-desc").
+// This method does not require authentication unless params needs it.
+func Propose(client *flickr.FlickrClient, galleryId string, params photos.SearchParams, limit int) ([]Candidate, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	info, err := GetInfo(client, galleryId)
+	if err != nil {
+		return nil, err
+	}
+	room := MaxPhotos - info.Gallery.CountPhotos - info.Gallery.CountVideos
+	if limit <= 0 || limit > room {
+		limit = room
+	}
+	candidates := []Candidate{}
+	if limit <= 0 {
+		return candidates, nil
+	}
+	existing, err := galleryPhotoIds(client, galleryId)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *photos.SearchResponse
+	it := flickr.NewPageIterator(func(page int) (*flickr.ListInfo, error) {
+		params.Page = page
+		var err error
+		if resp, err = photos.Search(client, &params); err != nil {
+			return nil, err
+		}
+		return &flickr.ListInfo{Page: page, Pages: resp.Photos.Pages, PerPage: resp.Photos.Perpage, Total: resp.Photos.Total}, nil
+	}, flickr.PageIteratorOptions{Dedupe: true})
+	for len(candidates) < limit && it.Next() {
+		for _, p := range resp.Photos.Items {
+			if !it.Keep(p.Id) || existing[p.Id] || p.Owner == info.Gallery.Owner {
+				continue
+			}
+			candidates = append(candidates, Candidate{Photo: p})
+			if len(candidates) == limit {
+				break
+			}
+		}
+	}
+	return candidates, it.Err()
+}
+
+// Add the approved candidates to a gallery of the calling user, in order, along with
+// their comments. Nothing is added when the selection would take the gallery beyond
+// MaxPhotos. Photos of the calling user are reported as failed without calling
+// Flickr.
+// This method requires authentication with 'write' permission.
+func AddSelection(client *flickr.FlickrClient, galleryId string, selection []Candidate, opts flickr.BatchOptions) (*flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	info, err := GetInfo(client, galleryId)
+	if err != nil {
+		return result, err
+	}
+	if count := info.Gallery.CountPhotos + info.Gallery.CountVideos; count+len(selection) > MaxPhotos {
+		return result, flickErr.NewError(flickErr.InvalidParamsError,
+			"gallery "+galleryId+" can't hold more than "+strconv.Itoa(MaxPhotos)+" photos")
+	}
+
+	for _, c := range selection {
+		var err error
+		if c.Photo.Owner == info.Gallery.Owner {
+			err = flickErr.NewError(flickErr.InvalidParamsError, "photo "+c.Photo.Id+" belongs to the gallery owner")
+		} else {
+			_, err = AddPhoto(client, galleryId, c.Photo.Id, c.Comment)
+		}
+		if err := result.AddPhoto(c.Photo.Id, c.Photo, err, opts); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
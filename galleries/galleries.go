@@ -0,0 +1,111 @@
+// Package implementing methods: flickr.galleries.*
+package galleries
+
+import (
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/photos"
+)
+
+// Maximum number of photos and videos a gallery can hold
+const MaxPhotos = 500
+
+type Gallery struct {
+	Id             string `xml:"id,attr"`
+	URL            string `xml:"url,attr"`
+	Owner          string `xml:"owner,attr"`
+	Username       string `xml:"username,attr"`
+	PrimaryPhotoId string `xml:"primary_photo_id,attr"`
+	// Unix timestamps
+	DateCreate  string `xml:"date_create,attr"`
+	DateUpdate  string `xml:"date_update,attr"`
+	CountPhotos int    `xml:"count_photos,attr"`
+	CountVideos int    `xml:"count_videos,attr"`
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+}
+
+type GalleryInfoResponse struct {
+	flickr.BasicResponse
+	Gallery Gallery `xml:"gallery"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r GalleryInfoResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// A photo of a gallery along with the comment of the curator
+type GalleryPhoto struct {
+	photos.SearchPhoto
+	IsPrimary bool   `xml:"is_primary,attr"`
+	Comment   string `xml:"comment"`
+}
+
+type PhotosResponse struct {
+	flickr.BasicResponse
+	Photos struct {
+		Page    int            `xml:"page,attr"`
+		Pages   int            `xml:"pages,attr"`
+		Perpage int            `xml:"perpage,attr"`
+		Total   int            `xml:"total,attr"`
+		Items   []GalleryPhoto `xml:"photo"`
+	} `xml:"photos"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PhotosResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Get information about a gallery.
+// This method does not require authentication.
+func GetInfo(client *flickr.FlickrClient, galleryId string) (*GalleryInfoResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.galleries.getInfo")
+	client.Args.Set("gallery_id", galleryId)
+	client.ApiSign()
+
+	response := &GalleryInfoResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Get a page of the photos of a gallery, extras is a comma separated list of extra
+// fields to fetch for each photo.
+// This method does not require authentication.
+func GetPhotos(client *flickr.FlickrClient, galleryId, extras string, page int) (*PhotosResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.galleries.getPhotos")
+	client.Args.Set("gallery_id", galleryId)
+	if extras != "" {
+		client.Args.Set("extras", extras)
+	}
+	client.SetInt("per_page", flickr.MaxPerPage)
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	client.ApiSign()
+
+	response := &PhotosResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Add a photo to a gallery of the calling user, along with an optional comment
+// explaining why it was picked. Galleries can only hold photos of other users.
+// This method requires authentication with 'write' permission.
+func AddPhoto(client *flickr.FlickrClient, galleryId, photoId, comment string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.galleries.addPhoto")
+	client.Args.Set("gallery_id", galleryId)
+	client.Args.Set("photo_id", photoId)
+	if comment != "" {
+		client.Args.Set("comment", comment)
+	}
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
@@ -0,0 +1,143 @@
+package galleries
+
+import (
+	"fmt"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/photos"
+)
+
+const galleryInfo = `<rsp stat="ok">
+  <gallery id="6065-72157617483228192" url="https://www.flickr.com/photos/straup/galleries/72157617483228192" owner="35034348999@N01" username="straup" count_photos="%d" count_videos="1">
+    <title>Cats</title>
+  </gallery>
+</rsp>`
+
+func TestGetPhotos(t *testing.T) {
+	body := `<rsp stat="ok">
+  <photos page="1" pages="1" perpage="500" total="2">
+    <photo id="1" owner="1@N01" secret="a" server="1" farm="1" title="one" is_primary="1" has_comment="1"><comment>Look at this cat</comment></photo>
+    <photo id="2" owner="2@N01" secret="b" server="1" farm="1" title="two"/>
+  </photos>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.galleries.getPhotos": body,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetPhotos(fclient, "6065-72157617483228192", "tags", 1)
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.galleries.getPhotos")
+	flickr.Expect(t, args.Get("gallery_id"), "6065-72157617483228192")
+	flickr.Expect(t, args.Get("extras"), "tags")
+	flickr.Expect(t, len(resp.Photos.Items), 2)
+	flickr.Expect(t, resp.Photos.Items[0].IsPrimary, true)
+	flickr.Expect(t, resp.Photos.Items[0].Comment, "Look at this cat")
+	flickr.Expect(t, resp.Photos.Items[1].Owner, "2@N01")
+}
+
+func TestAddPhoto(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.galleries.addPhoto": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := AddPhoto(fclient, "6065-72157617483228192", "123", "Great light")
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.galleries.addPhoto")
+	flickr.Expect(t, args.Get("photo_id"), "123")
+	flickr.Expect(t, args.Get("comment"), "Great light")
+}
+
+func TestPropose(t *testing.T) {
+	search := `<rsp stat="ok">
+  <photos page="1" pages="1" perpage="100" total="5">
+    <photo id="1" owner="1@N01" title="in the gallery"/>
+    <photo id="10" owner="35034348999@N01" title="own photo"/>
+    <photo id="11" owner="2@N01" title="first"/>
+    <photo id="12" owner="3@N01" title="second"/>
+    <photo id="13" owner="4@N01" title="third"/>
+  </photos>
+</rsp>`
+	gallery := `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="1"><photo id="1" owner="1@N01"/></photos></rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.galleries.getInfo":   fmt.Sprintf(galleryInfo, 10),
+		"flickr.galleries.getPhotos": gallery,
+		"flickr.photos.search":       search,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	params := photos.SearchParams{Tags: []string{"cat"}, Sort: "interestingness-desc"}
+	candidates, err := Propose(fclient, "6065-72157617483228192", params, 0)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(candidates), 3)
+	flickr.Expect(t, candidates[0].Photo.Id, "11")
+	flickr.Expect(t, candidates[2].Photo.Id, "13")
+	flickr.Expect(t, calls.Last("flickr.photos.search").Get("tags"), "cat")
+
+	candidates, err = Propose(fclient, "6065-72157617483228192", params, 2)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(candidates), 2)
+
+	// 498 photos and 1 video, room for a single photo
+	server2, client2, _ := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.galleries.getInfo":   fmt.Sprintf(galleryInfo, 498),
+		"flickr.galleries.getPhotos": gallery,
+		"flickr.photos.search":       search,
+	})
+	defer server2.Close()
+	fclient.HTTPClient = client2
+	candidates, err = Propose(fclient, "6065-72157617483228192", params, 0)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(candidates), 1)
+	flickr.Expect(t, candidates[0].Photo.Id, "11")
+}
+
+func TestAddSelection(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.galleries.getInfo":  fmt.Sprintf(galleryInfo, 10),
+		"flickr.galleries.addPhoto": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	selection := []Candidate{
+		{Photo: photos.SearchPhoto{Id: "11", Owner: "2@N01"}, Comment: "Curious cat"},
+		{Photo: photos.SearchPhoto{Id: "10", Owner: "35034348999@N01"}},
+	}
+	result, err := AddSelection(fclient, "6065-72157617483228192", selection, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Succeeded), 1)
+	flickr.Expect(t, result.Succeeded[0], "11")
+	flickr.Expect(t, len(result.Failed), 1)
+	flickr.Expect(t, result.Failed[0].Item, "10")
+	args := calls.Last("flickr.galleries.addPhoto")
+	flickr.Expect(t, args.Get("photo_id"), "11")
+	flickr.Expect(t, args.Get("comment"), "Curious cat")
+}
+
+func TestAddSelectionFull(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.galleries.getInfo":  fmt.Sprintf(galleryInfo, 498),
+		"flickr.galleries.addPhoto": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	selection := []Candidate{
+		{Photo: photos.SearchPhoto{Id: "11", Owner: "2@N01"}},
+		{Photo: photos.SearchPhoto{Id: "12", Owner: "3@N01"}},
+	}
+	_, err := AddSelection(fclient, "6065-72157617483228192", selection, flickr.BatchOptions{})
+	flickr.Expect(t, err != nil, true)
+	flickr.Expect(t, calls.Last("flickr.galleries.addPhoto") == nil, true)
+}
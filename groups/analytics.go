@@ -0,0 +1,193 @@
+package groups
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Length of the periods of a PoolReport
+type BucketSize int
+
+const (
+	BucketDay BucketSize = iota
+	// Weeks start on Monday
+	BucketWeek
+	BucketMonth
+)
+
+// Return the start of the period t belongs to, in UTC
+func (b BucketSize) start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch b {
+	case BucketWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// Return the start of the period following the one starting at start
+func (b BucketSize) next(start time.Time) time.Time {
+	switch b {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// Photos a member added to a group pool
+type MemberContribution struct {
+	Owner     string `xml:"owner"`
+	OwnerName string `xml:"ownername"`
+	Photos    int    `xml:"photos"`
+	// When the first and the last photos of the member were added
+	FirstAdded time.Time `xml:"first_added"`
+	LastAdded  time.Time `xml:"last_added"`
+}
+
+// Photos added to a group pool over a period
+type PeriodContribution struct {
+	Start  time.Time `xml:"start"`
+	Photos int       `xml:"photos"`
+	// Number of members who added photos during the period
+	Members int `xml:"members"`
+}
+
+// Contributions to a group pool by member and by period, see AnalyzePool
+type PoolReport struct {
+	GroupId string
+	Bucket  BucketSize
+	// Number of photos analyzed
+	Photos int
+	// Most active members first, ties broken by name
+	Members []MemberContribution
+	// In chronological order, periods without contributions included
+	Periods []PeriodContribution
+}
+
+// Write the contributions of the members as CSV, see flickr.WriteCSV
+func (r *PoolReport) WriteMembersCSV(w io.Writer) error {
+	return flickr.WriteCSV(w, r.Members)
+}
+
+// Write the contributions by period as CSV, see flickr.WriteCSV
+func (r *PoolReport) WritePeriodsCSV(w io.Writer) error {
+	return flickr.WriteCSV(w, r.Periods)
+}
+
+// Options of AnalyzePool
+type PoolAnalyticsOptions struct {
+	// Length of the periods, BucketDay when zero
+	Bucket BucketSize
+	// Only analyze the photos added at or after Since, the whole pool when zero
+	Since time.Time
+}
+
+// Aggregate pool photos by member and by period. Photos whose date can't be parsed
+// are only counted for their member.
+func newPoolReport(groupId string, photos []PoolPhoto, bucket BucketSize) *PoolReport {
+	report := &PoolReport{GroupId: groupId, Bucket: bucket, Photos: len(photos)}
+	members := map[string]*MemberContribution{}
+	periods := map[time.Time]*PeriodContribution{}
+	periodMembers := map[time.Time]map[string]bool{}
+	var first, last time.Time
+
+	for _, p := range photos {
+		m, found := members[p.Owner]
+		if !found {
+			m = &MemberContribution{Owner: p.Owner, OwnerName: p.OwnerName}
+			members[p.Owner] = m
+		}
+		m.Photos++
+
+		ts, err := strconv.ParseInt(p.DateAdded, 10, 64)
+		if err != nil {
+			continue
+		}
+		added := time.Unix(ts, 0).UTC()
+		if m.FirstAdded.IsZero() || added.Before(m.FirstAdded) {
+			m.FirstAdded = added
+		}
+		if added.After(m.LastAdded) {
+			m.LastAdded = added
+		}
+
+		start := bucket.start(added)
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+		if periods[start] == nil {
+			periods[start] = &PeriodContribution{Start: start}
+			periodMembers[start] = map[string]bool{}
+		}
+		periods[start].Photos++
+		periodMembers[start][p.Owner] = true
+	}
+
+	report.Members = make([]MemberContribution, 0, len(members))
+	for _, m := range members {
+		report.Members = append(report.Members, *m)
+	}
+	sort.Slice(report.Members, func(i, j int) bool {
+		a, b := report.Members[i], report.Members[j]
+		if a.Photos != b.Photos {
+			return a.Photos > b.Photos
+		}
+		if a.OwnerName != b.OwnerName {
+			return a.OwnerName < b.OwnerName
+		}
+		return a.Owner < b.Owner
+	})
+
+	report.Periods = []PeriodContribution{}
+	if first.IsZero() {
+		return report
+	}
+	for start := first; !start.After(last); start = bucket.next(start) {
+		p := PeriodContribution{Start: start}
+		if found := periods[start]; found != nil {
+			p = *found
+			p.Members = len(periodMembers[start])
+		}
+		report.Periods = append(report.Periods, p)
+	}
+	return report
+}
+
+// Walk the photos of a group pool, most recently added first, and aggregate the
+// contributions by member and by period, e.g. to reward the most active members or
+// spot a decline in activity.
+// This method requires authentication to access private groups.
+func AnalyzePool(client *flickr.FlickrClient, groupId string, opts PoolAnalyticsOptions) (*PoolReport, error) {
+	photos := []PoolPhoto{}
+	for page := 1; ; page++ {
+		resp, err := getPoolPhotos(client, groupId, "", "owner_name", page, flickr.MaxPerPage)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range resp.Photos.Items {
+			if !opts.Since.IsZero() {
+				if ts, err := strconv.ParseInt(p.DateAdded, 10, 64); err == nil && time.Unix(ts, 0).Before(opts.Since) {
+					// the rest of the pool was added earlier
+					return newPoolReport(groupId, photos, opts.Bucket), nil
+				}
+			}
+			photos = append(photos, p)
+		}
+		if page >= resp.Photos.Pages || len(resp.Photos.Items) == 0 {
+			break
+		}
+	}
+	return newPoolReport(groupId, photos, opts.Bucket), nil
+}
@@ -0,0 +1,64 @@
+package groups
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const analyticsPool = `<rsp stat="ok">
+  <photos page="1" pages="1" perpage="500" total="3">
+    <photo id="1" owner="1@N00" ownername="Alice" secret="a" server="1" farm="1" title="one" dateadded="1705312800" />
+    <photo id="2" owner="2@N00" ownername="Bob" secret="a" server="1" farm="1" title="two" dateadded="1704153600" />
+    <photo id="3" owner="1@N00" ownername="Alice" secret="a" server="1" farm="1" title="three" dateadded="1704110400" />
+  </photos>
+</rsp>`
+
+func TestAnalyzePool(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.groups.pools.getPhotos": analyticsPool,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	report, err := AnalyzePool(fclient, "42@N01", PoolAnalyticsOptions{Bucket: BucketWeek})
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.groups.pools.getPhotos")
+	flickr.Expect(t, args.Get("extras"), "owner_name")
+	flickr.Expect(t, args.Get("per_page"), "500")
+	flickr.Expect(t, report.Photos, 3)
+
+	flickr.Expect(t, len(report.Members), 2)
+	alice := report.Members[0]
+	flickr.Expect(t, alice.OwnerName, "Alice")
+	flickr.Expect(t, alice.Photos, 2)
+	flickr.Expect(t, alice.FirstAdded, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	flickr.Expect(t, alice.LastAdded, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+
+	// weeks start on monday, empty weeks are reported
+	flickr.Expect(t, len(report.Periods), 3)
+	flickr.Expect(t, report.Periods[0].Start, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	flickr.Expect(t, report.Periods[0].Photos, 2)
+	flickr.Expect(t, report.Periods[0].Members, 2)
+	flickr.Expect(t, report.Periods[1].Photos, 0)
+	flickr.Expect(t, report.Periods[2].Members, 1)
+
+	buf := &bytes.Buffer{}
+	flickr.Expect(t, report.WriteMembersCSV(buf), nil)
+	lines := strings.Split(buf.String(), "\n")
+	flickr.Expect(t, lines[0], "owner,ownername,photos,first_added,last_added")
+	flickr.Expect(t, lines[1], "1@N00,Alice,2,2024-01-01T12:00:00Z,2024-01-15T10:00:00Z")
+
+	report, err = AnalyzePool(fclient, "42@N01", PoolAnalyticsOptions{
+		Bucket: BucketMonth,
+		Since:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, report.Photos, 2)
+	flickr.Expect(t, len(report.Periods), 1)
+	flickr.Expect(t, report.Periods[0].Members, 2)
+}
@@ -0,0 +1,112 @@
+package groups
+
+import (
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Error codes returned by flickr.groups.pools.add
+const (
+	// The group doesn't exist or the caller can't see it, code 1 is a photo that
+	// doesn't exist, see flickr.PhotoNotFound
+	groupNotFound = 2
+	// The photo is already in the pool
+	photoAlreadyInPool = 3
+	// The caller reached the number of photos they can add to the pool
+	poolLimitReached = 5
+	// The photo was added to the queue of photos awaiting moderation
+	poolPendingQueue = 6
+	// The photo is already in the queue of photos awaiting moderation
+	poolAlreadyPending = 7
+)
+
+// Outcome of adding a photo to a group pool
+type PoolDisposition string
+
+const (
+	// The photo was added to the pool
+	PoolAdded PoolDisposition = "added"
+	// The photo was already in the pool
+	PoolAlreadyIn PoolDisposition = "already_in_pool"
+	// The photo is waiting for the group admins to approve it
+	PoolPending PoolDisposition = "pending"
+	// The pool doesn't accept more photos from the caller, see PoolAddResult.Skipped
+	PoolLimitReached PoolDisposition = "limit_reached"
+	// The photo couldn't be added, see PoolAddResult.Err
+	PoolFailed PoolDisposition = "failed"
+)
+
+// Outcome of adding one photo with AddPhotos
+type PoolAddResult struct {
+	PhotoId     string
+	Disposition PoolDisposition
+	// Set for PoolLimitReached and PoolFailed when Flickr reported the failure
+	Err error
+	// The photo wasn't submitted because the limit was reached by a previous one
+	Skipped bool
+}
+
+// Return the disposition of a photo given the response of flickr.groups.pools.add
+func poolDisposition(resp *flickr.BasicResponse, err error) PoolDisposition {
+	if err == nil {
+		return PoolAdded
+	}
+	switch resp.ErrorCode() {
+	case photoAlreadyInPool:
+		return PoolAlreadyIn
+	case poolPendingQueue, poolAlreadyPending:
+		return PoolPending
+	case poolLimitReached:
+		return PoolLimitReached
+	}
+	return PoolFailed
+}
+
+// Add many photos to a group pool, one call per photo, returning the disposition of
+// each photo in the same order as photoIds. Photos already in the pool or sent to
+// the moderation queue are not failures. Once the pool limit is reached the
+// remaining photos are not submitted and reported as PoolLimitReached with Skipped
+// set. The batch result records the disposition of every photo in its Actions,
+// failures in Failed and photos that don't exist anymore in Tombstones. Other
+// failures don't stop the batch unless the group can't be found: then AddPhotos
+// stops and returns the error along with the results collected so far, like it
+// does on the first failure when opts.FailFast is set.
+// This method requires authentication with 'write' permission.
+func AddPhotos(client *flickr.FlickrClient, groupId string, photoIds []string, opts flickr.BatchOptions) ([]PoolAddResult, *flickr.BatchResult, error) {
+	results := make([]PoolAddResult, 0, len(photoIds))
+	batch := flickr.NewBatchResult()
+	limited := false
+	for _, photoId := range photoIds {
+		if limited {
+			results = append(results, PoolAddResult{PhotoId: photoId, Disposition: PoolLimitReached, Skipped: true})
+			batch.SetAction(photoId, string(PoolLimitReached))
+			continue
+		}
+
+		resp, err := AddPhoto(client, groupId, photoId)
+		result := PoolAddResult{PhotoId: photoId, Disposition: poolDisposition(resp, err)}
+		switch result.Disposition {
+		case PoolLimitReached:
+			result.Err = err
+			limited = true
+			batch.Warn("pool limit reached adding photo %s to group %s", photoId, groupId)
+		case PoolFailed:
+			result.Err = err
+		}
+		results = append(results, result)
+		batch.SetAction(photoId, string(result.Disposition))
+
+		switch result.Disposition {
+		case PoolAdded, PoolAlreadyIn, PoolPending:
+			batch.Add(photoId, nil, opts)
+		case PoolFailed:
+			if resp.ErrorCode() == groupNotFound {
+				batch.Add(photoId, err, opts)
+				return results, batch, err
+			}
+			if err := batch.AddPhoto(photoId, nil, err, opts); err != nil {
+				return results, batch, err
+			}
+		}
+	}
+	return results, batch, nil
+}
@@ -0,0 +1,162 @@
+package groups
+
+import (
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A discussion topic of a group, Message is its opening post
+type Topic struct {
+	Id         string `xml:"id,attr" json:"id"`
+	Subject    string `xml:"subject,attr" json:"subject"`
+	Author     string `xml:"author,attr" json:"author"`
+	AuthorName string `xml:"authorname,attr" json:"authorname"`
+	// Role of the author in the group: "member", "moderator" or "admin"
+	Role         string `xml:"role,attr" json:"role"`
+	CountReplies int    `xml:"count_replies,attr" json:"count_replies"`
+	IsSticky     bool   `xml:"is_sticky,attr" json:"is_sticky"`
+	IsLocked     bool   `xml:"is_locked,attr" json:"is_locked"`
+	// Unix timestamps
+	DateCreate   string `xml:"datecreate,attr" json:"datecreate"`
+	DateLastPost string `xml:"datelastpost,attr" json:"datelastpost"`
+	Message      string `xml:"message" json:"message"`
+}
+
+// A reply to a discussion topic
+type Reply struct {
+	Id         string `xml:"id,attr" json:"id"`
+	Author     string `xml:"author,attr" json:"author"`
+	AuthorName string `xml:"authorname,attr" json:"authorname"`
+	Role       string `xml:"role,attr" json:"role"`
+	// Unix timestamps, LastEdit is empty if the reply was never edited
+	DateCreate string `xml:"datecreate,attr" json:"datecreate"`
+	LastEdit   string `xml:"lastedit,attr" json:"lastedit"`
+	Message    string `xml:"message" json:"message"`
+}
+
+type TopicsListResponse struct {
+	flickr.BasicResponse
+	Topics struct {
+		GroupId string  `xml:"group_id,attr"`
+		Name    string  `xml:"name,attr"`
+		Page    int     `xml:"page,attr"`
+		Pages   int     `xml:"pages,attr"`
+		PerPage int     `xml:"per_page,attr"`
+		Total   int     `xml:"total,attr"`
+		Items   []Topic `xml:"topic"`
+	} `xml:"topics"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r TopicsListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+type RepliesListResponse struct {
+	flickr.BasicResponse
+	Replies struct {
+		// The topic replied to, along with the pagination of its replies
+		Topic struct {
+			Id      string `xml:"topic_id,attr"`
+			Subject string `xml:"subject,attr"`
+			Page    int    `xml:"page,attr"`
+			Pages   int    `xml:"pages,attr"`
+			PerPage int    `xml:"per_page,attr"`
+			Total   int    `xml:"total,attr"`
+		} `xml:"topic"`
+		Items []Reply `xml:"reply"`
+	} `xml:"replies"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r RepliesListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the discussion topics of a group, most recently active first
+// This method requires authentication to access private groups.
+func GetTopics(client *flickr.FlickrClient, groupId string, page, perPage int) (*TopicsListResponse, error) {
+	if err := flickr.ValidatePerPage(perPage); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.groups.discuss.topics.getList")
+	client.Args.Set("group_id", groupId)
+	// if not provided, flickr defaults this argument to 1
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+	client.OAuthSign()
+
+	response := &TopicsListResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Return the replies to a discussion topic, oldest first
+// This method requires authentication to access private groups.
+func GetReplies(client *flickr.FlickrClient, groupId, topicId string, page, perPage int) (*RepliesListResponse, error) {
+	if err := flickr.ValidatePerPage(perPage); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.groups.discuss.replies.getList")
+	client.Args.Set("group_id", groupId)
+	client.Args.Set("topic_id", topicId)
+	// if not provided, flickr defaults this argument to 1
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+	client.OAuthSign()
+
+	response := &RepliesListResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Call fn for every discussion topic of a group, fetching the pages as needed.
+// Returning an error from fn stops the iteration and the error is returned.
+// This method requires authentication to access private groups.
+func EachTopic(client *flickr.FlickrClient, groupId string, fn func(*Topic) error) error {
+	for page := 1; ; page++ {
+		resp, err := GetTopics(client, groupId, page, flickr.MaxPerPage)
+		if err != nil {
+			return err
+		}
+		for i := range resp.Topics.Items {
+			if err := fn(&resp.Topics.Items[i]); err != nil {
+				return err
+			}
+		}
+		if page >= resp.Topics.Pages || len(resp.Topics.Items) == 0 {
+			return nil
+		}
+	}
+}
+
+// Call fn for every reply to a discussion topic, fetching the pages as needed.
+// Returning an error from fn stops the iteration and the error is returned.
+// This method requires authentication to access private groups.
+func EachReply(client *flickr.FlickrClient, groupId, topicId string, fn func(*Reply) error) error {
+	for page := 1; ; page++ {
+		resp, err := GetReplies(client, groupId, topicId, page, flickr.MaxPerPage)
+		if err != nil {
+			return err
+		}
+		for i := range resp.Replies.Items {
+			if err := fn(&resp.Replies.Items[i]); err != nil {
+				return err
+			}
+		}
+		if page >= resp.Replies.Topic.Pages || len(resp.Replies.Items) == 0 {
+			return nil
+		}
+	}
+}
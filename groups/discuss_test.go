@@ -0,0 +1,83 @@
+package groups
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Serve a group with two topics, the first one with three replies split in two pages
+func discussServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.FormValue("page")
+		switch r.FormValue("method") {
+		case "flickr.groups.discuss.topics.getList":
+			fmt.Fprintln(w, `<rsp stat="ok"><topics group_id="46744914@N00" name="Tequila" total="2" page="1" per_page="500" pages="1">
+  <topic id="t1" subject="Welcome" author="1@N00" authorname="Ann" role="admin" count_replies="3" is_sticky="1" is_locked="" datecreate="1287070965" datelastpost="1287370151"><message>Hi all</message></topic>
+  <topic id="t2" subject="Quiet" author="2@N00" authorname="Bob" role="member" count_replies="0" is_sticky="0" is_locked="1" datecreate="1287070000" datelastpost="1287070000"><message>Anyone?</message></topic>
+</topics></rsp>`)
+		case "flickr.groups.discuss.replies.getList":
+			if r.FormValue("topic_id") != "t1" {
+				fmt.Fprintln(w, `<rsp stat="fail"><err code="2" msg="Topic not found" /></rsp>`)
+				return
+			}
+			if page == "" {
+				fmt.Fprintln(w, `<rsp stat="ok"><replies><topic topic_id="t1" subject="Welcome" total="3" page="1" per_page="2" pages="2" />
+  <reply id="r1" author="3@N00" authorname="Cid" role="member" datecreate="1287071000" lastedit=""><message>Hello</message></reply>
+  <reply id="r2" author="1@N00" authorname="Ann" role="admin" datecreate="1287072000" lastedit="1287073000"><message>Welcome Cid</message></reply>
+</replies></rsp>`)
+				return
+			}
+			fmt.Fprintln(w, `<rsp stat="ok"><replies><topic topic_id="t1" subject="Welcome" total="3" page="2" per_page="2" pages="2" />
+  <reply id="r3" author="4@N00" authorname="Dee" role="member" datecreate="1287370151"><message>Hey</message></reply>
+</replies></rsp>`)
+		}
+	}))
+}
+
+func discussClient(server *httptest.Server) *flickr.FlickrClient {
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+	return fclient
+}
+
+func TestGetTopics(t *testing.T) {
+	server := discussServer()
+	defer server.Close()
+
+	resp, err := GetTopics(discussClient(server), "46744914@N00", 1, 0)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Topics.Name, "Tequila")
+	flickr.Expect(t, len(resp.Topics.Items), 2)
+	topic := resp.Topics.Items[0]
+	flickr.Expect(t, topic.Subject, "Welcome")
+	flickr.Expect(t, topic.CountReplies, 3)
+	flickr.Expect(t, topic.IsSticky, true)
+	flickr.Expect(t, topic.IsLocked, false)
+	flickr.Expect(t, topic.Message, "Hi all")
+	flickr.Expect(t, resp.Topics.Items[1].IsLocked, true)
+
+	_, err = GetTopics(discussClient(server), "46744914@N00", 1, 501)
+	flickr.Expect(t, err != nil, true)
+}
+
+func TestEachReply(t *testing.T) {
+	server := discussServer()
+	defer server.Close()
+
+	ids := ""
+	err := EachReply(discussClient(server), "46744914@N00", "t1", func(r *Reply) error {
+		ids += r.Id
+		return nil
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, ids, "r1r2r3")
+
+	err = EachReply(discussClient(server), "46744914@N00", "t9", func(r *Reply) error { return nil })
+	flickr.Expect(t, err != nil, true)
+}
@@ -0,0 +1,82 @@
+// Package implementing methods: flickr.groups.*
+package groups
+
+import (
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A group as listed by flickr.groups.pools.getGroups
+type Group struct {
+	Nsid       string `xml:"nsid,attr"`
+	Id         string `xml:"id,attr"`
+	Name       string `xml:"name,attr"`
+	Admin      bool   `xml:"admin,attr"`
+	Privacy    int    `xml:"privacy,attr"`
+	IconServer string `xml:"iconserver,attr"`
+	IconFarm   string `xml:"iconfarm,attr"`
+	Photos     int    `xml:"photos,attr"`
+	// populated by flickr.people.getGroups
+	Members        int  `xml:"members,attr"`
+	PoolCount      int  `xml:"pool_count,attr"`
+	InvitationOnly bool `xml:"invitation_only,attr"`
+}
+
+// Return the group ID, Flickr uses either nsid or id depending on the method
+func (g Group) GroupId() string {
+	if g.Nsid != "" {
+		return g.Nsid
+	}
+	return g.Id
+}
+
+type GroupsListResponse struct {
+	flickr.BasicResponse
+	Groups struct {
+		Page    int     `xml:"page,attr"`
+		Pages   int     `xml:"pages,attr"`
+		Perpage int     `xml:"per_page,attr"`
+		Total   int     `xml:"total,attr"`
+		Items   []Group `xml:"group"`
+	} `xml:"groups"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r GroupsListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Join a public group, acceptRules must be true for groups that have rules
+// This method requires authentication with 'write' permission.
+func Join(client *flickr.FlickrClient, groupId string, acceptRules bool) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.groups.join")
+	client.Args.Set("group_id", groupId)
+	if acceptRules {
+		client.SetBool("accept_rules", true)
+	}
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Leave a group, when deletePhotos is true the photos of the user are removed
+// from the group pool
+// This method requires authentication with 'delete' permission.
+func Leave(client *flickr.FlickrClient, groupId string, deletePhotos bool) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.groups.leave")
+	client.Args.Set("group_id", groupId)
+	// any value of delete_photos, even "0", may be taken as true
+	if deletePhotos {
+		client.SetBool("delete_photos", true)
+	}
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
@@ -0,0 +1,27 @@
+package groups
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestLeave(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.groups.leave": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := Leave(fclient, "123@N00", false)
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.groups.leave")
+	flickr.Expect(t, args.Get("group_id"), "123@N00")
+	_, sent := args["delete_photos"]
+	flickr.Expect(t, sent, false)
+
+	_, err = Leave(fclient, "123@N00", true)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.groups.leave").Get("delete_photos"), "1")
+}
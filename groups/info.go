@@ -0,0 +1,110 @@
+package groups
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Flickr NSIDs look like "34427465497@N01"
+var nsidPattern = regexp.MustCompile(`^\d+@N\d+$`)
+
+// Detailed information about a group, as returned by flickr.groups.getInfo
+type GroupInfo struct {
+	Id              string `xml:"id,attr"`
+	PathAlias       string `xml:"path_alias,attr"`
+	IconServer      string `xml:"iconserver,attr"`
+	IconFarm        string `xml:"iconfarm,attr"`
+	Lang            string `xml:"lang,attr"`
+	IsPoolModerated bool   `xml:"ispoolmoderated,attr"`
+	Name            string `xml:"name"`
+	Description     string `xml:"description"`
+	Rules           string `xml:"rules"`
+	Members         int    `xml:"members"`
+	PoolCount       int    `xml:"pool_count"`
+	TopicCount      int    `xml:"topic_count"`
+	Privacy         int    `xml:"privacy"`
+	Throttle        struct {
+		Count     int    `xml:"count,attr"`
+		Mode      string `xml:"mode,attr"`
+		Remaining int    `xml:"remaining,attr"`
+	} `xml:"throttle"`
+	// Photos added to the pool must be posted to a discussion topic, see
+	// AddPhotoWithOptions
+	PoolTopicRequired bool `xml:"pool_topic_required,attr"`
+}
+
+type GroupInfoResponse struct {
+	flickr.BasicResponse
+	Group GroupInfo `xml:"group"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r GroupInfoResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+type LookupGroupResponse struct {
+	flickr.BasicResponse
+	Group struct {
+		Id        string `xml:"id,attr"`
+		GroupName string `xml:"groupname"`
+	} `xml:"group"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r LookupGroupResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Get information about a group
+// This method does not require authentication.
+func GetInfo(client *flickr.FlickrClient, groupId string) (*GroupInfoResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.groups.getInfo")
+	client.Args.Set("group_id", groupId)
+	client.ApiSign()
+
+	response := &GroupInfoResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Return the NSID of a group given the URL of its page or photo pool, e.g.
+// "https://www.flickr.com/groups/flickrcentral/"
+// This method does not require authentication.
+func LookupGroupId(client *flickr.FlickrClient, groupUrl string) (*LookupGroupResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.urls.lookupGroup")
+	client.Args.Set("url", groupUrl)
+	client.ApiSign()
+
+	response := &LookupGroupResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Build the URL of a group page from its path alias, URLs are returned as they are
+func groupUrl(urlOrAlias string) string {
+	if strings.Contains(urlOrAlias, "/") {
+		return urlOrAlias
+	}
+	return "https://www.flickr.com/groups/" + urlOrAlias + "/"
+}
+
+// Get information about a group given the URL of its page, its path alias (the
+// "flickrcentral" in "https://www.flickr.com/groups/flickrcentral/") or its NSID.
+// The URL is resolved with flickr.urls.lookupGroup, then flickr.groups.getInfo is called.
+// This method does not require authentication.
+func LookupGroup(client *flickr.FlickrClient, urlOrAlias string) (*GroupInfoResponse, error) {
+	groupId := strings.TrimSpace(urlOrAlias)
+	if !nsidPattern.MatchString(groupId) {
+		lookup, err := LookupGroupId(client, groupUrl(groupId))
+		if err != nil {
+			return nil, err
+		}
+		groupId = lookup.Group.Id
+	}
+	return GetInfo(client, groupId)
+}
@@ -0,0 +1,75 @@
+package groups
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+const (
+	groupInfo = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <group id="34427465446@N01" path_alias="flickrcentral" iconserver="1" iconfarm="1" lang="en-us" ispoolmoderated="1">
+    <name>FlickrCentral</name>
+    <description>The group for Flickr fans</description>
+    <members>69</members>
+    <pool_count>1234</pool_count>
+    <privacy>3</privacy>
+    <throttle count="10" mode="month" remaining="3" />
+  </group>
+</rsp>`
+	lookupGroup = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <group id="34427465446@N01">
+    <groupname>FlickrCentral</groupname>
+  </group>
+</rsp>`
+)
+
+func TestLookupGroup(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.urls.lookupGroup": lookupGroup,
+		"flickr.groups.getInfo":   groupInfo,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := LookupGroup(fclient, "flickrcentral")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Group.Name, "FlickrCentral")
+	flickr.Expect(t, resp.Group.PathAlias, "flickrcentral")
+	flickr.Expect(t, resp.Group.Members, 69)
+	flickr.Expect(t, resp.Group.IsPoolModerated, true)
+	flickr.Expect(t, resp.Group.Throttle.Remaining, 3)
+	flickr.Expect(t, calls.Last("flickr.urls.lookupGroup").Get("url"), "https://www.flickr.com/groups/flickrcentral/")
+	flickr.Expect(t, calls.Last("flickr.groups.getInfo").Get("group_id"), "34427465446@N01")
+
+	LookupGroup(fclient, "https://www.flickr.com/groups/flickrcentral/pool/")
+	flickr.Expect(t, calls.Last("flickr.urls.lookupGroup").Get("url"), "https://www.flickr.com/groups/flickrcentral/pool/")
+
+	// NSIDs don't need to be resolved
+	LookupGroup(fclient, "12345@N00")
+	flickr.Expect(t, calls.Last("flickr.groups.getInfo").Get("group_id"), "12345@N00")
+	lookups := 0
+	for _, m := range calls.Methods() {
+		if m == "flickr.urls.lookupGroup" {
+			lookups++
+		}
+	}
+	flickr.Expect(t, lookups, 2)
+}
+
+func TestLookupGroupNotFound(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, `<rsp stat="fail"><err code="1" msg="Group not found" /></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := LookupGroup(fclient, "nope")
+	flickr.Expect(t, resp == nil, true)
+	ee, ok := err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, ee.ApiCode, 1)
+}
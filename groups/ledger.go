@@ -0,0 +1,188 @@
+package groups
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A photo posted to a group pool
+type LedgerEntry struct {
+	PhotoId string    `json:"photo_id"`
+	GroupId string    `json:"group_id"`
+	Posted  time.Time `json:"posted"`
+}
+
+type ledgerKey struct {
+	photoId string
+	groupId string
+}
+
+// PostingLedger keeps track of which photos were posted to which groups and when,
+// so the same photo is not submitted twice to a pool.
+// A ledger created with OpenPostingLedger or OpenPostingLedgerStore is persisted as
+// JSON every time it changes, one created with NewPostingLedger lives in memory only.
+// A PostingLedger is safe for concurrent use.
+type PostingLedger struct {
+	mu      sync.Mutex
+	store   flickr.Store
+	key     string
+	entries map[ledgerKey]LedgerEntry
+}
+
+// Create an in-memory ledger
+func NewPostingLedger() *PostingLedger {
+	return &PostingLedger{entries: map[ledgerKey]LedgerEntry{}}
+}
+
+// Load a ledger from a JSON file, a missing file yields an empty ledger that will be
+// created on the first change
+func OpenPostingLedger(path string) (*PostingLedger, error) {
+	return OpenPostingLedgerStore(flickr.NewFileStore(filepath.Dir(path)), filepath.Base(path))
+}
+
+// Load a ledger stored under key, a missing key yields an empty ledger that will be
+// stored on the first change
+func OpenPostingLedgerStore(store flickr.Store, key string) (*PostingLedger, error) {
+	l := NewPostingLedger()
+	l.store = store
+	l.key = key
+
+	entries := []LedgerEntry{}
+	if _, err := flickr.LoadJSON(store, key, &entries); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		l.entries[ledgerKey{e.PhotoId, e.GroupId}] = e
+	}
+	return l, nil
+}
+
+// Write the ledger to its store, noop for in-memory ledgers. Must be called with the lock held.
+func (l *PostingLedger) save() error {
+	if l.store == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(l.sortedEntries(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return l.store.Put(l.key, data)
+}
+
+// Entries sorted by posting time. Must be called with the lock held.
+func (l *PostingLedger) sortedEntries() []LedgerEntry {
+	ret := make([]LedgerEntry, 0, len(l.entries))
+	for _, e := range l.entries {
+		ret = append(ret, e)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].Posted.Equal(ret[j].Posted) {
+			return ret[i].Posted.Before(ret[j].Posted)
+		}
+		if ret[i].GroupId != ret[j].GroupId {
+			return ret[i].GroupId < ret[j].GroupId
+		}
+		return ret[i].PhotoId < ret[j].PhotoId
+	})
+	return ret
+}
+
+// Return all the entries of the ledger, oldest first
+func (l *PostingLedger) Entries() []LedgerEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sortedEntries()
+}
+
+// Return whether the photo was posted to the group
+func (l *PostingLedger) HasPosted(photoId, groupId string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, found := l.entries[ledgerKey{photoId, groupId}]
+	return found
+}
+
+// Record that a photo was posted to a group at the given time
+func (l *PostingLedger) Record(photoId, groupId string, posted time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[ledgerKey{photoId, groupId}] = LedgerEntry{PhotoId: photoId, GroupId: groupId, Posted: posted}
+	return l.save()
+}
+
+// Forget that a photo was posted to a group
+func (l *PostingLedger) Forget(photoId, groupId string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, ledgerKey{photoId, groupId})
+	return l.save()
+}
+
+// Add a photo to a group pool unless the ledger says it was already posted there.
+// Returns whether the photo was actually submitted, a photo Flickr reports as
+// already in the pool is recorded and not considered submitted.
+// This method requires authentication with 'write' permission.
+func (l *PostingLedger) Post(client *flickr.FlickrClient, groupId, photoId string) (bool, error) {
+	if l.HasPosted(photoId, groupId) {
+		return false, nil
+	}
+
+	resp, err := AddPhoto(client, groupId, photoId)
+	if err != nil {
+		if resp.ErrorCode() == photoAlreadyInPool {
+			return false, l.Record(photoId, groupId, time.Now())
+		}
+		return false, err
+	}
+	return true, l.Record(photoId, groupId, time.Now())
+}
+
+// Align the ledger with the photos userId actually has in a group pool: photos
+// missing from the ledger are recorded with the date Flickr added them to the pool,
+// entries for photos no longer in the pool are dropped, so the ledger is expected
+// to only track photos owned by userId.
+// Returns the entries added and removed.
+func (l *PostingLedger) Reconcile(client *flickr.FlickrClient, groupId, userId string) ([]LedgerEntry, []LedgerEntry, error) {
+	inPool := map[string]LedgerEntry{}
+	for page := 1; ; page++ {
+		resp, err := GetPoolPhotos(client, groupId, userId, page)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range resp.Photos.Items {
+			added, _ := strconv.ParseInt(p.DateAdded, 10, 64)
+			inPool[p.Id] = LedgerEntry{PhotoId: p.Id, GroupId: groupId, Posted: time.Unix(added, 0)}
+		}
+		if page >= resp.Photos.Pages {
+			break
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	added := []LedgerEntry{}
+	for id, e := range inPool {
+		if _, found := l.entries[ledgerKey{id, groupId}]; !found {
+			l.entries[ledgerKey{id, groupId}] = e
+			added = append(added, e)
+		}
+	}
+	removed := []LedgerEntry{}
+	for k, e := range l.entries {
+		if k.groupId != groupId {
+			continue
+		}
+		if _, found := inPool[k.photoId]; !found {
+			delete(l.entries, k)
+			removed = append(removed, e)
+		}
+	}
+	return added, removed, l.save()
+}
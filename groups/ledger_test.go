@@ -0,0 +1,102 @@
+package groups
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestPostingLedgerPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger")
+	flickr.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ledger.json")
+
+	l, err := OpenPostingLedger(path)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(l.Entries()), 0)
+	flickr.Expect(t, l.Record("1", "g1", time.Unix(100, 0)), nil)
+	flickr.Expect(t, l.Record("2", "g1", time.Unix(50, 0)), nil)
+
+	l, err = OpenPostingLedger(path)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, l.HasPosted("1", "g1"), true)
+	flickr.Expect(t, l.HasPosted("1", "g2"), false)
+	entries := l.Entries()
+	flickr.Expect(t, len(entries), 2)
+	flickr.Expect(t, entries[0].PhotoId, "2")
+
+	flickr.Expect(t, l.Forget("2", "g1"), nil)
+	l, _ = OpenPostingLedger(path)
+	flickr.Expect(t, l.HasPosted("2", "g1"), false)
+}
+
+func TestPostingLedgerStore(t *testing.T) {
+	s := flickr.NewMemoryStore()
+	l, err := OpenPostingLedgerStore(s, "ledger")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, l.Record("1", "g1", time.Unix(100, 0)), nil)
+
+	l, err = OpenPostingLedgerStore(s, "ledger")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, l.HasPosted("1", "g1"), true)
+}
+
+func TestPostingLedgerPost(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.groups.pools.add": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	l := NewPostingLedger()
+	posted, err := l.Post(fclient, "g1", "123")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, posted, true)
+	flickr.Expect(t, l.HasPosted("123", "g1"), true)
+
+	// already posted, no request is made
+	posted, err = l.Post(fclient, "g1", "123")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, posted, false)
+	flickr.Expect(t, len(calls.Methods()), 1)
+}
+
+func TestPostingLedgerPostAlreadyInPool(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, `<rsp stat="fail"><err code="3" msg="Photo already in pool" /></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	l := NewPostingLedger()
+	posted, err := l.Post(fclient, "g1", "123")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, posted, false)
+	flickr.Expect(t, l.HasPosted("123", "g1"), true)
+}
+
+func TestPostingLedgerReconcile(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, poolPhotos, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	l := NewPostingLedger()
+	l.Record("2645", "g1", time.Unix(1089839628, 0))
+	l.Record("999", "g1", time.Unix(1089839628, 0))
+	l.Record("999", "g2", time.Unix(1089839628, 0))
+
+	added, removed, err := l.Reconcile(fclient, "g1", "12037949754@N01")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(added), 2)
+	flickr.Expect(t, len(removed), 1)
+	flickr.Expect(t, removed[0].PhotoId, "999")
+	flickr.Expect(t, l.HasPosted("2643", "g1"), true)
+	flickr.Expect(t, l.HasPosted("999", "g2"), true)
+	flickr.Expect(t, l.Entries()[0].Posted, time.Unix(1000000000, 0))
+}
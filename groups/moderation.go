@@ -0,0 +1,120 @@
+package groups
+
+import (
+	"strconv"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A photo waiting for a moderation decision
+type PendingPhoto struct {
+	GroupId   string
+	GroupName string
+	Photo     PoolPhoto
+}
+
+// ModerationQueue aggregates the photos recently added to the pools of all the groups
+// the calling user administers.
+// The Flickr API does not expose the queue of photos pending approval, so the queue
+// contains the photos added to each pool after a given time: approving a photo
+// leaves it in the pool, denying removes it through flickr.groups.pools.remove.
+type ModerationQueue struct {
+	client *flickr.FlickrClient
+	Items  []PendingPhoto
+}
+
+// Build a ModerationQueue with the photos added since the given time to the pools
+// of every group the calling user administers.
+// This method requires authentication with 'read' permission, Deny requires 'write'.
+func NewModerationQueue(client *flickr.FlickrClient, since time.Time) (*ModerationQueue, error) {
+	queue := &ModerationQueue{client: client}
+
+	groups := []Group{}
+	for page := 1; ; page++ {
+		resp, err := GetPoolGroups(client, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range resp.Groups.Items {
+			if g.Admin {
+				groups = append(groups, g)
+			}
+		}
+		if page >= resp.Groups.Pages {
+			break
+		}
+	}
+
+	for _, g := range groups {
+		if err := queue.collect(g, since); err != nil {
+			return nil, err
+		}
+	}
+	return queue, nil
+}
+
+// Add the pool photos of a group added after since, pools are returned most recent
+// first so we stop as soon as we meet an older photo
+func (q *ModerationQueue) collect(g Group, since time.Time) error {
+	for page := 1; ; page++ {
+		resp, err := GetPoolPhotos(q.client, g.GroupId(), "", page)
+		if err != nil {
+			return err
+		}
+		for _, p := range resp.Photos.Items {
+			added, _ := strconv.ParseInt(p.DateAdded, 10, 64)
+			if added < since.Unix() {
+				return nil
+			}
+			q.Items = append(q.Items, PendingPhoto{GroupId: g.GroupId(), GroupName: g.Name, Photo: p})
+		}
+		if page >= resp.Photos.Pages {
+			return nil
+		}
+	}
+}
+
+// Remove an item from the queue, returning whether it was found
+func (q *ModerationQueue) pop(item PendingPhoto) bool {
+	for i, it := range q.Items {
+		if it.GroupId == item.GroupId && it.Photo.Id == item.Photo.Id {
+			q.Items = append(q.Items[:i], q.Items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Approve a photo: it stays in the pool and leaves the queue
+func (q *ModerationQueue) Approve(item PendingPhoto) {
+	q.pop(item)
+}
+
+// Deny a photo: it's removed from the group pool and from the queue
+func (q *ModerationQueue) Deny(item PendingPhoto) error {
+	if _, err := RemovePhoto(q.client, item.GroupId, item.Photo.Id); err != nil {
+		return err
+	}
+	q.pop(item)
+	return nil
+}
+
+// Call decide for every item in the queue, approving the photos for which it
+// returns true and denying the others. The result lists the photo IDs denied and the
+// ones Flickr failed to remove, which stay in the queue. With opts.FailFast the
+// review stops at the first error.
+func (q *ModerationQueue) Review(decide func(PendingPhoto) bool, opts flickr.BatchOptions) (*flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	items := append([]PendingPhoto(nil), q.Items...)
+	for _, item := range items {
+		if decide(item) {
+			q.Approve(item)
+			continue
+		}
+		if err := result.Add(item.Photo.Id, q.Deny(item), opts); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
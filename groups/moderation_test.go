@@ -0,0 +1,68 @@
+package groups
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestModerationQueue(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.groups.pools.getGroups": poolGroups,
+		"flickr.groups.pools.getPhotos": poolPhotos,
+		"flickr.groups.pools.remove":    `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	q, err := NewModerationQueue(fclient, time.Unix(1089839000, 0))
+	flickr.Expect(t, err, nil)
+	// only the admin group is scanned, the old photo is left out
+	flickr.Expect(t, len(q.Items), 2)
+	flickr.Expect(t, q.Items[0].GroupId, "33853651681@N01")
+	flickr.Expect(t, q.Items[0].GroupName, "Art and Literature Hoedown")
+
+	result, err := q.Review(func(p PendingPhoto) bool {
+		return p.Photo.Title != "Spam"
+	}, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Succeeded), 1)
+	flickr.Expect(t, len(q.Items), 0)
+	removed := calls.Last("flickr.groups.pools.remove")
+	flickr.Expect(t, removed.Get("photo_id"), "2644")
+	flickr.Expect(t, removed.Get("group_id"), "33853651681@N01")
+}
+
+func TestModerationQueueKo(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMockMethods(200, map[string]string{
+		"flickr.groups.pools.getGroups": poolGroups,
+		"flickr.groups.pools.getPhotos": poolPhotos,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	q, err := NewModerationQueue(fclient, time.Unix(0, 0))
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(q.Items), 3)
+
+	// remove fails, the item stays in the queue
+	err = q.Deny(q.Items[0])
+	flickr.Expect(t, err != nil, true)
+	flickr.Expect(t, len(q.Items), 3)
+
+	q.Approve(q.Items[0])
+	flickr.Expect(t, len(q.Items), 2)
+
+	// every denial fails but the review goes on
+	result, err := q.Review(func(p PendingPhoto) bool { return false }, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Failed), 2)
+	flickr.Expect(t, len(q.Items), 2)
+
+	result, err = q.Review(func(p PendingPhoto) bool { return false }, flickr.BatchOptions{FailFast: true})
+	flickr.Expect(t, err != nil, true)
+	flickr.Expect(t, len(result.Failed), 1)
+}
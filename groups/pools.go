@@ -0,0 +1,173 @@
+package groups
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A photo in a group pool
+type PoolPhoto struct {
+	Id        string `xml:"id,attr"`
+	Owner     string `xml:"owner,attr"`
+	OwnerName string `xml:"ownername,attr"`
+	Secret    string `xml:"secret,attr"`
+	Server    string `xml:"server,attr"`
+	Farm      string `xml:"farm,attr"`
+	Title     string `xml:"title,attr"`
+	IsPublic  bool   `xml:"ispublic,attr"`
+	IsFriend  bool   `xml:"isfriend,attr"`
+	IsFamily  bool   `xml:"isfamily,attr"`
+	// Unix timestamp of when the photo was added to the pool
+	DateAdded string `xml:"dateadded,attr"`
+}
+
+type PoolPhotosResponse struct {
+	flickr.BasicResponse
+	Photos struct {
+		Page    int         `xml:"page,attr"`
+		Pages   int         `xml:"pages,attr"`
+		Perpage int         `xml:"perpage,attr"`
+		Total   int         `xml:"total,attr"`
+		Items   []PoolPhoto `xml:"photo"`
+	} `xml:"photos"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PoolPhotosResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the photos in a group pool, most recently added first.
+// userId is optional and restricts results to photos posted by that user.
+// This method requires authentication to access private groups.
+func GetPoolPhotos(client *flickr.FlickrClient, groupId, userId string, page int) (*PoolPhotosResponse, error) {
+	return getPoolPhotos(client, groupId, userId, "", page, 0)
+}
+
+// Same as GetPoolPhotos, with an optional comma separated list of extra fields and
+// page size
+func getPoolPhotos(client *flickr.FlickrClient, groupId, userId, extras string, page, perPage int) (*PoolPhotosResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.groups.pools.getPhotos")
+	client.Args.Set("group_id", groupId)
+	if userId != "" {
+		client.Args.Set("user_id", userId)
+	}
+	if extras != "" {
+		client.Args.Set("extras", extras)
+	}
+	// if not provided, flickr defaults this argument to 1
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+	client.OAuthSign()
+
+	response := &PoolPhotosResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Return the groups the calling user is able to add photos to, admin groups are
+// flagged with Admin set to true
+// This method requires authentication with 'read' permission.
+func GetPoolGroups(client *flickr.FlickrClient, page int) (*GroupsListResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.groups.pools.getGroups")
+	// if not provided, flickr defaults this argument to 1
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	client.OAuthSign()
+
+	response := &GroupsListResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Add a photo to a group pool
+// This method requires authentication with 'write' permission.
+func AddPhoto(client *flickr.FlickrClient, groupId, photoId string) (*flickr.BasicResponse, error) {
+	return AddPhotoWithOptions(client, groupId, photoId, AddPhotoOptions{})
+}
+
+// Options of AddPhotoWithOptions
+type AddPhotoOptions struct {
+	// Discussion topic the photo is posted to, for the groups requiring one, see
+	// GroupInfo.PoolTopicRequired
+	TopicId string
+	// Check with getInfo whether the group requires a topic before adding the photo,
+	// failing with ErrTopicRequired when TopicId is empty
+	CheckTopic bool
+}
+
+// Returned by AddPhotoWithOptions when the group requires a discussion topic and
+// none was given, pick one of Topics and try again
+type ErrTopicRequired struct {
+	GroupId   string
+	GroupName string
+	// Most recently active topics of the group
+	Topics []Topic
+}
+
+// Implement error interface
+func (e *ErrTopicRequired) Error() string {
+	subjects := make([]string, 0, len(e.Topics))
+	for _, t := range e.Topics {
+		subjects = append(subjects, fmt.Sprintf("%s (%s)", t.Subject, t.Id))
+	}
+	return fmt.Sprintf("group %s requires a discussion topic to add photos, available topics: %s", e.GroupName, strings.Join(subjects, ", "))
+}
+
+// Same as AddPhoto, posting the photo to a discussion topic for the groups that
+// require one. With opts.CheckTopic, a missing topic is detected before adding the
+// photo and an *ErrTopicRequired listing the topics of the group is returned.
+// This method requires authentication with 'write' permission.
+func AddPhotoWithOptions(client *flickr.FlickrClient, groupId, photoId string, opts AddPhotoOptions) (*flickr.BasicResponse, error) {
+	if opts.CheckTopic && opts.TopicId == "" {
+		info, err := GetInfo(client, groupId)
+		if err != nil {
+			return nil, err
+		}
+		if info.Group.PoolTopicRequired {
+			topics, err := GetTopics(client, groupId, 1, 0)
+			if err != nil {
+				return nil, err
+			}
+			return nil, &ErrTopicRequired{GroupId: groupId, GroupName: info.Group.Name, Topics: topics.Topics.Items}
+		}
+	}
+
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.groups.pools.add")
+	client.Args.Set("group_id", groupId)
+	client.Args.Set("photo_id", photoId)
+	if opts.TopicId != "" {
+		client.Args.Set("topic_id", opts.TopicId)
+	}
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Remove a photo from a group pool, group admins can remove any photo
+// This method requires authentication with 'write' permission.
+func RemovePhoto(client *flickr.FlickrClient, groupId, photoId string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.groups.pools.remove")
+	client.Args.Set("group_id", groupId)
+	client.Args.Set("photo_id", photoId)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
@@ -0,0 +1,169 @@
+package groups
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+const (
+	poolGroups = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <groups page="1" pages="1" per_page="400" total="2">
+    <group nsid="33853651681@N01" name="Art and Literature Hoedown" admin="1" privacy="3" photos="2" iconserver="1" iconfarm="1" />
+    <group nsid="34427465446@N01" name="FlickrGeeks" admin="0" privacy="3" photos="17" iconserver="1" iconfarm="1" />
+  </groups>
+</rsp>`
+	poolPhotos = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photos page="1" pages="1" perpage="100" total="3">
+    <photo id="2645" owner="12037949754@N01" title="36679_o" secret="a9f4a06091" server="2" farm="1" ispublic="1" isfriend="0" isfamily="0" ownername="Bees" dateadded="1089839628" />
+    <photo id="2644" owner="12037949754@N01" title="Spam" secret="a9f4a06091" server="2" farm="1" ispublic="1" isfriend="0" isfamily="0" ownername="Bees" dateadded="1089839600" />
+    <photo id="2643" owner="12037949754@N01" title="old one" secret="a9f4a06091" server="2" farm="1" ispublic="1" isfriend="0" isfamily="0" ownername="Bees" dateadded="1000000000" />
+  </photos>
+</rsp>`
+)
+
+func TestGetPoolGroups(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, poolGroups, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetPoolGroups(fclient, 1)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Groups.Total, 2)
+	flickr.Expect(t, len(resp.Groups.Items), 2)
+	g := resp.Groups.Items[0]
+	flickr.Expect(t, g.GroupId(), "33853651681@N01")
+	flickr.Expect(t, g.Admin, true)
+	flickr.Expect(t, g.Photos, 2)
+	flickr.Expect(t, resp.Groups.Items[1].Admin, false)
+}
+
+func TestGetPoolPhotos(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.groups.pools.getPhotos": poolPhotos,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetPoolPhotos(fclient, "33853651681@N01", "123@N00", 2)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(resp.Photos.Items), 3)
+	flickr.Expect(t, resp.Photos.Items[0].OwnerName, "Bees")
+	flickr.Expect(t, resp.Photos.Items[0].DateAdded, "1089839628")
+	args := calls.Last("flickr.groups.pools.getPhotos")
+	flickr.Expect(t, args.Get("group_id"), "33853651681@N01")
+	flickr.Expect(t, args.Get("user_id"), "123@N00")
+	flickr.Expect(t, args.Get("page"), "2")
+}
+
+func TestAddRemovePhoto(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, `<rsp stat="ok"></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := AddPhoto(fclient, "1@N01", "123")
+	flickr.Expect(t, err, nil)
+	_, err = RemovePhoto(fclient, "1@N01", "123")
+	flickr.Expect(t, err, nil)
+
+	server, client = flickr.FlickrMock(200, `<rsp stat="fail"><err code="3" msg="Photo already in pool" /></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+	resp, err := AddPhoto(fclient, "1@N01", "123")
+	_, ok := err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, resp.ErrorCode(), 3)
+
+	fclient = flickr.GetTestClient()
+	AddPhoto(fclient, "1@N01", "123")
+	flickr.AssertParamsInBody(t, fclient, []string{"group_id", "photo_id"})
+}
+
+func TestAddPhotoWithTopic(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.groups.getInfo": `<rsp stat="ok"><group id="1@N01" pool_topic_required="1"><name>Birds</name></group></rsp>`,
+		"flickr.groups.discuss.topics.getList": `<rsp stat="ok"><topics group_id="1@N01" page="1" pages="1" per_page="100" total="2">
+  <topic id="10" subject="Owls" />
+  <topic id="11" subject="Ducks" />
+</topics></rsp>`,
+		"flickr.groups.pools.add": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := AddPhotoWithOptions(fclient, "1@N01", "123", AddPhotoOptions{CheckTopic: true})
+	required, ok := err.(*ErrTopicRequired)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, len(required.Topics), 2)
+	flickr.Expect(t, required.Error(), "group Birds requires a discussion topic to add photos, available topics: Owls (10), Ducks (11)")
+	flickr.Expect(t, calls.Last("flickr.groups.pools.add") == nil, true)
+
+	_, err = AddPhotoWithOptions(fclient, "1@N01", "123", AddPhotoOptions{TopicId: "11", CheckTopic: true})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.groups.pools.add").Get("topic_id"), "11")
+	flickr.Expect(t, len(calls.Methods()), 3)
+}
+
+func TestAddPhotos(t *testing.T) {
+	codes := map[string]int{"2": photoAlreadyInPool, "3": poolPendingQueue, "4": flickr.PhotoNotFound, "5": poolLimitReached, "7": 99}
+	submitted := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		photoId := r.FormValue("photo_id")
+		submitted = append(submitted, photoId)
+		if code, found := codes[photoId]; found {
+			fmt.Fprintf(w, `<rsp stat="fail"><err code="%d" msg="error" /></rsp>`, code)
+			return
+		}
+		fmt.Fprintln(w, `<rsp stat="ok"></rsp>`)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	results, batch, err := AddPhotos(fclient, "1@N01", []string{"1", "2", "3", "4", "5", "6"}, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(submitted), 5)
+	flickr.Expect(t, len(results), 6)
+	expected := []PoolDisposition{PoolAdded, PoolAlreadyIn, PoolPending, PoolFailed, PoolLimitReached, PoolLimitReached}
+	for i, r := range results {
+		flickr.Expect(t, r.PhotoId, strconv.Itoa(i+1))
+		flickr.Expect(t, r.Disposition, expected[i])
+		flickr.Expect(t, batch.Actions[r.PhotoId], string(expected[i]))
+	}
+	flickr.Expect(t, results[1].Err, nil)
+	flickr.Expect(t, results[3].Err != nil, true)
+	flickr.Expect(t, results[4].Err != nil, true)
+	flickr.Expect(t, results[4].Skipped, false)
+	flickr.Expect(t, results[5].Skipped, true)
+	flickr.Expect(t, len(batch.Succeeded), 3)
+	flickr.Expect(t, len(batch.Failed), 0)
+	// a photo that doesn't exist is tombstoned, the batch goes on
+	flickr.Expect(t, len(batch.Tombstones), 1)
+	flickr.Expect(t, batch.Tombstones[0].Id, "4")
+
+	submitted = submitted[:0]
+	results, _, err = AddPhotos(fclient, "1@N01", []string{"7", "1"}, flickr.BatchOptions{FailFast: true})
+	flickr.Expect(t, err != nil, true)
+	flickr.Expect(t, len(results), 1)
+	flickr.Expect(t, len(submitted), 1)
+
+	codes["1"] = groupNotFound
+	results, batch, err = AddPhotos(fclient, "1@N01", []string{"1", "6"}, flickr.BatchOptions{})
+	flickr.Expect(t, err != nil, true)
+	flickr.Expect(t, len(results), 1)
+	flickr.Expect(t, results[0].Disposition, PoolFailed)
+	flickr.Expect(t, len(batch.Failed), 1)
+}
@@ -0,0 +1,217 @@
+package groups
+
+import (
+	"strconv"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Return when the photo was added to the pool, zero if unknown
+func (p PoolPhoto) Added() time.Time {
+	ts, err := strconv.ParseInt(p.DateAdded, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(ts, 0)
+}
+
+// Where a photo stands in a group pool, see FindPoolPosition
+type PoolPosition struct {
+	GroupId string
+	Photo   PoolPhoto
+	// Index of the photo in the pool, 0 for the most recently added one
+	Index int
+	// Page holding the photo when the pool is listed PerPage photos at a time
+	Page    int
+	PerPage int
+	// Number of photos in the pool
+	Total int
+}
+
+// Number of photos added to the pool after this one
+func (p *PoolPosition) Newer() int {
+	return p.Index
+}
+
+// Number of photos added to the pool before this one
+func (p *PoolPosition) Older() int {
+	return p.Total - p.Index - 1
+}
+
+// Find the date a photo was added to a pool among the photos its owner posted there
+func poolDateAdded(client *flickr.FlickrClient, groupId, photoId, ownerId string) (time.Time, error) {
+	for page := 1; ; page++ {
+		resp, err := getPoolPhotos(client, groupId, ownerId, "", page, flickr.MaxPerPage)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, p := range resp.Photos.Items {
+			if p.Id == photoId {
+				return p.Added(), nil
+			}
+		}
+		if page >= resp.Photos.Pages || len(resp.Photos.Items) == 0 {
+			return time.Time{}, nil
+		}
+	}
+}
+
+// Look for a photo on a page of the pool, returns its position if found
+func poolPageLookup(resp *PoolPhotosResponse, groupId, photoId string, page, perPage int) *PoolPosition {
+	for i, p := range resp.Photos.Items {
+		if p.Id == photoId {
+			return &PoolPosition{
+				GroupId: groupId,
+				Photo:   p,
+				Index:   (page-1)*perPage + i,
+				Page:    page,
+				PerPage: perPage,
+				Total:   resp.Photos.Total,
+			}
+		}
+	}
+	return nil
+}
+
+// Find where a photo stands in a group pool, which lists photos from the most
+// recently added. When ownerId, the NSID of the owner of the photo, is given, the
+// date the photo was added is looked up among the photos of the owner and the pool
+// is bisected on it, costing a few calls whatever its size. Otherwise the pool is
+// walked from the most recent photo. perPage is the page size positions are
+// computed for, flickr.MaxPerPage when zero. Returns nil if the photo is not in
+// the pool.
+// This method requires authentication to access private groups.
+func FindPoolPosition(client *flickr.FlickrClient, groupId, photoId, ownerId string, perPage int) (*PoolPosition, error) {
+	if err := flickr.ValidatePerPage(perPage); err != nil {
+		return nil, err
+	}
+	if perPage == 0 {
+		perPage = flickr.MaxPerPage
+	}
+
+	var added time.Time
+	if ownerId != "" {
+		var err error
+		if added, err = poolDateAdded(client, groupId, photoId, ownerId); err != nil {
+			return nil, err
+		}
+		if added.IsZero() {
+			return nil, nil
+		}
+	}
+
+	first, err := getPoolPhotos(client, groupId, "", "", 1, perPage)
+	if err != nil {
+		return nil, err
+	}
+	if pos := poolPageLookup(first, groupId, photoId, 1, perPage); pos != nil {
+		return pos, nil
+	}
+	pages := first.Photos.Pages
+
+	if added.IsZero() {
+		for page := 2; page <= pages; page++ {
+			resp, err := getPoolPhotos(client, groupId, "", "", page, perPage)
+			if err != nil {
+				return nil, err
+			}
+			if pos := poolPageLookup(resp, groupId, photoId, page, perPage); pos != nil {
+				return pos, nil
+			}
+			if len(resp.Photos.Items) == 0 {
+				break
+			}
+		}
+		return nil, nil
+	}
+
+	// bisect the pages, photos added in the same second may straddle two pages
+	lo, hi := 2, pages
+	for lo <= hi {
+		page := lo + (hi-lo)/2
+		resp, err := getPoolPhotos(client, groupId, "", "", page, perPage)
+		if err != nil {
+			return nil, err
+		}
+		if pos := poolPageLookup(resp, groupId, photoId, page, perPage); pos != nil {
+			return pos, nil
+		}
+		items := resp.Photos.Items
+		switch {
+		case len(items) == 0:
+			hi = page - 1
+		case added.After(items[0].Added()):
+			hi = page - 1
+		case added.Before(items[len(items)-1].Added()):
+			lo = page + 1
+		default:
+			// the photo should have been on this page, the pool changed meanwhile
+			for _, neighbour := range []int{page - 1, page + 1} {
+				if neighbour < 1 || neighbour > pages {
+					continue
+				}
+				resp, err := getPoolPhotos(client, groupId, "", "", neighbour, perPage)
+				if err != nil {
+					return nil, err
+				}
+				if pos := poolPageLookup(resp, groupId, photoId, neighbour, perPage); pos != nil {
+					return pos, nil
+				}
+			}
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
+// Return up to before photos added to the pool after the photo at pos and up to
+// after photos added before it, along with the photo itself, most recent first, e.g.
+// to show the neighbourhood of a photo. The pages around pos are fetched again, if
+// the pool changed since pos was found the window is centered on the photo again
+// when it's still on those pages.
+// This method requires authentication to access private groups.
+func PoolAround(client *flickr.FlickrClient, pos *PoolPosition, before, after int) ([]PoolPhoto, error) {
+	if before < 0 || after < 0 {
+		return nil, flickErr.NewError(flickErr.InvalidParamsError, "before and after must not be negative")
+	}
+	start := pos.Index - before
+	if start < 0 {
+		start = 0
+	}
+	end := pos.Index + after
+
+	items := []PoolPhoto{}
+	firstPage := start/pos.PerPage + 1
+	for page := firstPage; page <= end/pos.PerPage+1; page++ {
+		resp, err := getPoolPhotos(client, pos.GroupId, "", "", page, pos.PerPage)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Photos.Items...)
+		if page >= resp.Photos.Pages {
+			break
+		}
+	}
+
+	// index of the photo among the fetched items
+	center := pos.Index - (firstPage-1)*pos.PerPage
+	for i, p := range items {
+		if p.Id == pos.Photo.Id {
+			center = i
+			break
+		}
+	}
+	lo, hi := center-before, center+after+1
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > len(items) {
+		hi = len(items)
+	}
+	if lo >= hi {
+		return []PoolPhoto{}, nil
+	}
+	return items[lo:hi], nil
+}
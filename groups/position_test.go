@@ -0,0 +1,98 @@
+package groups
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Serve a pool of n photos, photo i was added at 1000+i and belongs to "me@N00" when
+// i is a multiple of 5, the pool lists the most recent first
+func poolServer(n int, calls *int) (*httptest.Server, *flickr.FlickrClient) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		ids := []int{}
+		for i := n - 1; i >= 0; i-- {
+			if r.FormValue("user_id") == "" || i%5 == 0 {
+				ids = append(ids, i)
+			}
+		}
+		perPage, _ := strconv.Atoi(r.FormValue("per_page"))
+		page, _ := strconv.Atoi(r.FormValue("page"))
+		if page == 0 {
+			page = 1
+		}
+		pages := (len(ids) + perPage - 1) / perPage
+		fmt.Fprintf(w, `<rsp stat="ok"><photos page="%d" pages="%d" perpage="%d" total="%d">`, page, pages, perPage, len(ids))
+		for k := (page - 1) * perPage; k < page*perPage && k < len(ids); k++ {
+			fmt.Fprintf(w, `<photo id="p%d" dateadded="%d"/>`, ids[k], 1000+ids[k])
+		}
+		fmt.Fprint(w, `</photos></rsp>`)
+	}))
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+	return server, fclient
+}
+
+func TestFindPoolPosition(t *testing.T) {
+	calls := 0
+	server, fclient := poolServer(230, &calls)
+	defer server.Close()
+
+	// bisected with the date added found among the photos of the owner
+	pos, err := FindPoolPosition(fclient, "1@N01", "p20", "me@N00", 10)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, pos.Index, 209)
+	flickr.Expect(t, pos.Page, 21)
+	flickr.Expect(t, pos.Total, 230)
+	flickr.Expect(t, pos.Newer(), 209)
+	flickr.Expect(t, pos.Older(), 20)
+	flickr.Expect(t, pos.Photo.Added().Unix(), int64(1020))
+	flickr.Expect(t, calls <= 8, true)
+
+	// walked without an owner
+	calls = 0
+	pos, err = FindPoolPosition(fclient, "1@N01", "p20", "", 10)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, pos.Index, 209)
+	flickr.Expect(t, calls, 21)
+
+	pos, err = FindPoolPosition(fclient, "1@N01", "p21", "me@N00", 10)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, pos == nil, true)
+	pos, err = FindPoolPosition(fclient, "1@N01", "nope", "", 100)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, pos == nil, true)
+}
+
+func TestPoolAround(t *testing.T) {
+	calls := 0
+	server, fclient := poolServer(230, &calls)
+	defer server.Close()
+
+	pos, err := FindPoolPosition(fclient, "1@N01", "p20", "me@N00", 10)
+	flickr.Expect(t, err, nil)
+	around, err := PoolAround(fclient, pos, 2, 12)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(around), 15)
+	flickr.Expect(t, around[0].Id, "p22")
+	flickr.Expect(t, around[2].Id, "p20")
+	flickr.Expect(t, around[14].Id, "p8")
+
+	// the window is cut at the ends of the pool
+	pos, err = FindPoolPosition(fclient, "1@N01", "p0", "me@N00", 10)
+	flickr.Expect(t, err, nil)
+	around, err = PoolAround(fclient, pos, 3, 3)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(around), 4)
+	flickr.Expect(t, around[3].Id, "p0")
+
+	_, err = PoolAround(fclient, pos, -1, 3)
+	flickr.Expect(t, err != nil, true)
+}
@@ -0,0 +1,88 @@
+package groups
+
+import (
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Values of GroupInfo.Privacy
+const (
+	PrivacyPrivate    = 1
+	PrivacyInviteOnly = 2
+	PrivacyPublic     = 3
+)
+
+// Request to join a group that needs approval by its administrators, message is
+// shown to them and the rules of the group must be accepted
+// This method requires authentication with 'write' permission.
+func JoinRequest(client *flickr.FlickrClient, groupId, message string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.groups.joinRequest")
+	client.Args.Set("group_id", groupId)
+	client.Args.Set("message", message)
+	client.SetBool("accept_rules", true)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// A function presenting the rules of a group to a human, returning whether they
+// were accepted
+type RulesPrompt func(group *GroupInfo) (bool, error)
+
+// Record of the rules accepted when joining a group, to be kept for auditing
+type RulesAcknowledgement struct {
+	GroupId   string
+	GroupName string
+	// The rules text that was accepted, empty if the group has none
+	Rules      string
+	AcceptedAt time.Time
+	// Whether a request was sent to the administrators instead of joining directly
+	Requested bool
+}
+
+// Join a group after its rules were accepted: the rules are fetched with getInfo and
+// passed to prompt, groups without rules are joined without prompting. Public groups
+// are joined directly, for the others a join request carrying message is sent.
+// If prompt declines the rules, a flickErr.Error with code RulesNotAcceptedError is
+// returned and nothing is sent to Flickr.
+// This method requires authentication with 'write' permission.
+func JoinWithRules(client *flickr.FlickrClient, groupId, message string, prompt RulesPrompt) (*RulesAcknowledgement, error) {
+	info, err := GetInfo(client, groupId)
+	if err != nil {
+		return nil, err
+	}
+	group := &info.Group
+
+	if group.Rules != "" {
+		accepted, err := prompt(group)
+		if err != nil {
+			return nil, err
+		}
+		if !accepted {
+			return nil, flickErr.NewError(flickErr.RulesNotAcceptedError, group.Name)
+		}
+	}
+
+	ack := &RulesAcknowledgement{
+		GroupId:    groupId,
+		GroupName:  group.Name,
+		Rules:      group.Rules,
+		AcceptedAt: time.Now(),
+	}
+	if group.Privacy == PrivacyPublic {
+		_, err = Join(client, groupId, group.Rules != "")
+	} else {
+		ack.Requested = true
+		_, err = JoinRequest(client, groupId, message)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ack, nil
+}
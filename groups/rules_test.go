@@ -0,0 +1,72 @@
+package groups
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func groupWithRules(privacy string) string {
+	return `<rsp stat="ok"><group id="34427465446@N01"><name>FlickrCentral</name>
+  <rules>Be nice.</rules><privacy>` + privacy + `</privacy></group></rsp>`
+}
+
+func TestJoinWithRules(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.groups.getInfo": groupWithRules("3"),
+		"flickr.groups.join":    `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	shown := ""
+	ack, err := JoinWithRules(fclient, "34427465446@N01", "", func(g *GroupInfo) (bool, error) {
+		shown = g.Rules
+		return true, nil
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, shown, "Be nice.")
+	flickr.Expect(t, ack.Rules, "Be nice.")
+	flickr.Expect(t, ack.GroupName, "FlickrCentral")
+	flickr.Expect(t, ack.Requested, false)
+	flickr.Expect(t, ack.AcceptedAt.IsZero(), false)
+	flickr.Expect(t, calls.Last("flickr.groups.join").Get("accept_rules"), "1")
+}
+
+func TestJoinWithRulesRequest(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.groups.getInfo":     groupWithRules("2"),
+		"flickr.groups.joinRequest": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	ack, err := JoinWithRules(fclient, "34427465446@N01", "Hi there", func(g *GroupInfo) (bool, error) {
+		return true, nil
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, ack.Requested, true)
+	args := calls.Last("flickr.groups.joinRequest")
+	flickr.Expect(t, args.Get("message"), "Hi there")
+	flickr.Expect(t, args.Get("accept_rules"), "1")
+}
+
+func TestJoinWithRulesDeclined(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.groups.getInfo": groupWithRules("3"),
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	ack, err := JoinWithRules(fclient, "34427465446@N01", "", func(g *GroupInfo) (bool, error) {
+		return false, nil
+	})
+	flickr.Expect(t, ack == nil, true)
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.RulesNotAcceptedError)
+	flickr.Expect(t, strings.Join(calls.Methods(), ","), "flickr.groups.getInfo")
+}
@@ -0,0 +1,72 @@
+package groups
+
+import (
+	"encoding/json"
+	"io"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A discussion topic along with all its replies
+type Thread struct {
+	Topic   Topic   `json:"topic"`
+	Replies []Reply `json:"replies"`
+}
+
+// Every discussion of a group, see ExportThreads
+type ThreadArchive struct {
+	GroupId string   `json:"group_id"`
+	Threads []Thread `json:"threads"`
+}
+
+// Call fn with every discussion topic of a group along with all its replies, one
+// thread at a time, most recently active first. Returning an error from fn stops
+// the walk and the error is returned.
+// This method requires authentication to access private groups.
+func EachThread(client *flickr.FlickrClient, groupId string, fn func(*Thread) error) error {
+	return EachTopic(client, groupId, func(topic *Topic) error {
+		thread := &Thread{Topic: *topic, Replies: []Reply{}}
+		if topic.CountReplies > 0 {
+			err := EachReply(client, groupId, topic.Id, func(reply *Reply) error {
+				thread.Replies = append(thread.Replies, *reply)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return fn(thread)
+	})
+}
+
+// Walk every discussion of a group into an archive held in memory, use
+// WriteThreadsJSONL for groups with a lot of history.
+// This method requires authentication to access private groups.
+func ExportThreads(client *flickr.FlickrClient, groupId string) (*ThreadArchive, error) {
+	archive := &ThreadArchive{GroupId: groupId, Threads: []Thread{}}
+	err := EachThread(client, groupId, func(thread *Thread) error {
+		archive.Threads = append(archive.Threads, *thread)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// Write every discussion of a group to w as JSON Lines, one Thread per line, so
+// that only one thread at a time is kept in memory. Returns the number of threads
+// written, threads written before a failure are left in w.
+// This method requires authentication to access private groups.
+func WriteThreadsJSONL(client *flickr.FlickrClient, groupId string, w io.Writer) (int, error) {
+	encoder := json.NewEncoder(w)
+	written := 0
+	err := EachThread(client, groupId, func(thread *Thread) error {
+		if err := encoder.Encode(thread); err != nil {
+			return err
+		}
+		written++
+		return nil
+	})
+	return written, err
+}
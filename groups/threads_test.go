@@ -0,0 +1,54 @@
+package groups
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestExportThreads(t *testing.T) {
+	server := discussServer()
+	defer server.Close()
+
+	archive, err := ExportThreads(discussClient(server), "46744914@N00")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, archive.GroupId, "46744914@N00")
+	flickr.Expect(t, len(archive.Threads), 2)
+	flickr.Expect(t, archive.Threads[0].Topic.Id, "t1")
+	flickr.Expect(t, len(archive.Threads[0].Replies), 3)
+	flickr.Expect(t, archive.Threads[0].Replies[1].LastEdit, "1287073000")
+	flickr.Expect(t, archive.Threads[0].Replies[2].Message, "Hey")
+	// topics without replies are not asked for them
+	flickr.Expect(t, len(archive.Threads[1].Replies), 0)
+}
+
+func TestWriteThreadsJSONL(t *testing.T) {
+	server := discussServer()
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	n, err := WriteThreadsJSONL(discussClient(server), "46744914@N00", buf)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, n, 2)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	flickr.Expect(t, len(lines), 2)
+
+	thread := Thread{}
+	flickr.Expect(t, json.Unmarshal([]byte(lines[0]), &thread), nil)
+	flickr.Expect(t, thread.Topic.Subject, "Welcome")
+	flickr.Expect(t, thread.Replies[0].AuthorName, "Cid")
+	flickr.Expect(t, strings.Contains(lines[1], `"replies":[]`), true)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = EachThread(discussClient(server), "46744914@N00", func(*Thread) error {
+		calls++
+		return stop
+	})
+	flickr.Expect(t, err, stop)
+	flickr.Expect(t, calls, 1)
+}
@@ -0,0 +1,249 @@
+package groups
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Parse a unix timestamp of a response, zero if missing or invalid
+func unixTime(ts string) time.Time {
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// Return when the topic was opened
+func (t *Topic) Created() time.Time {
+	return unixTime(t.DateCreate)
+}
+
+// Return when the last reply was posted, when the topic was opened if none
+func (t *Topic) LastPost() time.Time {
+	return unixTime(t.DateLastPost)
+}
+
+// Return when the reply was posted
+func (r *Reply) Created() time.Time {
+	return unixTime(r.DateCreate)
+}
+
+// What a TopicTracker remembers of a topic
+type TopicMark struct {
+	GroupId string `json:"group_id"`
+	TopicId string `json:"topic_id"`
+	// Time of the last post read, the posts after it are unread
+	LastSeen time.Time `json:"last_seen"`
+}
+
+type topicKey struct {
+	groupId string
+	topicId string
+}
+
+// Unread posts of a topic, see TopicTracker.Unread
+type TopicUnread struct {
+	Topic Topic
+	// Number of posts after the last one seen, the opening post included for new topics
+	Unread int
+	// The topic was never seen
+	New bool
+}
+
+// Unread posts of a group, see TopicTracker.Unread
+type GroupUnread struct {
+	GroupId string
+	// Number of unread posts across the topics
+	Unread int
+	// Topics with unread posts, most recently active first
+	Topics []TopicUnread
+}
+
+// TopicTracker remembers the last post seen in every discussion topic of the groups
+// it follows and reports the unread ones, e.g. for a bot notifying the admins of a
+// group of new discussions. Topics never seen are entirely unread: mark the first
+// report read with MarkAllRead to only hear about what comes next.
+// A tracker created with OpenTopicTracker or OpenTopicTrackerStore is persisted as
+// JSON every time it changes, one created with NewTopicTracker lives in memory only.
+// A TopicTracker is safe for concurrent use.
+type TopicTracker struct {
+	mu    sync.Mutex
+	store flickr.Store
+	key   string
+	marks map[topicKey]TopicMark
+}
+
+// Create an in-memory tracker
+func NewTopicTracker() *TopicTracker {
+	return &TopicTracker{marks: map[topicKey]TopicMark{}}
+}
+
+// Load a tracker from a JSON file, a missing file yields an empty tracker that will
+// be created on the first change
+func OpenTopicTracker(path string) (*TopicTracker, error) {
+	return OpenTopicTrackerStore(flickr.NewFileStore(filepath.Dir(path)), filepath.Base(path))
+}
+
+// Load a tracker stored under key, a missing key yields an empty tracker that will
+// be stored on the first change
+func OpenTopicTrackerStore(store flickr.Store, key string) (*TopicTracker, error) {
+	t := NewTopicTracker()
+	t.store = store
+	t.key = key
+
+	marks := []TopicMark{}
+	if _, err := flickr.LoadJSON(store, key, &marks); err != nil {
+		return nil, err
+	}
+	for _, m := range marks {
+		t.marks[topicKey{m.GroupId, m.TopicId}] = m
+	}
+	return t, nil
+}
+
+// Write the tracker to its store, noop for in-memory trackers. Must be called with
+// the lock held.
+func (t *TopicTracker) save() error {
+	if t.store == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(t.sortedMarks(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return t.store.Put(t.key, data)
+}
+
+// Marks sorted by group and topic. Must be called with the lock held.
+func (t *TopicTracker) sortedMarks() []TopicMark {
+	ret := make([]TopicMark, 0, len(t.marks))
+	for _, m := range t.marks {
+		ret = append(ret, m)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].GroupId != ret[j].GroupId {
+			return ret[i].GroupId < ret[j].GroupId
+		}
+		return ret[i].TopicId < ret[j].TopicId
+	})
+	return ret
+}
+
+// Return every topic seen, sorted by group and topic
+func (t *TopicTracker) Marks() []TopicMark {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sortedMarks()
+}
+
+// Return the time of the last post seen in a topic, zero if the topic was never seen
+func (t *TopicTracker) LastSeen(groupId, topicId string) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.marks[topicKey{groupId, topicId}].LastSeen
+}
+
+// Record that the posts of a topic up to seen were read. Marks never move back.
+func (t *TopicTracker) MarkRead(groupId, topicId string, seen time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.mark(groupId, topicId, seen)
+	return t.save()
+}
+
+// Move the mark of a topic forward. Must be called with the lock held.
+func (t *TopicTracker) mark(groupId, topicId string, seen time.Time) {
+	key := topicKey{groupId, topicId}
+	if m, found := t.marks[key]; found && !seen.After(m.LastSeen) {
+		return
+	}
+	t.marks[key] = TopicMark{GroupId: groupId, TopicId: topicId, LastSeen: seen}
+}
+
+// Record every topic of a report as read up to its last post, e.g. once its
+// notifications were sent
+func (t *TopicTracker) MarkAllRead(report *GroupUnread) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range report.Topics {
+		topic := &report.Topics[i].Topic
+		t.mark(report.GroupId, topic.Id, topic.LastPost())
+	}
+	return t.save()
+}
+
+// Forget the topics of a group, e.g. when the group is no longer followed
+func (t *TopicTracker) ForgetGroup(groupId string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k := range t.marks {
+		if k.groupId == groupId {
+			delete(t.marks, k)
+		}
+	}
+	return t.save()
+}
+
+// Count the unread posts of a topic, fetching its replies only when some of them
+// were seen already
+func (t *TopicTracker) topicUnread(client *flickr.FlickrClient, groupId string, topic *Topic) (*TopicUnread, error) {
+	seen := t.LastSeen(groupId, topic.Id)
+	if seen.IsZero() {
+		return &TopicUnread{Topic: *topic, Unread: topic.CountReplies + 1, New: true}, nil
+	}
+	if !topic.LastPost().After(seen) {
+		return nil, nil
+	}
+	unread := 0
+	err := EachReply(client, groupId, topic.Id, func(reply *Reply) error {
+		if reply.Created().After(seen) {
+			unread++
+		}
+		return nil
+	})
+	if err != nil || unread == 0 {
+		return nil, err
+	}
+	return &TopicUnread{Topic: *topic, Unread: unread}, nil
+}
+
+// Report the unread posts of a group without marking them read, see MarkAllRead.
+// Replies are only fetched for the topics active since they were last seen.
+// This method requires authentication to access private groups.
+func (t *TopicTracker) Unread(client *flickr.FlickrClient, groupId string) (*GroupUnread, error) {
+	report := &GroupUnread{GroupId: groupId, Topics: []TopicUnread{}}
+	err := EachTopic(client, groupId, func(topic *Topic) error {
+		unread, err := t.topicUnread(client, groupId, topic)
+		if err != nil || unread == nil {
+			return err
+		}
+		report.Unread += unread.Unread
+		report.Topics = append(report.Topics, *unread)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Report the unread posts of several groups, in the same order as groupIds
+// This method requires authentication to access private groups.
+func (t *TopicTracker) UnreadGroups(client *flickr.FlickrClient, groupIds []string) ([]*GroupUnread, error) {
+	ret := make([]*GroupUnread, 0, len(groupIds))
+	for _, groupId := range groupIds {
+		report, err := t.Unread(client, groupId)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, report)
+	}
+	return ret, nil
+}
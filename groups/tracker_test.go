@@ -0,0 +1,48 @@
+package groups
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestTopicTracker(t *testing.T) {
+	server := discussServer()
+	defer server.Close()
+	fclient := discussClient(server)
+
+	store := flickr.NewMemoryStore()
+	tracker, err := OpenTopicTrackerStore(store, "topics")
+	flickr.Expect(t, err, nil)
+
+	report, err := tracker.Unread(fclient, "g1")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, report.Unread, 5)
+	flickr.Expect(t, len(report.Topics), 2)
+	flickr.Expect(t, report.Topics[0].New, true)
+	flickr.Expect(t, report.Topics[0].Unread, 4)
+
+	flickr.Expect(t, tracker.MarkAllRead(report), nil)
+	tracker, err = OpenTopicTrackerStore(store, "topics")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(tracker.Marks()), 2)
+	flickr.Expect(t, tracker.LastSeen("g1", "t1").Unix(), int64(1287370151))
+
+	report, err = tracker.Unread(fclient, "g1")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, report.Unread, 0)
+	flickr.Expect(t, len(report.Topics), 0)
+
+	// only the replies after the first one were not read
+	flickr.Expect(t, tracker.ForgetGroup("g1"), nil)
+	flickr.Expect(t, tracker.MarkRead("g1", "t1", time.Unix(1287071000, 0)), nil)
+	flickr.Expect(t, tracker.MarkRead("g1", "t2", time.Unix(1287070000, 0)), nil)
+	// marks never move back
+	flickr.Expect(t, tracker.MarkRead("g1", "t1", time.Unix(1, 0)), nil)
+	reports, err := tracker.UnreadGroups(fclient, []string{"g1"})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, reports[0].Unread, 2)
+	flickr.Expect(t, reports[0].Topics[0].Topic.Id, "t1")
+	flickr.Expect(t, reports[0].Topics[0].New, false)
+}
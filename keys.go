@@ -0,0 +1,166 @@
+package flickr
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// A Flickr application api key along with its secret
+type ApiKey struct {
+	Key    string
+	Secret string
+}
+
+// Usage statistics of an api key
+type KeyUsage struct {
+	Key string
+	// Number of calls signed with the key
+	Calls int
+	// Number of calls rejected because of rate limiting
+	RateLimited int
+	// When the key will be used again, zero if the key is available
+	LimitedUntil time.Time
+}
+
+// KeyRing spreads the load of unauthenticated calls among several api keys, e.g.
+// the ones of the different applications registered by a team.
+// Keys are used in turn, either at every request or only when the current key gets
+// rate limited (HTTP 429), in which case the key is put aside for Cooldown.
+// Calls signed with OAuth keep using the ApiKey of the client since user tokens
+// are bound to the key they were obtained with.
+// A KeyRing is safe for concurrent use and can be shared by many clients.
+type KeyRing struct {
+	Keys []ApiKey
+	// Switch to the next key at every request instead of only on rate limiting
+	RotatePerRequest bool
+	// How long a rate limited key is put aside
+	Cooldown time.Duration
+	// Optional callback invoked after every call signed with a key of the ring,
+	// err is nil on success
+	OnUse func(key string, err error)
+
+	mu      sync.Mutex
+	current int
+	usage   map[string]*KeyUsage
+	// replaced in tests
+	now func() time.Time
+}
+
+// Create a KeyRing rotating keys on rate limiting, putting them aside for 1 hour
+func NewKeyRing(keys ...ApiKey) *KeyRing {
+	return &KeyRing{
+		Keys:     keys,
+		Cooldown: time.Hour,
+	}
+}
+
+// Sign unauthenticated API calls with the keys of ring
+func WithKeyRing(ring *KeyRing) ClientOption {
+	return func(c *FlickrClient) {
+		c.KeyRing = ring
+	}
+}
+
+func (r *KeyRing) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// Return the usage entry of key, creating it if needed. Must be called with mu held
+func (r *KeyRing) entry(key string) *KeyUsage {
+	if r.usage == nil {
+		r.usage = map[string]*KeyUsage{}
+	}
+	u, found := r.usage[key]
+	if !found {
+		u = &KeyUsage{Key: key}
+		r.usage[key] = u
+	}
+	return u
+}
+
+// Choose the key to sign the next request with: the current one unless it's rate
+// limited, or the next available one. If every key is rate limited, the one
+// becoming available first is returned.
+func (r *KeyRing) pick() ApiKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.Keys) == 0 {
+		return ApiKey{}
+	}
+	if r.current >= len(r.Keys) {
+		r.current = 0
+	}
+
+	now := r.clock()
+	best := -1
+	for i := 0; i < len(r.Keys); i++ {
+		idx := (r.current + i) % len(r.Keys)
+		u := r.entry(r.Keys[idx].Key)
+		if !now.Before(u.LimitedUntil) {
+			best = idx
+			break
+		}
+		if best < 0 || u.LimitedUntil.Before(r.usage[r.Keys[best].Key].LimitedUntil) {
+			best = idx
+		}
+	}
+
+	r.current = best
+	if r.RotatePerRequest {
+		r.current = (best + 1) % len(r.Keys)
+	}
+	return r.Keys[best]
+}
+
+// Whether key belongs to the ring
+func (r *KeyRing) has(key string) bool {
+	for _, k := range r.Keys {
+		if k.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Record the outcome of a call signed with key
+func (r *KeyRing) record(key string, res *http.Response, err error) {
+	r.mu.Lock()
+	if !r.has(key) {
+		r.mu.Unlock()
+		return
+	}
+	u := r.entry(key)
+	u.Calls++
+	if res != nil && res.StatusCode == http.StatusTooManyRequests {
+		u.RateLimited++
+		u.LimitedUntil = r.clock().Add(r.Cooldown)
+	}
+	onUse := r.OnUse
+	r.mu.Unlock()
+
+	if onUse != nil {
+		onUse(key, err)
+	}
+}
+
+// Return the usage statistics of every key of the ring, in the same order as Keys
+func (r *KeyRing) Usage() []KeyUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock()
+	ret := make([]KeyUsage, 0, len(r.Keys))
+	for _, k := range r.Keys {
+		u := *r.entry(k.Key)
+		if !now.Before(u.LimitedUntil) {
+			u.LimitedUntil = time.Time{}
+		}
+		ret = append(ret, u)
+	}
+	return ret
+}
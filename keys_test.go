@@ -0,0 +1,82 @@
+package flickr
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestKeyRingRotatePerRequest(t *testing.T) {
+	ring := NewKeyRing(ApiKey{"k1", "s1"}, ApiKey{"k2", "s2"}, ApiKey{"k3", "s3"})
+	ring.RotatePerRequest = true
+
+	Expect(t, ring.pick().Key, "k1")
+	Expect(t, ring.pick().Key, "k2")
+	Expect(t, ring.pick().Key, "k3")
+	Expect(t, ring.pick().Key, "k1")
+}
+
+func TestKeyRingRateLimited(t *testing.T) {
+	now := time.Unix(1000, 0)
+	ring := NewKeyRing(ApiKey{"k1", "s1"}, ApiKey{"k2", "s2"})
+	ring.now = func() time.Time { return now }
+	used := []string{}
+	ring.OnUse = func(key string, err error) { used = append(used, key) }
+
+	// the same key is used until it gets rate limited
+	Expect(t, ring.pick().Key, "k1")
+	ring.record("k1", &http.Response{StatusCode: 200}, nil)
+	Expect(t, ring.pick().Key, "k1")
+	ring.record("k1", &http.Response{StatusCode: 429}, nil)
+	Expect(t, ring.pick().Key, "k2")
+	now = now.Add(time.Minute)
+	ring.record("k2", &http.Response{StatusCode: 429}, nil)
+
+	// every key is limited, k1 is available first
+	Expect(t, ring.pick().Key, "k1")
+
+	// keys not in the ring are ignored
+	ring.record("other", nil, nil)
+	Expect(t, len(used), 3)
+
+	usage := ring.Usage()
+	Expect(t, len(usage), 2)
+	Expect(t, usage[0].Calls, 2)
+	Expect(t, usage[0].RateLimited, 1)
+	Expect(t, usage[0].LimitedUntil, time.Unix(1000, 0).Add(time.Hour))
+	Expect(t, usage[1].Calls, 1)
+
+	now = now.Add(time.Hour)
+	Expect(t, ring.Usage()[0].LimitedUntil.IsZero(), true)
+}
+
+func TestApiSignKeyRing(t *testing.T) {
+	fclient := GetTestClient()
+	fclient.ApiKey = "main"
+	ring := NewKeyRing(ApiKey{"k1", "s1"}, ApiKey{"k2", "s2"})
+	ring.RotatePerRequest = true
+	WithKeyRing(ring)(fclient)
+
+	server, client := FlickrMock(200, `<rsp stat="ok"></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	fclient.ClearArgs()
+	fclient.ApiSign()
+	Expect(t, fclient.Args.Get("api_key"), "k1")
+	Expect(t, fclient.Args.Get("api_sig"), fmt.Sprintf("%x", md5.Sum([]byte("s1api_keyk1"))))
+	Expect(t, DoGet(fclient, &FooResponse{}), nil)
+	Expect(t, ring.Usage()[0].Calls, 1)
+
+	fclient.ClearArgs()
+	fclient.ApiSign()
+	Expect(t, fclient.Args.Get("api_key"), "k2")
+
+	// calls carrying an OAuth token keep the client key
+	fclient.ClearArgs()
+	fclient.Args.Set("oauth_token", "token")
+	fclient.ApiSign()
+	Expect(t, fclient.Args.Get("api_key"), "main")
+}
@@ -0,0 +1,85 @@
+package flickr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Upload limits of an account, see people.GetLimits. Zero values mean no limit is
+// known.
+type UploadLimits struct {
+	// Maximum size of a photo and of a video file, in bytes
+	MaxPhotoSize int64
+	MaxVideoSize int64
+	// Maximum duration of a video, not checked locally since the duration can't be
+	// known without decoding the video
+	MaxVideoDuration time.Duration
+}
+
+// Check the uploads of the client against the limits of the account, see UploadLimits
+func WithUploadLimits(limits *UploadLimits) ClientOption {
+	return func(c *FlickrClient) {
+		c.UploadLimits = limits
+	}
+}
+
+// Error returned by the uploads of a file bigger than the UploadLimits of the client,
+// before anything is sent
+type UploadLimitError struct {
+	// Name of the uploaded file
+	Name  string
+	Video bool
+	Size  int64
+	Limit int64
+}
+
+func (e *UploadLimitError) Error() string {
+	kind := "photo"
+	if e.Video {
+		kind = "video"
+	}
+	return fmt.Sprintf("%s is %d bytes, the account accepts %ss up to %d bytes", e.Name, e.Size, kind, e.Limit)
+}
+
+// Return the size of the content of r when it can be known without reading it, -1
+// otherwise
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case *os.File:
+		if stat, err := v.Stat(); err == nil && stat.Mode().IsRegular() {
+			if pos, err := v.Seek(0, io.SeekCurrent); err == nil {
+				return stat.Size() - pos
+			}
+		}
+	case interface{ Len() int }:
+		return int64(v.Len())
+	}
+	return -1
+}
+
+// Check the file uploaded as name against the limits, its format is sniffed to tell
+// photos from videos, unknown formats being treated as photos. Returns a reader
+// yielding the whole content of r. Files whose size can't be known in advance fail
+// with a FileTooLargeError while they're streamed.
+func (l *UploadLimits) check(r io.Reader, name string) (io.Reader, error) {
+	size := readerSize(r)
+	format, replay, err := SniffMedia(r)
+	if replay == nil {
+		return nil, err
+	}
+	limit := l.MaxPhotoSize
+	if format.Video {
+		limit = l.MaxVideoSize
+	}
+	switch {
+	case limit <= 0:
+		return replay, nil
+	case size > limit:
+		return nil, &UploadLimitError{Name: name, Video: format.Video, Size: size, Limit: limit}
+	case size < 0:
+		return &sizeLimitReader{r: replay, name: name, max: limit}, nil
+	}
+	return replay, nil
+}
@@ -0,0 +1,65 @@
+package flickr
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestUploadLimits(t *testing.T) {
+	fclient := GetTestClient()
+	server, client, calls := FlickrMockRecorder(200, map[string]string{
+		"upload": `<rsp stat="ok"><photoid>1234</photoid></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+	WithUploadLimits(&UploadLimits{MaxPhotoSize: 20, MaxVideoSize: 40})(fclient)
+
+	photo := "\xFF\xD8\xFF\xE0\x00\x10JFIF" + strings.Repeat("x", 20)
+	_, err := UploadReader(fclient, strings.NewReader(photo), "big.jpg", nil)
+	limitErr, ok := err.(*UploadLimitError)
+	Expect(t, ok, true)
+	Expect(t, limitErr.Video, false)
+	Expect(t, limitErr.Size, int64(30))
+	Expect(t, limitErr.Error(), "big.jpg is 30 bytes, the account accepts photos up to 20 bytes")
+	Expect(t, len(calls.Methods()), 0)
+
+	// the same size is fine for a video
+	video := "\x00\x00\x00\x18ftypisom\x00\x00\x02\x00" + strings.Repeat("x", 14)
+	resp, err := UploadReader(fclient, bytes.NewBufferString(video), "clip.mp4", nil)
+	Expect(t, err, nil)
+	Expect(t, resp.ID, "1234")
+
+	// files are measured without being read
+	file, _ := ioutil.TempFile("", "flickr")
+	defer os.Remove(file.Name())
+	file.WriteString(video + strings.Repeat("x", 20))
+	file.Close()
+	_, err = UploadFile(fclient, file.Name(), nil)
+	limitErr, ok = err.(*UploadLimitError)
+	Expect(t, ok, true)
+	Expect(t, limitErr.Video, true)
+	Expect(t, limitErr.Limit, int64(40))
+	Expect(t, len(calls.Methods()), 1)
+}
+
+func TestUploadLimitsUnknownSize(t *testing.T) {
+	limits := &UploadLimits{MaxPhotoSize: 20}
+	photo := "GIF89a" + strings.Repeat("x", 30)
+	r, err := limits.check(ioutil.NopCloser(strings.NewReader(photo)), "a.gif")
+	Expect(t, err, nil)
+	_, err = ioutil.ReadAll(r)
+	ee, ok := err.(*flickErr.Error)
+	Expect(t, ok, true)
+	Expect(t, ee.ErrorCode, flickErr.FileTooLargeError)
+
+	r, err = limits.check(ioutil.NopCloser(strings.NewReader(photo[:15])), "a.gif")
+	Expect(t, err, nil)
+	data, err := ioutil.ReadAll(r)
+	Expect(t, err, nil)
+	Expect(t, string(data), photo[:15])
+}
@@ -0,0 +1,417 @@
+package flickr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XML namespaces of the XMP properties we read
+const (
+	xmpDublinCore = "http://purl.org/dc/elements/1.1/"
+	xmpExif       = "http://ns.adobe.com/exif/1.0/"
+)
+
+// Descriptive metadata found in a local file, as written by tools like Lightroom
+type FileMetadata struct {
+	Title       string
+	Description string
+	Keywords    []string
+	// GPS coordinates in decimal degrees, valid when HasGPS is true
+	Latitude  float64
+	Longitude float64
+	HasGPS    bool
+	// When the photo was taken according to EXIF, the wall clock of the camera
+	// expressed in UTC since EXIF carries no time zone. Zero if unknown
+	Taken time.Time
+	// Camera that took the photo according to EXIF
+	CameraMake  string
+	CameraModel string
+}
+
+// Fill in the fields of m that are still empty with the ones of other
+func (m *FileMetadata) merge(other *FileMetadata) {
+	if m.Title == "" {
+		m.Title = other.Title
+	}
+	if m.Description == "" {
+		m.Description = other.Description
+	}
+	if len(m.Keywords) == 0 {
+		m.Keywords = other.Keywords
+	}
+	if !m.HasGPS && other.HasGPS {
+		m.Latitude, m.Longitude, m.HasGPS = other.Latitude, other.Longitude, true
+	}
+	if m.Taken.IsZero() {
+		m.Taken = other.Taken
+	}
+	if m.CameraMake == "" {
+		m.CameraMake = other.CameraMake
+	}
+	if m.CameraModel == "" {
+		m.CameraModel = other.CameraModel
+	}
+}
+
+// Set title, description and tags of params from the metadata, values already set
+// in params are left untouched
+func (m *FileMetadata) Apply(params *UploadParams) {
+	if params.Title == "" {
+		params.Title = m.Title
+	}
+	if params.Description == "" {
+		params.Description = m.Description
+	}
+	if len(params.Tags) == 0 {
+		params.Tags = m.Keywords
+	}
+}
+
+// Read the metadata of a local file. An XMP sidecar ("IMG_1.xmp" or "IMG_1.jpg.xmp")
+// has precedence, missing fields are then looked up in the XMP and IPTC blocks
+// embedded in JPEG files.
+func ReadMetadata(path string) (*FileMetadata, error) {
+	meta := &FileMetadata{}
+
+	ext := filepath.Ext(path)
+	for _, sidecar := range []string{strings.TrimSuffix(path, ext) + ".xmp", path + ".xmp"} {
+		data, err := ioutil.ReadFile(sidecar)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		xmp, err := parseXMP(data)
+		if err != nil {
+			return nil, err
+		}
+		meta.merge(xmp)
+		break
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	embedded, err := readJPEGMetadata(file)
+	if err != nil {
+		return nil, err
+	}
+	meta.merge(embedded)
+	return meta, nil
+}
+
+// Same as NewUploadParams, filling title, description and tags from the metadata
+// of the file at path
+func NewUploadParamsFromFile(path string) (*UploadParams, error) {
+	meta, err := ReadMetadata(path)
+	if err != nil {
+		return nil, err
+	}
+	params := NewUploadParams()
+	meta.Apply(params)
+	return params, nil
+}
+
+// Parse an XMP packet, reading Dublin Core title, description and subject along
+// with EXIF GPS coordinates
+func parseXMP(data []byte) (*FileMetadata, error) {
+	meta := &FileMetadata{}
+	var lat, lon string
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	property := ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			for _, a := range t.Attr {
+				if a.Name.Space == xmpExif && a.Name.Local == "GPSLatitude" {
+					lat = a.Value
+				}
+				if a.Name.Space == xmpExif && a.Name.Local == "GPSLongitude" {
+					lon = a.Value
+				}
+			}
+			if t.Name.Space == xmpDublinCore || t.Name.Space == xmpExif {
+				property = t.Name.Local
+			}
+		case xml.EndElement:
+			if t.Name.Space == xmpDublinCore || t.Name.Space == xmpExif {
+				property = ""
+			}
+		case xml.CharData:
+			value := strings.TrimSpace(string(t))
+			if value == "" {
+				continue
+			}
+			switch property {
+			case "title":
+				meta.Title = value
+			case "description":
+				meta.Description = value
+			case "subject":
+				meta.Keywords = append(meta.Keywords, value)
+			case "GPSLatitude":
+				lat = value
+			case "GPSLongitude":
+				lon = value
+			}
+		}
+	}
+
+	latitude, okLat := parseXMPCoordinate(lat)
+	longitude, okLon := parseXMPCoordinate(lon)
+	if okLat && okLon {
+		meta.Latitude, meta.Longitude, meta.HasGPS = latitude, longitude, true
+	}
+	return meta, nil
+}
+
+// Parse an XMP GPS coordinate, "DDD,MM.mmk" or "DDD,MM,SSk" where k is N, S, E or W
+func parseXMPCoordinate(s string) (float64, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	ref := s[len(s)-1]
+	parts := strings.Split(s[:len(s)-1], ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+
+	ret := 0.0
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return 0, false
+		}
+		ret += v / []float64{1, 60, 3600}[i]
+	}
+	switch ref {
+	case 'S', 'W':
+		ret = -ret
+	case 'N', 'E':
+	default:
+		return 0, false
+	}
+	return ret, true
+}
+
+// JPEG markers and headers of the segments holding metadata
+var (
+	jpegXMPHeader       = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	jpegExifHeader      = []byte("Exif\x00\x00")
+	jpegPhotoshopHeader = []byte("Photoshop 3.0\x00")
+)
+
+const (
+	jpegSOI  = 0xD8
+	jpegSOS  = 0xDA
+	jpegAPP1 = 0xE1
+	// Photoshop resources, containing IPTC data
+	jpegAPP13 = 0xED
+)
+
+// Read the XMP, IPTC and EXIF metadata embedded in a JPEG file, other files yield
+// empty metadata. XMP has precedence over IPTC.
+func readJPEGMetadata(r io.Reader) (*FileMetadata, error) {
+	meta := &FileMetadata{}
+	reader := bufio.NewReader(r)
+
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(reader, head); err != nil || head[0] != 0xFF || head[1] != jpegSOI {
+		return meta, nil
+	}
+
+	var xmp, iptc, exif *FileMetadata
+	for {
+		marker := make([]byte, 4)
+		if _, err := io.ReadFull(reader, marker); err != nil {
+			break
+		}
+		if marker[0] != 0xFF || marker[1] == jpegSOS {
+			// metadata segments come before the image data
+			break
+		}
+		size := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if size < 0 {
+			break
+		}
+		segment := make([]byte, size)
+		if _, err := io.ReadFull(reader, segment); err != nil {
+			break
+		}
+
+		switch {
+		case marker[1] == jpegAPP1 && bytes.HasPrefix(segment, jpegXMPHeader):
+			parsed, err := parseXMP(segment[len(jpegXMPHeader):])
+			if err == nil {
+				xmp = parsed
+			}
+		case marker[1] == jpegAPP1 && bytes.HasPrefix(segment, jpegExifHeader):
+			exif = parseExif(segment[len(jpegExifHeader):])
+		case marker[1] == jpegAPP13 && bytes.HasPrefix(segment, jpegPhotoshopHeader):
+			iptc = parseIPTC(segment[len(jpegPhotoshopHeader):])
+		}
+	}
+
+	for _, m := range []*FileMetadata{xmp, iptc, exif} {
+		if m != nil {
+			meta.merge(m)
+		}
+	}
+	return meta, nil
+}
+
+// EXIF tags read by parseExif
+const (
+	exifMake             = 0x010F
+	exifModel            = 0x0110
+	exifDateTime         = 0x0132
+	exifIFDPointer       = 0x8769
+	exifDateTimeOriginal = 0x9003
+)
+
+// Parse the TIFF structure of an EXIF APP1 segment, reading the camera make and
+// model along with the date the photo was taken (DateTimeOriginal, DateTime as a
+// fallback). Malformed data yields empty metadata.
+func parseExif(data []byte) *FileMetadata {
+	meta := &FileMetadata{}
+	if len(data) < 8 {
+		return meta
+	}
+	var order binary.ByteOrder
+	switch string(data[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return meta
+	}
+
+	// Return the ASCII and LONG values of the entries of the IFD at offset
+	readIFD := func(offset uint32) map[uint16]string {
+		ret := map[uint16]string{}
+		if int64(offset)+2 > int64(len(data)) {
+			return ret
+		}
+		count := int(order.Uint16(data[offset:]))
+		for i := 0; i < count; i++ {
+			entry := int(offset) + 2 + i*12
+			if entry+12 > len(data) {
+				break
+			}
+			tag := order.Uint16(data[entry:])
+			kind := order.Uint16(data[entry+2:])
+			n := order.Uint32(data[entry+4:])
+			value := data[entry+8 : entry+12]
+			switch kind {
+			case 2: // ASCII, inline when it fits in 4 bytes
+				if n > 4 {
+					start := order.Uint32(value)
+					if int64(start)+int64(n) > int64(len(data)) {
+						continue
+					}
+					value = data[start : start+n]
+				} else {
+					value = value[:n]
+				}
+				ret[tag] = strings.TrimSpace(strings.TrimRight(string(value), "\x00"))
+			case 4: // LONG
+				ret[tag] = strconv.FormatUint(uint64(order.Uint32(value)), 10)
+			}
+		}
+		return ret
+	}
+
+	ifd0 := readIFD(order.Uint32(data[4:]))
+	meta.CameraMake = ifd0[exifMake]
+	meta.CameraModel = ifd0[exifModel]
+	date := ifd0[exifDateTime]
+	if pointer, err := strconv.ParseUint(ifd0[exifIFDPointer], 10, 32); err == nil {
+		if original := readIFD(uint32(pointer))[exifDateTimeOriginal]; original != "" {
+			date = original
+		}
+	}
+	if taken, err := time.Parse("2006:01:02 15:04:05", date); err == nil {
+		meta.Taken = taken
+	}
+	return meta
+}
+
+// Parse the Photoshop image resources of an APP13 segment, reading the IPTC IIM
+// object name, caption and keywords
+func parseIPTC(data []byte) *FileMetadata {
+	meta := &FileMetadata{}
+
+	// look for the IPTC resource (ID 0x0404) among the 8BIM blocks
+	var iim []byte
+	for len(data) >= 12 && bytes.HasPrefix(data, []byte("8BIM")) {
+		id := binary.BigEndian.Uint16(data[4:6])
+		// the resource name is a pascal string padded to an even length
+		nameLen := int(data[6]) + 1
+		nameLen += nameLen % 2
+		offset := 6 + nameLen
+		if len(data) < offset+4 {
+			break
+		}
+		size := int(binary.BigEndian.Uint32(data[offset:]))
+		offset += 4
+		if len(data) < offset+size {
+			break
+		}
+		if id == 0x0404 {
+			iim = data[offset : offset+size]
+			break
+		}
+		// blocks are padded to an even length, the padding of the last one may be missing
+		next := offset + size + size%2
+		if next > len(data) {
+			break
+		}
+		data = data[next:]
+	}
+
+	// IIM datasets: 0x1C, record, dataset, size (2 bytes), value
+	for len(iim) >= 5 && iim[0] == 0x1C {
+		record, dataset := iim[1], iim[2]
+		size := int(binary.BigEndian.Uint16(iim[3:5]))
+		if len(iim) < 5+size {
+			break
+		}
+		value := strings.TrimSpace(string(iim[5 : 5+size]))
+		iim = iim[5+size:]
+		if record != 2 {
+			continue
+		}
+		switch dataset {
+		case 5:
+			meta.Title = value
+		case 25:
+			meta.Keywords = append(meta.Keywords, value)
+		case 120:
+			meta.Description = value
+		}
+	}
+	return meta
+}
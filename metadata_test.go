@@ -0,0 +1,178 @@
+package flickr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sidecarXMP = `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:exif="http://ns.adobe.com/exif/1.0/"
+    exif:GPSLatitude="37,46.5N"
+    exif:GPSLongitude="122,25,30W">
+   <dc:title><rdf:Alt><rdf:li xml:lang="x-default">Golden Gate</rdf:li></rdf:Alt></dc:title>
+   <dc:description><rdf:Alt><rdf:li xml:lang="x-default">Foggy morning</rdf:li></rdf:Alt></dc:description>
+   <dc:subject><rdf:Bag><rdf:li>bridge</rdf:li><rdf:li>san francisco</rdf:li></rdf:Bag></dc:subject>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>`
+
+// Build a JPEG segment with the given marker
+func jpegSegment(marker byte, data []byte) []byte {
+	size := make([]byte, 2)
+	binary.BigEndian.PutUint16(size, uint16(len(data)+2))
+	return append(append([]byte{0xFF, marker}, size...), data...)
+}
+
+// Build an IPTC IIM dataset of record 2
+func iimDataset(dataset byte, value string) []byte {
+	size := make([]byte, 2)
+	binary.BigEndian.PutUint16(size, uint16(len(value)))
+	return append(append([]byte{0x1C, 2, dataset}, size...), value...)
+}
+
+func testJPEG(xmp string) []byte {
+	iim := bytes.Join([][]byte{
+		iimDataset(5, "IPTC title"),
+		iimDataset(25, "iptc keyword"),
+		iimDataset(120, "IPTC caption"),
+	}, nil)
+	resource := []byte("8BIM\x04\x04\x00\x00")
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(iim)))
+	resource = append(append(resource, size...), iim...)
+
+	ret := []byte{0xFF, jpegSOI}
+	if xmp != "" {
+		ret = append(ret, jpegSegment(jpegAPP1, append(jpegXMPHeader, xmp...))...)
+	}
+	ret = append(ret, jpegSegment(jpegAPP13, append(jpegPhotoshopHeader, resource...))...)
+	return append(ret, 0xFF, jpegSOS, 0, 2)
+}
+
+func TestParseXMP(t *testing.T) {
+	meta, err := parseXMP([]byte(sidecarXMP))
+	Expect(t, err, nil)
+	Expect(t, meta.Title, "Golden Gate")
+	Expect(t, meta.Description, "Foggy morning")
+	Expect(t, strings.Join(meta.Keywords, ","), "bridge,san francisco")
+	Expect(t, meta.HasGPS, true)
+	Expect(t, meta.Latitude, 37.775)
+	Expect(t, meta.Longitude < -122.4249 && meta.Longitude > -122.4251, true)
+}
+
+func TestReadJPEGMetadata(t *testing.T) {
+	meta, err := readJPEGMetadata(bytes.NewReader(testJPEG("")))
+	Expect(t, err, nil)
+	Expect(t, meta.Title, "IPTC title")
+	Expect(t, meta.Description, "IPTC caption")
+	Expect(t, strings.Join(meta.Keywords, ","), "iptc keyword")
+
+	// XMP wins over IPTC
+	meta, err = readJPEGMetadata(bytes.NewReader(testJPEG(sidecarXMP)))
+	Expect(t, err, nil)
+	Expect(t, meta.Title, "Golden Gate")
+
+	// not a JPEG
+	meta, err = readJPEGMetadata(strings.NewReader("GIF89a"))
+	Expect(t, err, nil)
+	Expect(t, meta.Title, "")
+}
+
+func TestParseIPTCTruncated(t *testing.T) {
+	// a resource of odd size ending the segment without its padding byte
+	meta := parseIPTC([]byte("8BIM\x04\x00\x00\x00\x00\x00\x00\x01x"))
+	Expect(t, meta.Title, "")
+
+	// the IPTC resource following it is still found when padded
+	iim := iimDataset(5, "IPTC title")
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(iim)))
+	data := append([]byte("8BIM\x04\x00\x00\x00\x00\x00\x00\x01x\x00"), "8BIM\x04\x04\x00\x00"...)
+	data = append(append(data, size...), iim...)
+	meta = parseIPTC(data)
+	Expect(t, meta.Title, "IPTC title")
+}
+
+func TestNewUploadParamsFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metadata")
+	Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "IMG_1.jpg")
+	ioutil.WriteFile(path, testJPEG(""), 0644)
+
+	params, err := NewUploadParamsFromFile(path)
+	Expect(t, err, nil)
+	Expect(t, params.Title, "IPTC title")
+	Expect(t, params.SafetyLevel, 1)
+
+	// the sidecar has precedence
+	ioutil.WriteFile(filepath.Join(dir, "IMG_1.xmp"), []byte(sidecarXMP), 0644)
+	params, err = NewUploadParamsFromFile(path)
+	Expect(t, err, nil)
+	Expect(t, params.Title, "Golden Gate")
+	Expect(t, FormatTags(params.Tags), `bridge "san francisco"`)
+
+	_, err = ReadMetadata(filepath.Join(dir, "missing.jpg"))
+	Expect(t, os.IsNotExist(err), true)
+}
+
+// Build a big endian EXIF APP1 payload with the given camera and DateTimeOriginal
+func testExif(cameraMake, model, taken string) []byte {
+	be := binary.BigEndian
+	u16 := func(v int) []byte { b := make([]byte, 2); be.PutUint16(b, uint16(v)); return b }
+	u32 := func(v int) []byte { b := make([]byte, 4); be.PutUint32(b, uint32(v)); return b }
+
+	cameraMake += "\x00"
+	model += "\x00"
+	taken += "\x00"
+	// header, IFD0 with 3 entries, strings, Exif IFD with 1 entry, date
+	makeAt := 8 + 2 + 3*12 + 4
+	modelAt := makeAt + len(cameraMake)
+	exifAt := modelAt + len(model)
+	takenAt := exifAt + 2 + 12 + 4
+
+	tiff := bytes.Join([][]byte{
+		[]byte("MM\x00*"), u32(8),
+		u16(3),
+		u16(exifMake), u16(2), u32(len(cameraMake)), u32(makeAt),
+		u16(exifModel), u16(2), u32(len(model)), u32(modelAt),
+		u16(exifIFDPointer), u16(4), u32(1), u32(exifAt),
+		u32(0),
+		[]byte(cameraMake), []byte(model),
+		u16(1),
+		u16(exifDateTimeOriginal), u16(2), u32(len(taken)), u32(takenAt),
+		u32(0),
+		[]byte(taken),
+	}, nil)
+	return append(append([]byte(nil), jpegExifHeader...), tiff...)
+}
+
+func TestParseExif(t *testing.T) {
+	data := testExif("Canon", "EOS R5", "2024:06:01 10:30:00")
+	meta := parseExif(data[len(jpegExifHeader):])
+	Expect(t, meta.CameraMake, "Canon")
+	Expect(t, meta.CameraModel, "EOS R5")
+	Expect(t, meta.Taken.Format(time.RFC3339), "2024-06-01T10:30:00Z")
+
+	jpeg := []byte{0xFF, jpegSOI}
+	jpeg = append(jpeg, jpegSegment(jpegAPP1, data)...)
+	jpeg = append(jpeg, 0xFF, jpegSOS, 0, 2)
+	meta, err := readJPEGMetadata(bytes.NewReader(jpeg))
+	Expect(t, err, nil)
+	Expect(t, meta.CameraModel, "EOS R5")
+
+	// truncated data is ignored
+	meta = parseExif(data[len(jpegExifHeader) : len(data)-30])
+	Expect(t, meta.CameraModel, "EOS R5")
+	Expect(t, meta.Taken.IsZero(), true)
+}
@@ -0,0 +1,178 @@
+// Package model provides an object layer on top of the API packages: users, albums
+// and photos are bound to a client and load their relations lazily, caching them
+// so that repeated accesses don't hit the API again.
+//
+//	user := model.NewUser(client, "")
+//	albums, _ := user.Albums()
+//	photos, _ := albums[0].Photos()
+//	comments, _ := photos[0].Comments()
+//
+// Objects perform their calls with clones of the client, which is left untouched.
+// Objects are safe for concurrent use, call Refresh to drop cached data. Give the
+// client a flickr.Coalescer to merge the loads of the same data by distinct objects.
+package model
+
+import (
+	"sync"
+
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/photos"
+	"gopkg.in/masci/flickr.v2/photosets"
+)
+
+// A Flickr user
+type User struct {
+	// NSID of the user, empty for the calling user
+	Id string
+
+	client *flickr.FlickrClient
+	mu     sync.Mutex
+	albums []*Album
+}
+
+// An album (photoset) of a user
+type Album struct {
+	photosets.Photoset
+	Owner *User
+
+	client *flickr.FlickrClient
+	mu     sync.Mutex
+	photos []*Photo
+}
+
+// A photo, along with the album it was loaded from if any
+type Photo struct {
+	Id    string
+	Title string
+	// nil unless the photo was loaded from an album
+	Album *Album
+
+	client   *flickr.FlickrClient
+	mu       sync.Mutex
+	info     *photos.PhotoInfo
+	comments []photos.Comment
+}
+
+// Return a user bound to client, an empty id stands for the calling user
+func NewUser(client *flickr.FlickrClient, id string) *User {
+	return &User{Id: id, client: client}
+}
+
+// Return a photo bound to client
+func NewPhoto(client *flickr.FlickrClient, id string) *Photo {
+	return &Photo{Id: id, client: client}
+}
+
+// Whether calls must be signed with the user tokens of client
+func authenticated(client *flickr.FlickrClient) bool {
+	return client.OAuthToken != ""
+}
+
+// Return the albums of the user, loading all of them on the first call
+func (u *User) Albums() ([]*Album, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.albums != nil {
+		return u.albums, nil
+	}
+
+	albums := []*Album{}
+	client := u.client.Clone()
+	for page := 1; ; page++ {
+		list, err := photosets.StreamList(client, authenticated(client), u.Id, page, flickr.MaxPerPage, func(set *photosets.Photoset) error {
+			albums = append(albums, &Album{Photoset: *set, Owner: u, client: u.client})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if page >= list.Pages {
+			break
+		}
+	}
+	u.albums = albums
+	return albums, nil
+}
+
+// Drop the cached albums
+func (u *User) Refresh() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.albums = nil
+}
+
+// Return the photos of the album, loading all of them on the first call
+func (a *Album) Photos() ([]*Photo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.photos != nil {
+		return a.photos, nil
+	}
+
+	ret := []*Photo{}
+	client := a.client.Clone()
+	for page := 1; ; page++ {
+		list, err := photosets.StreamPhotos(client, authenticated(client), a.Id, a.Owner.Id, page, flickr.MaxPerPage, "", func(p *photosets.Photo) error {
+			ret = append(ret, &Photo{Id: p.Id, Title: p.Title, Album: a, client: a.client})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if page >= list.Pages {
+			break
+		}
+	}
+	a.photos = ret
+	return ret, nil
+}
+
+// Drop the cached photos
+func (a *Album) Refresh() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.photos = nil
+}
+
+// Return the details of the photo, loaded with getInfo on the first call
+func (p *Photo) Info() (*photos.PhotoInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.info != nil {
+		return p.info, nil
+	}
+
+	resp, err := photos.GetInfo(p.client.Clone(), p.Id, "")
+	if err != nil {
+		return nil, err
+	}
+	p.info = &resp.Photo
+	return p.info, nil
+}
+
+// Return the comments of the photo, loaded on the first call
+func (p *Photo) Comments() ([]photos.Comment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.comments != nil {
+		return p.comments, nil
+	}
+
+	resp, err := photos.GetComments(p.client.Clone(), p.Id)
+	if err != nil {
+		return nil, err
+	}
+	p.comments = resp.Comments.Items
+	if p.comments == nil {
+		p.comments = []photos.Comment{}
+	}
+	return p.comments, nil
+}
+
+// Drop the cached details and comments
+func (p *Photo) Refresh() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.info = nil
+	p.comments = nil
+}
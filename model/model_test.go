@@ -0,0 +1,88 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const (
+	setList = `<rsp stat="ok"><photosets page="1" pages="1" perpage="500" total="1">
+  <photoset id="72157" primary="1" secret="abc" server="2" photos="2" videos="0"><title>Holidays</title></photoset>
+</photosets></rsp>`
+	setPhotos = `<rsp stat="ok"><photoset id="72157" page="1" pages="1" perpage="500" total="2">
+  <photo id="1" title="beach" isprimary="1" />
+  <photo id="2" title="sunset" isprimary="0" />
+</photoset></rsp>`
+	comments = `<rsp stat="ok"><comments photo_id="1">
+  <comment id="c1" author="35468159852@N01" authorname="Dan" datecreate="1141841470">Nice!</comment>
+</comments></rsp>`
+	photoInfo = `<rsp stat="ok"><photo id="1" secret="abc" server="2"><title>beach</title></photo></rsp>`
+)
+
+func TestObjects(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.getList":       setList,
+		"flickr.photosets.getPhotos":     setPhotos,
+		"flickr.photos.comments.getList": comments,
+		"flickr.photos.getInfo":          photoInfo,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	user := NewUser(fclient, "123@N00")
+	albums, err := user.Albums()
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(albums), 1)
+	flickr.Expect(t, albums[0].Title, "Holidays")
+	flickr.Expect(t, albums[0].Owner, user)
+
+	list, err := albums[0].Photos()
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(list), 2)
+	flickr.Expect(t, list[1].Title, "sunset")
+	flickr.Expect(t, list[1].Album, albums[0])
+	flickr.Expect(t, calls.Last("flickr.photosets.getPhotos").Get("user_id"), "123@N00")
+
+	c, err := list[0].Comments()
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(c), 1)
+	flickr.Expect(t, c[0].Text, "Nice!")
+	flickr.Expect(t, c[0].AuthorName, "Dan")
+
+	info, err := list[0].Info()
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, info.Secret, "abc")
+
+	// relations are cached
+	user.Albums()
+	albums[0].Photos()
+	list[0].Comments()
+	list[0].Info()
+	flickr.Expect(t, strings.Join(calls.Methods(), ","),
+		"flickr.photosets.getList,flickr.photosets.getPhotos,flickr.photos.comments.getList,flickr.photos.getInfo")
+
+	// until they are refreshed
+	user.Refresh()
+	user.Albums()
+	list[0].Refresh()
+	list[0].Comments()
+	flickr.Expect(t, len(calls.Methods()), 6)
+	// the client is left untouched
+	flickr.Expect(t, fclient.Args.Get("method"), "")
+}
+
+func TestPhotoError(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, `<rsp stat="fail"><err code="1" msg="Photo not found" /></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	photo := NewPhoto(fclient, "404")
+	_, err := photo.Comments()
+	flickr.Expect(t, err != nil, true)
+	_, err = photo.Info()
+	flickr.Expect(t, err != nil, true)
+}
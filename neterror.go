@@ -0,0 +1,88 @@
+package flickr
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Kinds of failures of HTTP requests that didn't get a response
+type NetFailure string
+
+const (
+	// The request or the connection timed out
+	NetTimeout NetFailure = "timeout"
+	// The host name couldn't be resolved
+	NetDNS NetFailure = "dns"
+	// The connection couldn't be established
+	NetConnect NetFailure = "connect"
+	// The TLS handshake failed, e.g. because of an invalid certificate
+	NetTLS NetFailure = "tls"
+	// The request was canceled by the caller
+	NetCanceled NetFailure = "canceled"
+	// Any other failure, e.g. the connection was reset
+	NetOther NetFailure = "network"
+)
+
+// NetError describes an HTTP request that failed before Flickr could answer. It's
+// joined to the error returned by the http.Client with errors.Join, so callers can
+// tell network problems from Flickr API errors with errors.As while errors.Is and
+// errors.As keep working on the underlying net and url errors.
+type NetError struct {
+	// Host the request was sent to
+	Host string
+	Kind NetFailure
+	// Time spent before the request failed
+	Elapsed time.Duration
+}
+
+// Implement error interface
+func (e *NetError) Error() string {
+	return fmt.Sprintf("%s failure requesting %s after %s", e.Kind, e.Host, e.Elapsed.Round(time.Millisecond))
+}
+
+// Return the NetError carried by err, nil if err is not a network failure
+func AsNetError(err error) *NetError {
+	var netErr *NetError
+	if errors.As(err, &netErr) {
+		return netErr
+	}
+	return nil
+}
+
+// Classify the error returned by http.Client.Do
+func netFailureKind(err error) NetFailure {
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var opErr *net.OpError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return NetCanceled
+	case errors.As(err, &dnsErr):
+		return NetDNS
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &authorityErr),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return NetTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return NetTimeout
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return NetConnect
+	}
+	return NetOther
+}
+
+// Join a NetError describing the failure of a request to host to err, secrets in
+// the URL reported by err are masked
+func diagnoseNetError(host string, err error, elapsed time.Duration) error {
+	return errors.Join(&NetError{Host: host, Kind: netFailureKind(err), Elapsed: elapsed}, redactError(err))
+}
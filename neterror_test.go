@@ -0,0 +1,78 @@
+package flickr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Return a client whose requests are sent to a server that was shut down
+func unreachableClient() *FlickrClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	u, _ := url.Parse(server.URL)
+	server.Close()
+
+	client := GetTestClient()
+	client.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+	client.Init()
+	return client
+}
+
+func TestNetErrorConnect(t *testing.T) {
+	client := unreachableClient()
+	err := DoGet(client, &FooResponse{})
+
+	netErr := AsNetError(err)
+	Expect(t, netErr != nil, true)
+	Expect(t, netErr.Kind, NetConnect)
+	Expect(t, netErr.Host, "api.flickr.com")
+	// the original error is still reachable
+	var opErr *net.OpError
+	Expect(t, errors.As(err, &opErr), true)
+}
+
+func TestNetErrorTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprintln(w, `<rsp stat="ok"></rsp>`)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	client := NewFlickrClient("apikey", "apisecret", WithCallTimeout(5*time.Millisecond))
+	client.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+	client.Init()
+
+	err := DoGet(client, &FooResponse{})
+	netErr := AsNetError(err)
+	Expect(t, netErr != nil, true)
+	Expect(t, netErr.Kind, NetTimeout)
+	Expect(t, netErr.Elapsed >= 5*time.Millisecond, true)
+	Expect(t, errors.Is(err, context.DeadlineExceeded), true)
+}
+
+func TestNetFailureKind(t *testing.T) {
+	Expect(t, netFailureKind(&url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host", Name: "api.flickr.com"}}), NetDNS)
+	Expect(t, netFailureKind(&url.Error{Op: "Get", Err: context.Canceled}), NetCanceled)
+	Expect(t, netFailureKind(errors.New("connection reset by peer")), NetOther)
+
+	// API errors are not network failures
+	Expect(t, AsNetError(errors.New("boom")) == nil, true)
+}
+
+func TestRetryPolicyConnectFailure(t *testing.T) {
+	delays := []time.Duration{}
+	client := unreachableClient()
+	client.RetryPolicy = testPolicy(&delays)
+	client.Args.Set("method", "flickr.test.null")
+
+	err := DoGet(client, &FooResponse{})
+	Expect(t, AsNetError(err).Kind, NetConnect)
+	Expect(t, len(delays), 3)
+}
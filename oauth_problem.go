@@ -0,0 +1,163 @@
+package flickr
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A problem reported by Flickr in the oauth_problem parameter of a failed OAuth
+// request, following the OAuth Problem Reporting extension
+type OAuthProblem string
+
+const (
+	ProblemVersionRejected                 OAuthProblem = "version_rejected"
+	ProblemParameterAbsent                 OAuthProblem = "parameter_absent"
+	ProblemParameterRejected               OAuthProblem = "parameter_rejected"
+	ProblemTimestampRefused                OAuthProblem = "timestamp_refused"
+	ProblemNonceUsed                       OAuthProblem = "nonce_used"
+	ProblemSignatureMethodRejected         OAuthProblem = "signature_method_rejected"
+	ProblemSignatureInvalid                OAuthProblem = "signature_invalid"
+	ProblemConsumerKeyUnknown              OAuthProblem = "consumer_key_unknown"
+	ProblemConsumerKeyRejected             OAuthProblem = "consumer_key_rejected"
+	ProblemConsumerKeyRefused              OAuthProblem = "consumer_key_refused"
+	ProblemTokenUsed                       OAuthProblem = "token_used"
+	ProblemTokenExpired                    OAuthProblem = "token_expired"
+	ProblemTokenRevoked                    OAuthProblem = "token_revoked"
+	ProblemTokenRejected                   OAuthProblem = "token_rejected"
+	ProblemAdditionalAuthorizationRequired OAuthProblem = "additional_authorization_required"
+	ProblemPermissionUnknown               OAuthProblem = "permission_unknown"
+	ProblemPermissionDenied                OAuthProblem = "permission_denied"
+	ProblemUserRefused                     OAuthProblem = "user_refused"
+)
+
+// Remediation hints of the known problems
+var oauthProblemHints = map[OAuthProblem]string{
+	ProblemVersionRejected:                 "the OAuth version is not supported, use 1.0",
+	ProblemParameterAbsent:                 "add the missing OAuth parameters to the request",
+	ProblemParameterRejected:               "remove or fix the rejected parameters",
+	ProblemTimestampRefused:                "the clock of this machine is off, synchronize it (e.g. with NTP)",
+	ProblemNonceUsed:                       "the nonce was already used, sign each request with a fresh nonce and do not replay requests",
+	ProblemSignatureMethodRejected:         "sign requests with HMAC-SHA1",
+	ProblemSignatureInvalid:                "check the API secret and the token secret, and that every parameter is sent as signed",
+	ProblemConsumerKeyUnknown:              "the API key is unknown, check it in the App Garden",
+	ProblemConsumerKeyRejected:             "the API key was disabled, check its status in the App Garden",
+	ProblemConsumerKeyRefused:              "the API key is not allowed to make this request",
+	ProblemTokenUsed:                       "the request token was already exchanged, start the authorization over",
+	ProblemTokenExpired:                    "the token expired, start the authorization over",
+	ProblemTokenRevoked:                    "the user revoked the access of the application, authorize it again",
+	ProblemTokenRejected:                   "the token is unknown or belongs to another API key, authorize the application again",
+	ProblemAdditionalAuthorizationRequired: "the token lacks a permission, authorize the application again with more permissions",
+	ProblemPermissionUnknown:               "ask for one of the read, write or delete permissions",
+	ProblemPermissionDenied:                "the user did not grant the permission, authorize the application again",
+	ProblemUserRefused:                     "the user refused to authorize the application",
+}
+
+// OAuthError describes the oauth_problem Flickr reported for a request, along with
+// the advice parameters it sometimes adds. It's the Cause of the *flickErr.Error
+// returned by ParseRequestToken, ParseOAuthToken and the API calls whose signature
+// Flickr refused, use AsOAuthError to get it.
+type OAuthError struct {
+	Problem OAuthProblem
+	// Free text advice, from oauth_problem_advice
+	Advice string
+	// Names of the parameters missing or rejected, for ProblemParameterAbsent and
+	// ProblemParameterRejected
+	ParametersAbsent   []string
+	ParametersRejected []string
+	// Unix timestamps accepted by Flickr, for ProblemTimestampRefused, zero if not
+	// reported
+	MinTimestamp int64
+	MaxTimestamp int64
+	// OAuth versions accepted, for ProblemVersionRejected
+	AcceptableVersions string
+	// Time of the Date header of the response, zero if unknown
+	ServerTime time.Time
+}
+
+// Implement error interface
+func (e *OAuthError) Error() string {
+	msg := string(e.Problem)
+	if e.Advice != "" {
+		msg += ": " + e.Advice
+	}
+	if hint := e.Hint(); hint != "" {
+		msg += " (" + hint + ")"
+	}
+	return msg
+}
+
+// Return how to fix the problem, empty for unknown problems
+func (e *OAuthError) Hint() string {
+	hint := oauthProblemHints[e.Problem]
+	switch {
+	case e.Problem == ProblemParameterAbsent && len(e.ParametersAbsent) > 0:
+		hint += ": " + strings.Join(e.ParametersAbsent, ", ")
+	case e.Problem == ProblemParameterRejected && len(e.ParametersRejected) > 0:
+		hint += ": " + strings.Join(e.ParametersRejected, ", ")
+	case e.Problem == ProblemTimestampRefused && e.MaxTimestamp > 0:
+		hint += fmt.Sprintf(", timestamps between %d and %d are accepted", e.MinTimestamp, e.MaxTimestamp)
+	case e.Problem == ProblemVersionRejected && e.AcceptableVersions != "":
+		hint += ", accepted: " + e.AcceptableVersions
+	}
+	return hint
+}
+
+// Return whether authorizing the application again may fix the problem
+func (e *OAuthError) NeedsReauthorization() bool {
+	switch e.Problem {
+	case ProblemTokenUsed, ProblemTokenExpired, ProblemTokenRevoked, ProblemTokenRejected,
+		ProblemAdditionalAuthorizationRequired, ProblemPermissionDenied:
+		return true
+	}
+	return false
+}
+
+// Return the OAuthError carried by err, nil if err is not an OAuth problem
+func AsOAuthError(err error) *OAuthError {
+	var oauthErr *OAuthError
+	if errors.As(err, &oauthErr) {
+		return oauthErr
+	}
+	return nil
+}
+
+// Split a list of parameter names of the advice, separated by "&"
+func splitOAuthParams(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, "&")
+}
+
+// Build the OAuthError of a response, nil if it reports no oauth_problem
+func parseOAuthProblem(val url.Values) *OAuthError {
+	problem := val.Get("oauth_problem")
+	if problem == "" {
+		return nil
+	}
+	ret := &OAuthError{
+		Problem:            OAuthProblem(problem),
+		Advice:             val.Get("oauth_problem_advice"),
+		ParametersAbsent:   splitOAuthParams(val.Get("oauth_parameters_absent")),
+		ParametersRejected: splitOAuthParams(val.Get("oauth_parameters_rejected")),
+		AcceptableVersions: val.Get("oauth_acceptable_versions"),
+	}
+	if bounds := strings.SplitN(val.Get("oauth_acceptable_timestamps"), "-", 2); len(bounds) == 2 {
+		ret.MinTimestamp, _ = strconv.ParseInt(bounds[0], 10, 64)
+		ret.MaxTimestamp, _ = strconv.ParseInt(bounds[1], 10, 64)
+	}
+	return ret
+}
+
+// Build the OAuthError of a raw text response, nil if it's not an OAuth problem
+func parseOAuthProblemBody(body []byte) *OAuthError {
+	val, err := url.ParseQuery(strings.TrimSpace(string(body)))
+	if err != nil {
+		return nil
+	}
+	return parseOAuthProblem(val)
+}
@@ -0,0 +1,259 @@
+package flickr
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Key of the queued calls in the Store of an OfflineQueue when none is given
+const DefaultOfflineQueueKey = "offline-queue"
+
+// A write call queued while the network was down
+type QueuedCall struct {
+	Id string `json:"id"`
+	// Flickr API method, e.g. "flickr.photos.addTags"
+	Method string `json:"method"`
+	// Request params, without signature and OAuth params since the call is signed
+	// again when it's sent
+	Args   url.Values `json:"args"`
+	Queued time.Time  `json:"queued"`
+}
+
+// What to do with a queued call Flickr rejected when the queue is flushed
+type ConflictAction int
+
+const (
+	// Forget the call
+	ConflictDrop ConflictAction = iota
+	// Keep the call in the queue, it's sent again by the next flush
+	ConflictKeep
+)
+
+// A function deciding what to do with a queued call Flickr rejected with err when
+// the queue was flushed, e.g. a tag added to a photo deleted in the meantime
+type ConflictFunc func(call *QueuedCall, err error) ConflictAction
+
+// An OfflineQueue keeps the write calls (the ones sent with DoPost) that fail because
+// the network is down, so that apps used on poor connections, e.g. by photographers
+// in the field, keep working. Only the calls that couldn't reach Flickr (DNS,
+// connection and TLS failures) are queued: timeouts and other failures are returned
+// to the caller since Flickr may have performed the call already. Calls are persisted to a Store and sent again, in the
+// order they were made, by Flush: automatically before the next write call of the
+// client and periodically by Run. Calls made while the queue isn't empty are queued
+// behind the pending ones if it can't be flushed.
+// Uploads and OAuth calls are never queued.
+// An OfflineQueue is safe for concurrent use.
+type OfflineQueue struct {
+	Store Store
+	// Key of the queued calls in Store, DefaultOfflineQueueKey when empty
+	Key string
+	// Called for the queued calls Flickr rejects, nil drops them
+	OnConflict ConflictFunc
+
+	// guards Store
+	mu  sync.Mutex
+	seq int
+	// held by Flush, so that calls are sent once and in order
+	flushMu sync.Mutex
+}
+
+func NewOfflineQueue(store Store) *OfflineQueue {
+	return &OfflineQueue{Store: store}
+}
+
+// Queue the write calls of the client that fail because of the network
+func WithOfflineQueue(queue *OfflineQueue) ClientOption {
+	return func(c *FlickrClient) {
+		c.OfflineQueue = queue
+	}
+}
+
+// Return whether err reports a call queued by an OfflineQueue
+func IsQueuedOffline(err error) bool {
+	e, ok := err.(*flickErr.Error)
+	return ok && e.ErrorCode == flickErr.OfflineQueuedError
+}
+
+func (q *OfflineQueue) key() string {
+	if q.Key == "" {
+		return DefaultOfflineQueueKey
+	}
+	return q.Key
+}
+
+// Load the queued calls, must be called with the lock held
+func (q *OfflineQueue) load() ([]QueuedCall, error) {
+	calls := []QueuedCall{}
+	if _, err := LoadJSON(q.Store, q.key(), &calls); err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
+
+// Return the calls waiting to be sent, oldest first
+func (q *OfflineQueue) Pending() ([]QueuedCall, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.load()
+}
+
+// Whether a request param is added by the signing process
+func isSigningArg(name string) bool {
+	return strings.HasPrefix(name, "oauth_") || name == "api_key" || name == "api_sig"
+}
+
+// Save the call the client is about to make at the end of the queue, returns the
+// error reporting it to the caller
+func (q *OfflineQueue) enqueue(client *FlickrClient) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	calls, err := q.load()
+	if err != nil {
+		return err
+	}
+
+	q.seq++
+	now := time.Now()
+	call := QueuedCall{
+		Id:     strconv.FormatInt(now.UnixNano(), 10) + "-" + strconv.Itoa(q.seq),
+		Method: client.Args.Get("method"),
+		Args:   url.Values{},
+		Queued: now,
+	}
+	for name, values := range client.Args {
+		if !isSigningArg(name) {
+			call.Args[name] = append([]string(nil), values...)
+		}
+	}
+	if err := SaveJSON(q.Store, q.key(), append(calls, call)); err != nil {
+		return err
+	}
+	return flickErr.NewError(flickErr.OfflineQueuedError, call.Method)
+}
+
+// Return whether err reports a network failure worth queueing the call for: the
+// request didn't reach Flickr, so sending it again can't perform the call twice
+func isOffline(err error) bool {
+	netErr := AsNetError(err)
+	if netErr == nil {
+		return false
+	}
+	switch netErr.Kind {
+	case NetDNS, NetConnect, NetTLS:
+		return true
+	}
+	return false
+}
+
+// Remove a call from the queue
+func (q *OfflineQueue) remove(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	calls, err := q.load()
+	if err != nil {
+		return err
+	}
+	left := []QueuedCall{}
+	for _, call := range calls {
+		if call.Id != id {
+			left = append(left, call)
+		}
+	}
+	return SaveJSON(q.Store, q.key(), left)
+}
+
+// Send a write call, flushing the queue first so that calls are sent in order. The
+// call is queued when the queue can't be flushed or the call fails because of the
+// network.
+func (q *OfflineQueue) post(client *FlickrClient, r FlickrResponse) error {
+	pending, err := q.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		if _, err := q.Flush(client.Clone()); err != nil {
+			if isOffline(err) {
+				return q.enqueue(client)
+			}
+			return err
+		}
+	}
+
+	err = doPost(client, r)
+	if isOffline(err) {
+		return q.enqueue(client)
+	}
+	return err
+}
+
+// Send the queued calls in order, signed with the tokens of client. Calls accepted
+// by Flickr are removed from the queue, the ones it rejects are passed to OnConflict.
+// Flushing stops at the first network failure, returning it: the call is kept for
+// the next flush when it couldn't reach Flickr, otherwise Flickr may have performed
+// it and it's passed to OnConflict like a rejected call. The calls left are kept for
+// the next flush. The BatchResult lists the IDs of the calls sent and the rejected
+// ones that were dropped. The queue isn't locked while calls are sent, calls can be
+// queued in the meantime.
+// Requires the same permissions as the queued calls.
+func (q *OfflineQueue) Flush(client *FlickrClient) (*BatchResult, error) {
+	result := NewBatchResult()
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+	calls, err := q.Pending()
+	if err != nil {
+		return result, err
+	}
+
+	for i := range calls {
+		call := &calls[i]
+		client.Init()
+		client.HTTPVerb = "POST"
+		for name, values := range call.Args {
+			client.Args[name] = append([]string(nil), values...)
+		}
+		client.OAuthSign()
+		err := doPost(client, &BasicResponse{})
+		switch {
+		case err == nil:
+			result.Succeeded = append(result.Succeeded, call.Id)
+		case isOffline(err):
+			return result, err
+		case q.OnConflict != nil && q.OnConflict(call, err) == ConflictKeep:
+			result.Warn("call %s (%s) rejected, kept in the queue: %s", call.Id, call.Method, err)
+			if AsNetError(err) != nil {
+				return result, err
+			}
+			continue
+		default:
+			result.Failed = append(result.Failed, ItemError{Item: call.Id, Err: err})
+		}
+		if removeErr := q.remove(call.Id); removeErr != nil {
+			return result, removeErr
+		}
+		if AsNetError(err) != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// Flush the queue every interval until ctx is done, using client to send the calls.
+// Failures are left to the next attempt. Returns ctx.Err().
+func (q *OfflineQueue) Run(ctx context.Context, client *FlickrClient, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			q.Flush(client.Clone())
+		}
+	}
+}
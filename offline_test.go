@@ -0,0 +1,149 @@
+package flickr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// A transport failing like a dead network while down is set, or like a request
+// timing out once sent while timeout is set
+type flakyTransport struct {
+	sync.Mutex
+	down    bool
+	timeout bool
+	next    http.RoundTripper
+}
+
+func (t *flakyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.Lock()
+	down, timeout := t.down, t.timeout
+	t.Unlock()
+	if down {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("network is unreachable")}
+	}
+	if timeout {
+		return nil, context.DeadlineExceeded
+	}
+	return t.next.RoundTrip(r)
+}
+
+func (t *flakyTransport) set(down bool) {
+	t.Lock()
+	t.down = down
+	t.Unlock()
+}
+
+func TestOfflineQueue(t *testing.T) {
+	var mu sync.Mutex
+	sent := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent = append(sent, r.FormValue("tags"))
+		mu.Unlock()
+		if r.FormValue("photo_id") == "deleted" {
+			w.Write([]byte(`<rsp stat="fail"><err code="1" msg="Photo not found" /></rsp>`))
+			return
+		}
+		w.Write([]byte(`<rsp stat="ok"></rsp>`))
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	transport := &flakyTransport{down: true, next: RewriteTransport{URL: u}}
+
+	store := NewMemoryStore()
+	queue := NewOfflineQueue(store)
+	conflicts := 0
+	queue.OnConflict = func(call *QueuedCall, err error) ConflictAction {
+		conflicts++
+		// the queue isn't locked while it's flushed
+		pending, _ := queue.Pending()
+		Expect(t, len(pending), 1)
+		return ConflictDrop
+	}
+	client := NewFlickrClient("key", "secret", WithOfflineQueue(queue))
+	client.HTTPClient = &http.Client{Transport: transport}
+
+	addTags := func(photoId, tags string) error {
+		client.Init()
+		client.HTTPVerb = "POST"
+		client.Args.Set("method", "flickr.photos.addTags")
+		client.Args.Set("photo_id", photoId)
+		client.Args.Set("tags", tags)
+		client.OAuthSign()
+		return DoPost(client, &BasicResponse{})
+	}
+
+	Expect(t, IsQueuedOffline(addTags("1", "a")), true)
+	Expect(t, IsQueuedOffline(addTags("deleted", "b")), true)
+	pending, err := queue.Pending()
+	Expect(t, err, nil)
+	Expect(t, len(pending), 2)
+	Expect(t, pending[0].Method, "flickr.photos.addTags")
+	Expect(t, pending[0].Args.Get("oauth_signature"), "")
+	// reads are not queued, even the ones posted
+	Expect(t, AsNetError(DoGet(client, &BasicResponse{})) != nil, true)
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.getInfo")
+	client.Args.Set("photo_id", "1")
+	client.OAuthSign()
+	err = DoPost(client, &BasicResponse{})
+	Expect(t, IsQueuedOffline(err), false)
+	Expect(t, AsNetError(err) != nil, true)
+	pending, _ = queue.Pending()
+	Expect(t, len(pending), 2)
+
+	// the queue is flushed before the next write, in order
+	transport.set(false)
+	Expect(t, addTags("2", "c"), nil)
+	Expect(t, len(sent), 3)
+	Expect(t, sent[0], "a")
+	Expect(t, sent[2], "c")
+	Expect(t, conflicts, 1)
+	pending, _ = queue.Pending()
+	Expect(t, len(pending), 0)
+
+	// calls queued by a previous run are flushed from the store
+	transport.set(true)
+	addTags("3", "d")
+	transport.set(false)
+	result, err := NewOfflineQueue(store).Flush(client)
+	Expect(t, err, nil)
+	Expect(t, len(result.Succeeded), 1)
+	Expect(t, sent[3], "d")
+
+	// a flush failing on the network keeps the calls
+	transport.set(true)
+	addTags("4", "e")
+	_, err = queue.Flush(client)
+	Expect(t, AsNetError(err) != nil, true)
+	pending, _ = queue.Pending()
+	Expect(t, len(pending), 1)
+
+	// requests that may have reached Flickr are not queued, nor sent again
+	transport.set(false)
+	transport.Lock()
+	transport.timeout = true
+	transport.Unlock()
+	queue.OnConflict = nil
+	result, err = queue.Flush(client)
+	Expect(t, AsNetError(err).Kind, NetTimeout)
+	Expect(t, len(result.Failed), 1)
+	pending, _ = queue.Pending()
+	Expect(t, len(pending), 0)
+	err = addTags("5", "f")
+	Expect(t, IsQueuedOffline(err), false)
+	Expect(t, AsNetError(err).Kind, NetTimeout)
+	pending, _ = queue.Pending()
+	Expect(t, len(pending), 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	Expect(t, queue.Run(ctx, client, 1), context.Canceled)
+}
@@ -0,0 +1,211 @@
+package flickr
+
+// Fetch a page of a list, starting from 1, returning its pagination details
+type PageFetcher func(page int) (*ListInfo, error)
+
+// Fetch a page of a list, starting from 1, returning its pagination details and its
+// content, see NewPrefetchPageIterator
+type PageDataFetcher func(page int) (*ListInfo, interface{}, error)
+
+// Options of NewPageIterator
+type PageIteratorOptions struct {
+	// Skip the items already seen on a previous page, see PageIterator.Keep
+	Dedupe bool
+	// Fetch the next page in the background while the current one is processed, to
+	// hide the latency of the calls from streaming consumers. Only honoured by the
+	// iterators created with NewPrefetchPageIterator. At most one page is fetched for
+	// nothing when the consumer stops early.
+	Prefetch bool
+	// When set along with Prefetch, pages are only prefetched while the tracker
+	// reports more than a tenth of the hourly quota left, so that read-ahead doesn't
+	// eat the last calls
+	Quota *QuotaTracker
+}
+
+// Result of a page fetch
+type pageResult struct {
+	info *ListInfo
+	data interface{}
+	err  error
+}
+
+// Summary of a pagination, see PageIterator.Stats
+type PaginationStats struct {
+	// Number of pages fetched
+	Pages int
+	// Number of items seen, duplicates included
+	Items int
+	// Number of items already seen on a previous page, skipped when Dedupe is set
+	Duplicates int
+	// Whether the total reported by Flickr changed during the pagination, i.e. items
+	// were added or removed while iterating
+	TotalChanged bool
+}
+
+// A PageIterator walks the pages of a list the way Flickr lists must be walked when
+// they can change during the iteration: items added to the head of a list shift the
+// following ones to the next page, so they are returned twice, and the number of
+// pages changes. The number of pages of the latest response is the one honoured and
+// the iteration stops on the first empty page.
+//
+//	var resp *photos.SearchResponse
+//	it := flickr.NewPageIterator(func(page int) (*flickr.ListInfo, error) {
+//		params.Page = page
+//		var err error
+//		resp, err = photos.Search(client, params)
+//		...
+//	}, flickr.PageIteratorOptions{Dedupe: true})
+//	for it.Next() {
+//		for _, p := range resp.Photos.Items {
+//			if it.Keep(p.Id) {
+//				...
+//			}
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//
+// A PageIterator is not safe for concurrent use.
+type PageIterator struct {
+	fetch PageDataFetcher
+	opts  PageIteratorOptions
+	// whether the fetcher can run while the consumer processes a page
+	prefetch bool
+	// page being prefetched, nil if none
+	ahead chan pageResult
+	data  interface{}
+	info  *ListInfo
+	err   error
+	done  bool
+	total int
+	// items of the current page, to detect empty pages
+	pageItems int
+	seen      map[string]bool
+	stats     PaginationStats
+}
+
+func NewPageIterator(fetch PageFetcher, opts PageIteratorOptions) *PageIterator {
+	fetchData := func(page int) (*ListInfo, interface{}, error) {
+		info, err := fetch(page)
+		return info, nil, err
+	}
+	return &PageIterator{fetch: fetchData, opts: opts, seen: map[string]bool{}}
+}
+
+// Create an iterator whose pages carry their content, returned by Data, instead of
+// sharing it with the consumer through variables: pages can then be prefetched, see
+// PageIteratorOptions.Prefetch. fetch runs in the background, it must not call Keep
+// and must use a client of its own, see FlickrClient.Clone:
+//
+//	it := flickr.NewPrefetchPageIterator(func(page int) (*flickr.ListInfo, interface{}, error) {
+//		p := params
+//		p.Page = page
+//		resp, err := photos.Search(client.Clone(), &p)
+//		...
+//		return info, resp, nil
+//	}, flickr.PageIteratorOptions{Dedupe: true, Prefetch: true})
+//	for it.Next() {
+//		resp := it.Data().(*photos.SearchResponse)
+func NewPrefetchPageIterator(fetch PageDataFetcher, opts PageIteratorOptions) *PageIterator {
+	return &PageIterator{fetch: fetch, opts: opts, prefetch: opts.Prefetch, seen: map[string]bool{}}
+}
+
+// Fetch a page
+func (it *PageIterator) fetchPage(page int) pageResult {
+	info, data, err := it.fetch(page)
+	return pageResult{info: info, data: data, err: err}
+}
+
+// Return whether the quota allows a page to be prefetched
+func (it *PageIterator) quotaLeft() bool {
+	q := it.opts.Quota
+	return q == nil || q.Remaining() > q.Limit/10
+}
+
+// Fetch the next page, returns false once the list is exhausted or an error occurred,
+// see Err
+func (it *PageIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if it.info != nil && (it.pageItems == 0 || it.info.Page >= it.info.Pages) {
+		it.done = true
+		return false
+	}
+	page := 1
+	if it.info != nil {
+		page = it.info.Page + 1
+	}
+	// items may be recorded by fetch itself when the page is streamed
+	it.pageItems = 0
+	var res pageResult
+	if it.ahead != nil {
+		res = <-it.ahead
+		it.ahead = nil
+	} else {
+		res = it.fetchPage(page)
+	}
+	info, err := res.info, res.err
+	if err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+	if info == nil {
+		info = &ListInfo{}
+	}
+	if info.Page == 0 {
+		// not reported by every method
+		info.Page = page
+	}
+	if it.info != nil && info.Total != it.total {
+		it.stats.TotalChanged = true
+	}
+	it.info = info
+	it.data = res.data
+	it.total = info.Total
+	it.stats.Pages++
+
+	if it.prefetch && info.Page < info.Pages && it.quotaLeft() {
+		next := info.Page + 1
+		it.ahead = make(chan pageResult, 1)
+		go func(ahead chan pageResult) {
+			ahead <- it.fetchPage(next)
+		}(it.ahead)
+	}
+	return true
+}
+
+// Record an item of the current page, returns false if the item must be skipped
+// because it was already seen and Dedupe is set. Every item must be recorded, a page
+// without items ends the iteration.
+func (it *PageIterator) Keep(id string) bool {
+	it.pageItems++
+	it.stats.Items++
+	if !it.seen[id] {
+		it.seen[id] = true
+		return true
+	}
+	it.stats.Duplicates++
+	return !it.opts.Dedupe
+}
+
+// Return the content of the current page, as returned by the PageDataFetcher of the
+// iterator, nil for the iterators created with NewPageIterator
+func (it *PageIterator) Data() interface{} {
+	return it.data
+}
+
+// Return the pagination details of the current page
+func (it *PageIterator) Info() *ListInfo {
+	return it.info
+}
+
+// Return the error that stopped the iteration, if any
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// Return a summary of the pagination so far
+func (it *PageIterator) Stats() PaginationStats {
+	return it.stats
+}
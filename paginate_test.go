@@ -0,0 +1,127 @@
+package flickr
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Pages of 2 items, a photo is uploaded after the first page is fetched
+var shiftingPages = []struct {
+	ids   []string
+	pages int
+	total int
+}{
+	{[]string{"5", "4"}, 3, 5},
+	{[]string{"4", "3"}, 3, 6},
+	{[]string{"2", "1"}, 3, 6},
+}
+
+func walkShiftingPages(t *testing.T, opts PageIteratorOptions) ([]string, PaginationStats) {
+	var ids []string
+	it := NewPageIterator(func(page int) (*ListInfo, error) {
+		p := shiftingPages[page-1]
+		ids = p.ids
+		return &ListInfo{Page: page, Pages: p.pages, PerPage: 2, Total: p.total}, nil
+	}, opts)
+	kept := []string{}
+	for it.Next() {
+		for _, id := range ids {
+			if it.Keep(id) {
+				kept = append(kept, id)
+			}
+		}
+	}
+	Expect(t, it.Err(), nil)
+	return kept, it.Stats()
+}
+
+func TestPageIterator(t *testing.T) {
+	kept, stats := walkShiftingPages(t, PageIteratorOptions{Dedupe: true})
+	Expect(t, len(kept), 5)
+	Expect(t, stats, PaginationStats{Pages: 3, Items: 6, Duplicates: 1, TotalChanged: true})
+
+	kept, stats = walkShiftingPages(t, PageIteratorOptions{})
+	Expect(t, len(kept), 6)
+	Expect(t, stats.Duplicates, 1)
+}
+
+func TestPageIteratorStops(t *testing.T) {
+	// the list shrinks to an empty page before the announced number of pages
+	calls := 0
+	it := NewPageIterator(func(page int) (*ListInfo, error) {
+		calls++
+		return &ListInfo{Page: page, Pages: 5}, nil
+	}, PageIteratorOptions{})
+	for it.Next() {
+	}
+	Expect(t, calls, 1)
+
+	failure := errors.New("boom")
+	it = NewPageIterator(func(page int) (*ListInfo, error) {
+		return nil, failure
+	}, PageIteratorOptions{})
+	Expect(t, it.Next(), false)
+	Expect(t, it.Err(), failure)
+	Expect(t, it.Next(), false)
+}
+
+func TestPrefetchPageIterator(t *testing.T) {
+	fetched := make(chan int, 3)
+	it := NewPrefetchPageIterator(func(page int) (*ListInfo, interface{}, error) {
+		fetched <- page
+		p := shiftingPages[page-1]
+		return &ListInfo{Page: page, Pages: p.pages, PerPage: 2, Total: p.total}, p.ids, nil
+	}, PageIteratorOptions{Dedupe: true, Prefetch: true})
+
+	kept := []string{}
+	for it.Next() {
+		page := <-fetched
+		Expect(t, page, it.Info().Page)
+		if page < 3 {
+			// the next page is fetched while this one is processed
+			select {
+			case next := <-fetched:
+				Expect(t, next, page+1)
+				fetched <- next
+			case <-time.After(time.Second):
+				t.Fatalf("page %d was not prefetched", page+1)
+			}
+		}
+		for _, id := range it.Data().([]string) {
+			if it.Keep(id) {
+				kept = append(kept, id)
+			}
+		}
+	}
+	Expect(t, it.Err(), nil)
+	Expect(t, len(kept), 5)
+	Expect(t, it.Stats(), PaginationStats{Pages: 3, Items: 6, Duplicates: 1, TotalChanged: true})
+	Expect(t, len(fetched), 0)
+}
+
+func TestPrefetchPageIteratorQuota(t *testing.T) {
+	var mu sync.Mutex
+	calls := []int{}
+	quota := &QuotaTracker{Limit: 10}
+	for i := 0; i < 9; i++ {
+		quota.record()
+	}
+	it := NewPrefetchPageIterator(func(page int) (*ListInfo, interface{}, error) {
+		mu.Lock()
+		calls = append(calls, page)
+		mu.Unlock()
+		return &ListInfo{Page: page, Pages: 3}, nil, nil
+	}, PageIteratorOptions{Prefetch: true, Quota: quota})
+	Expect(t, it.Next(), true)
+	it.Keep("1")
+	time.Sleep(50 * time.Millisecond)
+	// a tenth of the quota is left, the next page is only fetched on demand
+	mu.Lock()
+	Expect(t, len(calls), 1)
+	mu.Unlock()
+	Expect(t, it.Next(), true)
+	Expect(t, it.Info().Page, 2)
+	Expect(t, it.Data(), nil)
+}
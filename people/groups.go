@@ -0,0 +1,107 @@
+package people
+
+import (
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/groups"
+)
+
+// Return the groups a user is a member of.
+// This method requires authentication with 'read' permission.
+func GetGroups(client *flickr.FlickrClient, userId string) (*groups.GroupsListResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.people.getGroups")
+	client.Args.Set("user_id", userId)
+	client.OAuthSign()
+
+	response := &groups.GroupsListResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Differences between the groups a user belongs to and the desired ones
+type GroupsDiff struct {
+	// desired groups the user is not a member of
+	Join []string
+	// groups the user is a member of but are not desired
+	Leave []string
+}
+
+// Compare the IDs of the groups a user belongs to with the desired ones
+func DiffGroups(current, desired []string) *GroupsDiff {
+	diff := &GroupsDiff{Join: []string{}, Leave: []string{}}
+
+	isCurrent := map[string]bool{}
+	for _, id := range current {
+		isCurrent[id] = true
+	}
+	isDesired := map[string]bool{}
+	for _, id := range desired {
+		if !isCurrent[id] && !isDesired[id] {
+			diff.Join = append(diff.Join, id)
+		}
+		isDesired[id] = true
+	}
+	for _, id := range current {
+		if !isDesired[id] {
+			diff.Leave = append(diff.Leave, id)
+		}
+	}
+	return diff
+}
+
+// Options for SyncGroups
+type SyncGroupsOptions struct {
+	// Leave the groups not in the desired list, by default they are left untouched
+	LeaveExtras bool
+	// Remove the user photos from the pools of the groups being left
+	DeletePhotos bool
+	// Accept the rules of the groups being joined
+	AcceptRules bool
+	// Compute the diff without joining or leaving any group
+	DryRun bool
+	// Set FailFast to stop at the first join or leave failing
+	flickr.BatchOptions
+}
+
+// Make the groups of the calling user match the desired list of group IDs: missing
+// groups are joined and, if requested, groups not in the list are left.
+// Returns the computed diff and the outcome of every join and leave, the error is non
+// nil when the current groups can't be retrieved or, with opts.FailFast, as soon as
+// a join or leave fails.
+// This method requires authentication with 'write' permission, 'delete' to leave groups.
+func SyncGroups(client *flickr.FlickrClient, userId string, desired []string, opts SyncGroupsOptions) (*GroupsDiff, *flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	resp, err := GetGroups(client, userId)
+	if err != nil {
+		return nil, result, err
+	}
+
+	current := []string{}
+	for _, g := range resp.Groups.Items {
+		current = append(current, g.GroupId())
+	}
+	diff := DiffGroups(current, desired)
+	if !opts.LeaveExtras {
+		for _, id := range diff.Leave {
+			result.Warn("not leaving group %s, LeaveExtras is not set", id)
+		}
+		diff.Leave = []string{}
+	}
+	if opts.DryRun {
+		return diff, result, nil
+	}
+
+	for _, id := range diff.Join {
+		_, err := groups.Join(client, id, opts.AcceptRules)
+		if err := result.Add(id, err, opts.BatchOptions); err != nil {
+			return diff, result, err
+		}
+	}
+	for _, id := range diff.Leave {
+		_, err := groups.Leave(client, id, opts.DeletePhotos)
+		if err := result.Add(id, err, opts.BatchOptions); err != nil {
+			return diff, result, err
+		}
+	}
+	return diff, result, nil
+}
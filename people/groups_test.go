@@ -0,0 +1,67 @@
+package people
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const peopleGroups = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <groups>
+    <group nsid="17274427@N00" name="Cream of the Crop" admin="0" eighteenplus="0" invitation_only="0" members="11935" pool_count="12522" />
+    <group nsid="34427465497@N01" name="GNEverybody" admin="1" eighteenplus="0" invitation_only="1" members="2214" pool_count="57" />
+  </groups>
+</rsp>`
+
+func TestGetGroups(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, peopleGroups, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetGroups(fclient, "123@N00")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(resp.Groups.Items), 2)
+	g := resp.Groups.Items[1]
+	flickr.Expect(t, g.GroupId(), "34427465497@N01")
+	flickr.Expect(t, g.Admin, true)
+	flickr.Expect(t, g.InvitationOnly, true)
+	flickr.Expect(t, g.Members, 2214)
+	flickr.Expect(t, g.PoolCount, 57)
+}
+
+func TestDiffGroups(t *testing.T) {
+	diff := DiffGroups([]string{"a", "b", "c"}, []string{"b", "d", "d", "e"})
+	flickr.Expect(t, strings.Join(diff.Join, ","), "d,e")
+	flickr.Expect(t, strings.Join(diff.Leave, ","), "a,c")
+}
+
+func TestSyncGroups(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.people.getGroups": peopleGroups,
+		"flickr.groups.join":      `<rsp stat="ok"></rsp>`,
+		"flickr.groups.leave":     `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	desired := []string{"17274427@N00", "999@N00"}
+	diff, result, err := SyncGroups(fclient, "123@N00", desired, SyncGroupsOptions{DryRun: true})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Warnings), 1)
+	flickr.Expect(t, strings.Join(diff.Join, ","), "999@N00")
+	flickr.Expect(t, len(diff.Leave), 0)
+	flickr.Expect(t, len(calls.Methods()), 1)
+
+	diff, result, err = SyncGroups(fclient, "123@N00", desired, SyncGroupsOptions{LeaveExtras: true, AcceptRules: true})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Succeeded), 2)
+	flickr.Expect(t, strings.Join(diff.Leave, ","), "34427465497@N01")
+	flickr.Expect(t, calls.Last("flickr.groups.join").Get("group_id"), "999@N00")
+	flickr.Expect(t, calls.Last("flickr.groups.join").Get("accept_rules"), "1")
+	flickr.Expect(t, calls.Last("flickr.groups.leave").Get("group_id"), "34427465497@N01")
+	flickr.Expect(t, calls.Last("flickr.groups.leave").Get("delete_photos"), "")
+}
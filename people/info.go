@@ -0,0 +1,83 @@
+package people
+
+import (
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Profile of a Flickr user
+type Person struct {
+	Id         string `xml:"id,attr"`
+	Nsid       string `xml:"nsid,attr"`
+	IsPro      bool   `xml:"ispro,attr"`
+	IsDeleted  bool   `xml:"is_deleted,attr"`
+	IconServer string `xml:"iconserver,attr"`
+	IconFarm   string `xml:"iconfarm,attr"`
+	PathAlias  string `xml:"path_alias,attr"`
+	Username   string `xml:"username"`
+	Realname   string `xml:"realname"`
+	Location   string `xml:"location"`
+	// Description of the user, HTML
+	Description string `xml:"description"`
+	PhotosUrl   string `xml:"photosurl"`
+	ProfileUrl  string `xml:"profileurl"`
+	Photos      struct {
+		FirstDateTaken string `xml:"firstdatetaken"`
+		// Unix timestamp of the first upload
+		FirstDate string `xml:"firstdate"`
+		Count     int    `xml:"count"`
+	} `xml:"photos"`
+}
+
+// Return the URL of the buddy icon of the user, the default icon if the user didn't
+// upload one, see flickr.BuddyIconURL
+func (p *Person) BuddyIconURL() string {
+	return flickr.BuddyIconURL(p.Nsid, p.IconServer, p.IconFarm)
+}
+
+type PersonInfoResponse struct {
+	flickr.BasicResponse
+	Person Person `xml:"person"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PersonInfoResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Get the profile of a user
+// This method does not require authentication.
+func GetInfo(client *flickr.FlickrClient, userId string) (*PersonInfoResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.people.getInfo")
+	client.Args.Set("user_id", userId)
+	client.ApiSign()
+
+	response := &PersonInfoResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Resolve the buddy icon URLs of many users through people.getInfo, keyed by user
+// ID. Every user is looked up once even if listed several times, users that can't be
+// found are reported as failures and left out.
+// This method does not require authentication.
+func BuddyIconURLs(client *flickr.FlickrClient, userIds []string, opts flickr.BatchOptions) (map[string]string, *flickr.BatchResult, error) {
+	ret := map[string]string{}
+	result := flickr.NewBatchResult()
+	seen := map[string]bool{}
+	for _, id := range userIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		resp, err := GetInfo(client, id)
+		if err == nil {
+			ret[id] = resp.Person.BuddyIconURL()
+		}
+		if err := result.Add(id, err, opts); err != nil {
+			return ret, result, err
+		}
+	}
+	return ret, result, nil
+}
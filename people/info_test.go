@@ -0,0 +1,86 @@
+package people
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestGetInfo(t *testing.T) {
+	body := `<rsp stat="ok">
+  <person id="12037949754@N01" nsid="12037949754@N01" ispro="1" iconserver="122" iconfarm="1" path_alias="bees">
+    <username>bees</username>
+    <realname>Cal Henderson</realname>
+    <photosurl>https://www.flickr.com/photos/bees/</photosurl>
+    <photos><firstdate>1071510391</firstdate><count>449</count></photos>
+  </person>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, body, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetInfo(fclient, "12037949754@N01")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Person.Username, "bees")
+	flickr.Expect(t, resp.Person.IsPro, true)
+	flickr.Expect(t, resp.Person.Photos.Count, 449)
+	flickr.Expect(t, resp.Person.BuddyIconURL(), "https://farm1.staticflickr.com/122/buddyicons/12037949754@N01.jpg")
+}
+
+func TestBuddyIconURLs(t *testing.T) {
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		switch id := r.FormValue("user_id"); id {
+		case "1@N00":
+			fmt.Fprint(w, `<rsp stat="ok"><person nsid="1@N00" iconserver="65535" iconfarm="66" /></rsp>`)
+		case "2@N00":
+			fmt.Fprint(w, `<rsp stat="ok"><person nsid="2@N00" iconserver="0" iconfarm="0" /></rsp>`)
+		default:
+			fmt.Fprint(w, `<rsp stat="fail"><err code="1" msg="User not found" /></rsp>`)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	urls, result, err := BuddyIconURLs(fclient, []string{"1@N00", "2@N00", "1@N00", "3@N00"}, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, lookups, 3)
+	flickr.Expect(t, len(urls), 2)
+	flickr.Expect(t, urls["1@N00"], "https://farm66.staticflickr.com/65535/buddyicons/1@N00.jpg")
+	flickr.Expect(t, urls["2@N00"], flickr.DEFAULT_BUDDYICON_URL)
+	flickr.Expect(t, len(result.Failed), 1)
+	flickr.Expect(t, result.Failed[0].Item, "3@N00")
+}
+
+func TestGetLimits(t *testing.T) {
+	body := `<rsp stat="ok">
+  <person nsid="30135021@N05">
+    <photos maxdisplaypx="1024" maxupload="15728640" />
+    <videos maxduration="90" maxupload="157286400" />
+  </person>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.people.getLimits": body,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetLimits(fclient)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.people.getLimits") != nil, true)
+	flickr.Expect(t, resp.Person.Photos.MaxDisplayPx, 1024)
+	limits := resp.UploadLimits()
+	flickr.Expect(t, limits.MaxPhotoSize, int64(15728640))
+	flickr.Expect(t, limits.MaxVideoSize, int64(157286400))
+	flickr.Expect(t, limits.MaxVideoDuration, 90*time.Second)
+}
@@ -0,0 +1,52 @@
+package people
+
+import (
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+type LimitsResponse struct {
+	flickr.BasicResponse
+	Person struct {
+		Nsid   string `xml:"nsid,attr"`
+		Photos struct {
+			// Longest side of the photos shown to others, in pixels, 0 when unlimited
+			MaxDisplayPx int `xml:"maxdisplaypx,attr"`
+			// Maximum size of a photo file, in bytes
+			MaxUpload int64 `xml:"maxupload,attr"`
+		} `xml:"photos"`
+		Videos struct {
+			// Maximum duration of a video, in seconds
+			MaxDuration int `xml:"maxduration,attr"`
+			// Maximum size of a video file, in bytes
+			MaxUpload int64 `xml:"maxupload,attr"`
+		} `xml:"videos"`
+	} `xml:"person"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r LimitsResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the limits to check uploads against, see flickr.WithUploadLimits
+func (r *LimitsResponse) UploadLimits() *flickr.UploadLimits {
+	return &flickr.UploadLimits{
+		MaxPhotoSize:     r.Person.Photos.MaxUpload,
+		MaxVideoSize:     r.Person.Videos.MaxUpload,
+		MaxVideoDuration: time.Duration(r.Person.Videos.MaxDuration) * time.Second,
+	}
+}
+
+// Get the upload limits of the calling user.
+// This method requires authentication with 'read' permission.
+func GetLimits(client *flickr.FlickrClient) (*LimitsResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.people.getLimits")
+	client.OAuthSign()
+
+	response := &LimitsResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
@@ -2,7 +2,6 @@ package people
 
 import (
 	"fmt"
-	"strconv"
 
 	"gopkg.in/masci/flickr.v2"
 )
@@ -28,14 +27,18 @@ type PhotoList struct {
 		HeightO int    `xml:"height_o,attr"`
 		WidthO  int    `xml:"width_o,attr"`
 
-		Description    string `xml:"description,attr"`
-		License        string `xml:"license,attr"`
-		DateUpload     string `xml:"date_upload,attr"`
-		DateTaken      string `xml:"date_taken,attr"`
-		OwnerName      string `xml:"owner_name,attr"`
-		IconServer     string `xml:"icon_server,attr"`
-		OriginalFormat string `xml:"original_format",attr`
-		LastUpdate     string `xml:"last_udpate",attr`
+		Description string `xml:"description,attr"`
+		License     string `xml:"license,attr"`
+		DateUpload  string `xml:"date_upload,attr"`
+		DateTaken   string `xml:"date_taken,attr"`
+		OwnerName   string `xml:"owner_name,attr"`
+		IconServer  string `xml:"icon_server,attr"`
+		LastUpdate  string `xml:"lastupdate,attr"`
+
+		// Original format - these attributes are provided when
+		// extras contains "original_format"
+		OriginalFormat string `xml:"originalformat,attr"`
+		OriginalSecret string `xml:"originalsecret,attr"`
 
 		// Geo - these attributes are provided when extras contains "geo"
 		Latitude  string `xml:"latitude,attr"`
@@ -111,6 +114,11 @@ type PhotoListResponse struct {
 	Photos PhotoList `xml:"photos"`
 }
 
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PhotoListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
 type SafetyLevel int
 
 const (
@@ -164,7 +172,7 @@ func GetPhotos(client *flickr.FlickrClient,
 	client.Args.Set("method", "flickr.people.getPhotos")
 	client.Args.Set("user_id", userId)
 	if opts.SafeSearch != NoSafetySpecified {
-		client.Args.Set("safe_search", strconv.Itoa(int(opts.SafeSearch)))
+		client.SetInt("safe_search", int(opts.SafeSearch))
 	}
 	if opts.MinUploadDate != "" {
 		client.Args.Set("min_upload_date", opts.MinUploadDate)
@@ -179,16 +187,16 @@ func GetPhotos(client *flickr.FlickrClient,
 		client.Args.Set("max_taken_date", opts.MaxTakenDate)
 	}
 	if opts.ContentType != NoContentTypeSpecified {
-		client.Args.Set("content_type", strconv.Itoa(int(opts.ContentType)))
+		client.SetInt("content_type", int(opts.ContentType))
 	}
 	if opts.PrivacyFilter != NoPrivacyFilterSpecified {
-		client.Args.Set("privacy_filter", strconv.Itoa(int(opts.PrivacyFilter)))
+		client.SetInt("privacy_filter", int(opts.PrivacyFilter))
 	}
 	if opts.PerPage != 0 {
-		client.Args.Set("per_page", strconv.Itoa(opts.PerPage))
+		client.SetInt("per_page", opts.PerPage)
 	}
 	if opts.Page != 0 {
-		client.Args.Set("page", strconv.Itoa(opts.Page))
+		client.SetInt("page", opts.Page)
 	}
 	if opts.Extras != "" {
 		client.Args.Set("extras", opts.Extras)
@@ -0,0 +1,79 @@
+package people
+
+import (
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/photos"
+)
+
+// Response type of GetPhotosOf
+type PhotosOfResponse struct {
+	flickr.BasicResponse
+	Photos struct {
+		Page    int `xml:"page,attr"`
+		PerPage int `xml:"perpage,attr"`
+		// getPhotosOf doesn't report the number of pages, only whether there are more
+		HasNextPage bool                 `xml:"has_next_page,attr"`
+		Items       []photos.SearchPhoto `xml:"photo"`
+	} `xml:"photos"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PhotosOfResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return a page of the photos containing a user, "me" for the calling user.
+// ownerId optionally restricts the results to the photos of a given owner, extras is
+// an optional comma separated list of extra fields. Private photos are only returned
+// with authenticate set.
+// This method does not require authentication.
+func GetPhotosOf(client *flickr.FlickrClient, authenticate bool, userId, ownerId, extras string, page, perPage int) (*PhotosOfResponse, error) {
+	if err := flickr.ValidatePerPage(perPage); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.people.getPhotosOf")
+	client.Args.Set("user_id", userId)
+	if ownerId != "" {
+		client.Args.Set("owner_id", ownerId)
+	}
+	if extras != "" {
+		client.Args.Set("extras", extras)
+	}
+	if page > 0 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+	if authenticate {
+		client.OAuthSign()
+	} else {
+		client.ApiSign()
+	}
+
+	response := &PhotosOfResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Call fn for every photo containing a user, going through all the pages of
+// GetPhotosOf. Returning an error from fn stops the iteration.
+// This method does not require authentication.
+func EachPhotoOf(client *flickr.FlickrClient, authenticate bool, userId, ownerId, extras string, fn func(*photos.SearchPhoto) error) error {
+	for page := 1; ; page++ {
+		resp, err := GetPhotosOf(client, authenticate, userId, ownerId, extras, page, flickr.MaxPerPage)
+		if err != nil {
+			return err
+		}
+		for i := range resp.Photos.Items {
+			if err := fn(&resp.Photos.Items[i]); err != nil {
+				return err
+			}
+		}
+		if !resp.Photos.HasNextPage || len(resp.Photos.Items) == 0 {
+			return nil
+		}
+	}
+}
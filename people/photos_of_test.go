@@ -0,0 +1,68 @@
+package people
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/photos"
+)
+
+const photosOf = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photos page="1" has_next_page="1" perpage="2">
+    <photo id="2733" owner="12037949754@N01" secret="123456" server="12" farm="1" title="test_04" ispublic="1" isfriend="0" isfamily="0" date_upload="1500000000" />
+    <photo id="2734" owner="12037949754@N01" secret="123456" server="12" farm="1" title="test_05" ispublic="0" isfriend="1" isfamily="0" />
+  </photos>
+</rsp>`
+
+func TestGetPhotosOf(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.people.getPhotosOf": photosOf,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetPhotosOf(fclient, true, "me", "12037949754@N01", "date_upload", 2, 2)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Photos.HasNextPage, true)
+	flickr.Expect(t, len(resp.Photos.Items), 2)
+	flickr.Expect(t, resp.Photos.Items[1].Title, "test_05")
+	flickr.Expect(t, resp.Photos.Items[1].IsFriend, true)
+
+	args := calls.Last("flickr.people.getPhotosOf")
+	flickr.Expect(t, args.Get("user_id"), "me")
+	flickr.Expect(t, args.Get("owner_id"), "12037949754@N01")
+	flickr.Expect(t, args.Get("extras"), "date_upload")
+	flickr.Expect(t, args.Get("page"), "2")
+	flickr.Expect(t, args.Get("per_page"), "2")
+	flickr.Expect(t, args.Get("oauth_signature") != "", true)
+
+	_, err = GetPhotosOf(fclient, false, "me", "", "", 1, flickr.MaxPerPage+1)
+	flickr.Expect(t, err != nil, true)
+	flickr.Expect(t, len(calls.Methods()), 1)
+}
+
+func TestEachPhotoOf(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.people.getPhotosOf": photosOf,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	// the mock always reports a next page
+	stop := errors.New("stop")
+	seen := 0
+	err := EachPhotoOf(fclient, false, "123@N00", "", "", func(p *photos.SearchPhoto) error {
+		seen++
+		if seen == 3 {
+			return stop
+		}
+		return nil
+	})
+	flickr.Expect(t, err, stop)
+	flickr.Expect(t, calls.Last("flickr.people.getPhotosOf").Get("page"), "2")
+	flickr.Expect(t, calls.Last("flickr.people.getPhotosOf").Get("api_sig") != "", true)
+}
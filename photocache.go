@@ -0,0 +1,25 @@
+package flickr
+
+// A cache of data derived from the file of photos, e.g. their sizes and URLs, which
+// change along with the photo secret when the file is rotated or replaced.
+// Implementations must be safe for concurrent use.
+type PhotoCache interface {
+	// Drop what's known about a photo
+	InvalidatePhoto(photoId string)
+}
+
+// Register a cache invalidated by the wrappers changing the file of a photo, e.g.
+// photos.Rotate, so that stale URLs aren't served. Can be given several times.
+func WithPhotoCache(cache PhotoCache) ClientOption {
+	return func(c *FlickrClient) {
+		c.PhotoCaches = append(c.PhotoCaches, cache)
+	}
+}
+
+// Invalidate a photo in every PhotoCache of the client. Call it after changing the
+// file of a photo without the wrappers of this library.
+func (c *FlickrClient) InvalidatePhoto(photoId string) {
+	for _, cache := range c.PhotoCaches {
+		cache.InvalidatePhoto(photoId)
+	}
+}
@@ -0,0 +1,41 @@
+package photos
+
+import (
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A comment left on a photo
+type Comment struct {
+	Id         string `xml:"id,attr"`
+	Author     string `xml:"author,attr"`
+	AuthorName string `xml:"authorname,attr"`
+	DateCreate string `xml:"datecreate,attr"`
+	Permalink  string `xml:"permalink,attr"`
+	Text       string `xml:",chardata"`
+}
+
+type CommentsListResponse struct {
+	flickr.BasicResponse
+	Comments struct {
+		PhotoId string    `xml:"photo_id,attr"`
+		Items   []Comment `xml:"comment"`
+	} `xml:"comments"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r CommentsListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the comments of a photo, oldest first
+// This method does not require authentication.
+func GetComments(client *flickr.FlickrClient, id string) (*CommentsListResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.photos.comments.getList")
+	client.Args.Set("photo_id", id)
+	client.ApiSign()
+
+	response := &CommentsListResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
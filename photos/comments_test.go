@@ -0,0 +1,27 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestGetComments(t *testing.T) {
+	body := `<rsp stat="ok"><comments photo_id="109722179">
+  <comment id="6065-109722179-72057594077818641" author="35468159852@N01" authorname="Rev Dan Catt" datecreate="1141841470" permalink="http://www.flickr.com/photos/straup/109722179/#comment72057594077818641">Umm, I'm not sure, can I get back to you on that one?</comment>
+</comments></rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.comments.getList": body,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetComments(fclient, "109722179")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Comments.PhotoId, "109722179")
+	flickr.Expect(t, len(resp.Comments.Items), 1)
+	flickr.Expect(t, resp.Comments.Items[0].AuthorName, "Rev Dan Catt")
+	flickr.Expect(t, resp.Comments.Items[0].DateCreate, "1141841470")
+	flickr.Expect(t, calls.Last("flickr.photos.comments.getList").Get("photo_id"), "109722179")
+}
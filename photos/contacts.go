@@ -0,0 +1,87 @@
+package photos
+
+import (
+	"fmt"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Maximum number of photos returned by the contacts photos methods
+const MaxContactsPhotos = 50
+
+// Optional params of GetContactsPhotos and GetContactsPublicPhotos
+type ContactsPhotosParams struct {
+	// Number of photos to return, up to MaxContactsPhotos. Zero for the Flickr
+	// default (10), ignored with SinglePhoto.
+	Count int
+	// Only return photos from friends and family, excluding other contacts
+	JustFriends bool
+	// Only return the latest photo of each contact
+	SinglePhoto bool
+	// Include the photos of the user along with the ones of the contacts
+	IncludeSelf bool
+	// Comma separated list of extra fields
+	Extras string
+}
+
+// Set the args of a contacts photos call
+func (p *ContactsPhotosParams) setArgs(client *flickr.FlickrClient) error {
+	if p.Count < 0 || p.Count > MaxContactsPhotos {
+		return flickErr.NewError(flickErr.InvalidParamsError,
+			fmt.Sprintf("count must be between 1 and %d, got %d", MaxContactsPhotos, p.Count))
+	}
+	if p.Count > 0 {
+		client.SetInt("count", p.Count)
+	}
+	if p.JustFriends {
+		client.SetBool("just_friends", true)
+	}
+	if p.SinglePhoto {
+		client.SetBool("single_photo", true)
+	}
+	if p.IncludeSelf {
+		client.SetBool("include_self", true)
+	}
+	if p.Extras != "" {
+		client.Args.Set("extras", p.Extras)
+	}
+	return nil
+}
+
+// Return the latest photos of the contacts of the calling user, newest first.
+// params can be nil.
+// This method requires authentication with 'read' permission.
+func GetContactsPhotos(client *flickr.FlickrClient, params *ContactsPhotosParams) (*SearchResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.photos.getContactsPhotos")
+	if params != nil {
+		if err := params.setArgs(client); err != nil {
+			return nil, err
+		}
+	}
+	client.OAuthSign()
+
+	response := &SearchResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Return the latest public photos of the contacts of a user, newest first.
+// params can be nil.
+// This method does not require authentication.
+func GetContactsPublicPhotos(client *flickr.FlickrClient, userId string, params *ContactsPhotosParams) (*SearchResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.photos.getContactsPublicPhotos")
+	client.Args.Set("user_id", userId)
+	if params != nil {
+		if err := params.setArgs(client); err != nil {
+			return nil, err
+		}
+	}
+	client.ApiSign()
+
+	response := &SearchResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
@@ -0,0 +1,51 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+const contactsPhotos = `<rsp stat="ok"><photos>
+  <photo id="2801" owner="12037949629@N01" secret="123456" server="1" farm="1" username="Eric is the best" title="grease" />
+  <photo id="2499" owner="33853651809@N01" secret="123456" server="1" farm="1" username="cal18" title="36679_o" />
+</photos></rsp>`
+
+func TestGetContactsPhotos(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.getContactsPhotos":       contactsPhotos,
+		"flickr.photos.getContactsPublicPhotos": contactsPhotos,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetContactsPhotos(fclient, &ContactsPhotosParams{Count: 20, JustFriends: true, IncludeSelf: true, Extras: "media"})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(resp.Photos.Items), 2)
+	flickr.Expect(t, resp.Photos.Items[0].Username, "Eric is the best")
+	args := calls.Last("flickr.photos.getContactsPhotos")
+	flickr.Expect(t, args.Get("count"), "20")
+	flickr.Expect(t, args.Get("just_friends"), "1")
+	flickr.Expect(t, args.Get("single_photo"), "")
+	flickr.Expect(t, args.Get("include_self"), "1")
+	flickr.Expect(t, args.Get("extras"), "media")
+	flickr.Expect(t, args.Get("oauth_signature") != "", true)
+
+	resp, err = GetContactsPublicPhotos(fclient, "123@N00", &ContactsPhotosParams{SinglePhoto: true})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Photos.Items[1].Owner, "33853651809@N01")
+	args = calls.Last("flickr.photos.getContactsPublicPhotos")
+	flickr.Expect(t, args.Get("user_id"), "123@N00")
+	flickr.Expect(t, args.Get("single_photo"), "1")
+	flickr.Expect(t, args.Get("count"), "")
+	flickr.Expect(t, args.Get("api_sig") != "", true)
+
+	_, err = GetContactsPhotos(fclient, nil)
+	flickr.Expect(t, err, nil)
+
+	_, err = GetContactsPhotos(fclient, &ContactsPhotosParams{Count: 51})
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+	flickr.Expect(t, len(calls.Methods()), 3)
+}
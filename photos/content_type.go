@@ -0,0 +1,97 @@
+package photos
+
+import (
+	"strconv"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Set the content type of a photo, one of ContentPhotos, ContentScreenshots or
+// ContentOther.
+// This method requires authentication with 'write' permission.
+func SetContentType(client *flickr.FlickrClient, id string, contentType ContentType) (*flickr.BasicResponse, error) {
+	if contentType < ContentPhotos || contentType > ContentOther {
+		return nil, flickErr.NewError(flickErr.InvalidParamsError, "content type must be 1 (photo), 2 (screenshot) or 3 (other), got "+strconv.Itoa(int(contentType)))
+	}
+
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.setContentType")
+	client.Args.Set("photo_id", id)
+	client.SetInt("content_type", int(contentType))
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Options of SweepContentType
+type ContentSweepOptions struct {
+	// Search selecting the candidates, e.g. Tags: []string{"screenshot"}. ContentType
+	// and Media are overridden, UserId defaults to the calling user ("me")
+	Params SearchParams
+	// Current content type of the photos to sweep, ContentPhotos when zero
+	From ContentType
+	// Content type to assign, ContentPhotos, ContentScreenshots or ContentOther
+	To ContentType
+	// Further filter the candidates, e.g. on their original format. Every photo the
+	// search returns is reclassified when nil.
+	Match func(*SearchPhoto) bool
+	// List the photos to reclassify without changing them
+	DryRun bool
+	// Set FailFast to stop at the first photo failing
+	flickr.BatchOptions
+}
+
+// Find the photos classified as opts.From, photos by default, matching opts.Params
+// and opts.Match, and reclassify them as opts.To, e.g. to move the screenshots
+// uploaded as photos out of the photostream. Returns the photos found and the
+// outcome of every setContentType call, the error is non nil when the search fails
+// or, with opts.FailFast, as soon as a photo fails.
+// This method requires authentication with 'write' permission.
+func SweepContentType(client *flickr.FlickrClient, opts ContentSweepOptions) ([]SearchPhoto, *flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	if opts.From == ContentDefault {
+		opts.From = ContentPhotos
+	}
+	if opts.From < ContentPhotos || opts.From > ContentOther {
+		return nil, result, flickErr.NewError(flickErr.InvalidParamsError, "sweep source must be 1 (photo), 2 (screenshot) or 3 (other), got "+strconv.Itoa(int(opts.From)))
+	}
+	if opts.To < ContentPhotos || opts.To > ContentOther || opts.To == opts.From {
+		return nil, result, flickErr.NewError(flickErr.InvalidParamsError, "sweep target must be a content type other than the source, got "+strconv.Itoa(int(opts.To)))
+	}
+
+	params := opts.Params
+	if params.UserId == "" {
+		params.UserId = "me"
+	}
+	params.ContentType = opts.From
+	params.Media = MediaPhotos
+	params.Extras = withExtra(withExtra(params.Extras, "original_format"), "tags")
+
+	// collect every candidate first, reclassified photos leave the search results
+	// and would shift the following pages
+	found := []SearchPhoto{}
+	err := searchAll(client, params, func(p *SearchPhoto) {
+		if opts.Match == nil || opts.Match(p) {
+			found = append(found, *p)
+		}
+	})
+	if err != nil {
+		return nil, result, err
+	}
+	if opts.DryRun {
+		return found, result, nil
+	}
+
+	for i := range found {
+		p := &found[i]
+		_, err := SetContentType(client, p.Id, opts.To)
+		if err := result.AddPhoto(p.Id, p, err, opts.BatchOptions); err != nil {
+			return found, result, err
+		}
+	}
+	return found, result, nil
+}
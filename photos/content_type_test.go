@@ -0,0 +1,65 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+const contentTypePage = `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="2">
+  <photo id="1" owner="me" secret="a" server="2" title="Screen Shot 2024" originalformat="png" tags="screenshot" />
+  <photo id="2" owner="me" secret="b" server="2" title="beach" originalformat="jpg" tags="screenshot" />
+</photos></rsp>`
+
+func TestSetContentType(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.setContentType": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := SetContentType(fclient, "1", ContentScreenshots)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photos.setContentType").Get("content_type"), "2")
+
+	_, err = SetContentType(fclient, "1", ContentAll)
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+}
+
+func TestSweepContentType(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search":         contentTypePage,
+		"flickr.photos.setContentType": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	opts := ContentSweepOptions{
+		Params: SearchParams{Tags: []string{"screenshot"}},
+		To:     ContentScreenshots,
+		Match:  func(p *SearchPhoto) bool { return p.OriginalFormat == "png" },
+		DryRun: true,
+	}
+	found, result, err := SweepContentType(fclient, opts)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(found), 1)
+	flickr.Expect(t, found[0].Id, "1")
+	flickr.Expect(t, len(result.Succeeded), 0)
+	search := calls.Last("flickr.photos.search")
+	flickr.Expect(t, search.Get("content_type"), "1")
+	flickr.Expect(t, search.Get("user_id"), "me")
+	flickr.Expect(t, calls.Last("flickr.photos.setContentType") == nil, true)
+
+	opts.DryRun = false
+	_, result, err = SweepContentType(fclient, opts)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Succeeded), 1)
+	flickr.Expect(t, calls.Last("flickr.photos.setContentType").Get("photo_id"), "1")
+
+	opts.To = ContentPhotos
+	_, _, err = SweepContentType(fclient, opts)
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+}
@@ -0,0 +1,197 @@
+package photos
+
+import (
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Namespace and predicate of the machine tag recording the SHA-256 of the file of a
+// photo, see ChecksumTag
+const (
+	ChecksumNamespace = "checksum"
+	ChecksumPredicate = "sha256"
+)
+
+// Return the machine tag recording the hex SHA-256 of the file of a photo
+func ChecksumTag(sum string) MachineTag {
+	return MachineTag{Namespace: ChecksumNamespace, Predicate: ChecksumPredicate, Value: sum}
+}
+
+// Return the photo of the calling user tagged with the checksum of a file, see
+// ChecksumTag, nil if there's none.
+// This method requires authentication with 'read' permission.
+func FindByChecksum(client *flickr.FlickrClient, sum string) (*SearchPhoto, error) {
+	resp, err := Search(client, &SearchParams{
+		UserId:      "me",
+		MachineTags: []string{ChecksumTag(sum).String()},
+		Extras:      "machine_tags",
+		PerPage:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Photos.Items) == 0 {
+		return nil, nil
+	}
+	return &resp.Photos.Items[0], nil
+}
+
+// What UploadDeduped does with a file whose checksum is already on a photo
+type ConflictPolicy int
+
+const (
+	// Leave the existing photo alone and don't send the file
+	ConflictSkip ConflictPolicy = iota
+	// Replace the file of the existing photo, keeping its ID, metadata and comments
+	ConflictReplace
+	// Upload the file as a new photo anyway
+	ConflictUploadAnyway
+	// Update the title, description and tags of the existing photo from the upload
+	// params without sending the file
+	ConflictUpdateMetadata
+)
+
+// What UploadDeduped did with a file
+type UploadAction string
+
+const (
+	ActionUploaded        UploadAction = "uploaded"
+	ActionSkipped         UploadAction = "skipped"
+	ActionReplaced        UploadAction = "replaced"
+	ActionMetadataUpdated UploadAction = "metadata_updated"
+)
+
+// Options of UploadDeduped
+type DedupUploadOptions struct {
+	// Optional upload parameters, same as flickr.UploadFile
+	Params *flickr.UploadParams
+	// What to do with the files already uploaded, ConflictSkip by default
+	Policy ConflictPolicy
+	flickr.BatchOptions
+}
+
+// What happened to a file of UploadDeduped
+type DedupUploadOutcome struct {
+	Path string
+	// Hex SHA-256 of the file
+	Checksum string
+	// ID of the photo holding the file after the call, empty if the upload failed
+	PhotoId string
+	// ID of the photo found with the same checksum, empty if none
+	Existing string
+	Action   UploadAction
+	Err      error
+}
+
+// Upload a new file, tagging it with its checksum
+func uploadTagged(client *flickr.FlickrClient, path, sum string, params *flickr.UploadParams) (string, error) {
+	tag := ChecksumTag(sum).String()
+	if params == nil {
+		// keep the account defaults, the tag is added once the photo exists
+		resp, err := flickr.UploadFile(client, path, nil)
+		if err != nil {
+			return "", err
+		}
+		return resp.ID, AddTags(client, resp.ID, []string{tag})
+	}
+	tagged := *params
+	tagged.Tags = append(append([]string{}, params.Tags...), tag)
+	resp, err := flickr.UploadFile(client, path, &tagged)
+	if err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// Apply the upload params to an existing photo without sending the file
+func updateMetadata(client *flickr.FlickrClient, photoId string, params *flickr.UploadParams) error {
+	if params == nil {
+		return nil
+	}
+	if params.Title != "" || params.Description != "" {
+		// setMeta replaces both, keep the one the params don't set as it is
+		title, description := params.Title, params.Description
+		if title == "" || description == "" {
+			info, err := GetInfo(client, photoId, "")
+			if err != nil {
+				return err
+			}
+			if title == "" {
+				title = info.Photo.Title
+			}
+			if description == "" {
+				description = info.Photo.Description
+			}
+		}
+		if _, err := SetMeta(client, photoId, title, description); err != nil {
+			return err
+		}
+	}
+	if len(params.Tags) > 0 {
+		if _, err := MergeTags(client, photoId, params.Tags, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Process a single file of UploadDeduped
+func uploadDeduped(client *flickr.FlickrClient, path string, opts *DedupUploadOptions) *DedupUploadOutcome {
+	outcome := &DedupUploadOutcome{Path: path}
+	_, sum, err := hashFile(path)
+	if err != nil {
+		outcome.Err = err
+		return outcome
+	}
+	outcome.Checksum = sum
+
+	existing, err := FindByChecksum(client, sum)
+	if err != nil {
+		outcome.Err = err
+		return outcome
+	}
+	if existing == nil || opts.Policy == ConflictUploadAnyway {
+		outcome.Action = ActionUploaded
+		if existing != nil {
+			outcome.Existing = existing.Id
+		}
+		outcome.PhotoId, outcome.Err = uploadTagged(client, path, sum, opts.Params)
+		return outcome
+	}
+
+	outcome.Existing = existing.Id
+	outcome.PhotoId = existing.Id
+	switch opts.Policy {
+	case ConflictReplace:
+		outcome.Action = ActionReplaced
+		_, outcome.Err = flickr.ReplaceFile(client, existing.Id, path)
+	case ConflictUpdateMetadata:
+		outcome.Action = ActionMetadataUpdated
+		outcome.Err = updateMetadata(client, existing.Id, opts.Params)
+	default:
+		outcome.Action = ActionSkipped
+	}
+	return outcome
+}
+
+// Upload files, looking first for a photo of the calling user already holding each
+// of them: files are identified by the SHA-256 of their content, recorded as a
+// checksum machine tag (see ChecksumTag) on the photos uploaded by this method.
+// opts.Policy tells what to do with the files found on Flickr. Every file gets an
+// outcome, in the same order as paths, and the batch result records the action
+// chosen for each path in its Actions.
+// This method requires authentication with 'write' permission.
+func UploadDeduped(client *flickr.FlickrClient, paths []string, opts DedupUploadOptions) ([]*DedupUploadOutcome, *flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	outcomes := make([]*DedupUploadOutcome, 0, len(paths))
+	for _, path := range paths {
+		outcome := uploadDeduped(client, path, &opts)
+		outcomes = append(outcomes, outcome)
+		if outcome.Action != "" {
+			result.SetAction(path, string(outcome.Action))
+		}
+		if err := result.Add(path, outcome.Err, opts.BatchOptions); err != nil {
+			return outcomes, result, err
+		}
+	}
+	return outcomes, result, nil
+}
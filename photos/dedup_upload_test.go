@@ -0,0 +1,137 @@
+package photos
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const (
+	dedupNoMatch = `<rsp stat="ok"><photos page="1" pages="0" perpage="1" total="0"></photos></rsp>`
+	dedupMatch   = `<rsp stat="ok"><photos page="1" pages="1" perpage="1" total="1">
+<photo id="42" owner="me" secret="s" server="1" farm="1" title="dup" ispublic="1" isfriend="0" isfamily="0"
+	machine_tags="checksum:sha256=x" />
+</photos></rsp>`
+	dedupUploaded = `<rsp stat="ok"><photoid>7</photoid></rsp>`
+)
+
+func dedupFile(t *testing.T) (string, string) {
+	dir, err := ioutil.TempDir("", "dedup")
+	flickr.Expect(t, err, nil)
+	path := filepath.Join(dir, "a.jpg")
+	flickr.Expect(t, ioutil.WriteFile(path, []byte("content"), 0644), nil)
+	_, sum, err := hashFile(path)
+	flickr.Expect(t, err, nil)
+	return path, sum
+}
+
+func TestUploadDedupedNew(t *testing.T) {
+	path, sum := dedupFile(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search": dedupNoMatch,
+		"upload":               dedupUploaded,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	params := flickr.NewUploadParams()
+	params.Tags = []string{"holiday"}
+	outcomes, result, err := UploadDeduped(fclient, []string{path}, DedupUploadOptions{Params: params})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, outcomes[0].Action, ActionUploaded)
+	flickr.Expect(t, outcomes[0].PhotoId, "7")
+	flickr.Expect(t, outcomes[0].Checksum, sum)
+	flickr.Expect(t, result.Actions[path], "uploaded")
+	flickr.Expect(t, calls.Last("flickr.photos.search").Get("machine_tags"), "checksum:sha256="+sum)
+	flickr.Expect(t, calls.Last("upload").Get("tags"), "holiday checksum:sha256="+sum)
+	flickr.Expect(t, len(params.Tags), 1)
+}
+
+func TestUploadDedupedConflict(t *testing.T) {
+	path, _ := dedupFile(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search":  dedupMatch,
+		"flickr.photos.setMeta": `<rsp stat="ok"></rsp>`,
+		"flickr.photos.getInfo": `<rsp stat="ok"><photo id="42"><title>dup</title><description>kept</description><tags></tags></photo></rsp>`,
+		"flickr.photos.setTags": `<rsp stat="ok"></rsp>`,
+		"upload":                dedupUploaded,
+		"replace":               `<rsp stat="ok"><photoid secret="abc" originalsecret="def">42</photoid></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	params := flickr.NewUploadParams()
+	params.Title = "new title"
+	params.Tags = []string{"holiday"}
+
+	outcomes, result, err := UploadDeduped(fclient, []string{path}, DedupUploadOptions{Params: params})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, outcomes[0].Action, ActionSkipped)
+	flickr.Expect(t, outcomes[0].Existing, "42")
+	flickr.Expect(t, result.Actions[path], "skipped")
+	flickr.Expect(t, len(result.Succeeded), 1)
+	flickr.Expect(t, calls.Last("upload") == nil, true)
+
+	outcomes, _, err = UploadDeduped(fclient, []string{path}, DedupUploadOptions{Params: params, Policy: ConflictReplace})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, outcomes[0].Action, ActionReplaced)
+	flickr.Expect(t, calls.Last("replace").Get("photo_id"), "42")
+
+	outcomes, _, err = UploadDeduped(fclient, []string{path}, DedupUploadOptions{Params: params, Policy: ConflictUpdateMetadata})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, outcomes[0].Action, ActionMetadataUpdated)
+	flickr.Expect(t, calls.Last("flickr.photos.setMeta").Get("title"), "new title")
+	// the description the params don't set is left as it is
+	flickr.Expect(t, calls.Last("flickr.photos.setMeta").Get("description"), "kept")
+	flickr.Expect(t, calls.Last("flickr.photos.setTags").Get("tags"), "holiday")
+
+	described := flickr.NewUploadParams()
+	described.Description = "new description"
+	_, _, err = UploadDeduped(fclient, []string{path}, DedupUploadOptions{Params: described, Policy: ConflictUpdateMetadata})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photos.setMeta").Get("title"), "dup")
+	flickr.Expect(t, calls.Last("flickr.photos.setMeta").Get("description"), "new description")
+
+	outcomes, _, err = UploadDeduped(fclient, []string{path}, DedupUploadOptions{Params: params, Policy: ConflictUploadAnyway})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, outcomes[0].Action, ActionUploaded)
+	flickr.Expect(t, outcomes[0].PhotoId, "7")
+	flickr.Expect(t, outcomes[0].Existing, "42")
+}
+
+func TestUploadDedupedBatch(t *testing.T) {
+	path, _ := dedupFile(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	other := filepath.Join(filepath.Dir(path), "b.jpg")
+	flickr.Expect(t, ioutil.WriteFile(other, []byte("other content"), 0644), nil)
+
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search": dedupNoMatch,
+		"upload":               dedupUploaded,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+	signatures := &bytes.Buffer{}
+	fclient.SignatureDebug = signatures
+
+	outcomes, result, err := UploadDeduped(fclient, []string{path, other}, DedupUploadOptions{Params: flickr.NewUploadParams()})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(outcomes), 2)
+	flickr.Expect(t, len(result.Succeeded), 2)
+	flickr.Expect(t, strings.Join(calls.Methods(), ","), "flickr.photos.search,upload,flickr.photos.search,upload")
+	// the search following an upload is signed as the GET it is sent with
+	flickr.Expect(t, strings.Count(signatures.String(), "signature of GET flickr.photos.search"), 2)
+	flickr.Expect(t, strings.Contains(signatures.String(), "signature of POST flickr.photos.search"), false)
+}
@@ -0,0 +1,92 @@
+package photos
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Return the URL of the original file, empty if the original secret is not known
+// (Flickr only gives it to the owner and to users allowed to download the photo)
+func (p *PhotoInfo) OriginalURL() string {
+	return flickr.OriginalURL(p.Server, p.Id, p.OriginalSecret, p.OriginalFormat)
+}
+
+// Return whether the photo can be seen by anyone
+func (p *PhotoInfo) IsPrivate() bool {
+	return !p.Visibility.IsPublic
+}
+
+// Return the URL of the original file of a photo. Lists only include the original
+// secret when asked for the "original_format" extra, and for private photos the
+// secret is only disclosed to authenticated owners: when originalSecret is empty it
+// is fetched via getInfo with the client credentials.
+// Returns a flickErr.Error with code DownloadError if the original is not accessible.
+func ResolveOriginalURL(client *flickr.FlickrClient, photoId, server, originalSecret, originalFormat string) (string, error) {
+	if originalSecret != "" && server != "" {
+		return flickr.OriginalURL(server, photoId, originalSecret, originalFormat), nil
+	}
+
+	info, err := GetInfo(client, photoId, "")
+	if err != nil {
+		return "", err
+	}
+	return accessibleOriginalURL(&info.Photo)
+}
+
+// Return the URL of the original file of a photo or a DownloadError if not accessible
+func accessibleOriginalURL(photo *PhotoInfo) (string, error) {
+	url := photo.OriginalURL()
+	if url == "" {
+		msg := "original of photo " + photo.Id + " is not accessible"
+		if photo.IsPrivate() {
+			msg += ", private photos can only be downloaded by their owner"
+		}
+		return "", flickErr.NewError(flickErr.DownloadError, msg)
+	}
+	return url, nil
+}
+
+// Download the original file of a photo into w, resolving the original URL with
+// ResolveOriginalURL. Returns the number of bytes written.
+// This method requires authentication to download private photos.
+func DownloadOriginal(client *flickr.FlickrClient, photoId string, w io.Writer) (int64, error) {
+	url, err := ResolveOriginalURL(client, photoId, "", "", "")
+	if err != nil {
+		return 0, err
+	}
+	return flickr.Download(client, url, w)
+}
+
+// Download the original file of a photo into dir, naming it after the photo ID and
+// its original format (e.g. "52435165562.jpg"). Returns the path of the file, which
+// is removed if the download fails.
+// This method requires authentication to download private photos.
+func SaveOriginal(client *flickr.FlickrClient, photoId, dir string) (string, error) {
+	info, err := GetInfo(client, photoId, "")
+	if err != nil {
+		return "", err
+	}
+	url, err := accessibleOriginalURL(&info.Photo)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, photoId+flickr.FormatExtension(info.Photo.OriginalFormat))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	_, err = flickr.Download(client, url, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,87 @@
+package photos
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestResolveOriginalURL(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.getInfo": photoInfo,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	// known secret, no API call
+	url, err := ResolveOriginalURL(fclient, "1", "2", "3", "png")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, url, "https://live.staticflickr.com/2/1_3_o.png")
+	flickr.Expect(t, len(calls.Methods()), 0)
+
+	url, err = ResolveOriginalURL(fclient, "52435165562", "", "", "")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, url, "https://live.staticflickr.com/65535/52435165562_9_o.jpg")
+	flickr.Expect(t, calls.Last("flickr.photos.getInfo").Get("photo_id"), "52435165562")
+}
+
+func TestResolveOriginalURLPrivate(t *testing.T) {
+	body := `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photo id="1" secret="abc" server="65535" farm="66" media="photo">
+    <visibility ispublic="0" isfriend="1" isfamily="0" />
+  </photo>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, body, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := ResolveOriginalURL(fclient, "1", "", "", "")
+	ee, ok := err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, ee.ErrorCode, flickErr.DownloadError)
+	flickr.Expect(t, strings.Contains(ee.Message, "owner"), true)
+}
+
+func TestDownloadOriginal(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMockMethods(200, map[string]string{
+		"flickr.photos.getInfo":      photoInfo,
+		"/65535/52435165562_9_o.jpg": "jpeg data",
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	buf := &bytes.Buffer{}
+	_, err := DownloadOriginal(fclient, "52435165562", buf)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, buf.String(), "jpeg data\n")
+}
+
+func TestSaveOriginal(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMockMethods(200, map[string]string{
+		"flickr.photos.getInfo":      photoInfo,
+		"/65535/52435165562_9_o.jpg": "jpeg data",
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	dir, err := ioutil.TempDir("", "flickr")
+	flickr.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	path, err := SaveOriginal(fclient, "52435165562", dir)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, path, filepath.Join(dir, "52435165562.jpg"))
+	data, _ := ioutil.ReadFile(path)
+	flickr.Expect(t, string(data), "jpeg data\n")
+}
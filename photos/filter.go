@@ -0,0 +1,179 @@
+package photos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Who can see a photo, see Filter
+type Visibility string
+
+const (
+	VisibilityPublic Visibility = "public"
+	// Not public, visible to friends (and possibly family)
+	VisibilityFriends Visibility = "friends"
+	// Not public, visible to family (and possibly friends)
+	VisibilityFamily Visibility = "family"
+	// Visible to the owner only
+	VisibilityPrivate Visibility = "private"
+)
+
+// Layout of the dates taken returned by Flickr
+const dateTakenLayout = "2006-01-02 15:04:05"
+
+// Criteria selecting the photos a sync or a backup processes, evaluated locally on
+// the photos Flickr returns so that partial backups ("videos only, last 2 years")
+// work with every listing method, recentlyUpdated included. Zero fields select
+// everything, a photo must match every criterion set. Request the fields the filter
+// needs with Extras:
+//
+//	filter := &photos.Filter{Media: []photos.Media{photos.MediaVideos}, TakenAfter: time.Now().AddDate(-2, 0, 0)}
+//	params.Extras = filter.Extras()
+//	err := photos.SyncUploads(client, params, cursor, filter.Wrap(backup))
+type Filter struct {
+	// MediaPhotos and/or MediaVideos
+	Media []Media
+	// Keep the photos having at least one of IncludeTags and none of ExcludeTags,
+	// tags are compared in their clean form, see CleanTag
+	IncludeTags []string
+	ExcludeTags []string
+	// IDs of the photos of the albums to keep or to leave out, see
+	// photosets.AlbumPhotoIds. A nil InAlbums keeps photos in no album as well.
+	InAlbums    map[string]bool
+	NotInAlbums map[string]bool
+	// Ranges of upload dates and dates taken, bounds are inclusive and zero bounds are
+	// open. Dates taken have no time zone, they are compared with the wall clock of
+	// the bounds. Photos without date taken are left out when a TakenAfter or
+	// TakenBefore bound is set.
+	UploadedAfter  time.Time
+	UploadedBefore time.Time
+	TakenAfter     time.Time
+	TakenBefore    time.Time
+	Visibility     []Visibility
+}
+
+// Return the extras the filter needs, to add to the ones of the listing
+func (f *Filter) Extras() string {
+	extras := "media"
+	if len(f.IncludeTags) > 0 || len(f.ExcludeTags) > 0 {
+		extras = withExtra(extras, "tags")
+	}
+	if !f.UploadedAfter.IsZero() || !f.UploadedBefore.IsZero() {
+		extras = withExtra(extras, "date_upload")
+	}
+	if !f.TakenAfter.IsZero() || !f.TakenBefore.IsZero() {
+		extras = withExtra(extras, "date_taken")
+	}
+	return extras
+}
+
+// Return the visibility of a photo
+func visibilityOf(p *SearchPhoto) []Visibility {
+	switch {
+	case p.IsPublic:
+		return []Visibility{VisibilityPublic}
+	case !p.IsFriend && !p.IsFamily:
+		return []Visibility{VisibilityPrivate}
+	}
+	ret := []Visibility{}
+	if p.IsFriend {
+		ret = append(ret, VisibilityFriends)
+	}
+	if p.IsFamily {
+		ret = append(ret, VisibilityFamily)
+	}
+	return ret
+}
+
+// Return whether t is within the inclusive range [after, before], zero bounds being open
+func inRange(t, after, before time.Time) bool {
+	return (after.IsZero() || !t.Before(after)) && (before.IsZero() || !t.After(before))
+}
+
+// Return whether a photo matches the filter
+func (f *Filter) Match(p *SearchPhoto) bool {
+	if len(f.Media) > 0 {
+		// Flickr reports "photo" or "video", photo when the extra is missing
+		media := MediaPhotos
+		if p.Media == "video" {
+			media = MediaVideos
+		}
+		found := false
+		for _, m := range f.Media {
+			found = found || m == media || m == MediaAll
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.IncludeTags) > 0 || len(f.ExcludeTags) > 0 {
+		tags := map[string]bool{}
+		for _, t := range strings.Fields(p.Tags) {
+			tags[t] = true
+		}
+		if len(f.IncludeTags) > 0 {
+			found := false
+			for _, t := range f.IncludeTags {
+				found = found || tags[CleanTag(t)]
+			}
+			if !found {
+				return false
+			}
+		}
+		for _, t := range f.ExcludeTags {
+			if tags[CleanTag(t)] {
+				return false
+			}
+		}
+	}
+
+	if f.InAlbums != nil && !f.InAlbums[p.Id] {
+		return false
+	}
+	if f.NotInAlbums[p.Id] {
+		return false
+	}
+
+	if !f.UploadedAfter.IsZero() || !f.UploadedBefore.IsZero() {
+		upload, err := strconv.ParseInt(p.DateUpload, 10, 64)
+		if err != nil || !inRange(time.Unix(upload, 0), f.UploadedAfter, f.UploadedBefore) {
+			return false
+		}
+	}
+	if !f.TakenAfter.IsZero() || !f.TakenBefore.IsZero() {
+		loc := f.TakenAfter.Location()
+		if f.TakenAfter.IsZero() {
+			loc = f.TakenBefore.Location()
+		}
+		taken, err := time.ParseInLocation(dateTakenLayout, p.DateTaken, loc)
+		if err != nil || !inRange(taken, f.TakenAfter, f.TakenBefore) {
+			return false
+		}
+	}
+
+	if len(f.Visibility) > 0 {
+		found := false
+		for _, v := range visibilityOf(p) {
+			for _, want := range f.Visibility {
+				found = found || v == want
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Return a function calling fn for the photos matching the filter only, to pass to
+// SyncUploads, SyncUpdates, SearchAll and the like
+func (f *Filter) Wrap(fn func(*SearchPhoto) error) func(*SearchPhoto) error {
+	return func(p *SearchPhoto) error {
+		if !f.Match(p) {
+			return nil
+		}
+		return fn(p)
+	}
+}
@@ -0,0 +1,91 @@
+package photos
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestFilterMatch(t *testing.T) {
+	video := &SearchPhoto{Id: "1", Media: "video", Tags: "beach family", DateUpload: "1700000000", DateTaken: "2023-11-14 10:00:00", IsFamily: true}
+	photo := &SearchPhoto{Id: "2", Media: "photo", Tags: "beach", DateUpload: "1500000000", DateTaken: "2017-07-14 02:40:00", IsPublic: true}
+	private := &SearchPhoto{Id: "3", Media: "photo", DateTaken: "2023-01-01 00:00:00"}
+
+	match := func(f *Filter) string {
+		ids := []string{}
+		for _, p := range []*SearchPhoto{video, photo, private} {
+			if f.Match(p) {
+				ids = append(ids, p.Id)
+			}
+		}
+		return strings.Join(ids, ",")
+	}
+
+	flickr.Expect(t, match(&Filter{}), "1,2,3")
+	flickr.Expect(t, match(&Filter{Media: []Media{MediaVideos}}), "1")
+	flickr.Expect(t, match(&Filter{Media: []Media{MediaPhotos}}), "2,3")
+	flickr.Expect(t, match(&Filter{IncludeTags: []string{"Beach"}}), "1,2")
+	flickr.Expect(t, match(&Filter{IncludeTags: []string{"beach"}, ExcludeTags: []string{"family"}}), "2")
+	flickr.Expect(t, match(&Filter{InAlbums: map[string]bool{"2": true, "3": true}}), "2,3")
+	flickr.Expect(t, match(&Filter{NotInAlbums: map[string]bool{"2": true}}), "1,3")
+	flickr.Expect(t, match(&Filter{UploadedAfter: time.Unix(1600000000, 0)}), "1")
+	flickr.Expect(t, match(&Filter{UploadedBefore: time.Unix(1500000000, 0)}), "2")
+	flickr.Expect(t, match(&Filter{TakenAfter: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}), "1,3")
+	flickr.Expect(t, match(&Filter{TakenBefore: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}), "2,3")
+	flickr.Expect(t, match(&Filter{Visibility: []Visibility{VisibilityFamily, VisibilityPrivate}}), "1,3")
+	flickr.Expect(t, match(&Filter{Visibility: []Visibility{VisibilityPublic}}), "2")
+
+	// videos of the last 2 years
+	f := &Filter{Media: []Media{MediaVideos}, TakenAfter: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+	flickr.Expect(t, match(f), "1")
+	flickr.Expect(t, f.Extras(), "media,date_taken")
+	flickr.Expect(t, (&Filter{ExcludeTags: []string{"x"}, UploadedAfter: time.Unix(1, 0)}).Extras(), "media,tags,date_upload")
+}
+
+func TestFilterWrap(t *testing.T) {
+	f := &Filter{Media: []Media{MediaVideos}}
+	ids := []string{}
+	fn := f.Wrap(func(p *SearchPhoto) error {
+		ids = append(ids, p.Id)
+		return nil
+	})
+	fn(&SearchPhoto{Id: "1", Media: "photo"})
+	fn(&SearchPhoto{Id: "2", Media: "video"})
+	flickr.Expect(t, strings.Join(ids, ","), "2")
+}
+
+func TestMirrorFilter(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	bodies := map[string]string{
+		"flickr.photos.recentlyUpdated": `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="2">
+  <photo id="1" secret="a" server="2" title="beach" media="video" tags="keep" lastupdate="100" />
+  <photo id="2" secret="b" server="2" title="sunset" media="photo" tags="keep" lastupdate="200" />
+</photos></rsp>`,
+	}
+	server, client, calls := flickr.FlickrMockRecorder(200, bodies)
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	m, err := OpenMirror(flickr.NewMemoryStore(), "mirror")
+	flickr.Expect(t, err, nil)
+	m.Filter = &Filter{Media: []Media{MediaVideos}, IncludeTags: []string{"keep"}}
+	events := []string{}
+	record := func(e *MirrorEvent) error {
+		events = append(events, string(e.Kind)+" "+e.Id)
+		return nil
+	}
+	flickr.Expect(t, m.Update(fclient, record), nil)
+	flickr.Expect(t, strings.Join(events, ","), "added 1")
+	flickr.Expect(t, strings.Contains(calls.Last("flickr.photos.recentlyUpdated").Get("extras"), "media"), true)
+
+	// the video lost its tag
+	bodies["flickr.photos.recentlyUpdated"] = `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="1">
+  <photo id="1" secret="a" server="2" title="beach" media="video" lastupdate="300" />
+</photos></rsp>`
+	events = nil
+	flickr.Expect(t, m.Update(fclient, record), nil)
+	flickr.Expect(t, strings.Join(events, ","), "deleted 1")
+	flickr.Expect(t, len(m.Photos), 0)
+}
@@ -0,0 +1,128 @@
+package photos
+
+import (
+	"sort"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// The place a photo was taken at, as identified by Flickr Places or Yahoo WOE IDs.
+// Either PlaceId or WoeId must be set.
+type PlaceRef struct {
+	PlaceId string
+	WoeId   string
+}
+
+// Set the place_id and woe_id args, failing if both are empty
+func (p PlaceRef) setArgs(client *flickr.FlickrClient) error {
+	if p.PlaceId == "" && p.WoeId == "" {
+		return flickErr.NewError(flickErr.InvalidParamsError, "either place_id or woe_id must be set")
+	}
+	if p.PlaceId != "" {
+		client.Args.Set("place_id", p.PlaceId)
+	}
+	if p.WoeId != "" {
+		client.Args.Set("woe_id", p.WoeId)
+	}
+	return nil
+}
+
+// Correct the place of a photo, e.g. after a reverse geocoding pass found the
+// coordinates were resolved to the wrong neighbourhood. foursquareId is optional,
+// any venue already set on the photo is removed if empty.
+// This method requires authentication with 'write' permission.
+func CorrectLocation(client *flickr.FlickrClient, id string, place PlaceRef, foursquareId string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.geo.correctLocation")
+	client.Args.Set("photo_id", id)
+	if err := place.setArgs(client); err != nil {
+		return nil, err
+	}
+	if foursquareId != "" {
+		client.Args.Set("foursquare_id", foursquareId)
+	}
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Correct the place of all the photos of the calling user at the given location
+// and accuracy (1 to 16), in a single call
+// This method requires authentication with 'write' permission.
+func BatchCorrectLocation(client *flickr.FlickrClient, lat, lon float64, accuracy int, place PlaceRef) (*flickr.BasicResponse, error) {
+	if err := flickr.ValidateLocation(lat, lon, accuracy); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.geo.batchCorrectLocation")
+	client.SetFloat("lat", lat)
+	client.SetFloat("lon", lon)
+	client.SetInt("accuracy", accuracy)
+	if err := place.setArgs(client); err != nil {
+		return nil, err
+	}
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Correct the place of many photos, corrections maps photo IDs to their place
+// This method requires authentication with 'write' permission.
+func CorrectLocations(client *flickr.FlickrClient, corrections map[string]PlaceRef, opts flickr.BatchOptions) (*flickr.BatchResult, error) {
+	ids := make([]string, 0, len(corrections))
+	for id := range corrections {
+		ids = append(ids, id)
+	}
+	// process photos in a predictable order
+	sort.Strings(ids)
+
+	result := flickr.NewBatchResult()
+	for _, id := range ids {
+		_, err := CorrectLocation(client, id, corrections[id], "")
+		if err := result.Add(id, err, opts); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// Return the photos of the calling user taken at a location, at the given accuracy
+// (1 to 16). extras is a comma separated list of extra fields to fetch for each photo.
+// This method requires authentication with 'read' permission.
+func PhotosForLocation(client *flickr.FlickrClient, lat, lon float64, accuracy int, extras string, page, perPage int) (*SearchResponse, error) {
+	if err := flickr.ValidateLocation(lat, lon, accuracy); err != nil {
+		return nil, err
+	}
+	if err := flickr.ValidatePerPage(perPage); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.photos.geo.photosForLocation")
+	client.SetFloat("lat", lat)
+	client.SetFloat("lon", lon)
+	client.SetInt("accuracy", accuracy)
+	if extras != "" {
+		client.Args.Set("extras", extras)
+	}
+	// if not provided, flickr defaults this argument to 1
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+	client.OAuthSign()
+
+	response := &SearchResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
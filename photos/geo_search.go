@@ -0,0 +1,117 @@
+package photos
+
+import (
+	"fmt"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Units of the radius of a geo search
+type RadiusUnits string
+
+const (
+	RadiusKm    RadiusUnits = "km"
+	RadiusMiles RadiusUnits = "mi"
+)
+
+// Largest radius accepted by Flickr for radial searches
+const (
+	MaxRadiusKm    = 32
+	MaxRadiusMiles = 20
+)
+
+// Parameters of GeoSearch: the ones of a regular search restricted to an area, which
+// is either a circle (Lat, Lon and Radius), a Flickr place (PlaceId), a Yahoo WOE
+// ID (WoeId) or a bounding box (SearchParams.BBox). Exactly one must be set.
+type GeoSearchParams struct {
+	SearchParams
+	// Center of a radial search, only used when Radius is set
+	Lat float64
+	Lon float64
+	// Radius of the circle, at most MaxRadiusKm or MaxRadiusMiles
+	Radius float64
+	// RadiusKm when empty
+	RadiusUnits RadiusUnits
+	PlaceId     string
+	WoeId       string
+	// Recorded accuracy of the photos, from 1 (world level) to 16 (street level),
+	// zero means Flickr default (16)
+	Accuracy int
+}
+
+// Check the parameters without hitting the API, the returned error is a
+// flickErr.Error with code InvalidParamsError
+func (p *GeoSearchParams) Validate() error {
+	var invalid = func(format string, a ...interface{}) error {
+		return flickErr.NewError(flickErr.InvalidParamsError, fmt.Sprintf(format, a...))
+	}
+
+	if err := p.SearchParams.validate(false); err != nil {
+		return err
+	}
+
+	areas := 0
+	for _, set := range []bool{p.Radius != 0, p.PlaceId != "", p.WoeId != "", p.BBox != ""} {
+		if set {
+			areas++
+		}
+	}
+	if areas != 1 {
+		return invalid("exactly one of radius, place_id, woe_id or bbox must be set")
+	}
+	if p.Radius != 0 {
+		// accuracy is checked below, any valid one will do here
+		if err := flickr.ValidateLocation(p.Lat, p.Lon, 1); err != nil {
+			return err
+		}
+		units, max := p.RadiusUnits, float64(MaxRadiusKm)
+		switch units {
+		case "":
+			units = RadiusKm
+		case RadiusKm:
+		case RadiusMiles:
+			max = MaxRadiusMiles
+		default:
+			return invalid("radius_units must be \"km\" or \"mi\", got %q", p.RadiusUnits)
+		}
+		if p.Radius < 0 || p.Radius > max {
+			return invalid("radius must be greater than 0 and at most %g%s, got %g", max, units, p.Radius)
+		}
+	}
+	if p.Accuracy < 0 || p.Accuracy > 16 {
+		return invalid("accuracy must be between 1 and 16, got %d", p.Accuracy)
+	}
+	return nil
+}
+
+// Set the geo params
+func (p *GeoSearchParams) setArgs(client *flickr.FlickrClient) {
+	if p.Radius != 0 {
+		client.SetFloat("lat", p.Lat)
+		client.SetFloat("lon", p.Lon)
+		client.SetFloat("radius", p.Radius)
+		if p.RadiusUnits != "" {
+			client.Args.Set("radius_units", string(p.RadiusUnits))
+		}
+	}
+	if p.PlaceId != "" {
+		client.Args.Set("place_id", p.PlaceId)
+	}
+	if p.WoeId != "" {
+		client.Args.Set("woe_id", p.WoeId)
+	}
+	if p.Accuracy > 0 {
+		client.SetInt("accuracy", p.Accuracy)
+	}
+	client.SetBool("has_geo", true)
+}
+
+// Search geotagged photos within an area, params are validated before performing
+// the request. Errors are the same as Search.
+func GeoSearch(client *flickr.FlickrClient, params *GeoSearchParams) (*SearchResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return search(client, &params.SearchParams, params.setArgs)
+}
@@ -0,0 +1,58 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestGeoSearchParamsValidate(t *testing.T) {
+	// the equator is a valid center, no other criteria are needed
+	flickr.Expect(t, (&GeoSearchParams{Radius: 5}).Validate(), nil)
+	flickr.Expect(t, (&GeoSearchParams{Lat: 45.5, Lon: 9.2, Radius: 20, RadiusUnits: RadiusMiles}).Validate(), nil)
+	flickr.Expect(t, (&GeoSearchParams{WoeId: "718345", Accuracy: 11}).Validate(), nil)
+	flickr.Expect(t, (&GeoSearchParams{SearchParams: SearchParams{BBox: "9,45,10,46", Text: "duomo"}}).Validate(), nil)
+
+	expectInvalidParams(t, (&GeoSearchParams{}).Validate())
+	expectInvalidParams(t, (&GeoSearchParams{Radius: 5, WoeId: "718345"}).Validate())
+	expectInvalidParams(t, (&GeoSearchParams{Radius: 33}).Validate())
+	expectInvalidParams(t, (&GeoSearchParams{Radius: 21, RadiusUnits: RadiusMiles}).Validate())
+	expectInvalidParams(t, (&GeoSearchParams{Radius: -1}).Validate())
+	expectInvalidParams(t, (&GeoSearchParams{Radius: 1, RadiusUnits: "ft"}).Validate())
+	expectInvalidParams(t, (&GeoSearchParams{Lat: 91, Radius: 1}).Validate())
+	expectInvalidParams(t, (&GeoSearchParams{PlaceId: "abc", Accuracy: 17}).Validate())
+	expectInvalidParams(t, (&GeoSearchParams{PlaceId: "abc", SearchParams: SearchParams{PerPage: 501}}).Validate())
+}
+
+func TestGeoSearch(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search": `<rsp stat="ok"><photos page="1" pages="1" perpage="100" total="1"><photo id="1" /></photos></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GeoSearch(fclient, &GeoSearchParams{
+		SearchParams: SearchParams{Text: "duomo", PerPage: 10},
+		Lat:          45.4642,
+		Lon:          9.19,
+		Radius:       1.5,
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Photos.Items[0].Id, "1")
+	args := calls.Last("flickr.photos.search")
+	flickr.Expect(t, args.Get("text"), "duomo")
+	flickr.Expect(t, args.Get("per_page"), "10")
+	flickr.Expect(t, args.Get("lat"), "45.4642")
+	flickr.Expect(t, args.Get("lon"), "9.19")
+	flickr.Expect(t, args.Get("radius"), "1.5")
+	flickr.Expect(t, args.Get("radius_units"), "")
+	flickr.Expect(t, args.Get("has_geo"), "1")
+
+	_, err = GeoSearch(fclient, &GeoSearchParams{WoeId: "718345", Accuracy: 11})
+	flickr.Expect(t, err, nil)
+	args = calls.Last("flickr.photos.search")
+	flickr.Expect(t, args.Get("woe_id"), "718345")
+	flickr.Expect(t, args.Get("accuracy"), "11")
+	flickr.Expect(t, args.Get("lat"), "")
+}
@@ -0,0 +1,93 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestCorrectLocation(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.geo.correctLocation": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := CorrectLocation(fclient, "123", PlaceRef{WoeId: "12591829"}, "4ab2d0d6f964a520e36d20e3")
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.photos.geo.correctLocation")
+	flickr.Expect(t, args.Get("photo_id"), "123")
+	flickr.Expect(t, args.Get("woe_id"), "12591829")
+	flickr.Expect(t, args.Get("place_id"), "")
+	flickr.Expect(t, args.Get("foursquare_id"), "4ab2d0d6f964a520e36d20e3")
+
+	_, err = CorrectLocation(fclient, "123", PlaceRef{}, "")
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+	flickr.Expect(t, len(calls.Methods()), 1)
+}
+
+func TestBatchCorrectLocation(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.geo.batchCorrectLocation": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := BatchCorrectLocation(fclient, 45.4642, 9.19, 16, PlaceRef{PlaceId: "kH8dLOubBZRvX_YZ"})
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.photos.geo.batchCorrectLocation")
+	flickr.Expect(t, args.Get("lat"), "45.4642")
+	flickr.Expect(t, args.Get("lon"), "9.19")
+	flickr.Expect(t, args.Get("accuracy"), "16")
+	flickr.Expect(t, args.Get("place_id"), "kH8dLOubBZRvX_YZ")
+
+	_, err = BatchCorrectLocation(fclient, 95, 9.19, 16, PlaceRef{PlaceId: "x"})
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+	_, err = BatchCorrectLocation(fclient, 45, 9.19, 17, PlaceRef{PlaceId: "x"})
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+}
+
+func TestCorrectLocations(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.geo.correctLocation": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	result, err := CorrectLocations(fclient, map[string]PlaceRef{
+		"2": {WoeId: "1"},
+		"1": {PlaceId: "a"},
+		"3": {},
+	}, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Succeeded), 2)
+	flickr.Expect(t, result.Succeeded[0], "1")
+	flickr.Expect(t, len(result.Failed), 1)
+	flickr.Expect(t, result.Failed[0].Item, "3")
+	flickr.Expect(t, calls.Last("flickr.photos.geo.correctLocation").Get("photo_id"), "2")
+}
+
+func TestPhotosForLocation(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.geo.photosForLocation": `<rsp stat="ok"><photos page="1" pages="1" perpage="100" total="1"><photo id="7" title="Here" /></photos></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := PhotosForLocation(fclient, 45.5, 9.25, 16, "geo", 2, 50)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Photos.Items[0].Title, "Here")
+	args := calls.Last("flickr.photos.geo.photosForLocation")
+	flickr.Expect(t, args.Get("lat"), "45.5")
+	flickr.Expect(t, args.Get("accuracy"), "16")
+	flickr.Expect(t, args.Get("extras"), "geo")
+	flickr.Expect(t, args.Get("page"), "2")
+
+	_, err = PhotosForLocation(fclient, 45.5, 9.25, 0, "", 1, 0)
+	flickr.Expect(t, err != nil, true)
+}
@@ -0,0 +1,101 @@
+package photos
+
+import (
+	"strconv"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Set the safety level and the hidden flag of a photo. safetyLevel goes from 1 (safe)
+// to 3 (restricted), hidden is either flickr.VisibleInSearch or flickr.HiddenFromSearch;
+// zero leaves the corresponding setting unchanged.
+// This method requires authentication with 'write' permission.
+func SetSafetyLevel(client *flickr.FlickrClient, id string, safetyLevel int, hidden int) (*flickr.BasicResponse, error) {
+	if safetyLevel == 0 && hidden == 0 {
+		return nil, flickErr.NewError(flickErr.InvalidParamsError, "either safety_level or hidden must be set")
+	}
+	if safetyLevel != 0 {
+		if err := flickr.ValidateSafetyLevel(safetyLevel); err != nil {
+			return nil, err
+		}
+	}
+
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.setSafetyLevel")
+	client.Args.Set("photo_id", id)
+	if safetyLevel != 0 {
+		client.SetInt("safety_level", safetyLevel)
+	}
+	switch hidden {
+	case 0:
+	case flickr.VisibleInSearch, flickr.HiddenFromSearch:
+		client.SetBool("hidden", hidden == flickr.HiddenFromSearch)
+	default:
+		return nil, flickErr.NewError(flickErr.InvalidParamsError, "unknown hidden value "+strconv.Itoa(hidden))
+	}
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Call fn for every photo returned by a search, once even if photos uploaded during
+// the search shift it to the next page, see SearchAll
+func searchAll(client *flickr.FlickrClient, params SearchParams, fn func(*SearchPhoto)) error {
+	_, err := SearchAll(client, params, func(p *SearchPhoto) error {
+		fn(p)
+		return nil
+	})
+	return err
+}
+
+// List the public photos of a user hidden from public searches.
+// Flickr doesn't report the hidden flag, so the public photos of the user are compared
+// with the ones an anonymous search returns. Anonymous searches only return safe
+// photos, hence photos with a moderate or restricted safety level are not listed.
+// This method requires authentication with 'read' permission.
+func ListHidden(client *flickr.FlickrClient, userId string) ([]SearchPhoto, error) {
+	params := SearchParams{UserId: userId, SafeSearch: SafeSearchSafe}
+
+	visible := map[string]bool{}
+	anonymous := params
+	anonymous.Anonymous = true
+	err := searchAll(client, anonymous, func(p *SearchPhoto) {
+		visible[p.Id] = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []SearchPhoto{}
+	err = searchAll(client, params, func(p *SearchPhoto) {
+		if p.IsPublic && !visible[p.Id] {
+			ret = append(ret, *p)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Set the hidden flag of many photos, hidden is either flickr.VisibleInSearch or
+// flickr.HiddenFromSearch. Photos not found are tombstoned.
+// This method requires authentication with 'write' permission.
+func SetHidden(client *flickr.FlickrClient, ids []string, hidden int, opts flickr.BatchOptions) (*flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	if hidden != flickr.VisibleInSearch && hidden != flickr.HiddenFromSearch {
+		return result, flickErr.NewError(flickErr.InvalidParamsError, "unknown hidden value "+strconv.Itoa(hidden))
+	}
+
+	for _, id := range ids {
+		_, err := SetSafetyLevel(client, id, 0, hidden)
+		if err := result.AddPhoto(id, nil, err, opts); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package photos
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestSetSafetyLevel(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.setSafetyLevel": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := SetSafetyLevel(fclient, "123", 2, flickr.HiddenFromSearch)
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.photos.setSafetyLevel")
+	flickr.Expect(t, args.Get("safety_level"), "2")
+	flickr.Expect(t, args.Get("hidden"), "1")
+
+	_, err = SetSafetyLevel(fclient, "123", 0, flickr.VisibleInSearch)
+	flickr.Expect(t, err, nil)
+	args = calls.Last("flickr.photos.setSafetyLevel")
+	flickr.Expect(t, args.Get("safety_level"), "")
+	flickr.Expect(t, args.Get("hidden"), "0")
+
+	for _, bad := range [][2]int{{0, 0}, {4, 0}, {1, 3}} {
+		_, err = SetSafetyLevel(fclient, "123", bad[0], bad[1])
+		ee, ok := err.(*flickErr.Error)
+		flickr.Expect(t, ok, true)
+		flickr.Expect(t, ee.ErrorCode, flickErr.InvalidParamsError)
+	}
+	flickr.Expect(t, len(calls.Methods()), 2)
+}
+
+func TestListHidden(t *testing.T) {
+	visible := `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="1">
+    <photo id="3" owner="me" secret="a" server="2" farm="1" title="sunset" ispublic="1" isfriend="0" isfamily="0" />
+  </photos></rsp>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// anonymous searches are not signed with OAuth
+		if r.URL.Query().Get("oauth_token") == "" {
+			fmt.Fprintln(w, visible)
+		} else {
+			fmt.Fprintln(w, libraryPage)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	fclient := flickr.GetTestClient()
+	fclient.OAuthToken = "token"
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	hidden, err := ListHidden(fclient, "me")
+	flickr.Expect(t, err, nil)
+	// photo 2 is private, photo 3 shows up in public searches
+	flickr.Expect(t, len(hidden), 1)
+	flickr.Expect(t, hidden[0].Id, "1")
+}
+
+func TestSetHidden(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.setSafetyLevel": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	result, err := SetHidden(fclient, []string{"1", "2"}, flickr.VisibleInSearch, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Succeeded), 2)
+	flickr.Expect(t, calls.Last("flickr.photos.setSafetyLevel").Get("photo_id"), "2")
+
+	_, err = SetHidden(fclient, []string{"1"}, 0, flickr.BatchOptions{})
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+}
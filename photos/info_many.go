@@ -0,0 +1,80 @@
+package photos
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Options of GetInfoMany
+type GetInfoManyOptions struct {
+	// Maximum number of getInfo calls performed at the same time, 4 when zero
+	Concurrency int
+	// Maximum number of getInfo calls started per second, zero means no limit
+	RequestsPerSecond float64
+}
+
+// Outcome of getInfo for a photo of GetInfoMany, Info is nil when Err is set
+type InfoResult struct {
+	PhotoId string
+	Info    *PhotoInfo
+	Err     error
+}
+
+// Get information about many photos, fanning out getInfo calls to a bounded pool of
+// workers using clones of client, so they share its KeyRing, QuotaTracker and
+// RetryPolicy. Results are in the same order as ids and failures are reported per
+// photo. Once ctx is done no more calls are started, the photos left get ctx.Err()
+// which is returned along with the partial results.
+// This method requires authentication to access private photos.
+func GetInfoMany(ctx context.Context, client *flickr.FlickrClient, ids []string, opts GetInfoManyOptions) ([]InfoResult, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	var tick <-chan time.Time
+	if opts.RequestsPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RequestsPerSecond))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	results := make([]InfoResult, len(ids))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for i, id := range ids {
+		results[i].PhotoId = id
+		if i > 0 && tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+			}
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			for j := i; j < len(ids); j++ {
+				results[j] = InfoResult{PhotoId: ids[j], Err: ctx.Err()}
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(r *InfoResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := GetInfo(client.Clone(), r.PhotoId, "")
+			if err != nil {
+				r.Err = err
+				return
+			}
+			r.Info = &resp.Photo
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, ctx.Err()
+}
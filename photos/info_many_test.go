@@ -0,0 +1,67 @@
+package photos
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestGetInfoMany(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		id := r.FormValue("photo_id")
+		if id == "404" {
+			fmt.Fprint(w, `<rsp stat="fail"><err code="1" msg="Photo not found" /></rsp>`)
+			return
+		}
+		// answer the first photos last
+		rank, _ := strconv.Atoi(id)
+		time.Sleep(time.Duration(10-rank) * time.Millisecond)
+		fmt.Fprintf(w, `<rsp stat="ok"><photo id="%s"><title>photo %s</title></photo></rsp>`, id, id)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	ids := []string{"1", "2", "404", "3", "4", "5", "6"}
+	results, err := GetInfoMany(context.Background(), fclient, ids, GetInfoManyOptions{Concurrency: 2})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(results), len(ids))
+	for i, r := range results {
+		flickr.Expect(t, r.PhotoId, ids[i])
+		if r.PhotoId == "404" {
+			flickr.Expect(t, flickr.IsPhotoNotFound(r.Err), true)
+			flickr.Expect(t, r.Info == nil, true)
+			continue
+		}
+		flickr.Expect(t, r.Err, nil)
+		flickr.Expect(t, r.Info.Title, "photo "+ids[i])
+	}
+	flickr.Expect(t, atomic.LoadInt32(&maxInFlight) <= 2, true)
+
+	// no call is started once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = GetInfoMany(ctx, fclient, ids[:2], GetInfoManyOptions{RequestsPerSecond: 1})
+	flickr.Expect(t, err, context.Canceled)
+	flickr.Expect(t, results[1].PhotoId, "2")
+	flickr.Expect(t, results[1].Err, context.Canceled)
+}
@@ -0,0 +1,156 @@
+package photos
+
+import (
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// IDs of the licenses of the photos, as reported by the "license" attribute
+const (
+	LicenseAllRightsReserved = "0"
+	LicenseCCByNcSa          = "1"
+	LicenseCCByNc            = "2"
+	LicenseCCByNcNd          = "3"
+	LicenseCCBy              = "4"
+	LicenseCCBySa            = "5"
+	LicenseCCByNd            = "6"
+	// Photos shared by Flickr Commons institutions
+	LicenseNoKnownCopyright = "7"
+	LicenseUSGovernmentWork = "8"
+	LicenseCC0              = "9"
+	LicensePublicDomainMark = "10"
+)
+
+var licenseNames = map[string]string{
+	LicenseAllRightsReserved: "All Rights Reserved",
+	LicenseCCByNcSa:          "Attribution-NonCommercial-ShareAlike License",
+	LicenseCCByNc:            "Attribution-NonCommercial License",
+	LicenseCCByNcNd:          "Attribution-NonCommercial-NoDerivs License",
+	LicenseCCBy:              "Attribution License",
+	LicenseCCBySa:            "Attribution-ShareAlike License",
+	LicenseCCByNd:            "Attribution-NoDerivs License",
+	LicenseNoKnownCopyright:  "No known copyright restrictions",
+	LicenseUSGovernmentWork:  "United States Government Work",
+	LicenseCC0:               "Public Domain Dedication (CC0)",
+	LicensePublicDomainMark:  "Public Domain Mark",
+}
+
+// Return the name of a license, empty for unknown IDs
+func LicenseName(license string) string {
+	return licenseNames[license]
+}
+
+// Return whether a license can be assigned with SetLicense. No known copyright and
+// United States Government Work are reserved to Flickr Commons institutions and
+// government accounts, they can't be set through the API.
+func IsAssignable(license string) bool {
+	switch license {
+	case LicenseNoKnownCopyright, LicenseUSGovernmentWork:
+		return false
+	}
+	_, known := licenseNames[license]
+	return known
+}
+
+// Return whether a license puts no known restriction on the reuse of a photo: no
+// known copyright (Flickr Commons), US government works and public domain
+func IsUnrestricted(license string) bool {
+	switch license {
+	case LicenseNoKnownCopyright, LicenseUSGovernmentWork, LicenseCC0, LicensePublicDomainMark:
+		return true
+	}
+	return false
+}
+
+// Return whether the photo has no known copyright restrictions, like the ones shared
+// by Flickr Commons institutions. Requires the "license" extra.
+func (p *SearchPhoto) NoKnownCopyright() bool {
+	return p.License == LicenseNoKnownCopyright
+}
+
+// Return whether the photo has no known copyright restrictions, like the ones shared
+// by Flickr Commons institutions
+func (p *PhotoInfo) NoKnownCopyright() bool {
+	return p.License == LicenseNoKnownCopyright
+}
+
+// Set the license of a photo, one of the IDs IsAssignable accepts.
+// This method requires authentication with 'write' permission.
+func SetLicense(client *flickr.FlickrClient, photoId, license string) (*flickr.BasicResponse, error) {
+	if !IsAssignable(license) {
+		return nil, flickErr.NewError(flickErr.InvalidParamsError, "license "+license+" can't be assigned through the API")
+	}
+
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.licenses.setLicense")
+	client.Args.Set("photo_id", photoId)
+	client.Args.Set("license_id", license)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Options of SetLicenseBatch
+type LicenseBatchOptions struct {
+	// Search selecting the photos, UserId defaults to the calling user ("me")
+	Params SearchParams
+	// License to assign, one of the IDs IsAssignable accepts
+	License string
+	// Further filter the photos, e.g. on their current license. Every photo the search
+	// returns is relicensed when nil.
+	Match func(*SearchPhoto) bool
+	// List the photos to relicense without changing them
+	DryRun bool
+	// Set FailFast to stop at the first photo failing
+	flickr.BatchOptions
+}
+
+// Relicense the photos matching opts.Params and opts.Match under opts.License, e.g.
+// to move a whole photostream to a Creative Commons license. Photos already under
+// opts.License are left alone. Returns the photos affected grouped by their license
+// before the change, which is all that's done with opts.DryRun, and the outcome of
+// every setLicense call. The error is non nil when the license can't be assigned,
+// the search fails or, with opts.FailFast, as soon as a photo fails.
+// This method requires authentication with 'write' permission.
+func SetLicenseBatch(client *flickr.FlickrClient, opts LicenseBatchOptions) (map[string][]SearchPhoto, *flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	if !IsAssignable(opts.License) {
+		return nil, result, flickErr.NewError(flickErr.InvalidParamsError, "license "+opts.License+" can't be assigned through the API")
+	}
+
+	params := opts.Params
+	if params.UserId == "" {
+		params.UserId = "me"
+	}
+	params.Extras = withExtra(params.Extras, "license")
+
+	// collect every photo first, like SweepContentType, so that a failure doesn't
+	// leave the caller without the full list
+	affected := map[string][]SearchPhoto{}
+	found := []SearchPhoto{}
+	err := searchAll(client, params, func(p *SearchPhoto) {
+		if p.License == opts.License || (opts.Match != nil && !opts.Match(p)) {
+			return
+		}
+		affected[p.License] = append(affected[p.License], *p)
+		found = append(found, *p)
+	})
+	if err != nil {
+		return nil, result, err
+	}
+	if opts.DryRun {
+		return affected, result, nil
+	}
+
+	for i := range found {
+		p := &found[i]
+		_, err := SetLicense(client, p.Id, opts.License)
+		if err := result.AddPhoto(p.Id, p, err, opts.BatchOptions); err != nil {
+			return affected, result, err
+		}
+	}
+	return affected, result, nil
+}
@@ -0,0 +1,79 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestSearchCommons(t *testing.T) {
+	body := `<rsp stat="ok">
+  <photos page="1" pages="1" perpage="100" total="2">
+    <photo id="1" owner="8623220@N02" secret="a" server="2" farm="1" title="Bain collection" ispublic="1" isfriend="0" isfamily="0" license="7" />
+    <photo id="2" owner="8623220@N02" secret="a" server="2" farm="1" title="Portrait" ispublic="1" isfriend="0" isfamily="0" license="4" />
+  </photos>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search": body,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := Search(fclient, &SearchParams{IsCommons: true, Extras: "license"})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photos.search").Get("is_commons"), "1")
+	flickr.Expect(t, resp.Photos.Items[0].NoKnownCopyright(), true)
+	flickr.Expect(t, resp.Photos.Items[1].NoKnownCopyright(), false)
+	flickr.Expect(t, LicenseName(resp.Photos.Items[1].License), "Attribution License")
+}
+
+func TestLicenses(t *testing.T) {
+	flickr.Expect(t, IsUnrestricted(LicenseNoKnownCopyright), true)
+	flickr.Expect(t, IsUnrestricted(LicenseCC0), true)
+	flickr.Expect(t, IsUnrestricted(LicenseCCBy), false)
+	flickr.Expect(t, IsUnrestricted(""), false)
+	flickr.Expect(t, LicenseName("42"), "")
+	flickr.Expect(t, (&PhotoInfo{License: "7"}).NoKnownCopyright(), true)
+}
+
+func TestSetLicenseBatch(t *testing.T) {
+	body := `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="3">
+  <photo id="1" owner="me" secret="a" server="2" title="a" license="0" />
+  <photo id="2" owner="me" secret="b" server="2" title="b" license="0" />
+  <photo id="3" owner="me" secret="c" server="2" title="c" license="4" />
+</photos></rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search":              body,
+		"flickr.photos.licenses.setLicense": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	opts := LicenseBatchOptions{License: LicenseCCBy, DryRun: true}
+	affected, result, err := SetLicenseBatch(fclient, opts)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(affected), 1)
+	flickr.Expect(t, len(affected[LicenseAllRightsReserved]), 2)
+	flickr.Expect(t, len(result.Succeeded), 0)
+	search := calls.Last("flickr.photos.search")
+	flickr.Expect(t, search.Get("user_id"), "me")
+	flickr.Expect(t, search.Get("extras"), "license")
+	flickr.Expect(t, calls.Last("flickr.photos.licenses.setLicense") == nil, true)
+
+	opts.DryRun = false
+	_, result, err = SetLicenseBatch(fclient, opts)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Succeeded), 2)
+	set := calls.Last("flickr.photos.licenses.setLicense")
+	flickr.Expect(t, set.Get("photo_id"), "2")
+	flickr.Expect(t, set.Get("license_id"), LicenseCCBy)
+
+	opts.License = LicenseNoKnownCopyright
+	_, _, err = SetLicenseBatch(fclient, opts)
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+	flickr.Expect(t, IsAssignable(LicenseCC0), true)
+	flickr.Expect(t, IsAssignable("42"), false)
+}
@@ -0,0 +1,124 @@
+package photos
+
+import (
+	"strings"
+)
+
+// A machine tag, a tag of the form namespace:predicate=value holding structured
+// data, e.g. "checksum:sha256=9f86d081" or "geo:lat=45.07"
+type MachineTag struct {
+	Namespace string
+	Predicate string
+	Value     string
+}
+
+// Format the tag the way it's sent to Flickr, see flickr.FormatTags for quoting
+func (m MachineTag) String() string {
+	return m.Namespace + ":" + m.Predicate + "=" + m.Value
+}
+
+// Return whether the tag has the given namespace and predicate, compared without
+// regard to case since Flickr lowercases them in the clean form
+func (m MachineTag) Is(namespace, predicate string) bool {
+	return strings.EqualFold(m.Namespace, namespace) && strings.EqualFold(m.Predicate, predicate)
+}
+
+// Return whether s is a valid namespace or predicate: a letter followed by letters,
+// digits and underscores
+func isMachineTagName(s string) bool {
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r == '_' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Parse a tag as a machine tag, returns false for ordinary tags. The value may be
+// wrapped in double quotes, which are dropped.
+func ParseMachineTag(tag string) (MachineTag, bool) {
+	tag = strings.TrimSpace(tag)
+	colon := strings.Index(tag, ":")
+	if colon < 0 {
+		return MachineTag{}, false
+	}
+	equal := strings.Index(tag[colon:], "=")
+	if equal < 0 {
+		return MachineTag{}, false
+	}
+	equal += colon
+	m := MachineTag{Namespace: tag[:colon], Predicate: tag[colon+1 : equal], Value: tag[equal+1:]}
+	if !isMachineTagName(m.Namespace) || !isMachineTagName(m.Predicate) || m.Value == "" {
+		return MachineTag{}, false
+	}
+	if len(m.Value) >= 2 && strings.HasPrefix(m.Value, `"`) && strings.HasSuffix(m.Value, `"`) {
+		m.Value = m.Value[1 : len(m.Value)-1]
+	}
+	return m, true
+}
+
+// Return the machine tags among tags, ordinary tags are skipped
+func ParseMachineTags(tags []string) []MachineTag {
+	ret := []MachineTag{}
+	for _, tag := range tags {
+		if m, ok := ParseMachineTag(tag); ok {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// Return the values of the machine tags with the given namespace and predicate
+func machineTagValues(tags []MachineTag, namespace, predicate string) []string {
+	values := []string{}
+	for _, m := range tags {
+		if m.Is(namespace, predicate) {
+			values = append(values, m.Value)
+		}
+	}
+	return values
+}
+
+// Return the machine tags of a photo, parsed from the raw tags
+func (p *PhotoInfo) MachineTags() []MachineTag {
+	raw := make([]string, 0, len(p.Tags))
+	for _, tag := range p.Tags {
+		raw = append(raw, tag.Raw)
+	}
+	return ParseMachineTags(raw)
+}
+
+// Return the value of the first machine tag of a photo with the given namespace and
+// predicate, e.g. photo.MachineTag("checksum", "sha256"), and whether one was found
+func (p *PhotoInfo) MachineTag(namespace, predicate string) (string, bool) {
+	values := machineTagValues(p.MachineTags(), namespace, predicate)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// Return the values of every machine tag of a photo with the given namespace and
+// predicate, a photo may carry several
+func (p *PhotoInfo) MachineTagValues(namespace, predicate string) []string {
+	return machineTagValues(p.MachineTags(), namespace, predicate)
+}
+
+// Return the machine tags of a photo, parsed from MachineTagList which is only
+// filled when extras contains "machine_tags". Flickr returns them in their clean
+// form: lowercased, values included.
+func (p *SearchPhoto) MachineTags() []MachineTag {
+	return ParseMachineTags(strings.Fields(p.MachineTagList))
+}
+
+// Same as PhotoInfo.MachineTag, from MachineTagList
+func (p *SearchPhoto) MachineTag(namespace, predicate string) (string, bool) {
+	values := machineTagValues(p.MachineTags(), namespace, predicate)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
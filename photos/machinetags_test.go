@@ -0,0 +1,47 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestParseMachineTag(t *testing.T) {
+	m, ok := ParseMachineTag("checksum:sha256=9f86d081")
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, m, MachineTag{"checksum", "sha256", "9f86d081"})
+	flickr.Expect(t, m.String(), "checksum:sha256=9f86d081")
+
+	m, ok = ParseMachineTag(`dc:title="A day: at the sea=fun"`)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, m.Predicate, "title")
+	flickr.Expect(t, m.Value, "A day: at the sea=fun")
+
+	for _, tag := range []string{"sunset", "a:b", "a:=c", ":b=c", "1a:b=c", "a:b=", "a b:c=d", "a:b_1=x"} {
+		_, ok := ParseMachineTag(tag)
+		flickr.Expect(t, ok, tag == "a:b_1=x")
+	}
+}
+
+func TestPhotoMachineTags(t *testing.T) {
+	info := &PhotoInfo{Tags: []Tag{
+		{Raw: "sunset"},
+		{Raw: "Checksum:SHA256=abc"},
+		{Raw: "sync:source=laptop"},
+		{Raw: "sync:source=phone"},
+	}}
+	flickr.Expect(t, len(info.MachineTags()), 3)
+	value, found := info.MachineTag("checksum", "sha256")
+	flickr.Expect(t, found, true)
+	flickr.Expect(t, value, "abc")
+	values := info.MachineTagValues("sync", "source")
+	flickr.Expect(t, len(values), 2)
+	flickr.Expect(t, values[1], "phone")
+	_, found = info.MachineTag("checksum", "md5")
+	flickr.Expect(t, found, false)
+
+	photo := &SearchPhoto{MachineTagList: "checksum:sha256=abc geo:lat=45.07"}
+	value, found = photo.MachineTag("geo", "lat")
+	flickr.Expect(t, found, true)
+	flickr.Expect(t, value, "45.07")
+}
@@ -0,0 +1,205 @@
+package photos
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A file of a BackupManifest
+type ManifestEntry struct {
+	PhotoId string `json:"photo_id"`
+	// Path of the file relative to the backup directory, slash separated
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// SHA-256 of the chain hash of the previous entry and of the fields above, so that
+	// entries can't be dropped, reordered or altered without breaking the chain
+	Chain string `json:"chain"`
+}
+
+// Compute the chain hash of an entry following the one whose chain hash is prev
+func (e *ManifestEntry) chainHash(prev string) string {
+	h := sha256.New()
+	for _, field := range []string{prev, e.PhotoId, e.Path, strconv.FormatInt(e.Size, 10), e.SHA256} {
+		io.WriteString(h, field)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// A BackupManifest records the size and the SHA-256 of every file of a backup, in
+// the order they were written, along with a rolling chain hash, so that Verify can
+// later detect bit rot, partial downloads and tampering with the manifest itself.
+// Store it with SaveTo next to the backup, or better elsewhere.
+type BackupManifest struct {
+	Entries []ManifestEntry `json:"entries"`
+}
+
+func NewBackupManifest() *BackupManifest {
+	return &BackupManifest{Entries: []ManifestEntry{}}
+}
+
+// Load a manifest stored under key with SaveTo, a missing key yields an empty manifest
+func LoadBackupManifest(store flickr.Store, key string) (*BackupManifest, error) {
+	m := NewBackupManifest()
+	if _, err := flickr.LoadJSON(store, key, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Store the manifest as JSON under key
+func (m *BackupManifest) SaveTo(store flickr.Store, key string) error {
+	return flickr.SaveJSON(store, key, m)
+}
+
+// Return the chain hash of the last entry, empty for an empty manifest. Keeping it
+// aside is enough to detect later changes to the whole manifest.
+func (m *BackupManifest) Head() string {
+	if len(m.Entries) == 0 {
+		return ""
+	}
+	return m.Entries[len(m.Entries)-1].Chain
+}
+
+// Hash a file, returns its size and hex SHA-256
+func hashFile(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Hash the file at path, relative to dir, and append it to the manifest. A file added
+// again, e.g. a photo backed up after it was replaced, gets a new entry superseding
+// the previous one.
+func (m *BackupManifest) Add(photoId, dir, path string) (*ManifestEntry, error) {
+	size, sum, err := hashFile(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		return nil, err
+	}
+	entry := ManifestEntry{PhotoId: photoId, Path: filepath.ToSlash(path), Size: size, SHA256: sum}
+	entry.Chain = entry.chainHash(m.Head())
+	m.Entries = append(m.Entries, entry)
+	return &m.Entries[len(m.Entries)-1], nil
+}
+
+// Download the original file of a photo into dir with SaveOriginal and record it in
+// the manifest. The file is hashed as written on disk.
+// This method requires authentication to download private photos.
+func BackupOriginal(client *flickr.FlickrClient, photoId, dir string, manifest *BackupManifest) (*ManifestEntry, error) {
+	path, err := SaveOriginal(client, photoId, dir)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Add(photoId, dir, filepath.Base(path))
+}
+
+// Kind of problem found by BackupManifest.Verify
+type DiscrepancyKind string
+
+const (
+	// The file is missing
+	FileMissing DiscrepancyKind = "missing"
+	// The file can't be read
+	FileUnreadable DiscrepancyKind = "unreadable"
+	// The file size differs, e.g. a partial download
+	SizeMismatch DiscrepancyKind = "size"
+	// The file content changed, e.g. bit rot
+	ChecksumMismatch DiscrepancyKind = "checksum"
+	// The manifest was altered from this entry on
+	ChainBroken DiscrepancyKind = "chain"
+)
+
+// A problem found by BackupManifest.Verify
+type Discrepancy struct {
+	Kind    DiscrepancyKind
+	PhotoId string
+	Path    string
+	// Values recorded in the manifest and found, for size and checksum mismatches
+	Expected string
+	Actual   string
+}
+
+func (d Discrepancy) String() string {
+	switch d.Kind {
+	case SizeMismatch, ChecksumMismatch:
+		return fmt.Sprintf("%s: %s mismatch, expected %s, got %s", d.Path, d.Kind, d.Expected, d.Actual)
+	case ChainBroken:
+		return fmt.Sprintf("%s: manifest chain broken", d.Path)
+	case FileUnreadable:
+		return fmt.Sprintf("%s: unreadable: %s", d.Path, d.Actual)
+	}
+	return fmt.Sprintf("%s: %s", d.Path, d.Kind)
+}
+
+// Result of BackupManifest.Verify
+type ManifestReport struct {
+	// Number of files checked
+	Checked       int
+	Discrepancies []Discrepancy
+}
+
+// Return whether the backup matches the manifest
+func (r *ManifestReport) OK() bool {
+	return len(r.Discrepancies) == 0
+}
+
+// Check the chain of the manifest and every file it lists against the files found
+// in dir, only the latest entry of a path is checked. The chain is reported broken
+// at the first entry that doesn't match, the entries following it can't be trusted.
+func (m *BackupManifest) Verify(dir string) *ManifestReport {
+	report := &ManifestReport{Discrepancies: []Discrepancy{}}
+	prev := ""
+	for _, e := range m.Entries {
+		if e.chainHash(prev) != e.Chain {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{Kind: ChainBroken, PhotoId: e.PhotoId, Path: e.Path})
+			break
+		}
+		prev = e.Chain
+	}
+
+	latest := map[string]int{}
+	for i, e := range m.Entries {
+		latest[e.Path] = i
+	}
+	for i, e := range m.Entries {
+		if latest[e.Path] != i {
+			continue
+		}
+		report.Checked++
+		d := Discrepancy{PhotoId: e.PhotoId, Path: e.Path}
+		size, sum, err := hashFile(filepath.Join(dir, filepath.FromSlash(e.Path)))
+		switch {
+		case os.IsNotExist(err):
+			d.Kind = FileMissing
+		case err != nil:
+			d.Kind = FileUnreadable
+			d.Actual = err.Error()
+		case size != e.Size:
+			d.Kind = SizeMismatch
+			d.Expected, d.Actual = strconv.FormatInt(e.Size, 10), strconv.FormatInt(size, 10)
+		case sum != e.SHA256:
+			d.Kind = ChecksumMismatch
+			d.Expected, d.Actual = e.SHA256, sum
+		default:
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+	return report
+}
@@ -0,0 +1,82 @@
+package photos
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestBackupManifest(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMockMethods(200, map[string]string{
+		"flickr.photos.getInfo":      photoInfo,
+		"/65535/52435165562_9_o.jpg": "jpeg data",
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	dir, err := ioutil.TempDir("", "flickr")
+	flickr.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	m := NewBackupManifest()
+	entry, err := BackupOriginal(fclient, "52435165562", dir, m)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, entry.Path, "52435165562.jpg")
+	flickr.Expect(t, entry.Size, int64(10))
+	flickr.Expect(t, len(entry.SHA256), 64)
+
+	ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0644)
+	_, err = m.Add("", dir, "notes.txt")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, m.Head(), m.Entries[1].Chain)
+	flickr.Expect(t, m.Verify(dir).OK(), true)
+
+	// the manifest survives a round trip through a store
+	store := flickr.NewMemoryStore()
+	flickr.Expect(t, m.SaveTo(store, "manifest"), nil)
+	loaded, err := LoadBackupManifest(store, "manifest")
+	flickr.Expect(t, err, nil)
+	report := loaded.Verify(dir)
+	flickr.Expect(t, report.Checked, 2)
+	flickr.Expect(t, report.OK(), true)
+}
+
+func TestBackupManifestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flickr")
+	flickr.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	m := NewBackupManifest()
+	for _, name := range []string{"1.jpg", "2.jpg", "3.jpg", "4.jpg"} {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte("data of "+name), 0644)
+		_, err := m.Add(name[:1], dir, name)
+		flickr.Expect(t, err, nil)
+	}
+
+	// bit rot, partial download and lost file
+	ioutil.WriteFile(filepath.Join(dir, "1.jpg"), []byte("data of 1.jpf"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "2.jpg"), []byte("data"), 0644)
+	os.Remove(filepath.Join(dir, "3.jpg"))
+	report := m.Verify(dir)
+	flickr.Expect(t, report.Checked, 4)
+	flickr.Expect(t, len(report.Discrepancies), 3)
+	flickr.Expect(t, report.Discrepancies[0].Kind, ChecksumMismatch)
+	flickr.Expect(t, report.Discrepancies[1].Kind, SizeMismatch)
+	flickr.Expect(t, report.Discrepancies[1].String(), "2.jpg: size mismatch, expected 13, got 4")
+	flickr.Expect(t, report.Discrepancies[2].Kind, FileMissing)
+
+	// the file is backed up again, the new entry supersedes the old one
+	_, err = m.Add("1", dir, "1.jpg")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(m.Verify(dir).Discrepancies), 2)
+
+	// editing an entry breaks the chain
+	m.Entries[1].Size = 4
+	report = m.Verify(dir)
+	flickr.Expect(t, report.Discrepancies[0].Kind, ChainBroken)
+	flickr.Expect(t, report.Discrepancies[0].Path, "2.jpg")
+}
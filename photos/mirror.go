@@ -0,0 +1,146 @@
+package photos
+
+import (
+	"sort"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A change of the library applied to a Mirror, Before is nil for added photos and
+// After is nil for deleted ones
+type MirrorEvent struct {
+	Kind   ChangeKind
+	Id     string
+	Before *SearchPhoto
+	After  *SearchPhoto
+}
+
+// A Mirror keeps a local copy of the metadata of every photo of the calling user in
+// a flickr.Store, updated incrementally with recentlyUpdated and a persisted cursor
+// so that each run only fetches what changed since the previous one instead of
+// rescanning the whole library like DiffLive.
+// A Mirror is not safe for concurrent use.
+type Mirror struct {
+	store flickr.Store
+	key   string
+	// Comma separated list of extra fields to keep besides the ones needed to detect
+	// changes (last_update, tags, description, original_format and media)
+	Extras string
+	// The mirrored photos, keyed by ID
+	Photos map[string]SearchPhoto
+	Cursor *flickr.Cursor
+	// Optional criteria of the photos to mirror, photos no longer matching it are
+	// dropped with a PhotoDeleted event. Set it before the first Update.
+	Filter *Filter
+}
+
+// Load the mirror stored under key, key+"/photos" and key+"/cursor" are used. A
+// missing mirror is empty, its first Update fetches the whole library.
+func OpenMirror(store flickr.Store, key string) (*Mirror, error) {
+	m := &Mirror{store: store, key: key, Photos: map[string]SearchPhoto{}}
+	if _, err := flickr.LoadJSON(store, key+"/photos", &m.Photos); err != nil {
+		return nil, err
+	}
+	cursor, err := flickr.LoadCursorFrom(store, key+"/cursor")
+	if err != nil {
+		return nil, err
+	}
+	m.Cursor = cursor
+	return m, nil
+}
+
+// Write the photos and the cursor to the store
+func (m *Mirror) Save() error {
+	if err := flickr.SaveJSON(m.store, m.key+"/photos", m.Photos); err != nil {
+		return err
+	}
+	return m.Cursor.SaveTo(m.store, m.key+"/cursor")
+}
+
+// Apply an updated photo, calling fn when its file or metadata changed. The photo is
+// only recorded once fn succeeded so that a failed event is emitted again.
+func (m *Mirror) apply(p *SearchPhoto, fn func(*MirrorEvent) error) error {
+	if m.Filter != nil && !m.Filter.Match(p) {
+		before, found := m.Photos[p.Id]
+		if !found {
+			return nil
+		}
+		if err := fn(&MirrorEvent{Kind: PhotoDeleted, Id: p.Id, Before: &before}); err != nil {
+			return err
+		}
+		delete(m.Photos, p.Id)
+		return nil
+	}
+	event := &MirrorEvent{Id: p.Id, After: p}
+	if before, found := m.Photos[p.Id]; !found {
+		event.Kind = PhotoAdded
+	} else {
+		a, b := newSnapshotEntry(p), newSnapshotEntry(&before)
+		if a.Checksum != b.Checksum || a.MetaHash != b.MetaHash {
+			event.Kind = PhotoModified
+			event.Before = &before
+		}
+	}
+	if event.Kind != "" {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	m.Photos[p.Id] = *p
+	return nil
+}
+
+// Fetch the photos updated since the previous run and call fn for every photo added
+// or modified, updates not affecting the file or the metadata (e.g. new comments)
+// are recorded silently. The mirror is saved even when the update fails, so the next
+// run resumes where this one stopped. Deleted photos are only detected by Reconcile.
+// This method requires authentication with 'read' permission.
+func (m *Mirror) Update(client *flickr.FlickrClient, fn func(*MirrorEvent) error) error {
+	extras := snapshotExtras
+	if m.Extras != "" {
+		extras += "," + m.Extras
+	}
+	if m.Filter != nil {
+		extras += "," + m.Filter.Extras()
+	}
+	err := SyncUpdates(client, m.Cursor, extras, flickr.MaxPerPage, func(p *SearchPhoto) error {
+		return m.apply(p, fn)
+	})
+	if saveErr := m.Save(); err == nil {
+		err = saveErr
+	}
+	return err
+}
+
+// List the IDs of the whole library to drop the photos deleted since they were
+// mirrored, calling fn for each of them in ID order, then save the mirror.
+// recentlyUpdated doesn't report deletions, run it from time to time.
+// This method requires authentication with 'read' permission.
+func (m *Mirror) Reconcile(client *flickr.FlickrClient, fn func(*MirrorEvent) error) error {
+	live := map[string]bool{}
+	err := searchAll(client, SearchParams{UserId: "me"}, func(p *SearchPhoto) {
+		live[p.Id] = true
+	})
+	if err != nil {
+		return err
+	}
+
+	deleted := []string{}
+	for id := range m.Photos {
+		if !live[id] {
+			deleted = append(deleted, id)
+		}
+	}
+	sort.Strings(deleted)
+	for _, id := range deleted {
+		p := m.Photos[id]
+		if err = fn(&MirrorEvent{Kind: PhotoDeleted, Id: id, Before: &p}); err != nil {
+			break
+		}
+		delete(m.Photos, id)
+	}
+	if saveErr := m.Save(); err == nil {
+		err = saveErr
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestMirror(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	bodies := map[string]string{
+		"flickr.photos.recentlyUpdated": `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="2">
+  <photo id="1" secret="a" server="2" title="beach" lastupdate="100" />
+  <photo id="2" secret="b" server="2" title="sunset" lastupdate="200" />
+</photos></rsp>`,
+	}
+	server, client, calls := flickr.FlickrMockRecorder(200, bodies)
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	store := flickr.NewMemoryStore()
+	m, err := OpenMirror(store, "mirror")
+	flickr.Expect(t, err, nil)
+	events := []string{}
+	record := func(e *MirrorEvent) error {
+		events = append(events, string(e.Kind)+" "+e.Id)
+		return nil
+	}
+	flickr.Expect(t, m.Update(fclient, record), nil)
+	flickr.Expect(t, len(events), 2)
+	flickr.Expect(t, events[0], "added 1")
+	flickr.Expect(t, m.Cursor.Since, int64(200))
+
+	// photo 1 only got a comment, photo 2 was renamed
+	bodies["flickr.photos.recentlyUpdated"] = `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="2">
+  <photo id="1" secret="a" server="2" title="beach" lastupdate="300" />
+  <photo id="2" secret="b" server="2" title="red sunset" lastupdate="300" />
+</photos></rsp>`
+	events = nil
+	m, _ = OpenMirror(store, "mirror")
+	flickr.Expect(t, len(m.Photos), 2)
+	flickr.Expect(t, m.Update(fclient, record), nil)
+	flickr.Expect(t, calls.Last("flickr.photos.recentlyUpdated").Get("min_date"), "200")
+	flickr.Expect(t, len(events), 1)
+	flickr.Expect(t, events[0], "modified 2")
+	flickr.Expect(t, m.Photos["2"].Title, "red sunset")
+
+	bodies["flickr.photos.search"] = `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="1">
+  <photo id="2" />
+</photos></rsp>`
+	events = nil
+	flickr.Expect(t, m.Reconcile(fclient, record), nil)
+	flickr.Expect(t, len(events), 1)
+	flickr.Expect(t, events[0], "deleted 1")
+	m, _ = OpenMirror(store, "mirror")
+	flickr.Expect(t, len(m.Photos), 1)
+}
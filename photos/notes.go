@@ -0,0 +1,75 @@
+package photos
+
+import (
+	"image"
+	"strconv"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Length of the longest side of the size notes are drawn on, the "Medium" size
+const NoteSpaceSize = 500
+
+// Return the size the coordinates of notes refer to for a photo whose original is
+// original: the photo scaled down to fit in 500x500, like the "Medium" size. Photos
+// smaller than that are not scaled up, their notes refer to the original.
+func NoteSpace(original image.Point) image.Point {
+	longest := original.X
+	if original.Y > longest {
+		longest = original.Y
+	}
+	if longest <= NoteSpaceSize {
+		return original
+	}
+	scale := float64(NoteSpaceSize) / float64(longest)
+	return image.Point{
+		X: int(float64(original.X)*scale + 0.5),
+		Y: int(float64(original.Y)*scale + 0.5),
+	}
+}
+
+// Scale a box of note space to a size of the photo, e.g. to draw a note over the
+// "Large" size or the original. original is the size of the original, or of any
+// size of the photo, they share the aspect ratio.
+func NoteToSize(b Box, original, size image.Point) Box {
+	return b.Scale(NoteSpace(original), size)
+}
+
+// Scale a box drawn on a size of the photo to note space, the reverse of NoteToSize
+func NoteFromSize(b Box, original, size image.Point) Box {
+	return b.Scale(size, NoteSpace(original))
+}
+
+// Return the dimensions of a size, zero if not reported
+func (s *PhotoDownloadInfo) Dimensions() image.Point {
+	w, _ := strconv.Atoi(s.Width)
+	h, _ := strconv.Atoi(s.Height)
+	return image.Point{X: w, Y: h}
+}
+
+// Return the dimensions of the largest still image of a list of sizes, which stands
+// for the original when it's not available
+func largestDimensions(sizes []PhotoDownloadInfo) image.Point {
+	var best image.Point
+	for i := range sizes {
+		if sizes[i].Media == "video" {
+			continue
+		}
+		if d := sizes[i].Dimensions(); d.X*d.Y > best.X*best.Y {
+			best = d
+		}
+	}
+	return best
+}
+
+// Scale the box of a note to the size with the given label, e.g. "Large" or
+// "Original", among the sizes of the photo returned by GetSizes. The error is a
+// flickErr.Error with code InvalidParamsError when the label is not listed.
+func (n *Note) ScaleToSize(sizes []PhotoDownloadInfo, label string) (Box, error) {
+	for i := range sizes {
+		if sizes[i].Label == label {
+			return NoteToSize(n.Box, largestDimensions(sizes), sizes[i].Dimensions()), nil
+		}
+	}
+	return Box{}, flickErr.NewError(flickErr.InvalidParamsError, "size "+label+" is not available")
+}
@@ -0,0 +1,44 @@
+package photos
+
+import (
+	"image"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestNoteSpace(t *testing.T) {
+	flickr.Expect(t, NoteSpace(image.Pt(4000, 3000)), image.Pt(500, 375))
+	flickr.Expect(t, NoteSpace(image.Pt(2000, 3000)), image.Pt(333, 500))
+	flickr.Expect(t, NoteSpace(image.Pt(400, 300)), image.Pt(400, 300))
+
+	b := Box{X: 50, Y: 75, W: 100, H: 25}
+	flickr.Expect(t, NoteToSize(b, image.Pt(4000, 3000), image.Pt(4000, 3000)), Box{X: 400, Y: 600, W: 800, H: 200})
+	flickr.Expect(t, NoteToSize(b, image.Pt(400, 300), image.Pt(800, 600)), Box{X: 100, Y: 150, W: 200, H: 50})
+	flickr.Expect(t, NoteFromSize(Box{X: 400, Y: 600, W: 800, H: 200}, image.Pt(4000, 3000), image.Pt(4000, 3000)), b)
+}
+
+func TestNoteScaleToSize(t *testing.T) {
+	sizes := []PhotoDownloadInfo{
+		{Label: "Medium", Width: "500", Height: "375"},
+		{Label: "Large", Width: "1024", Height: "768"},
+		{Label: "Original", Width: "2048", Height: "1536"},
+	}
+	n := &Note{Box: Box{X: 50, Y: 75, W: 100, H: 25}}
+	box, err := n.ScaleToSize(sizes, "Large")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, box, Box{X: 102, Y: 154, W: 205, H: 51})
+
+	box, err = n.ScaleToSize(sizes, "Medium")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, box, n.Box)
+
+	// the original is hidden, the largest size stands for it
+	box, err = n.ScaleToSize(sizes[:2], "Large")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, box, Box{X: 102, Y: 154, W: 205, H: 51})
+
+	_, err = n.ScaleToSize(sizes, "Huge")
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+}
@@ -0,0 +1,189 @@
+package photos
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// A tag of the EXIF, IPTC or XMP metadata of a photo
+type ExifTag struct {
+	// e.g. "IFD0" or "ExifIFD"
+	TagSpace   string `xml:"tagspace,attr"`
+	TagSpaceId string `xml:"tagspaceid,attr"`
+	Tag        string `xml:"tag,attr"`
+	Label      string `xml:"label,attr"`
+	Raw        string `xml:"raw"`
+	// Human readable version of Raw, not provided for every tag
+	Clean string `xml:"clean"`
+}
+
+type ExifResponse struct {
+	flickr.BasicResponse
+	Photo struct {
+		Id     string    `xml:"id,attr"`
+		Secret string    `xml:"secret,attr"`
+		Server string    `xml:"server,attr"`
+		Farm   string    `xml:"farm,attr"`
+		Camera string    `xml:"camera,attr"`
+		Exif   []ExifTag `xml:"exif"`
+	} `xml:"photo"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r ExifResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the raw value of a tag, empty if the photo doesn't have it
+func (r *ExifResponse) Tag(tag string) string {
+	for _, t := range r.Photo.Exif {
+		if t.Tag == tag {
+			return t.Raw
+		}
+	}
+	return ""
+}
+
+// Return the EXIF orientation of the photo, OrientationUnknown if not recorded
+func (r *ExifResponse) Orientation() Orientation {
+	return ParseOrientation(r.Tag("Orientation"))
+}
+
+// Code returned by getExif when the owner of the photo doesn't share its EXIF data
+const exifPermissionDenied = 2
+
+// Get the EXIF, IPTC and XMP metadata of a photo, secret is optional and lets the
+// owner skip permission checks. When the owner doesn't share the EXIF data of their
+// photos the returned error is a flickErr.Error with code ExifHiddenError, see
+// IsExifHidden.
+// This method requires authentication to access private photos.
+func GetExif(client *flickr.FlickrClient, id string, secret string) (*ExifResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.photos.getExif")
+	client.Args.Set("photo_id", id)
+	if secret != "" {
+		client.Args.Set("secret", secret)
+	}
+	client.OAuthSign()
+
+	response := &ExifResponse{}
+	err := flickr.DoGet(client, response)
+	if err != nil && response.ErrorCode() == exifPermissionDenied {
+		e := flickErr.NewError(flickErr.ExifHiddenError, "photo "+id)
+		e.ApiCode = exifPermissionDenied
+		err = e
+	}
+	return response, err
+}
+
+// Return whether err reports a photo whose owner doesn't share its EXIF data
+func IsExifHidden(err error) bool {
+	e, ok := err.(*flickErr.Error)
+	return ok && e.ErrorCode == flickErr.ExifHiddenError
+}
+
+// Get the EXIF metadata of the photos of a list, e.g. the items of a search
+// response, keyed by photo ID. Photos whose owner doesn't share EXIF data are
+// skipped with a warning, photos that can't be found anymore are tombstoned.
+// This method requires authentication to access private photos.
+func GetExifAll(client *flickr.FlickrClient, list []SearchPhoto, opts flickr.BatchOptions) (map[string]*ExifResponse, *flickr.BatchResult, error) {
+	ret := map[string]*ExifResponse{}
+	result := flickr.NewBatchResult()
+	for _, p := range list {
+		resp, err := GetExif(client, p.Id, p.Secret)
+		if IsExifHidden(err) {
+			result.Warn("photo %s: EXIF data not shared by its owner", p.Id)
+			continue
+		}
+		if err == nil {
+			ret[p.Id] = resp
+		}
+		if err := result.AddPhoto(p.Id, p, err, opts); err != nil {
+			return ret, result, err
+		}
+	}
+	return ret, result, nil
+}
+
+// Value of the EXIF Orientation tag, telling how an image must be transformed to be
+// displayed upright
+type Orientation int
+
+const (
+	OrientationUnknown Orientation = iota
+	OrientationNormal
+	OrientationMirrorHorizontal
+	OrientationRotate180
+	OrientationMirrorVertical
+	OrientationMirrorHorizontalRotate270
+	OrientationRotate90
+	OrientationMirrorHorizontalRotate90
+	OrientationRotate270
+)
+
+// Descriptions Flickr reports for the EXIF orientations, the ones of exiftool
+var orientationNames = map[string]Orientation{
+	"horizontal (normal)":                 OrientationNormal,
+	"mirror horizontal":                   OrientationMirrorHorizontal,
+	"rotate 180":                          OrientationRotate180,
+	"mirror vertical":                     OrientationMirrorVertical,
+	"mirror horizontal and rotate 270 cw": OrientationMirrorHorizontalRotate270,
+	"rotate 90 cw":                        OrientationRotate90,
+	"mirror horizontal and rotate 90 cw":  OrientationMirrorHorizontalRotate90,
+	"rotate 270 cw":                       OrientationRotate270,
+}
+
+// Parse an EXIF orientation given either as its number ("6") or as its description
+// ("Rotate 90 CW"), OrientationUnknown if not recognized
+func ParseOrientation(s string) Orientation {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil {
+		if n >= int(OrientationNormal) && n <= int(OrientationRotate270) {
+			return Orientation(n)
+		}
+		return OrientationUnknown
+	}
+	return orientationNames[s]
+}
+
+// Return how to display upright an image with this orientation: mirror it
+// horizontally if mirrored, then rotate it clockwise by degrees (0, 90, 180 or 270)
+func (o Orientation) Transform() (degrees int, mirrored bool) {
+	switch o {
+	case OrientationMirrorHorizontal:
+		return 0, true
+	case OrientationRotate180:
+		return 180, false
+	case OrientationMirrorVertical:
+		return 180, true
+	case OrientationMirrorHorizontalRotate270:
+		return 270, true
+	case OrientationRotate90:
+		return 90, false
+	case OrientationMirrorHorizontalRotate90:
+		return 90, true
+	case OrientationRotate270:
+		return 270, false
+	}
+	return 0, false
+}
+
+// Return how to transform the original file of a photo for correct display, given
+// the EXIF orientation of the original, e.g. from GetExif: mirror it horizontally if
+// mirrored, then rotate it clockwise by degrees. Flickr leaves originals as uploaded,
+// so the rotation applied on Flickr (info.Rotation) adds to the EXIF one; the other
+// sizes are served upright already.
+func OriginalTransform(info *PhotoInfo, orientation Orientation) (degrees int, mirrored bool) {
+	degrees, mirrored = orientation.Transform()
+	return (degrees + info.Rotation) % 360, mirrored
+}
+
+// Return whether the original file of a photo must be rotated or mirrored by the
+// client to be displayed correctly, see OriginalTransform
+func NeedsRotation(info *PhotoInfo, orientation Orientation) bool {
+	degrees, mirrored := OriginalTransform(info, orientation)
+	return degrees != 0 || mirrored
+}
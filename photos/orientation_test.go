@@ -0,0 +1,94 @@
+package photos
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestGetExif(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.getInfo": `<rsp stat="ok"><photo id="42" secret="a" rotation="90" originalsecret="b" originalformat="jpg" media="photo" /></rsp>`,
+		"flickr.photos.getExif": `<rsp stat="ok"><photo id="42" secret="a" server="1" farm="1" camera="Canon EOS 5D">
+  <exif tagspace="IFD0" tagspaceid="0" tag="Make" label="Make"><raw>Canon</raw></exif>
+  <exif tagspace="IFD0" tagspaceid="0" tag="Orientation" label="Orientation"><raw>Rotate 90 CW</raw></exif>
+</photo></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	info, err := GetInfo(fclient, "42", "")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, info.Photo.Rotation, 90)
+	flickr.Expect(t, info.Photo.OriginalSecret, "b")
+	flickr.Expect(t, info.Photo.OriginalFormat, "jpg")
+
+	exif, err := GetExif(fclient, "42", "a")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photos.getExif").Get("secret"), "a")
+	flickr.Expect(t, exif.Photo.Camera, "Canon EOS 5D")
+	flickr.Expect(t, exif.Tag("Make"), "Canon")
+	flickr.Expect(t, exif.Orientation(), OrientationRotate90)
+
+	degrees, mirrored := OriginalTransform(&info.Photo, exif.Orientation())
+	flickr.Expect(t, degrees, 180)
+	flickr.Expect(t, mirrored, false)
+	flickr.Expect(t, NeedsRotation(&info.Photo, exif.Orientation()), true)
+}
+
+func TestOrientation(t *testing.T) {
+	flickr.Expect(t, ParseOrientation("8"), OrientationRotate270)
+	flickr.Expect(t, ParseOrientation("9"), OrientationUnknown)
+	flickr.Expect(t, ParseOrientation("Mirror horizontal and rotate 270 CW"), OrientationMirrorHorizontalRotate270)
+	flickr.Expect(t, ParseOrientation(""), OrientationUnknown)
+
+	upright := &PhotoInfo{}
+	flickr.Expect(t, NeedsRotation(upright, OrientationNormal), false)
+	flickr.Expect(t, NeedsRotation(upright, OrientationUnknown), false)
+	flickr.Expect(t, NeedsRotation(upright, OrientationMirrorHorizontal), true)
+	flickr.Expect(t, NeedsRotation(&PhotoInfo{Rotation: 90}, OrientationRotate270), false)
+
+	degrees, mirrored := OrientationMirrorVertical.Transform()
+	flickr.Expect(t, degrees, 180)
+	flickr.Expect(t, mirrored, true)
+}
+
+func TestGetExifAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("photo_id") {
+		case "1":
+			fmt.Fprint(w, `<rsp stat="ok"><photo id="1"><exif tag="Orientation"><raw>1</raw></exif></photo></rsp>`)
+		case "2":
+			fmt.Fprint(w, `<rsp stat="fail"><err code="2" msg="Permission denied" /></rsp>`)
+		default:
+			fmt.Fprint(w, `<rsp stat="fail"><err code="1" msg="Photo not found" /></rsp>`)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	resp, err := GetExif(fclient, "2", "")
+	flickr.Expect(t, IsExifHidden(err), true)
+	flickr.Expect(t, err.(*flickErr.Error).ApiCode, 2)
+	flickr.Expect(t, resp.ErrorMsg(), "Permission denied")
+	_, err = GetExif(fclient, "3", "")
+	flickr.Expect(t, IsExifHidden(err), false)
+
+	list := []SearchPhoto{{Id: "1"}, {Id: "2"}, {Id: "3"}}
+	exifs, result, err := GetExifAll(fclient, list, flickr.BatchOptions{FailFast: true})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(exifs), 1)
+	flickr.Expect(t, exifs["1"].Orientation(), OrientationNormal)
+	flickr.Expect(t, len(result.Succeeded), 1)
+	flickr.Expect(t, len(result.Warnings), 1)
+	flickr.Expect(t, len(result.Failed), 0)
+	flickr.Expect(t, len(result.Tombstones), 1)
+}
@@ -0,0 +1,170 @@
+package photos
+
+import (
+	"image"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Box is a rectangular region of a photo, as used by notes and people tags.
+// Coordinates are in pixels, relative to the size the box was drawn on.
+type Box struct {
+	X int `xml:"x,attr"`
+	Y int `xml:"y,attr"`
+	W int `xml:"w,attr"`
+	H int `xml:"h,attr"`
+}
+
+// Whether the box is empty, ie. no coordinates were provided
+func (b Box) IsZero() bool {
+	return b.W == 0 && b.H == 0
+}
+
+// Return the box as an image.Rectangle
+func (b Box) Rect() image.Rectangle {
+	return image.Rect(b.X, b.Y, b.X+b.W, b.Y+b.H)
+}
+
+// Scale the box drawn on an image of size from to an image of size to
+func (b Box) Scale(from, to image.Point) Box {
+	if from.X == 0 || from.Y == 0 {
+		return b
+	}
+	sx := float64(to.X) / float64(from.X)
+	sy := float64(to.Y) / float64(from.Y)
+	return Box{
+		X: int(float64(b.X)*sx + 0.5),
+		Y: int(float64(b.Y)*sy + 0.5),
+		W: int(float64(b.W)*sx + 0.5),
+		H: int(float64(b.H)*sy + 0.5),
+	}
+}
+
+// A note attached to a photo
+type Note struct {
+	Box
+	Id         string `xml:"id,attr"`
+	Author     string `xml:"author,attr"`
+	AuthorName string `xml:"authorname,attr"`
+	Text       string `xml:",chardata"`
+}
+
+// A person tagged in a photo, Box is zero when the person wasn't tagged
+// in a specific area of the photo
+type Person struct {
+	Box
+	Nsid       string `xml:"nsid,attr"`
+	Username   string `xml:"username,attr"`
+	Realname   string `xml:"realname,attr"`
+	PathAlias  string `xml:"path_alias,attr"`
+	IconServer string `xml:"iconserver,attr"`
+	IconFarm   string `xml:"iconfarm,attr"`
+	AddedBy    string `xml:"added_by,attr"`
+}
+
+// Return the URL of the buddy icon of the person, see flickr.BuddyIconURL
+func (p *Person) BuddyIconURL() string {
+	return flickr.BuddyIconURL(p.Nsid, p.IconServer, p.IconFarm)
+}
+
+type PeopleListResponse struct {
+	flickr.BasicResponse
+	People struct {
+		Total       int      `xml:"total,attr"`
+		PhotoWidth  int      `xml:"photo_width,attr"`
+		PhotoHeight int      `xml:"photo_height,attr"`
+		Persons     []Person `xml:"person"`
+	} `xml:"people"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PeopleListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Get a list of people in a given photo
+func GetPeople(client *flickr.FlickrClient, photoId string) (*PeopleListResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.people.getList")
+	client.Args.Set("photo_id", photoId)
+	client.OAuthSign()
+
+	response := &PeopleListResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Set the person_* args for the given box, nothing is set for a zero box
+func setPersonBox(client *flickr.FlickrClient, box Box) {
+	if box.IsZero() {
+		return
+	}
+	client.SetInt("person_x", box.X)
+	client.SetInt("person_y", box.Y)
+	client.SetInt("person_w", box.W)
+	client.SetInt("person_h", box.H)
+}
+
+// Add a person to a photo, optionally within the given box (pass a zero Box to
+// tag the whole photo)
+// This method requires authentication with 'write' permission.
+func AddPerson(client *flickr.FlickrClient, photoId, userId string, box Box) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.people.add")
+	client.Args.Set("photo_id", photoId)
+	client.Args.Set("user_id", userId)
+	setPersonBox(client, box)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Remove a person from a photo
+// This method requires authentication with 'write' permission.
+func DeletePerson(client *flickr.FlickrClient, photoId, userId string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.people.delete")
+	client.Args.Set("photo_id", photoId)
+	client.Args.Set("user_id", userId)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Edit the bounding box of an existing person on a photo
+// This method requires authentication with 'write' permission.
+func EditPersonCoords(client *flickr.FlickrClient, photoId, userId string, box Box) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.people.editCoords")
+	client.Args.Set("photo_id", photoId)
+	client.Args.Set("user_id", userId)
+	setPersonBox(client, box)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Remove the bounding box from a person on a photo
+// This method requires authentication with 'write' permission.
+func DeletePersonCoords(client *flickr.FlickrClient, photoId, userId string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.people.deleteCoords")
+	client.Args.Set("photo_id", photoId)
+	client.Args.Set("user_id", userId)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
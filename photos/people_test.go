@@ -0,0 +1,95 @@
+package photos
+
+import (
+	"image"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestBox(t *testing.T) {
+	b := Box{X: 10, Y: 20, W: 100, H: 50}
+	flickr.Expect(t, b.IsZero(), false)
+	flickr.Expect(t, Box{}.IsZero(), true)
+	flickr.Expect(t, b.Rect(), image.Rect(10, 20, 110, 70))
+
+	scaled := b.Scale(image.Pt(500, 375), image.Pt(1000, 750))
+	flickr.Expect(t, scaled, Box{X: 20, Y: 40, W: 200, H: 100})
+
+	scaled = b.Scale(image.Pt(500, 375), image.Pt(100, 75))
+	flickr.Expect(t, scaled, Box{X: 2, Y: 4, W: 20, H: 10})
+
+	// degenerate source size leaves the box untouched
+	flickr.Expect(t, b.Scale(image.Pt(0, 0), image.Pt(100, 75)), b)
+}
+
+func TestGetPeople(t *testing.T) {
+	body := `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <people total="2" photo_width="500" photo_height="375">
+    <person nsid="12037949754@N01" username="Eric" iconserver="1" iconfarm="1" realname="Eric Costello" added_by="12037949754@N01" x="50" y="50" w="100" h="100" />
+    <person nsid="12037949755@N01" username="Bees" iconserver="1" iconfarm="1" realname="Cal Henderson" added_by="12037949754@N01" />
+  </people>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, body, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetPeople(fclient, "123")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.People.Total, 2)
+	flickr.Expect(t, resp.People.PhotoWidth, 500)
+	flickr.Expect(t, len(resp.People.Persons), 2)
+	p := resp.People.Persons[0]
+	flickr.Expect(t, p.Nsid, "12037949754@N01")
+	flickr.Expect(t, p.Realname, "Eric Costello")
+	flickr.Expect(t, p.Box, Box{X: 50, Y: 50, W: 100, H: 100})
+	flickr.Expect(t, resp.People.Persons[1].IsZero(), true)
+}
+
+func TestAddPerson(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, `<rsp stat="fail"><err code="1" msg="Person not found" /></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := AddPerson(fclient, "123", "456@N00", Box{X: 1, Y: 2, W: 3, H: 4})
+	_, ok := err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, resp.ErrorCode(), 1)
+
+	fclient = flickr.GetTestClient()
+	AddPerson(fclient, "123", "456@N00", Box{X: 1, Y: 2, W: 3, H: 4})
+	flickr.AssertParamsInBody(t, fclient, []string{"photo_id", "user_id", "person_x", "person_y", "person_w", "person_h"})
+
+	fclient = flickr.GetTestClient()
+	AddPerson(fclient, "123", "456@N00", Box{})
+	flickr.Expect(t, fclient.Args.Get("person_x"), "")
+}
+
+func TestNotes(t *testing.T) {
+	body := `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photo id="1" media="photo">
+    <notes>
+      <note id="313" author="12037949754@N01" authorname="Bees" x="10" y="10" w="50" h="50">foo</note>
+    </notes>
+    <people haspeople="1" />
+  </photo>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, body, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetInfo(fclient, "1", "")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Photo.People.HasPeople, true)
+	flickr.Expect(t, len(resp.Photo.Notes), 1)
+	n := resp.Photo.Notes[0]
+	flickr.Expect(t, n.Id, "313")
+	flickr.Expect(t, n.Text, "foo")
+	flickr.Expect(t, n.Box, Box{X: 10, Y: 10, W: 50, H: 50})
+}
@@ -0,0 +1,239 @@
+package photos
+
+import (
+	"strings"
+	"sync"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Visibility of a photo
+type Perms struct {
+	IsPublic bool `xml:"ispublic,attr"`
+	IsFriend bool `xml:"isfriend,attr"`
+	IsFamily bool `xml:"isfamily,attr"`
+}
+
+type PermsResponse struct {
+	flickr.BasicResponse
+	Perms struct {
+		Id string `xml:"id,attr"`
+		Perms
+		// who can comment and add notes/tags, from 0 (nobody) to 3 (everybody)
+		PermComment int `xml:"permcomment,attr"`
+		PermAddMeta int `xml:"permaddmeta,attr"`
+	} `xml:"perms"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PermsResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Get permissions for a photo
+// This method requires authentication with 'read' permission.
+func GetPerms(client *flickr.FlickrClient, id string) (*PermsResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.photos.getPerms")
+	client.Args.Set("photo_id", id)
+	client.OAuthSign()
+
+	response := &PermsResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// A rule of a permissions policy
+type PermsRule struct {
+	// Name used to report violations
+	Name string
+	// Whether the rule applies to a photo
+	Match func(p *SearchPhoto) bool
+	// Return the permissions a photo must have given the current ones, a photo
+	// violates the rule when they differ
+	Fix func(current Perms) Perms
+}
+
+// A rule requiring photos with the given tag not to be public, offending photos are
+// fixed by making them visible to family only
+func TaggedNotPublic(tag string) PermsRule {
+	clean := CleanTag(tag)
+	return PermsRule{
+		Name: "photos tagged '" + tag + "' must not be public",
+		Match: func(p *SearchPhoto) bool {
+			for _, t := range strings.Fields(p.Tags) {
+				if t == clean {
+					return true
+				}
+			}
+			return false
+		},
+		Fix: func(current Perms) Perms {
+			if current.IsPublic {
+				return Perms{IsFamily: true}
+			}
+			return current
+		},
+	}
+}
+
+// A photo not complying with a rule
+type PermsViolation struct {
+	Photo    SearchPhoto
+	Rule     string
+	Current  Perms
+	Expected Perms
+}
+
+// Result of AuditPerms
+type PermsAudit struct {
+	// Number of photos checked
+	Scanned    int
+	Violations []PermsViolation
+	// Outcome of the fixes by photo ID, nil unless fixes were requested
+	Fixes *flickr.BatchResult
+}
+
+// Options for AuditPerms
+type AuditPermsOptions struct {
+	// Apply the expected permissions to the photos violating a rule via setPerms
+	Fix bool
+	// Set FailFast to stop at the first fix failing
+	flickr.BatchOptions
+}
+
+// Convert a boolean to the value expected by SetPerms
+func privacy(b bool) PrivacyType {
+	if b {
+		return yes
+	}
+	return no
+}
+
+// Scan the library of a user and report the photos violating the rules, only the
+// first matching rule is applied to each photo. With opts.Fix the violations are
+// corrected through setPerms, photos deleted in the meantime are tombstoned.
+// This method requires authentication with 'read' permission, 'write' to fix.
+func AuditPerms(client *flickr.FlickrClient, userId string, rules []PermsRule, opts AuditPermsOptions) (*PermsAudit, error) {
+	audit := &PermsAudit{Violations: []PermsViolation{}}
+
+	params := &SearchParams{UserId: userId, Extras: "tags", PerPage: flickr.MaxPerPage}
+	for page := 1; ; page++ {
+		params.Page = page
+		resp, err := Search(client, params)
+		if err != nil {
+			return audit, err
+		}
+		for _, p := range resp.Photos.Items {
+			audit.Scanned++
+			current := Perms{IsPublic: p.IsPublic, IsFriend: p.IsFriend, IsFamily: p.IsFamily}
+			for _, rule := range rules {
+				if !rule.Match(&p) {
+					continue
+				}
+				if expected := rule.Fix(current); expected != current {
+					audit.Violations = append(audit.Violations, PermsViolation{
+						Photo: p, Rule: rule.Name, Current: current, Expected: expected,
+					})
+				}
+				break
+			}
+		}
+		if page >= resp.Photos.Pages {
+			break
+		}
+	}
+
+	if !opts.Fix {
+		return audit, nil
+	}
+	audit.Fixes = flickr.NewBatchResult()
+	for _, v := range audit.Violations {
+		e := v.Expected
+		_, err := SetPerms(client, v.Photo.Id, privacy(e.IsPublic), privacy(e.IsFriend), privacy(e.IsFamily))
+		if err := audit.Fixes.AddPhoto(v.Photo.Id, v.Photo, err, opts.BatchOptions); err != nil {
+			return audit, err
+		}
+	}
+	return audit, nil
+}
+
+// Options of SetPermsBatch
+type SetPermsBatchOptions struct {
+	// Maximum number of getPerms calls performed at the same time, 4 when zero
+	Concurrency int
+	// Set FailFast to stop at the first photo failing
+	flickr.BatchOptions
+}
+
+// Give many photos the same visibility, e.g. for privacy sweeps. The current
+// permissions are fetched first, concurrently using clones of client, and setPerms
+// is only called for the photos whose visibility differs: those already matching
+// are reported as warnings and are neither succeeded nor failed. Photos that can't
+// be found anymore are tombstoned.
+// This method requires authentication with 'write' permission.
+func SetPermsBatch(client *flickr.FlickrClient, ids []string, visibility Perms, opts SetPermsBatchOptions) (*flickr.BatchResult, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+
+	current := make([]*Perms, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := false
+	sem := make(chan struct{}, opts.Concurrency)
+	for i := range ids {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := failed && opts.FailFast
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := GetPerms(client.Clone(), ids[i])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[i] = err
+				if !flickr.IsPhotoNotFound(err) {
+					failed = true
+				}
+				return
+			}
+			current[i] = &resp.Perms.Perms
+		}(i)
+	}
+	wg.Wait()
+
+	result := flickr.NewBatchResult()
+	for i, id := range ids {
+		if current[i] == nil && errs[i] == nil {
+			// not fetched because of FailFast
+			break
+		}
+		if errs[i] != nil {
+			if err := result.AddPhoto(id, nil, errs[i], opts.BatchOptions); err != nil {
+				return result, err
+			}
+			continue
+		}
+		if *current[i] == visibility {
+			result.Warn("photo %s already has the requested visibility, not updated", id)
+			continue
+		}
+		v := visibility
+		_, err := SetPerms(client, id, privacy(v.IsPublic), privacy(v.IsFriend), privacy(v.IsFamily))
+		if err := result.AddPhoto(id, nil, err, opts.BatchOptions); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
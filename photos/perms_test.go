@@ -0,0 +1,102 @@
+package photos
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const libraryPage = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photos page="1" pages="1" perpage="500" total="3">
+    <photo id="1" owner="me" secret="a" server="2" farm="1" title="kids" ispublic="1" isfriend="0" isfamily="0" tags="family beach" />
+    <photo id="2" owner="me" secret="a" server="2" farm="1" title="grandma" ispublic="0" isfriend="0" isfamily="1" tags="family" />
+    <photo id="3" owner="me" secret="a" server="2" farm="1" title="sunset" ispublic="1" isfriend="0" isfamily="0" tags="sunset" />
+  </photos>
+</rsp>`
+
+func TestGetPerms(t *testing.T) {
+	body := `<rsp stat="ok"><perms id="2733" ispublic="1" isfriend="1" isfamily="0" permcomment="0" permaddmeta="3" /></rsp>`
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, body, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetPerms(fclient, "2733")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Perms.Id, "2733")
+	flickr.Expect(t, resp.Perms.Perms, Perms{IsPublic: true, IsFriend: true})
+	flickr.Expect(t, resp.Perms.PermAddMeta, 3)
+}
+
+func TestAuditPerms(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search":   libraryPage,
+		"flickr.photos.setPerms": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	rules := []PermsRule{TaggedNotPublic("Family")}
+	audit, err := AuditPerms(fclient, "me", rules, AuditPermsOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, audit.Scanned, 3)
+	flickr.Expect(t, len(audit.Violations), 1)
+	v := audit.Violations[0]
+	flickr.Expect(t, v.Photo.Id, "1")
+	flickr.Expect(t, v.Expected, Perms{IsFamily: true})
+	flickr.Expect(t, audit.Fixes == nil, true)
+	flickr.Expect(t, calls.Last("flickr.photos.setPerms") == nil, true)
+
+	audit, err = AuditPerms(fclient, "me", rules, AuditPermsOptions{Fix: true})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(audit.Fixes.Succeeded), 1)
+	args := calls.Last("flickr.photos.setPerms")
+	flickr.Expect(t, args.Get("photo_id"), "1")
+	flickr.Expect(t, args.Get("is_public"), "0")
+	flickr.Expect(t, args.Get("is_family"), "1")
+}
+
+func TestSetPermsBatch(t *testing.T) {
+	var mu sync.Mutex
+	updated := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.FormValue("photo_id")
+		if r.FormValue("method") == "flickr.photos.setPerms" {
+			mu.Lock()
+			updated = append(updated, id)
+			mu.Unlock()
+			fmt.Fprint(w, `<rsp stat="ok"></rsp>`)
+			return
+		}
+		switch id {
+		case "1", "3":
+			fmt.Fprintf(w, `<rsp stat="ok"><perms id="%s" ispublic="1" isfriend="0" isfamily="0" /></rsp>`, id)
+		case "2":
+			fmt.Fprint(w, `<rsp stat="ok"><perms id="2" ispublic="0" isfriend="0" isfamily="1" /></rsp>`)
+		default:
+			fmt.Fprint(w, `<rsp stat="fail"><err code="1" msg="Photo not found" /></rsp>`)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	result, err := SetPermsBatch(fclient, []string{"1", "2", "3", "4"}, Perms{IsFamily: true}, SetPermsBatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(updated), 2)
+	flickr.Expect(t, updated[0], "1")
+	flickr.Expect(t, updated[1], "3")
+	flickr.Expect(t, len(result.Succeeded), 2)
+	flickr.Expect(t, len(result.Warnings), 1)
+	flickr.Expect(t, result.Warnings[0], "photo 2 already has the requested visibility, not updated")
+	flickr.Expect(t, len(result.Tombstones), 1)
+	flickr.Expect(t, result.Tombstones[0].Id, "4")
+}
@@ -1,9 +1,6 @@
 package photos
 
 import (
-	"strconv"
-	"strings"
-
 	"gopkg.in/masci/flickr.v2"
 )
 
@@ -55,10 +52,21 @@ type PhotoInfo struct {
 		CanPrint    string `xml:"canprint,attr"`
 		CanShare    string `xml:"canshare,attr"`
 	} `xml:"usage"`
-	Comments int   `xml:"comments"`
-	Tags     []Tag `xml:"tags>tag"`
-	// Notes XXX: not handled yet
-	// People XXX: not handled yet
+	// Video details, only provided when Media is "video"
+	Video struct {
+		Ready    bool `xml:"ready,attr"`
+		Failed   bool `xml:"failed,attr"`
+		Pending  bool `xml:"pending,attr"`
+		Duration int  `xml:"duration,attr"`
+		Width    int  `xml:"width,attr"`
+		Height   int  `xml:"height,attr"`
+	} `xml:"video"`
+	Comments int    `xml:"comments"`
+	Tags     []Tag  `xml:"tags>tag"`
+	Notes    []Note `xml:"notes>note"`
+	People   struct {
+		HasPeople bool `xml:"haspeople,attr"`
+	} `xml:"people"`
 	// Urls XXX: not handled yet
 }
 type Tag struct {
@@ -71,6 +79,12 @@ type PhotoInfoResponse struct {
 	flickr.BasicResponse
 	Photo PhotoInfo `xml:"photo"`
 }
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PhotoInfoResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
 type PrivacyType int64
 
 const (
@@ -116,9 +130,9 @@ func SetPerms(client *flickr.FlickrClient, id string, isPublic PrivacyType, IsFr
 	client.HTTPVerb = "POST"
 	client.Args.Set("method", "flickr.photos.setPerms")
 	client.Args.Set("photo_id", id)
-	client.Args.Set("is_public", strconv.Itoa(int(isPublic)))
-	client.Args.Set("is_friend", strconv.Itoa(int(IsFriend)))
-	client.Args.Set("is_family", strconv.Itoa(int(isFamily)))
+	client.SetInt("is_public", int(isPublic))
+	client.SetInt("is_friend", int(IsFriend))
+	client.SetInt("is_family", int(isFamily))
 	client.OAuthSign()
 	response := &flickr.BasicResponse{}
 	err := flickr.DoPost(client, response)
@@ -140,6 +154,28 @@ func Delete(client *flickr.FlickrClient, id string) (*flickr.BasicResponse, erro
 	return response, err
 }
 
+// Rotate a photo clockwise, degrees must be 90, 180 or 270. Rotating changes the
+// secret of the photo, it's invalidated in the PhotoCaches of the client.
+// This method requires authentication with 'write' permission.
+func Rotate(client *flickr.FlickrClient, id string, degrees int) (*flickr.BasicResponse, error) {
+	if err := flickr.ValidateRotation(degrees); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.transform.rotate")
+	client.Args.Set("photo_id", id)
+	client.SetInt("degrees", degrees)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	// the rotation may have been applied even if the response was lost
+	client.InvalidatePhoto(id)
+	return response, err
+}
+
 // Get information about a Flickr photo
 func GetInfo(client *flickr.FlickrClient, id string, secret string) (*PhotoInfoResponse, error) {
 	client.Init()
@@ -157,6 +193,24 @@ func GetInfo(client *flickr.FlickrClient, id string, secret string) (*PhotoInfoR
 	return response, err
 }
 
+// Get information about the photos of a list, e.g. the items of a search response.
+// Photos that can't be found anymore are tombstoned in the result, along with their
+// list entry, instead of aborting. Details are keyed by photo ID.
+func GetInfoAll(client *flickr.FlickrClient, list []SearchPhoto, opts flickr.BatchOptions) (map[string]*PhotoInfo, *flickr.BatchResult, error) {
+	ret := map[string]*PhotoInfo{}
+	result := flickr.NewBatchResult()
+	for _, p := range list {
+		resp, err := GetInfo(client, p.Id, p.Secret)
+		if err == nil {
+			ret[p.Id] = &resp.Photo
+		}
+		if err := result.AddPhoto(p.Id, p, err, opts); err != nil {
+			return ret, result, err
+		}
+	}
+	return ret, result, nil
+}
+
 // Set date posted and date taken on a Flickr photo
 // datePosted and dateTaken are optional and may be set to ""
 func SetDates(client *flickr.FlickrClient, id string, datePosted string, dateTaken string) (*flickr.BasicResponse, error) {
@@ -178,15 +232,69 @@ func SetDates(client *flickr.FlickrClient, id string, datePosted string, dateTak
 	return response, err
 }
 
-// AddTags add tags to an existing photo
+// AddTags add tags to an existing photo, tags are raw tags and may contain spaces
+// This method requires authentication with 'write' permission.
 func AddTags(client *flickr.FlickrClient, photoId string, tags []string) error {
+	if err := flickr.ValidateTagCount(tags); err != nil {
+		return err
+	}
+
 	client.Init()
 	client.EndpointUrl = flickr.API_ENDPOINT
 	client.HTTPVerb = "POST"
 	client.Args.Set("method", "flickr.photos.addTags")
 	client.Args.Set("photo_id", photoId)
-	client.Args.Set("tags", strings.Join(tags, ","))
+	client.Args.Set("tags", flickr.FormatTags(tags))
 	client.OAuthSign()
 	response := &flickr.BasicResponse{}
 	return flickr.DoPost(client, response)
 }
+
+// Replace the tags of a photo, tags are raw tags and may contain spaces. An empty
+// list removes every tag, see MergeTags to keep the existing ones.
+// This method requires authentication with 'write' permission.
+func SetTags(client *flickr.FlickrClient, photoId string, tags []string) (*flickr.BasicResponse, error) {
+	if err := flickr.ValidateTagCount(tags); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.setTags")
+	client.Args.Set("photo_id", photoId)
+	client.Args.Set("tags", flickr.FormatTags(tags))
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Set the title and description of a photo
+// This method requires authentication with 'write' permission.
+func SetMeta(client *flickr.FlickrClient, photoId, title, description string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.EndpointUrl = flickr.API_ENDPOINT
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.setMeta")
+	client.Args.Set("photo_id", photoId)
+	client.Args.Set("title", title)
+	client.Args.Set("description", description)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Same as SetMeta, running the description through sanitize before submitting it.
+// Returns the fragments removed or changed by the sanitizer along with the response.
+// This method requires authentication with 'write' permission.
+func SetMetaSanitized(client *flickr.FlickrClient, photoId, title, description string, sanitize Sanitizer) (*flickr.BasicResponse, []string, error) {
+	var removed []string
+	if sanitize != nil {
+		description, removed = sanitize(description)
+	}
+	response, err := SetMeta(client, photoId, title, description)
+	return response, removed, err
+}
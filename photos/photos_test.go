@@ -1,6 +1,10 @@
 package photos
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"gopkg.in/masci/flickr.v2"
@@ -89,3 +93,84 @@ func TestGetTags(t *testing.T) {
 	}
 	flickr.Expect(t, resp.HasErrors(), false)
 }
+
+func TestSetMeta(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.setMeta": `<?xml version="1.0" encoding="utf-8" ?><rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := SetMeta(fclient, "123", "title", "desc")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.HasErrors(), false)
+	args := calls.Last("flickr.photos.setMeta")
+	flickr.Expect(t, args.Get("photo_id"), "123")
+	flickr.Expect(t, args.Get("title"), "title")
+	flickr.Expect(t, args.Get("description"), "desc")
+
+	_, removed, err := SetMetaSanitized(fclient, "123", "title", "<u>a</u><marquee>b</marquee>", StripDisallowedHTML)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(removed), 2)
+	flickr.Expect(t, calls.Last("flickr.photos.setMeta").Get("description"), "<u>a</u>b")
+
+	_, removed, err = SetMetaSanitized(fclient, "123", "title", "<marquee>b</marquee>", nil)
+	flickr.Expect(t, len(removed), 0)
+	flickr.Expect(t, calls.Last("flickr.photos.setMeta").Get("description"), "<marquee>b</marquee>")
+}
+
+func TestRotate(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.transform.rotate": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := Rotate(fclient, "123", 90)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photos.transform.rotate").Get("degrees"), "90")
+
+	// invalid values never reach the API
+	_, err = Rotate(fclient, "123", 45)
+	e, ok := err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, e.ErrorCode, flickErr.InvalidParamsError)
+	flickr.Expect(t, len(calls.Methods()), 1)
+}
+
+func TestGetInfoAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("photo_id") {
+		case "52435165562":
+			fmt.Fprintln(w, photoInfo)
+		case "2":
+			fmt.Fprintln(w, `<rsp stat="fail"><err code="1" msg="Photo not found" /></rsp>`)
+		default:
+			fmt.Fprintln(w, `<rsp stat="fail"><err code="105" msg="Service currently unavailable" /></rsp>`)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	list := []SearchPhoto{{Id: "52435165562"}, {Id: "2", Title: "gone"}, {Id: "3"}}
+	infos, result, err := GetInfoAll(fclient, list, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(infos), 1)
+	flickr.Expect(t, infos["52435165562"].Title, "Nikki !!")
+	flickr.Expect(t, len(result.Succeeded), 1)
+	flickr.Expect(t, len(result.Failed), 1)
+	flickr.Expect(t, result.Failed[0].Item, "3")
+	flickr.Expect(t, len(result.Tombstones), 1)
+	flickr.Expect(t, result.Tombstones[0].Id, "2")
+	flickr.Expect(t, result.Tombstones[0].Last.(SearchPhoto).Title, "gone")
+
+	// tombstones don't stop the batch, failures do with FailFast
+	_, result, err = GetInfoAll(fclient, list[1:], flickr.BatchOptions{FailFast: true})
+	flickr.Expect(t, err != nil, true)
+	flickr.Expect(t, len(result.Tombstones), 1)
+}
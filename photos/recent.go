@@ -0,0 +1,225 @@
+package photos
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Return the latest public photos uploaded to Flickr, most recent first. extras is an
+// optional comma separated list of extra fields.
+// This method does not require authentication.
+func GetRecent(client *flickr.FlickrClient, extras string, page, perPage int) (*SearchResponse, error) {
+	if err := flickr.ValidatePerPage(perPage); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.photos.getRecent")
+	if extras != "" {
+		client.Args.Set("extras", extras)
+	}
+	// if not provided, flickr defaults this argument to 1
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+	client.ApiSign()
+
+	response := &SearchResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Options of Sample
+type SamplerOptions struct {
+	// Time between two polls, one minute when zero
+	Interval time.Duration
+	// Number of photos fetched per poll, 100 when zero. Uploads beyond it between two
+	// polls are missed, this is a sample.
+	PerPage int
+	// Optional comma separated list of extra fields
+	Extras string
+	// Capacity of the channel, 100 when zero
+	Buffer int
+	// Drop the photos when the channel is full instead of waiting for the consumer,
+	// polls then keep their pace. By default a slow consumer delays the next polls.
+	DropWhenFull bool
+	// Number of IDs remembered to skip the photos returned again by the following
+	// polls, 10000 when zero
+	Memory int
+	// Number of consecutive failed polls stopping the sampler, 3 when zero
+	MaxFailures int
+}
+
+// A photo emitted by a Sampler
+type SampledPhoto struct {
+	SearchPhoto
+	// Time of the poll which returned the photo
+	SampledAt time.Time
+}
+
+// Counters of a Sampler
+type SamplerStats struct {
+	Polls    int
+	Failures int
+	// Photos sent to the channel
+	Emitted int
+	// Photos already emitted by a previous poll
+	Duplicates int
+	// Photos dropped because the channel was full, see SamplerOptions.DropWhenFull
+	Dropped int
+}
+
+// A Sampler polls flickr.photos.getRecent, see Sample
+type Sampler struct {
+	// The sampled photos, oldest first within a poll, closed when the sampler stops
+	C <-chan SampledPhoto
+
+	mu    sync.Mutex
+	stats SamplerStats
+	err   error
+
+	// IDs already emitted, in a ring so that the oldest are forgotten first
+	seen map[string]bool
+	ring []string
+	next int
+}
+
+// Start polling the public recent uploads in the background until ctx is done or
+// too many polls fail in a row, emitting every photo not seen before on Sampler.C.
+// Polls use clones of client. Meant for research and trend analysis: getRecent only
+// returns a window of the uploads, photos uploaded faster than the polls fetch them
+// are missed.
+// This method does not require authentication.
+func Sample(ctx context.Context, client *flickr.FlickrClient, opts SamplerOptions) *Sampler {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.PerPage <= 0 {
+		opts.PerPage = 100
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 100
+	}
+	if opts.Memory <= 0 {
+		opts.Memory = 10000
+	}
+	if opts.MaxFailures <= 0 {
+		opts.MaxFailures = 3
+	}
+
+	c := make(chan SampledPhoto, opts.Buffer)
+	s := &Sampler{C: c, seen: map[string]bool{}, ring: make([]string, opts.Memory)}
+	go s.run(ctx, client, opts, c)
+	return s
+}
+
+// Remember an emitted ID, forgetting the oldest one once the ring is full
+func (s *Sampler) remember(id string) {
+	if old := s.ring[s.next]; old != "" {
+		delete(s.seen, old)
+	}
+	s.ring[s.next] = id
+	s.seen[id] = true
+	s.next = (s.next + 1) % len(s.ring)
+}
+
+// Poll until ctx is done or the failures pile up
+func (s *Sampler) run(ctx context.Context, client *flickr.FlickrClient, opts SamplerOptions, c chan<- SampledPhoto) {
+	defer close(c)
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		now := time.Now()
+		resp, err := GetRecent(client.Clone(), opts.Extras, 1, opts.PerPage)
+		s.mu.Lock()
+		s.stats.Polls++
+		if err != nil {
+			s.stats.Failures++
+			failures++
+			if failures >= opts.MaxFailures {
+				s.err = err
+				s.mu.Unlock()
+				return
+			}
+		} else {
+			failures = 0
+		}
+		s.mu.Unlock()
+
+		if err == nil && !s.emit(ctx, resp.Photos.Items, now, opts.DropWhenFull, c) {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Send the photos not seen before, oldest first, returns false once ctx is done
+func (s *Sampler) emit(ctx context.Context, items []SearchPhoto, now time.Time, drop bool, c chan<- SampledPhoto) bool {
+	for i := len(items) - 1; i >= 0; i-- {
+		if ctx.Err() != nil {
+			return false
+		}
+		p := items[i]
+		s.mu.Lock()
+		if s.seen[p.Id] {
+			s.stats.Duplicates++
+			s.mu.Unlock()
+			continue
+		}
+		s.remember(p.Id)
+		s.mu.Unlock()
+
+		photo := SampledPhoto{SearchPhoto: p, SampledAt: now}
+		sent := true
+		if drop {
+			select {
+			case c <- photo:
+			case <-ctx.Done():
+				return false
+			default:
+				sent = false
+			}
+		} else {
+			select {
+			case c <- photo:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		s.mu.Lock()
+		if sent {
+			s.stats.Emitted++
+		} else {
+			s.stats.Dropped++
+		}
+		s.mu.Unlock()
+	}
+	return true
+}
+
+// Return the counters of the sampler so far
+func (s *Sampler) Stats() SamplerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Return the error of the last poll once the sampler stopped because too many polls
+// failed in a row, nil otherwise
+func (s *Sampler) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
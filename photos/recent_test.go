@@ -0,0 +1,104 @@
+package photos
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestGetRecent(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, `<rsp stat="ok"><photos page="1" pages="10" perpage="2" total="20"><photo id="2" owner="a"/><photo id="1" owner="b"/></photos></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetRecent(fclient, "date_upload", 2, 2)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(resp.Photos.Items), 2)
+	flickr.Expect(t, resp.Photos.Items[0].Id, "2")
+	flickr.Expect(t, fclient.Args.Get("method"), "flickr.photos.getRecent")
+	flickr.Expect(t, fclient.Args.Get("extras"), "date_upload")
+	flickr.Expect(t, fclient.Args.Get("page"), "2")
+	flickr.Expect(t, fclient.Args.Get("per_page"), "2")
+}
+
+// Serve a window of 3 photos sliding by one upload per poll, most recent first
+func recentServer(fail bool) (*httptest.Server, *flickr.FlickrClient) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&polls, 1))
+		if fail {
+			fmt.Fprint(w, `<rsp stat="fail"><err code="105" msg="Service currently unavailable" /></rsp>`)
+			return
+		}
+		fmt.Fprint(w, `<rsp stat="ok"><photos page="1" pages="1" perpage="3" total="3">`)
+		for id := n + 2; id >= n; id-- {
+			fmt.Fprintf(w, `<photo id="%d"/>`, id)
+		}
+		fmt.Fprint(w, `</photos></rsp>`)
+	}))
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+	return server, fclient
+}
+
+func TestSample(t *testing.T) {
+	server, fclient := recentServer(false)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := Sample(ctx, fclient, SamplerOptions{Interval: 5 * time.Millisecond, PerPage: 3, Buffer: 1})
+	for want := 1; want <= 6; want++ {
+		p := <-s.C
+		flickr.Expect(t, p.Id, strconv.Itoa(want))
+		flickr.Expect(t, p.SampledAt.IsZero(), false)
+	}
+	cancel()
+	for range s.C {
+	}
+	stats := s.Stats()
+	flickr.Expect(t, stats.Emitted >= 6, true)
+	flickr.Expect(t, stats.Duplicates >= 2*(stats.Polls-2), true)
+	flickr.Expect(t, stats.Dropped, 0)
+	flickr.Expect(t, s.Err(), nil)
+}
+
+func TestSampleDropWhenFull(t *testing.T) {
+	server, fclient := recentServer(false)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := Sample(ctx, fclient, SamplerOptions{Interval: time.Millisecond, PerPage: 3, Buffer: 2, DropWhenFull: true})
+	for s.Stats().Polls < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	received := 0
+	for range s.C {
+		received++
+	}
+	stats := s.Stats()
+	flickr.Expect(t, received, stats.Emitted)
+	flickr.Expect(t, stats.Dropped > 0, true)
+}
+
+func TestSampleFailures(t *testing.T) {
+	server, fclient := recentServer(true)
+	defer server.Close()
+
+	s := Sample(context.Background(), fclient, SamplerOptions{Interval: time.Millisecond, MaxFailures: 2})
+	for range s.C {
+	}
+	flickr.Expect(t, s.Stats().Polls, 2)
+	flickr.Expect(t, s.Stats().Failures, 2)
+	flickr.Expect(t, s.Err() != nil, true)
+}
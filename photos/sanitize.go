@@ -0,0 +1,94 @@
+package photos
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// A function cleaning up a description before it's sent to Flickr. It returns the
+// sanitized text and the list of fragments it removed or changed, so callers can
+// tell users exactly what happened to their content.
+type Sanitizer func(description string) (string, []string)
+
+// HTML tags Flickr accepts in descriptions, along with the attributes it keeps
+var allowedTags = map[string][]string{
+	"a":          {"href", "title"},
+	"abbr":       {"title"},
+	"acronym":    {"title"},
+	"b":          nil,
+	"blockquote": nil,
+	"cite":       nil,
+	"code":       nil,
+	"del":        nil,
+	"em":         nil,
+	"i":          nil,
+	"ins":        nil,
+	"pre":        nil,
+	"q":          nil,
+	"s":          nil,
+	"strike":     nil,
+	"strong":     nil,
+	"u":          nil,
+}
+
+var (
+	tagRegexp  = regexp.MustCompile(`</?([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^>]*)?)/?>`)
+	attrRegexp = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*("[^"]*"|'[^']*'|[^\s>]+)`)
+)
+
+// Rebuild an allowed tag keeping only the attributes Flickr supports
+func cleanTag(tag, name, attrs string) string {
+	if strings.HasPrefix(tag, "</") {
+		return "</" + name + ">"
+	}
+
+	var buf strings.Builder
+	buf.WriteString("<" + name)
+	for _, m := range attrRegexp.FindAllStringSubmatch(attrs, -1) {
+		attr := strings.ToLower(m[1])
+		for _, allowed := range allowedTags[name] {
+			if attr != allowed {
+				continue
+			}
+			value := strings.ToLower(strings.Trim(m[2], `"'`))
+			// no scripts in links
+			if attr == "href" && strings.HasPrefix(strings.TrimSpace(value), "javascript:") {
+				continue
+			}
+			buf.WriteString(" " + attr + "=" + m[2])
+		}
+	}
+	buf.WriteString(">")
+	return buf.String()
+}
+
+func sanitizeHTML(description string, replace func(tag string) string) (string, []string) {
+	removed := []string{}
+	ret := tagRegexp.ReplaceAllStringFunc(description, func(tag string) string {
+		m := tagRegexp.FindStringSubmatch(tag)
+		name := strings.ToLower(m[1])
+		if _, allowed := allowedTags[name]; allowed {
+			clean := cleanTag(tag, name, m[2])
+			if clean != tag {
+				removed = append(removed, tag)
+			}
+			return clean
+		}
+		removed = append(removed, tag)
+		return replace(tag)
+	})
+	return ret, removed
+}
+
+// A Sanitizer removing HTML tags Flickr doesn't accept, along with unsupported attributes
+// of allowed tags. The text inside removed tags is preserved.
+func StripDisallowedHTML(description string) (string, []string) {
+	return sanitizeHTML(description, func(string) string { return "" })
+}
+
+// A Sanitizer escaping HTML tags Flickr doesn't accept so they're displayed as
+// text instead of being silently dropped
+func EscapeDisallowedHTML(description string) (string, []string) {
+	return sanitizeHTML(description, html.EscapeString)
+}
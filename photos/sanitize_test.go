@@ -0,0 +1,30 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestStripDisallowedHTML(t *testing.T) {
+	in := `<b>Bold</b> <script>alert(1)</script><div class="x">text</div> <a href="http://example.com" onclick="evil()">link</a>`
+	out, removed := StripDisallowedHTML(in)
+	flickr.Expect(t, out, `<b>Bold</b> alert(1)text <a href="http://example.com">link</a>`)
+	flickr.Expect(t, len(removed), 5)
+	flickr.Expect(t, removed[0], "<script>")
+	flickr.Expect(t, removed[4], `<a href="http://example.com" onclick="evil()">`)
+
+	out, removed = StripDisallowedHTML(`<a href="javascript:alert(1)">x</a>`)
+	flickr.Expect(t, out, `<a>x</a>`)
+	flickr.Expect(t, len(removed), 1)
+
+	out, removed = StripDisallowedHTML("nothing to see <i>here</i>")
+	flickr.Expect(t, out, "nothing to see <i>here</i>")
+	flickr.Expect(t, len(removed), 0)
+}
+
+func TestEscapeDisallowedHTML(t *testing.T) {
+	out, removed := EscapeDisallowedHTML(`<em>a</em><span>b</span>`)
+	flickr.Expect(t, out, `<em>a</em>&lt;span&gt;b&lt;/span&gt;`)
+	flickr.Expect(t, len(removed), 2)
+}
@@ -0,0 +1,284 @@
+package photos
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Safe search level, zero means Flickr decides based on the calling user settings
+type SafeSearch int
+
+const (
+	SafeSearchDefault SafeSearch = iota
+	SafeSearchSafe
+	SafeSearchModerate
+	SafeSearchRestricted
+)
+
+// Kind of content to search for, zero means Flickr default (everything)
+type ContentType int
+
+const (
+	ContentDefault ContentType = iota
+	ContentPhotos
+	ContentScreenshots
+	ContentOther
+	ContentPhotosAndScreenshots
+	ContentScreenshotsAndOther
+	ContentPhotosAndOther
+	ContentAll
+)
+
+// Media type to search for, empty means Flickr default ("all")
+type Media string
+
+const (
+	MediaDefault Media = ""
+	MediaAll     Media = "all"
+	MediaPhotos  Media = "photos"
+	MediaVideos  Media = "videos"
+)
+
+// Sort orders accepted by flickr.photos.search
+var searchSorts = map[string]bool{
+	"date-posted-asc":      true,
+	"date-posted-desc":     true,
+	"date-taken-asc":       true,
+	"date-taken-desc":      true,
+	"interestingness-desc": true,
+	"interestingness-asc":  true,
+	"relevance":            true,
+}
+
+// Flickr error codes returned by photos.search when the parameters are rejected
+var searchParamsErrors = map[int]bool{
+	1:  true, // Too many tags in ALL query
+	3:  true, // Parameterless searches have been disabled
+	11: true, // Invalid extra
+	17: true, // Bad value for min/max date
+}
+
+// Parameters for flickr.photos.search, zero values are not sent to Flickr
+type SearchParams struct {
+	UserId string
+	Text   string
+	Tags   []string
+	// "any" (default) or "all"
+	TagMode string
+	// machine tags or patterns, e.g. "checksum:sha256=9f86d081" or "checksum:", see
+	// MachineTag
+	MachineTags []string
+	// geo bounding box, "min_lon,min_lat,max_lon,max_lat"
+	BBox string
+	// unix timestamps, only photos uploaded at or after MinUploadDate and at or before
+	// MaxUploadDate are returned
+	MinUploadDate int64
+	MaxUploadDate int64
+	// one of the sort orders supported by Flickr, e.g. "date-posted-asc"
+	Sort        string
+	SafeSearch  SafeSearch
+	ContentType ContentType
+	Media       Media
+	// comma separated list of extra fields to fetch for each photo
+	Extras  string
+	PerPage int
+	Page    int
+	// Only return photos of Flickr Commons institutions
+	IsCommons bool
+	// Sign the request with the api key only, so that results are the ones visible
+	// to everyone (safe photos not hidden from public searches)
+	Anonymous bool
+}
+
+// Restrict the search to photos that are safe for all audiences, leaving out
+// videos, screenshots and other non-photo content
+func (p *SearchParams) SafePhotosOnly() *SearchParams {
+	p.SafeSearch = SafeSearchSafe
+	p.ContentType = ContentPhotos
+	p.Media = MediaPhotos
+	return p
+}
+
+// Check the parameters without hitting the API, the returned error is a
+// flickErr.Error with code InvalidParamsError
+func (p *SearchParams) Validate() error {
+	return p.validate(true)
+}
+
+// Same as Validate, criteria tells whether at least one search criterion is
+// required, geo searches bring their own
+func (p *SearchParams) validate(criteria bool) error {
+	var invalid = func(format string, a ...interface{}) error {
+		return flickErr.NewError(flickErr.InvalidParamsError, fmt.Sprintf(format, a...))
+	}
+
+	if p.SafeSearch < SafeSearchDefault || p.SafeSearch > SafeSearchRestricted {
+		return invalid("unknown safe_search value %d", p.SafeSearch)
+	}
+	if p.ContentType < ContentDefault || p.ContentType > ContentAll {
+		return invalid("unknown content_type value %d", p.ContentType)
+	}
+	switch p.Media {
+	case MediaDefault, MediaAll, MediaPhotos, MediaVideos:
+	default:
+		return invalid("unknown media value %q", p.Media)
+	}
+	// content types other than photos only make sense for still images
+	if p.Media == MediaVideos && p.ContentType != ContentDefault && p.ContentType != ContentAll {
+		return invalid("content_type %d cannot be combined with media=videos", p.ContentType)
+	}
+	if p.TagMode != "" && p.TagMode != "any" && p.TagMode != "all" {
+		return invalid("tag_mode must be \"any\" or \"all\", got %q", p.TagMode)
+	}
+	if err := flickr.ValidatePerPage(p.PerPage); err != nil {
+		return err
+	}
+	if err := flickr.ValidateBBox(p.BBox); err != nil {
+		return err
+	}
+	if p.Sort != "" && !searchSorts[p.Sort] {
+		return invalid("unknown sort order %q", p.Sort)
+	}
+	if p.Page < 0 {
+		return invalid("page must be positive")
+	}
+	if p.MaxUploadDate != 0 && p.MaxUploadDate < p.MinUploadDate {
+		return invalid("max_upload_date must not be before min_upload_date")
+	}
+	if criteria && p.UserId == "" && p.Text == "" && len(p.Tags) == 0 && len(p.MachineTags) == 0 && p.BBox == "" && p.MinUploadDate == 0 && p.MaxUploadDate == 0 && !p.IsCommons {
+		return invalid("at least one of user_id, text, tags, machine_tags, bbox, min_upload_date, max_upload_date or is_commons is required")
+	}
+	return nil
+}
+
+// A photo as returned by flickr.photos.search
+type SearchPhoto struct {
+	Id       string `xml:"id,attr"`
+	Owner    string `xml:"owner,attr"`
+	Secret   string `xml:"secret,attr"`
+	Server   string `xml:"server,attr"`
+	Farm     string `xml:"farm,attr"`
+	Title    string `xml:"title,attr"`
+	IsPublic bool   `xml:"ispublic,attr"`
+	IsFriend bool   `xml:"isfriend,attr"`
+	IsFamily bool   `xml:"isfamily,attr"`
+	// provided when extras contains "media"
+	Media string `xml:"media,attr"`
+	// unix timestamps, provided when extras contains "date_upload" and "last_update"
+	DateUpload string `xml:"dateupload,attr"`
+	LastUpdate string `xml:"lastupdate,attr"`
+	// MySQL datetime without time zone, provided when extras contains "date_taken"
+	DateTaken string `xml:"datetaken,attr"`
+	// space separated clean tags, provided when extras contains "tags"
+	Tags string `xml:"tags,attr"`
+	// space separated machine tags, provided when extras contains "machine_tags",
+	// see MachineTags
+	MachineTagList string `xml:"machine_tags,attr"`
+	// provided when extras contains "original_format"
+	OriginalSecret string `xml:"originalsecret,attr"`
+	OriginalFormat string `xml:"originalformat,attr"`
+	// provided when extras contains "description"
+	Description string `xml:"description"`
+	// name of the owner, provided by the contacts photos methods
+	Username string `xml:"username,attr"`
+	// license ID, provided when extras contains "license", see LicenseName
+	License string `xml:"license,attr"`
+}
+
+// A list of photos as returned by flickr.photos.search and flickr.photos.recentlyUpdated
+type SearchResponse struct {
+	flickr.BasicResponse
+	Photos struct {
+		Page    int           `xml:"page,attr"`
+		Pages   int           `xml:"pages,attr"`
+		Perpage int           `xml:"perpage,attr"`
+		Total   int           `xml:"total,attr"`
+		Items   []SearchPhoto `xml:"photo"`
+	} `xml:"photos"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r SearchResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Search photos, params are validated before performing the request.
+// When Flickr rejects the combination of parameters the returned error is a
+// flickErr.Error with code InvalidParamsError, the original Flickr error is
+// available in the response.
+func Search(client *flickr.FlickrClient, params *SearchParams) (*SearchResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return search(client, params, nil)
+}
+
+// Perform flickr.photos.search with already validated params, setArgs sets the
+// params not covered by SearchParams if not nil
+func search(client *flickr.FlickrClient, params *SearchParams, setArgs func(*flickr.FlickrClient)) (*SearchResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.photos.search")
+	if params.UserId != "" {
+		client.Args.Set("user_id", params.UserId)
+	}
+	if params.Text != "" {
+		client.Args.Set("text", params.Text)
+	}
+	client.SetCSV("tags", params.Tags)
+	if params.TagMode != "" {
+		client.Args.Set("tag_mode", params.TagMode)
+	}
+	client.SetCSV("machine_tags", params.MachineTags)
+	if params.BBox != "" {
+		client.Args.Set("bbox", params.BBox)
+	}
+	if params.MinUploadDate > 0 {
+		client.SetTime("min_upload_date", time.Unix(params.MinUploadDate, 0), flickr.UnixDate)
+	}
+	if params.MaxUploadDate > 0 {
+		client.SetTime("max_upload_date", time.Unix(params.MaxUploadDate, 0), flickr.UnixDate)
+	}
+	if params.Sort != "" {
+		client.Args.Set("sort", params.Sort)
+	}
+	if params.SafeSearch != SafeSearchDefault {
+		client.SetInt("safe_search", int(params.SafeSearch))
+	}
+	if params.ContentType != ContentDefault {
+		client.SetInt("content_type", int(params.ContentType))
+	}
+	if params.Media != MediaDefault {
+		client.Args.Set("media", string(params.Media))
+	}
+	if params.IsCommons {
+		client.SetBool("is_commons", true)
+	}
+	if params.Extras != "" {
+		client.Args.Set("extras", params.Extras)
+	}
+	if params.PerPage > 0 {
+		client.SetInt("per_page", params.PerPage)
+	}
+	// if not provided, flickr defaults this argument to 1
+	if params.Page > 1 {
+		client.SetInt("page", params.Page)
+	}
+	if setArgs != nil {
+		setArgs(client)
+	}
+	if params.Anonymous {
+		client.ApiSign()
+	} else {
+		client.OAuthSign()
+	}
+
+	response := &SearchResponse{}
+	err := flickr.DoGet(client, response)
+	if err != nil && searchParamsErrors[response.ErrorCode()] {
+		err = flickErr.NewError(flickErr.InvalidParamsError, response.ErrorMsg())
+	}
+	return response, err
+}
@@ -0,0 +1,110 @@
+package photos
+
+import (
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Maximum number of results Flickr returns for a search, the pages beyond it repeat
+// the last photos
+const MaxSearchResults = 4000
+
+// Lower bound of the upload dates when a search is split, Flickr opened in February 2004
+const firstUploadDate = 1075593600
+
+// State of a SearchAll
+type searchSplitter struct {
+	client *flickr.FlickrClient
+	params SearchParams
+	fn     func(*SearchPhoto) error
+	seen   map[string]bool
+	stats  flickr.PaginationStats
+	// error returned by fn, it stops the walk
+	fnErr error
+}
+
+// Walk the results uploaded between min and max, zero meaning unbounded, splitting the
+// window in halves as long as it holds more than MaxSearchResults
+func (s *searchSplitter) walk(min, max int64) error {
+	params := s.params
+	params.MinUploadDate, params.MaxUploadDate = min, max
+	params.PerPage = flickr.MaxPerPage
+	params.Page = 0
+	resp, err := Search(s.client, &params)
+	if err != nil {
+		return err
+	}
+
+	if resp.Photos.Total > MaxSearchResults {
+		lo, hi := min, max
+		if lo == 0 {
+			lo = firstUploadDate
+		}
+		if hi == 0 {
+			hi = time.Now().Unix()
+		}
+		// a single second holding more than MaxSearchResults can't be split further
+		if hi > lo {
+			mid := lo + (hi-lo)/2
+			if err := s.walk(lo, mid); err != nil {
+				return err
+			}
+			return s.walk(mid+1, hi)
+		}
+	}
+
+	it := flickr.NewPageIterator(func(page int) (*flickr.ListInfo, error) {
+		if page > 1 {
+			params.Page = page
+			var err error
+			if resp, err = Search(s.client, &params); err != nil {
+				return nil, err
+			}
+		}
+		return &flickr.ListInfo{Page: page, Pages: resp.Photos.Pages, PerPage: resp.Photos.Perpage, Total: resp.Photos.Total}, nil
+	}, flickr.PageIteratorOptions{Dedupe: true})
+	for s.fnErr == nil && it.Next() {
+		for i := range resp.Photos.Items {
+			p := &resp.Photos.Items[i]
+			if !it.Keep(p.Id) {
+				continue
+			}
+			if s.seen[p.Id] {
+				s.stats.Duplicates++
+				continue
+			}
+			s.seen[p.Id] = true
+			if s.fnErr = s.fn(p); s.fnErr != nil {
+				break
+			}
+		}
+	}
+	stats := it.Stats()
+	s.stats.Pages += stats.Pages
+	s.stats.Items += stats.Items
+	s.stats.Duplicates += stats.Duplicates
+	s.stats.TotalChanged = s.stats.TotalChanged || stats.TotalChanged
+	if s.fnErr != nil {
+		return s.fnErr
+	}
+	return it.Err()
+}
+
+// Call fn for every photo matching params, even when the search holds more than the
+// MaxSearchResults Flickr returns for a single query: such searches are split in
+// upload date windows, halved until each of them holds few enough results, walked
+// from the oldest to the most recent. The results of each window come in the order of
+// params.Sort, use "date-posted-asc" for a globally sorted walk. Photos returned by
+// several pages or windows, because of uploads made during the walk, are only
+// reported once. Each split costs an extra search call. Returning an error from fn
+// stops the walk.
+// This method does not require authentication unless params needs it.
+func SearchAll(client *flickr.FlickrClient, params SearchParams, fn func(*SearchPhoto) error) (flickr.PaginationStats, error) {
+	if err := params.Validate(); err != nil {
+		return flickr.PaginationStats{}, err
+	}
+	s := &searchSplitter{client: client, params: params, fn: fn, seen: map[string]bool{}}
+	err := s.walk(params.MinUploadDate, params.MaxUploadDate)
+	return s.stats, err
+}
@@ -0,0 +1,113 @@
+package photos
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Serve a search over photos uploaded at the given dates, capped at MaxSearchResults
+// like Flickr does
+func searchCapServer(dates []int64, searches *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*searches++
+		q := r.URL.Query()
+		min, _ := strconv.ParseInt(q.Get("min_upload_date"), 10, 64)
+		max, _ := strconv.ParseInt(q.Get("max_upload_date"), 10, 64)
+		matching := []int{}
+		for i, d := range dates {
+			if (min == 0 || d >= min) && (max == 0 || d <= max) {
+				matching = append(matching, i)
+			}
+		}
+		perPage, _ := strconv.Atoi(q.Get("per_page"))
+		page, _ := strconv.Atoi(q.Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		pages := (len(matching) + perPage - 1) / perPage
+		// pages beyond the cap repeat the last one
+		if last := MaxSearchResults / perPage; page > last {
+			page = last
+		}
+		fmt.Fprintf(w, `<rsp stat="ok"><photos page="%d" pages="%d" perpage="%d" total="%d">`, page, pages, perPage, len(matching))
+		for n := (page - 1) * perPage; n < page*perPage && n < len(matching); n++ {
+			fmt.Fprintf(w, `<photo id="%d" owner="me" dateupload="%d"/>`, matching[n], dates[matching[n]])
+		}
+		fmt.Fprint(w, `</photos></rsp>`)
+	}))
+}
+
+func TestSearchAll(t *testing.T) {
+	dates := []int64{}
+	for i := 0; i < 9000; i++ {
+		dates = append(dates, 1500000000+int64(i)*60)
+	}
+	searches := 0
+	server := searchCapServer(dates, &searches)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	seen := map[string]int{}
+	stats, err := SearchAll(fclient, SearchParams{Tags: []string{"cat"}, Sort: "date-posted-asc"}, func(p *SearchPhoto) error {
+		seen[p.Id]++
+		return nil
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(seen), 9000)
+	for id, n := range seen {
+		if n != 1 {
+			t.Fatalf("photo %s reported %d times", id, n)
+		}
+	}
+	flickr.Expect(t, stats.Items >= 9000, true)
+	flickr.Expect(t, searches > stats.Pages, true)
+
+	// small searches are not split
+	searches = 0
+	count := 0
+	_, err = SearchAll(fclient, SearchParams{Tags: []string{"cat"}, MinUploadDate: dates[8000]}, func(p *SearchPhoto) error {
+		count++
+		return nil
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, count, 1000)
+	flickr.Expect(t, searches, 2)
+}
+
+func TestSearchAllStop(t *testing.T) {
+	dates := []int64{}
+	for i := 0; i < 1200; i++ {
+		dates = append(dates, 1500000000+int64(i))
+	}
+	searches := 0
+	server := searchCapServer(dates, &searches)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	stop := errors.New("enough")
+	count := 0
+	_, err := SearchAll(fclient, SearchParams{UserId: "me"}, func(p *SearchPhoto) error {
+		count++
+		if count == 10 {
+			return stop
+		}
+		return nil
+	})
+	flickr.Expect(t, err, stop)
+	flickr.Expect(t, count, 10)
+	flickr.Expect(t, searches, 1)
+
+	_, err = SearchAll(fclient, SearchParams{UserId: "me", MinUploadDate: 20, MaxUploadDate: 10}, nil)
+	flickr.Expect(t, err != nil, true)
+}
@@ -0,0 +1,83 @@
+package photos
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func expectInvalidParams(t *testing.T, err error) {
+	e, ok := err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, e.ErrorCode, flickErr.InvalidParamsError)
+}
+
+func TestSearchParamsValidate(t *testing.T) {
+	p := &SearchParams{Text: "sunset"}
+	flickr.Expect(t, p.Validate(), nil)
+	flickr.Expect(t, p.SafePhotosOnly().Validate(), nil)
+
+	expectInvalidParams(t, (&SearchParams{}).Validate())
+	expectInvalidParams(t, (&SearchParams{Text: "a", SafeSearch: 4}).Validate())
+	expectInvalidParams(t, (&SearchParams{Text: "a", ContentType: 8}).Validate())
+	expectInvalidParams(t, (&SearchParams{Text: "a", Media: "gifs"}).Validate())
+	expectInvalidParams(t, (&SearchParams{Text: "a", Media: MediaVideos, ContentType: ContentScreenshots}).Validate())
+	expectInvalidParams(t, (&SearchParams{Text: "a", TagMode: "some"}).Validate())
+	expectInvalidParams(t, (&SearchParams{Text: "a", PerPage: 501}).Validate())
+}
+
+func TestSearch(t *testing.T) {
+	body := `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photos page="1" pages="1" perpage="100" total="1">
+    <photo id="2636" owner="47058503995@N01" secret="a123456" server="2" farm="1" title="test_04" ispublic="1" isfriend="0" isfamily="0" media="photo" />
+  </photos>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search": body,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	params := &SearchParams{Tags: []string{"sea", "sunset"}}
+	resp, err := Search(fclient, params.SafePhotosOnly())
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(resp.Photos.Items), 1)
+	flickr.Expect(t, resp.Photos.Items[0].Media, "photo")
+
+	args := calls.Last("flickr.photos.search")
+	flickr.Expect(t, args.Get("tags"), "sea,sunset")
+	flickr.Expect(t, args.Get("safe_search"), "1")
+	flickr.Expect(t, args.Get("content_type"), "1")
+	flickr.Expect(t, args.Get("media"), "photos")
+	flickr.Expect(t, args.Get("page"), "")
+
+	data, err := json.Marshal(resp)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, strings.HasPrefix(string(data), `{"photos":{"page":1,"pages":1,"perpage":100,"total":1,"photo":[{"id":"2636",`), true)
+}
+
+func TestSearchRejected(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, `<rsp stat="fail"><err code="1" msg="Too many tags in ALL query" /></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := Search(fclient, &SearchParams{Tags: []string{"a", "b"}, TagMode: "all"})
+	expectInvalidParams(t, err)
+	flickr.Expect(t, resp.ErrorCode(), 1)
+
+	// invalid params never reach the API
+	resp, err = Search(fclient, &SearchParams{})
+	expectInvalidParams(t, err)
+	flickr.Expect(t, resp == nil, true)
+}
+
+func TestSearchParamsBBox(t *testing.T) {
+	flickr.Expect(t, (&SearchParams{BBox: "-122.5,37.7,-122.3,37.8"}).Validate(), nil)
+	expectInvalidParams(t, (&SearchParams{BBox: "-122.5,37.7"}).Validate())
+}
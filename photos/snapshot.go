@@ -0,0 +1,184 @@
+package photos
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Extra fields needed to build snapshot entries
+const snapshotExtras = "last_update,tags,description,original_format,media"
+
+// The state of a photo in a LibrarySnapshot
+type SnapshotEntry struct {
+	Id string `json:"id"`
+	// Hash of the fields identifying the file (server, secrets, format), it changes
+	// when the photo is replaced. Flickr doesn't expose checksums of the files.
+	Checksum string `json:"checksum"`
+	// Hash of title, description, tags, visibility and media type
+	MetaHash string `json:"meta_hash"`
+	// Unix timestamp of the last change
+	LastUpdate int64 `json:"last_update"`
+}
+
+// The state of all the photos of a library at a given time, to be persisted as JSON
+// and compared with a later snapshot with DiffSnapshots
+type LibrarySnapshot struct {
+	Taken  time.Time                `json:"taken"`
+	Photos map[string]SnapshotEntry `json:"photos"`
+}
+
+// Kinds of changes between two snapshots
+type ChangeKind string
+
+const (
+	PhotoAdded    ChangeKind = "added"
+	PhotoModified ChangeKind = "modified"
+	PhotoDeleted  ChangeKind = "deleted"
+)
+
+// A change between two snapshots, Before is nil for added photos and After is nil
+// for deleted ones
+type Change struct {
+	Kind   ChangeKind
+	Id     string
+	Before *SnapshotEntry
+	After  *SnapshotEntry
+}
+
+// Whether the file of a modified photo was replaced
+func (c *Change) FileChanged() bool {
+	return c.Before != nil && c.After != nil && c.Before.Checksum != c.After.Checksum
+}
+
+// Whether the metadata of a modified photo changed
+func (c *Change) MetaChanged() bool {
+	return c.Before != nil && c.After != nil && c.Before.MetaHash != c.After.MetaHash
+}
+
+// Create an empty snapshot
+func NewLibrarySnapshot() *LibrarySnapshot {
+	return &LibrarySnapshot{Taken: time.Now(), Photos: map[string]SnapshotEntry{}}
+}
+
+// Load a snapshot from a JSON file
+func LoadLibrarySnapshot(path string) (*LibrarySnapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := NewLibrarySnapshot()
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write the snapshot to a JSON file
+func (s *LibrarySnapshot) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Load a snapshot stored under key with SaveTo, a missing key yields an empty
+// snapshot so that the first run of a sync job sees the whole library as added
+func LoadLibrarySnapshotFrom(store flickr.Store, key string) (*LibrarySnapshot, error) {
+	s := NewLibrarySnapshot()
+	if _, err := flickr.LoadJSON(store, key, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Store the snapshot as JSON under key
+func (s *LibrarySnapshot) SaveTo(store flickr.Store, key string) error {
+	return flickr.SaveJSON(store, key, s)
+}
+
+// Hash a list of values
+func hashFields(fields ...string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(fields, "\x00"))))
+}
+
+// Record the state of a photo, the search must have been performed with the extras
+// last_update, tags, description, original_format and media
+func (s *LibrarySnapshot) Add(p *SearchPhoto) {
+	s.Photos[p.Id] = newSnapshotEntry(p)
+}
+
+// Return the state of a photo, see Add
+func newSnapshotEntry(p *SearchPhoto) SnapshotEntry {
+	// tags order is not significant
+	tags := strings.Fields(p.Tags)
+	sort.Strings(tags)
+
+	lastUpdate, _ := strconv.ParseInt(p.LastUpdate, 10, 64)
+	return SnapshotEntry{
+		Id:       p.Id,
+		Checksum: hashFields(p.Server, p.Secret, p.OriginalSecret, p.OriginalFormat),
+		MetaHash: hashFields(p.Title, p.Description, strings.Join(tags, " "), p.Media,
+			strconv.FormatBool(p.IsPublic), strconv.FormatBool(p.IsFriend), strconv.FormatBool(p.IsFamily)),
+		LastUpdate: lastUpdate,
+	}
+}
+
+// Take a snapshot of all the photos of a user, "me" for the calling user
+// This method requires authentication with 'read' permission.
+func TakeLibrarySnapshot(client *flickr.FlickrClient, userId string) (*LibrarySnapshot, error) {
+	snap := NewLibrarySnapshot()
+	params := SearchParams{UserId: userId, Extras: snapshotExtras}
+	err := searchAll(client, params, func(p *SearchPhoto) {
+		snap.Add(p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// Compare two snapshots, changes are sorted by photo ID
+func DiffSnapshots(before, after *LibrarySnapshot) []Change {
+	changes := []Change{}
+	for id, a := range after.Photos {
+		a := a
+		b, found := before.Photos[id]
+		switch {
+		case !found:
+			changes = append(changes, Change{Kind: PhotoAdded, Id: id, After: &a})
+		case b.Checksum != a.Checksum || b.MetaHash != a.MetaHash:
+			changes = append(changes, Change{Kind: PhotoModified, Id: id, Before: &b, After: &a})
+		}
+	}
+	for id, b := range before.Photos {
+		b := b
+		if _, found := after.Photos[id]; !found {
+			changes = append(changes, Change{Kind: PhotoDeleted, Id: id, Before: &b})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Id < changes[j].Id
+	})
+	return changes
+}
+
+// Compare a snapshot with the current state of the library of a user, returning the
+// changes along with the new snapshot to be saved for the next comparison
+// This method requires authentication with 'read' permission.
+func DiffLive(client *flickr.FlickrClient, before *LibrarySnapshot, userId string) ([]Change, *LibrarySnapshot, error) {
+	after, err := TakeLibrarySnapshot(client, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return DiffSnapshots(before, after), after, nil
+}
@@ -0,0 +1,76 @@
+package photos
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const snapshotPage = `<rsp stat="ok"><photos page="1" pages="1" perpage="500" total="2">
+  <photo id="1" owner="me" secret="a" server="2" title="beach" ispublic="1" isfriend="0" isfamily="0" lastupdate="1700000000" tags="sea beach" originalsecret="o1" originalformat="jpg" media="photo"><description>Summer</description></photo>
+  <photo id="2" owner="me" secret="b" server="2" title="sunset" ispublic="0" isfriend="0" isfamily="1" lastupdate="1700000100" tags="" originalsecret="o2" originalformat="jpg" media="photo"><description /></photo>
+</photos></rsp>`
+
+func TestTakeLibrarySnapshot(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search": snapshotPage,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	snap, err := TakeLibrarySnapshot(fclient, "me")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(snap.Photos), 2)
+	flickr.Expect(t, snap.Photos["2"].LastUpdate, int64(1700000100))
+	flickr.Expect(t, calls.Last("flickr.photos.search").Get("extras"), snapshotExtras)
+
+	dir, _ := ioutil.TempDir("", "flickr")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "snapshot.json")
+	flickr.Expect(t, snap.Save(path), nil)
+	loaded, err := LoadLibrarySnapshot(path)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, loaded.Photos["1"], snap.Photos["1"])
+	flickr.Expect(t, len(DiffSnapshots(loaded, snap)), 0)
+
+	store := flickr.NewMemoryStore()
+	empty, err := LoadLibrarySnapshotFrom(store, "snapshot")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(DiffSnapshots(empty, snap)), 2)
+	flickr.Expect(t, snap.SaveTo(store, "snapshot"), nil)
+	loaded, err = LoadLibrarySnapshotFrom(store, "snapshot")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, loaded.Photos["2"], snap.Photos["2"])
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	photo := func(id, secret, title, tags string) *SearchPhoto {
+		return &SearchPhoto{Id: id, Server: "2", Secret: secret, Title: title, Tags: tags}
+	}
+	before := NewLibrarySnapshot()
+	before.Add(photo("1", "a", "beach", "sea beach"))
+	before.Add(photo("2", "b", "sunset", ""))
+	before.Add(photo("3", "c", "old", ""))
+
+	after := NewLibrarySnapshot()
+	// same tags in a different order
+	after.Add(photo("1", "a", "beach", "beach sea"))
+	after.Add(photo("2", "x", "sunset!", ""))
+	after.Add(photo("4", "d", "new", ""))
+
+	changes := DiffSnapshots(before, after)
+	flickr.Expect(t, len(changes), 3)
+	flickr.Expect(t, changes[0].Id, "2")
+	flickr.Expect(t, changes[0].Kind, PhotoModified)
+	flickr.Expect(t, changes[0].FileChanged(), true)
+	flickr.Expect(t, changes[0].MetaChanged(), true)
+	flickr.Expect(t, changes[1].Kind, PhotoDeleted)
+	flickr.Expect(t, changes[1].After == nil, true)
+	flickr.Expect(t, changes[2].Kind, PhotoAdded)
+	flickr.Expect(t, changes[2].Id, "4")
+	flickr.Expect(t, changes[2].FileChanged(), false)
+}
@@ -0,0 +1,205 @@
+package photos
+
+import (
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/places"
+)
+
+// Status of a location suggestion
+type SuggestionStatus int
+
+const (
+	SuggestionPending SuggestionStatus = iota
+	SuggestionApproved
+	SuggestionRejected
+)
+
+// A location suggested by another user for a photo
+type Suggestion struct {
+	Id      string `xml:"id,attr"`
+	PhotoId string `xml:"photo_id,attr"`
+	// Unix timestamp
+	DateSuggested string `xml:"date_suggested,attr"`
+	SuggestedBy   struct {
+		Nsid     string `xml:"nsid,attr"`
+		Username string `xml:"username,attr"`
+	} `xml:"suggested_by"`
+	Note     string `xml:"note"`
+	Location struct {
+		Latitude  float64 `xml:"latitude,attr"`
+		Longitude float64 `xml:"longitude,attr"`
+		Accuracy  int     `xml:"accuracy,attr"`
+		WoeId     string  `xml:"woeid,attr"`
+		PlaceId   string  `xml:"place_id,attr"`
+	} `xml:"location"`
+}
+
+type SuggestionsResponse struct {
+	flickr.BasicResponse
+	Suggestions struct {
+		Total   int          `xml:"total,attr"`
+		Page    int          `xml:"page,attr"`
+		PerPage int          `xml:"per_page,attr"`
+		Items   []Suggestion `xml:"suggestion"`
+	} `xml:"suggestions"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r SuggestionsResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the location suggestions with the given status for a photo of the calling
+// user, or for all of them when photoId is empty
+// This method requires authentication with 'read' permission.
+func GetSuggestions(client *flickr.FlickrClient, photoId string, status SuggestionStatus) (*SuggestionsResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.photos.suggestions.getList")
+	if photoId != "" {
+		client.Args.Set("photo_id", photoId)
+	}
+	client.SetInt("status_id", int(status))
+	client.OAuthSign()
+
+	response := &SuggestionsResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Act on a suggestion with one of the flickr.photos.suggestions methods
+func suggestionCall(client *flickr.FlickrClient, method, suggestionId string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", method)
+	client.Args.Set("suggestion_id", suggestionId)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Approve a location suggestion, the photo is geotagged with it
+// This method requires authentication with 'write' permission.
+func ApproveSuggestion(client *flickr.FlickrClient, suggestionId string) (*flickr.BasicResponse, error) {
+	return suggestionCall(client, "flickr.photos.suggestions.approveSuggestion", suggestionId)
+}
+
+// Reject a location suggestion
+// This method requires authentication with 'write' permission.
+func RejectSuggestion(client *flickr.FlickrClient, suggestionId string) (*flickr.BasicResponse, error) {
+	return suggestionCall(client, "flickr.photos.suggestions.rejectSuggestion", suggestionId)
+}
+
+// Remove a location suggestion made by the calling user
+// This method requires authentication with 'write' permission.
+func RemoveSuggestion(client *flickr.FlickrClient, suggestionId string) (*flickr.BasicResponse, error) {
+	return suggestionCall(client, "flickr.photos.suggestions.removeSuggestion", suggestionId)
+}
+
+// Suggest a location for a photo of another user, with an accuracy from 1 to 16.
+// place and note are optional.
+// This method requires authentication with 'write' permission.
+func SuggestLocation(client *flickr.FlickrClient, photoId string, lat, lon float64, accuracy int, place PlaceRef, note string) (*flickr.BasicResponse, error) {
+	if err := flickr.ValidateLocation(lat, lon, accuracy); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.suggestions.suggestLocation")
+	client.Args.Set("photo_id", photoId)
+	client.SetFloat("lat", lat)
+	client.SetFloat("lon", lon)
+	client.SetInt("accuracy", accuracy)
+	if place.PlaceId != "" || place.WoeId != "" {
+		place.setArgs(client)
+	}
+	if note != "" {
+		client.Args.Set("note", note)
+	}
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// What to do with a suggestion under review, see ReviewSuggestions
+type SuggestionDecision int
+
+const (
+	// Leave the suggestion pending, e.g. for a human to look at it
+	DecisionDefer SuggestionDecision = iota
+	DecisionApprove
+	DecisionReject
+)
+
+// A pending suggestion along with the name of the place suggested
+type ReviewedSuggestion struct {
+	Suggestion
+	// Name of the place suggested, e.g. "Montreal, Quebec, Canada", empty when the
+	// suggestion has no place or it couldn't be resolved
+	PlaceName string
+	Decision  SuggestionDecision
+}
+
+// Resolve the name of the place of a suggestion, names are cached by place
+func suggestionPlaceName(client *flickr.FlickrClient, s *Suggestion, names map[string]string) (string, error) {
+	key := s.Location.PlaceId + "/" + s.Location.WoeId
+	if key == "/" {
+		return "", nil
+	}
+	if name, found := names[key]; found {
+		return name, nil
+	}
+	resp, err := places.GetInfo(client, s.Location.PlaceId, s.Location.WoeId)
+	if err != nil {
+		return "", err
+	}
+	names[key] = resp.Place.Name()
+	return names[key], nil
+}
+
+// Review the pending location suggestions for all the photos of the calling user:
+// the place of every suggestion is resolved to its name with flickr.places.getInfo,
+// then decide tells whether to approve, reject or leave it pending. Places that
+// can't be resolved are reported as warnings and left unnamed. Returns the
+// suggestions reviewed and the outcome of every approval or rejection, keyed by
+// suggestion ID, the batch result records the decision of every suggestion in its
+// Actions: "approved", "rejected" or "deferred".
+// This method requires authentication with 'write' permission.
+func ReviewSuggestions(client *flickr.FlickrClient, decide func(*ReviewedSuggestion) SuggestionDecision, opts flickr.BatchOptions) ([]ReviewedSuggestion, *flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	resp, err := GetSuggestions(client, "", SuggestionPending)
+	if err != nil {
+		return nil, result, err
+	}
+
+	names := map[string]string{}
+	reviewed := make([]ReviewedSuggestion, 0, len(resp.Suggestions.Items))
+	for _, s := range resp.Suggestions.Items {
+		r := ReviewedSuggestion{Suggestion: s}
+		if r.PlaceName, err = suggestionPlaceName(client, &s, names); err != nil {
+			result.Warn("suggestion %s: can't resolve the place: %s", s.Id, err)
+		}
+		r.Decision = decide(&r)
+		reviewed = append(reviewed, r)
+
+		switch r.Decision {
+		case DecisionApprove:
+			result.SetAction(s.Id, "approved")
+			_, err = ApproveSuggestion(client, s.Id)
+		case DecisionReject:
+			result.SetAction(s.Id, "rejected")
+			_, err = RejectSuggestion(client, s.Id)
+		default:
+			result.SetAction(s.Id, "deferred")
+			continue
+		}
+		if err := result.Add(s.Id, err, opts); err != nil {
+			return reviewed, result, err
+		}
+	}
+	return reviewed, result, nil
+}
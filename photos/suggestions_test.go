@@ -0,0 +1,85 @@
+package photos
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestReviewSuggestions(t *testing.T) {
+	suggestions := `<rsp stat="ok"><suggestions total="3" page="1" per_page="100">
+  <suggestion id="s1" photo_id="1" date_suggested="1273775231">
+    <suggested_by nsid="2@N00" username="ann" />
+    <note>That's the old port</note>
+    <location latitude="45.512" longitude="-73.554" accuracy="16" woeid="3534" place_id="4hLQygSaBJ92" />
+  </suggestion>
+  <suggestion id="s2" photo_id="2" date_suggested="1273775232">
+    <suggested_by nsid="3@N00" username="bob" />
+    <location latitude="45.5" longitude="-73.5" accuracy="11" woeid="3534" place_id="4hLQygSaBJ92" />
+  </suggestion>
+  <suggestion id="s3" photo_id="3" date_suggested="1273775233">
+    <suggested_by nsid="4@N00" username="cid" />
+    <location latitude="1" longitude="1" accuracy="3" />
+  </suggestion>
+</suggestions></rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.suggestions.getList":           suggestions,
+		"flickr.places.getInfo":                       `<rsp stat="ok"><place place_id="4hLQygSaBJ92" woeid="3534" name="Montreal, Quebec, Canada" /></rsp>`,
+		"flickr.photos.suggestions.approveSuggestion": `<rsp stat="ok"></rsp>`,
+		"flickr.photos.suggestions.rejectSuggestion":  `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	reviewed, result, err := ReviewSuggestions(fclient, func(s *ReviewedSuggestion) SuggestionDecision {
+		switch {
+		case s.PlaceName == "":
+			return DecisionDefer
+		case s.SuggestedBy.Username == "ann":
+			return DecisionApprove
+		}
+		return DecisionReject
+	}, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(reviewed), 3)
+	flickr.Expect(t, reviewed[0].PlaceName, "Montreal, Quebec, Canada")
+	flickr.Expect(t, reviewed[0].Note, "That's the old port")
+	flickr.Expect(t, reviewed[1].Location.Accuracy, 11)
+	flickr.Expect(t, result.Actions["s1"], "approved")
+	flickr.Expect(t, result.Actions["s2"], "rejected")
+	flickr.Expect(t, result.Actions["s3"], "deferred")
+	flickr.Expect(t, len(result.Succeeded), 2)
+
+	flickr.Expect(t, calls.Last("flickr.photos.suggestions.getList").Get("status_id"), "0")
+	flickr.Expect(t, calls.Last("flickr.photos.suggestions.getList").Get("photo_id"), "")
+	flickr.Expect(t, calls.Last("flickr.photos.suggestions.approveSuggestion").Get("suggestion_id"), "s1")
+	flickr.Expect(t, calls.Last("flickr.photos.suggestions.rejectSuggestion").Get("suggestion_id"), "s2")
+	// the place is resolved once
+	resolved := 0
+	for _, m := range calls.Methods() {
+		if m == "flickr.places.getInfo" {
+			resolved++
+		}
+	}
+	flickr.Expect(t, resolved, 1)
+}
+
+func TestSuggestLocation(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.suggestions.suggestLocation": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	_, err := SuggestLocation(fclient, "1", 45.5, -73.5, 16, PlaceRef{WoeId: "3534"}, "the old port")
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.photos.suggestions.suggestLocation")
+	flickr.Expect(t, args.Get("lat"), "45.5")
+	flickr.Expect(t, args.Get("woe_id"), "3534")
+	flickr.Expect(t, args.Get("note"), "the old port")
+
+	_, err = SuggestLocation(fclient, "1", 95, 0, 16, PlaceRef{}, "")
+	flickr.Expect(t, err != nil, true)
+}
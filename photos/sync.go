@@ -0,0 +1,111 @@
+package photos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Return the photos of the calling user updated (metadata, comments, notes, etc)
+// since minDate, a unix timestamp. extras is an optional comma separated list of
+// extra fields.
+// This method requires authentication with 'read' permission.
+func RecentlyUpdated(client *flickr.FlickrClient, minDate int64, extras string, page, perPage int) (*SearchResponse, error) {
+	if err := flickr.ValidatePerPage(perPage); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.photos.recentlyUpdated")
+	client.SetTime("min_date", time.Unix(minDate, 0), flickr.UnixDate)
+	if extras != "" {
+		client.Args.Set("extras", extras)
+	}
+	// if not provided, flickr defaults this argument to 1
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+	client.OAuthSign()
+
+	response := &SearchResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Add an extra field to a comma separated list of extras, if missing
+func withExtra(extras, extra string) string {
+	for _, e := range strings.Split(extras, ",") {
+		if strings.TrimSpace(e) == extra {
+			return extras
+		}
+	}
+	if extras == "" {
+		return extra
+	}
+	return extras + "," + extra
+}
+
+// Walk the pages of a photo list starting from the cursor position, calling fn for
+// every photo and marking pages as done. The run is completed once the last page
+// is processed.
+func walkCursor(cursor *flickr.Cursor, fetch func(page int) (*SearchResponse, error), timestamp func(*SearchPhoto) string, fn func(*SearchPhoto) error) error {
+	for {
+		resp, err := fetch(cursor.NextPage())
+		if err != nil {
+			return err
+		}
+		for i := range resp.Photos.Items {
+			photo := &resp.Photos.Items[i]
+			if err := fn(photo); err != nil {
+				return err
+			}
+			ts, _ := strconv.ParseInt(timestamp(photo), 10, 64)
+			cursor.Seen(ts)
+		}
+		cursor.PageDone()
+		if cursor.Page >= resp.Photos.Pages {
+			cursor.Complete()
+			return nil
+		}
+	}
+}
+
+// Call fn for every photo matching params uploaded since the cursor, oldest first,
+// updating the cursor as photos are processed. Persist the cursor after the call,
+// even when it fails, so the next run resumes where this one stopped.
+// Photos uploaded in the same second as the most recent one processed are returned
+// again by the next run.
+func SyncUploads(client *flickr.FlickrClient, params SearchParams, cursor *flickr.Cursor, fn func(*SearchPhoto) error) error {
+	if cursor.Since > 0 {
+		params.MinUploadDate = cursor.Since
+	}
+	// ascending order keeps the pages already processed stable between runs
+	params.Sort = "date-posted-asc"
+	params.Extras = withExtra(params.Extras, "date_upload")
+
+	fetch := func(page int) (*SearchResponse, error) {
+		params.Page = page
+		return Search(client, &params)
+	}
+	timestamp := func(p *SearchPhoto) string { return p.DateUpload }
+	return walkCursor(cursor, fetch, timestamp, fn)
+}
+
+// Call fn for every photo of the calling user updated since the cursor, updating the
+// cursor as photos are processed. Persist the cursor after the call, even when it
+// fails, so the next run resumes where this one stopped.
+// This method requires authentication with 'read' permission.
+func SyncUpdates(client *flickr.FlickrClient, cursor *flickr.Cursor, extras string, perPage int, fn func(*SearchPhoto) error) error {
+	extras = withExtra(extras, "last_update")
+
+	fetch := func(page int) (*SearchResponse, error) {
+		return RecentlyUpdated(client, cursor.Since, extras, page, perPage)
+	}
+	timestamp := func(p *SearchPhoto) string { return p.LastUpdate }
+	return walkCursor(cursor, fetch, timestamp, fn)
+}
@@ -0,0 +1,87 @@
+package photos
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const syncPage = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photos page="1" pages="2" perpage="2" total="3">
+    <photo id="1" owner="47058503995@N01" secret="a" server="2" farm="1" title="one" ispublic="1" isfriend="0" isfamily="0" dateupload="1500000001" lastupdate="1600000001" />
+    <photo id="2" owner="47058503995@N01" secret="a" server="2" farm="1" title="two" ispublic="1" isfriend="0" isfamily="0" dateupload="1500000002" lastupdate="1600000002" />
+  </photos>
+</rsp>`
+
+func TestSyncUploads(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search": syncPage,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	cursor := &flickr.Cursor{Since: 1400000000}
+	seen := 0
+	err := SyncUploads(fclient, SearchParams{UserId: "me", Extras: "media"}, cursor, func(p *SearchPhoto) error {
+		seen++
+		return nil
+	})
+	flickr.Expect(t, err, nil)
+	// the mock always returns the first of two pages
+	flickr.Expect(t, seen, 4)
+	flickr.Expect(t, *cursor, flickr.Cursor{Since: 1500000002})
+
+	args := calls.Last("flickr.photos.search")
+	flickr.Expect(t, args.Get("page"), "2")
+	flickr.Expect(t, args.Get("min_upload_date"), "1400000000")
+	flickr.Expect(t, args.Get("sort"), "date-posted-asc")
+	flickr.Expect(t, args.Get("extras"), "media,date_upload")
+}
+
+func TestSyncUploadsInterrupted(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.search": syncPage,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	cursor := &flickr.Cursor{}
+	stop := errors.New("stop")
+	seen := 0
+	err := SyncUploads(fclient, SearchParams{UserId: "me"}, cursor, func(p *SearchPhoto) error {
+		seen++
+		if seen == 3 {
+			return stop
+		}
+		return nil
+	})
+	flickr.Expect(t, err, stop)
+	flickr.Expect(t, *cursor, flickr.Cursor{Page: 1, Latest: 1500000002})
+
+	// the next run resumes from the second page
+	err = SyncUploads(fclient, SearchParams{UserId: "me"}, cursor, func(p *SearchPhoto) error { return nil })
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photos.search").Get("page"), "2")
+	flickr.Expect(t, cursor.Since, int64(1500000002))
+}
+
+func TestSyncUpdates(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.recentlyUpdated": syncPage,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	cursor := &flickr.Cursor{Since: 1500000000}
+	err := SyncUpdates(fclient, cursor, "", 0, func(p *SearchPhoto) error { return nil })
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, cursor.Since, int64(1600000002))
+	args := calls.Last("flickr.photos.recentlyUpdated")
+	flickr.Expect(t, args.Get("min_date"), "1500000000")
+	flickr.Expect(t, args.Get("extras"), "last_update")
+}
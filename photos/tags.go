@@ -0,0 +1,150 @@
+package photos
+
+import (
+	"strings"
+	"unicode"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Flickr stores every tag in three forms:
+//   - raw: the tag as typed by the user, e.g. "Tamron 70-180"
+//   - clean: the normalized form used in URLs and searches, e.g. "tamron70180"
+//   - id: the identifier of the tag on a specific photo, needed to remove it
+
+// CleanTag approximates Flickr normalization of a raw tag into its clean form:
+// letters are lowercased and anything that isn't a letter or a digit is dropped.
+// Machine tags keep their syntax, only their value is normalized, e.g.
+// "geo:lat=47.6" becomes "geo:lat=476".
+func CleanTag(raw string) string {
+	if m, ok := ParseMachineTag(raw); ok {
+		return strings.ToLower(m.Namespace+":"+m.Predicate) + "=" + cleanTagText(m.Value)
+	}
+	return cleanTagText(raw)
+}
+
+func cleanTagText(raw string) string {
+	var buf strings.Builder
+	for _, r := range strings.ToLower(raw) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// Remove a tag from a photo, tagId is the ID of the tag on that photo (see Tag.ID),
+// not its text
+// This method requires authentication with 'write' permission.
+func RemoveTag(client *flickr.FlickrClient, tagId string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.EndpointUrl = flickr.API_ENDPOINT
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photos.removeTag")
+	client.Args.Set("tag_id", tagId)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Resolve tag names (either raw or clean) to the IDs of the matching tags on a photo.
+// The returned map is keyed by the names passed in, names not found on the photo are
+// missing from the map.
+func ResolveTagIds(client *flickr.FlickrClient, photoId string, names []string) (map[string]string, error) {
+	info, err := GetInfo(client, photoId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ret := map[string]string{}
+	for _, name := range names {
+		clean := CleanTag(name)
+		for _, tag := range info.Photo.Tags {
+			if tag.Raw == name || tag.Value == clean {
+				ret[name] = tag.ID
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+// Remove tags from a photo by name, raw and clean forms are both accepted.
+// The result lists the names removed and the ones that failed, names not found on
+// the photo are reported as warnings. The error is non nil when the tags couldn't be
+// resolved or, with opts.FailFast, as soon as a removal fails.
+// This method requires authentication with 'write' permission.
+func RemoveTagsByName(client *flickr.FlickrClient, photoId string, names []string, opts flickr.BatchOptions) (*flickr.BatchResult, error) {
+	result := flickr.NewBatchResult()
+	ids, err := ResolveTagIds(client, photoId, names)
+	if err != nil {
+		return result, err
+	}
+
+	for _, name := range names {
+		id, found := ids[name]
+		if !found {
+			result.Warn("tag %q not found on photo %s", name, photoId)
+			continue
+		}
+		_, err := RemoveTag(client, id)
+		if err := result.Add(name, err, opts); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// Add and remove tags of a photo in a single write, keeping the tags that are not
+// mentioned, e.g. the ones added through the Flickr website while automation manages
+// its own. The current tags are fetched, add and remove are applied, names being
+// compared in their clean form, and the merged set is written with setTags. Tags
+// already on the photo keep their raw form. Nothing is written when the set doesn't
+// change. Returns the raw tags of the photo after the merge.
+// This method requires authentication with 'write' permission.
+func MergeTags(client *flickr.FlickrClient, photoId string, add, remove []string) ([]string, error) {
+	info, err := GetInfo(client, photoId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	removed := map[string]bool{}
+	for _, name := range remove {
+		removed[CleanTag(name)] = true
+	}
+	merged := []string{}
+	present := map[string]bool{}
+	changed := false
+	for _, tag := range info.Photo.Tags {
+		clean := tag.Value
+		if clean == "" {
+			clean = CleanTag(tag.Raw)
+		}
+		if removed[clean] {
+			changed = true
+			continue
+		}
+		if !present[clean] {
+			present[clean] = true
+			merged = append(merged, tag.Raw)
+		}
+	}
+	for _, name := range add {
+		clean := CleanTag(name)
+		if clean == "" || present[clean] || removed[clean] {
+			continue
+		}
+		present[clean] = true
+		merged = append(merged, name)
+		changed = true
+	}
+
+	if changed {
+		if _, err := SetTags(client, photoId, merged); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
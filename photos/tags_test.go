@@ -0,0 +1,121 @@
+package photos
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestCleanTag(t *testing.T) {
+	flickr.Expect(t, CleanTag("tamron 70-180"), "tamron70180")
+	flickr.Expect(t, CleanTag("Seattle"), "seattle")
+	flickr.Expect(t, CleanTag("Città Vecchia!"), "cittàvecchia")
+	flickr.Expect(t, CleanTag("geo:lat=1.5"), "geo:lat=15")
+	flickr.Expect(t, CleanTag(`Upcoming:Event="Jazz Night"`), "upcoming:event=jazznight")
+	flickr.Expect(t, CleanTag("geo:lat"), "geolat")
+	// namespaces and predicates start with a letter, see ParseMachineTag
+	flickr.Expect(t, CleanTag("_geo:lat=1.5"), "geolat15")
+}
+
+func TestResolveTagIds(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, photoInfo, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	ids, err := ResolveTagIds(fclient, "52435165562", []string{"body positive", "pinkhair", "Tamron 70-180", "missing"})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(ids), 3)
+	flickr.Expect(t, ids["body positive"], "41641790-52435165562-7257133")
+	flickr.Expect(t, ids["pinkhair"], "41641790-52435165562-41272")
+	flickr.Expect(t, ids["Tamron 70-180"], "41641790-52435165562-501971677")
+}
+
+func TestRemoveTagsByName(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.getInfo":   photoInfo,
+		"flickr.photos.removeTag": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	result, err := RemoveTagsByName(fclient, "52435165562", []string{"Seattle", "nope"}, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Succeeded), 1)
+	flickr.Expect(t, result.Succeeded[0], "Seattle")
+	flickr.Expect(t, len(result.Warnings), 1)
+	flickr.Expect(t, calls.Last("flickr.photos.removeTag").Get("tag_id"), "41641790-52435165562-69")
+}
+
+func TestRemoveTagsByNameFailures(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.getInfo": photoInfo,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	// removeTag is not mocked so every removal fails
+	names := []string{"Seattle", "pinkhair"}
+	result, err := RemoveTagsByName(fclient, "52435165562", names, flickr.BatchOptions{})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(result.Failed), 2)
+	flickr.Expect(t, result.Failed[1].Item, "pinkhair")
+	flickr.Expect(t, result.Err(), error(result.Failed[0]))
+
+	result, err = RemoveTagsByName(fclient, "52435165562", names, flickr.BatchOptions{FailFast: true})
+	_, ok := err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, len(result.Failed), 1)
+	flickr.Expect(t, len(calls.Methods()), 5)
+}
+
+func TestAddTags(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.addTags": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	err := AddTags(fclient, "123", []string{"sea", "pink hair"})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photos.addTags").Get("tags"), `sea "pink hair"`)
+	flickr.Expect(t, strings.Contains(calls.Last("flickr.photos.addTags").Get("tags"), ","), false)
+}
+
+func TestAddTagsTooMany(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	err := AddTags(fclient, "123", make([]string, flickr.MaxTagsPerPhoto+1))
+	expectInvalidParams(t, err)
+}
+
+func TestMergeTags(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photos.getInfo": photoInfo,
+		"flickr.photos.setTags": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	tags, err := MergeTags(fclient, "52435165562", []string{"Pink Hair", "auto:reviewed"}, []string{"seattle model"})
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.photos.setTags")
+	flickr.Expect(t, args.Get("photo_id"), "52435165562")
+	set := args.Get("tags")
+	// existing tags keep their raw form, the new one is appended once
+	flickr.Expect(t, strings.Contains(set, `"pink hair"`), true)
+	flickr.Expect(t, strings.Contains(set, "seattle model"), false)
+	flickr.Expect(t, strings.HasSuffix(set, "auto:reviewed"), true)
+	flickr.Expect(t, tags[len(tags)-1], "auto:reviewed")
+
+	// nothing to change, nothing written
+	before := len(calls.Methods())
+	_, err = MergeTags(fclient, "52435165562", []string{"pinkhair"}, []string{"unknown"})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(calls.Methods()), before+1)
+}
@@ -0,0 +1,335 @@
+package photos
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Sizes of photos as returned by getSizes, shared among PrefetchThumbnails calls so
+// that photos already seen don't hit the API again. Entries are keyed by photo ID
+// and secret: rotating or replacing a photo changes its secret, and so its URLs.
+// Register the cache with flickr.WithPhotoCache to have it invalidated by Rotate.
+// A SizesCache is safe for concurrent use.
+type SizesCache struct {
+	mu    sync.Mutex
+	sizes map[string]cachedSizes
+}
+
+// Sizes of a photo along with the secret found in their URLs
+type cachedSizes struct {
+	secret string
+	sizes  []PhotoDownloadInfo
+}
+
+func NewSizesCache() *SizesCache {
+	return &SizesCache{sizes: map[string]cachedSizes{}}
+}
+
+// Return the secret found in the URLs of the sizes of a photo, the original size
+// has a secret of its own and is skipped
+func sizesSecret(sizes []PhotoDownloadInfo) string {
+	for _, s := range sizes {
+		if s.Label == "Original" || s.Media == "video" {
+			continue
+		}
+		// e.g. 52435165562_9a8b7c6d5e_q.jpg
+		parts := strings.Split(path.Base(s.Source), "_")
+		if len(parts) >= 2 {
+			return strings.TrimSuffix(parts[1], path.Ext(parts[1]))
+		}
+	}
+	return ""
+}
+
+func (c *SizesCache) get(photoId, secret string) ([]PhotoDownloadInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, found := c.sizes[photoId]
+	if !found || secret != "" && entry.secret != secret {
+		return nil, false
+	}
+	return entry.sizes, true
+}
+
+func (c *SizesCache) put(photoId string, sizes []PhotoDownloadInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sizes[photoId] = cachedSizes{secret: sizesSecret(sizes), sizes: sizes}
+}
+
+// Implement flickr.PhotoCache
+func (c *SizesCache) InvalidatePhoto(photoId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sizes, photoId)
+}
+
+// Return the sizes of a photo, from the cache when they were fetched for the same
+// secret. secret is the current secret of the photo, e.g. SearchPhoto.Secret, an
+// empty secret accepts any cached entry.
+// This method requires authentication to access private photos.
+func (c *SizesCache) GetSizes(client *flickr.FlickrClient, photoId, secret string) ([]PhotoDownloadInfo, error) {
+	if sizes, found := c.get(photoId, secret); found {
+		return sizes, nil
+	}
+	resp, err := GetSizes(client, photoId)
+	if err != nil {
+		return nil, err
+	}
+	c.put(photoId, resp.Sizes)
+	return resp.Sizes, nil
+}
+
+// Storage of downloaded thumbnails, names are the file names of the thumbnails on
+// the Flickr static servers (e.g. "52435165562_9_q.jpg"), unique per photo and size.
+// Implementations must be safe for concurrent use.
+type ThumbnailCache interface {
+	// Return the thumbnail stored under name and whether it was found
+	Get(name string) ([]byte, bool)
+	Put(name string, data []byte) error
+}
+
+// A ThumbnailCache keeping thumbnails in memory
+type MemoryThumbnailCache struct {
+	mu    sync.Mutex
+	items map[string][]byte
+}
+
+func NewMemoryThumbnailCache() *MemoryThumbnailCache {
+	return &MemoryThumbnailCache{items: map[string][]byte{}}
+}
+
+// Implement ThumbnailCache
+func (c *MemoryThumbnailCache) Get(name string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, found := c.items[name]
+	return data, found
+}
+
+// Implement ThumbnailCache
+func (c *MemoryThumbnailCache) Put(name string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[name] = data
+	return nil
+}
+
+// A ThumbnailCache storing thumbnails as files in Dir, which must exist
+type DirThumbnailCache struct {
+	Dir string
+}
+
+// Implement ThumbnailCache
+func (c DirThumbnailCache) Get(name string) ([]byte, bool) {
+	data, err := ioutil.ReadFile(filepath.Join(c.Dir, name))
+	return data, err == nil
+}
+
+// Implement ThumbnailCache, the file is written under a temporary name and renamed
+// so that readers never see partial thumbnails
+func (c DirThumbnailCache) Put(name string, data []byte) error {
+	tmp, err := ioutil.TempFile(c.Dir, name+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.Dir, name))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// A ThumbnailCache backed by a flickr.Store, thumbnails are stored under Prefix
+// followed by their name, e.g. "thumbnails/"
+type StoreThumbnailCache struct {
+	Store  flickr.Store
+	Prefix string
+}
+
+// Implement ThumbnailCache, store errors are reported as cache misses
+func (c StoreThumbnailCache) Get(name string) ([]byte, bool) {
+	data, found, err := c.Store.Get(c.Prefix + name)
+	return data, found && err == nil
+}
+
+// Implement ThumbnailCache
+func (c StoreThumbnailCache) Put(name string, data []byte) error {
+	return c.Store.Put(c.Prefix+name, data)
+}
+
+// Options of PrefetchThumbnails
+type ThumbnailOptions struct {
+	// Minimum dimensions of the thumbnails, zero values match any size
+	MinWidth  int
+	MinHeight int
+	// Maximum number of photos processed at the same time, 4 when zero
+	Concurrency int
+	// Cache of getSizes results, nil to always call the API
+	Sizes *SizesCache
+	// Where to download the thumbnails, nil to only resolve their URLs
+	Cache ThumbnailCache
+	flickr.BatchOptions
+}
+
+// The size of a photo picked by PrefetchThumbnails
+type Thumbnail struct {
+	PhotoId string
+	// Label of the size, e.g. "Small 320"
+	Label  string
+	Width  int
+	Height int
+	Source string
+	// Name of the thumbnail in the ThumbnailCache, empty when not downloaded
+	CacheName string
+	// No size is as large as requested, the largest one was picked
+	Undersized bool
+}
+
+// Return the smallest still image at least minWidth x minHeight, the largest one
+// if none is large enough. Returns false if there are no still images.
+func smallestSize(sizes []PhotoDownloadInfo, minWidth, minHeight int) (Thumbnail, bool) {
+	var best, largest *PhotoDownloadInfo
+	var bestArea, largestArea int
+	for i := range sizes {
+		s := &sizes[i]
+		// videos also list the players and the encoded files
+		if s.Media == "video" {
+			continue
+		}
+		w, _ := strconv.Atoi(s.Width)
+		h, _ := strconv.Atoi(s.Height)
+		if largest == nil || w*h > largestArea {
+			largest, largestArea = s, w*h
+		}
+		if w >= minWidth && h >= minHeight && (best == nil || w*h < bestArea) {
+			best, bestArea = s, w*h
+		}
+	}
+	if largest == nil {
+		return Thumbnail{}, false
+	}
+	t := Thumbnail{}
+	if best == nil {
+		best, t.Undersized = largest, true
+	}
+	t.Label, t.Source = best.Label, best.Source
+	t.Width, _ = strconv.Atoi(best.Width)
+	t.Height, _ = strconv.Atoi(best.Height)
+	return t, true
+}
+
+// Resolve the thumbnail of a single photo and download it if a cache is set
+func prefetchThumbnail(client *flickr.FlickrClient, photo *SearchPhoto, opts *ThumbnailOptions) (*Thumbnail, error) {
+	photoId := photo.Id
+	var sizes []PhotoDownloadInfo
+	if opts.Sizes != nil {
+		var err error
+		if sizes, err = opts.Sizes.GetSizes(client, photoId, photo.Secret); err != nil {
+			return nil, err
+		}
+	} else {
+		resp, err := GetSizes(client, photoId)
+		if err != nil {
+			return nil, err
+		}
+		sizes = resp.Sizes
+	}
+
+	t, ok := smallestSize(sizes, opts.MinWidth, opts.MinHeight)
+	if !ok {
+		return nil, flickErr.NewError(flickErr.DownloadError, "photo "+photoId+" has no still image")
+	}
+	t.PhotoId = photoId
+	if opts.Cache == nil {
+		return &t, nil
+	}
+
+	name := path.Base(t.Source)
+	if _, found := opts.Cache.Get(name); !found {
+		buf := &bytes.Buffer{}
+		if _, err := flickr.Download(client, t.Source, buf); err != nil {
+			return nil, err
+		}
+		if err := opts.Cache.Put(name, buf.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	t.CacheName = name
+	return &t, nil
+}
+
+// For each photo of a list, e.g. the items of a search response, pick the smallest
+// size at least opts.MinWidth x opts.MinHeight and download it into opts.Cache when
+// set, for gallery UIs. Photos are processed concurrently using clones of client.
+// Thumbnails already in the cache are not downloaded again. Photos that can't be
+// found anymore are tombstoned in the result. Thumbnails are keyed by photo ID.
+// This method requires authentication to access private photos.
+func PrefetchThumbnails(client *flickr.FlickrClient, list []SearchPhoto, opts ThumbnailOptions) (map[string]*Thumbnail, *flickr.BatchResult, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+
+	thumbs := make([]*Thumbnail, len(list))
+	errs := make([]error, len(list))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := false
+	sem := make(chan struct{}, opts.Concurrency)
+	for i := range list {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := failed && opts.FailFast
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			t, err := prefetchThumbnail(client.Clone(), &list[i], &opts)
+			mu.Lock()
+			defer mu.Unlock()
+			thumbs[i], errs[i] = t, err
+			if err != nil && !flickr.IsPhotoNotFound(err) {
+				failed = true
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	ret := map[string]*Thumbnail{}
+	result := flickr.NewBatchResult()
+	for i, p := range list {
+		if thumbs[i] == nil && errs[i] == nil {
+			// not processed because of FailFast
+			break
+		}
+		if thumbs[i] != nil {
+			ret[p.Id] = thumbs[i]
+		}
+		if err := result.AddPhoto(p.Id, p, errs[i], opts.BatchOptions); err != nil {
+			return ret, result, err
+		}
+	}
+	return ret, result, nil
+}
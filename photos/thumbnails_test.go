@@ -0,0 +1,151 @@
+package photos
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Serve getSizes for photos "1" (three sizes) and "2" (a small one only), photo "3"
+// doesn't exist, and the thumbnail files themselves
+func thumbnailServer(getSizes, downloads *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("method") == "" {
+			atomic.AddInt32(downloads, 1)
+			fmt.Fprint(w, "jpeg "+r.URL.Path)
+			return
+		}
+		atomic.AddInt32(getSizes, 1)
+		switch id := r.FormValue("photo_id"); id {
+		case "1":
+			fmt.Fprintln(w, `<rsp stat="ok"><sizes>
+  <size label="Square" width="75" height="75" source="https://live.staticflickr.com/1/1_a_s.jpg" media="photo" />
+  <size label="Small 320" width="320" height="240" source="https://live.staticflickr.com/1/1_a_n.jpg" media="photo" />
+  <size label="Medium 640" width="640" height="480" source="https://live.staticflickr.com/1/1_a_z.jpg" media="photo" />
+  <size label="Site MP4" width="1280" height="960" source="https://www.flickr.com/photos/x/1/play/site/a/" media="video" />
+</sizes></rsp>`)
+		case "2":
+			fmt.Fprintln(w, `<rsp stat="ok"><sizes>
+  <size label="Thumbnail" width="100" height="75" source="https://live.staticflickr.com/1/2_b_t.jpg" media="photo" />
+</sizes></rsp>`)
+		default:
+			fmt.Fprintln(w, `<rsp stat="fail"><err code="1" msg="Photo not found" /></rsp>`)
+		}
+	}))
+}
+
+func TestPrefetchThumbnails(t *testing.T) {
+	var getSizes, downloads int32
+	server := thumbnailServer(&getSizes, &downloads)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	list := []SearchPhoto{{Id: "1"}, {Id: "2"}, {Id: "3", Title: "gone"}}
+	opts := ThumbnailOptions{MinWidth: 200, MinHeight: 200, Sizes: NewSizesCache(), Cache: NewMemoryThumbnailCache()}
+	thumbs, result, err := PrefetchThumbnails(fclient, list, opts)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(thumbs), 2)
+	flickr.Expect(t, thumbs["1"].Label, "Small 320")
+	flickr.Expect(t, thumbs["1"].Width, 320)
+	flickr.Expect(t, thumbs["1"].CacheName, "1_a_n.jpg")
+	flickr.Expect(t, thumbs["1"].Undersized, false)
+	flickr.Expect(t, thumbs["2"].Source, "https://live.staticflickr.com/1/2_b_t.jpg")
+	flickr.Expect(t, thumbs["2"].Undersized, true)
+	flickr.Expect(t, len(result.Succeeded), 2)
+	flickr.Expect(t, len(result.Tombstones), 1)
+	flickr.Expect(t, result.Tombstones[0].Id, "3")
+	data, found := opts.Cache.Get("1_a_n.jpg")
+	flickr.Expect(t, found, true)
+	flickr.Expect(t, string(data), "jpeg /1/1_a_n.jpg")
+	flickr.Expect(t, atomic.LoadInt32(&getSizes), int32(3))
+	flickr.Expect(t, atomic.LoadInt32(&downloads), int32(2))
+
+	// sizes and thumbnails are cached
+	_, _, err = PrefetchThumbnails(fclient, list[:2], opts)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, atomic.LoadInt32(&getSizes), int32(3))
+	flickr.Expect(t, atomic.LoadInt32(&downloads), int32(2))
+}
+
+func TestPrefetchThumbnailsDir(t *testing.T) {
+	var getSizes, downloads int32
+	server := thumbnailServer(&getSizes, &downloads)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	dir, err := ioutil.TempDir("", "thumbs")
+	flickr.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	// no size constraint, the smallest one is picked
+	thumbs, _, err := PrefetchThumbnails(fclient, []SearchPhoto{{Id: "1"}}, ThumbnailOptions{Cache: DirThumbnailCache{Dir: dir}})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, thumbs["1"].Label, "Square")
+	files, _ := ioutil.ReadDir(dir)
+	flickr.Expect(t, len(files), 1)
+	flickr.Expect(t, files[0].Name(), "1_a_s.jpg")
+}
+
+func TestPrefetchThumbnailsStore(t *testing.T) {
+	var getSizes, downloads int32
+	server := thumbnailServer(&getSizes, &downloads)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	store := flickr.NewMemoryStore()
+	_, _, err := PrefetchThumbnails(fclient, []SearchPhoto{{Id: "1"}}, ThumbnailOptions{Cache: StoreThumbnailCache{Store: store, Prefix: "thumbnails/"}})
+	flickr.Expect(t, err, nil)
+	_, found, _ := store.Get("thumbnails/1_a_s.jpg")
+	flickr.Expect(t, found, true)
+}
+
+func TestSmallestSize(t *testing.T) {
+	_, ok := smallestSize([]PhotoDownloadInfo{{Width: "640", Height: "480", Media: "video"}}, 0, 0)
+	flickr.Expect(t, ok, false)
+}
+
+func TestSizesCache(t *testing.T) {
+	var calls, downloads int32
+	server := thumbnailServer(&calls, &downloads)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	cache := NewSizesCache()
+	fclient := flickr.GetTestClient()
+	flickr.WithPhotoCache(cache)(fclient)
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	sizes, err := cache.GetSizes(fclient, "1", "a")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(sizes), 4)
+	_, err = cache.GetSizes(fclient, "1", "")
+	flickr.Expect(t, err, nil)
+	_, err = cache.GetSizes(fclient, "1", "a")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, atomic.LoadInt32(&calls), int32(1))
+
+	// the secret changed since the sizes were cached
+	_, err = cache.GetSizes(fclient, "1", "c")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, atomic.LoadInt32(&calls), int32(2))
+
+	// rotating the photo invalidates it, the mock answers the rotation as well
+	_, err = Rotate(fclient, "1", 90)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, atomic.LoadInt32(&calls), int32(3))
+	_, err = cache.GetSizes(fclient, "1", "")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, atomic.LoadInt32(&calls), int32(4))
+}
@@ -0,0 +1,180 @@
+package photos
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Flickr refuses videos bigger than 1GB
+const MaxVideoSize = flickr.MaxVideoUploadSize
+
+// LargeUploadOptions tunes the behaviour of UploadLarge, zero values are replaced
+// with meaningful defaults
+type LargeUploadOptions struct {
+	// Optional upload parameters, same as UploadFile
+	Params *flickr.UploadParams
+	// How many times an upload is attempted from scratch before giving up
+	MaxAttempts int
+	// Timeout of the first attempt, on top of the time needed to transfer the file
+	BaseTimeout time.Duration
+	// Worst case upload speed in bytes per second, used to compute the timeout
+	MinThroughput int64
+	// Files bigger than this are refused without even trying
+	MaxSize int64
+	// Expected media type ("photo" or "video"), leave empty to skip the check
+	Media string
+	// Compare uploaded files with Params, see VerifyUpload
+	VerifyParams bool
+}
+
+// UploadOutcome reports what happened to a single file processed by UploadLarge
+type UploadOutcome struct {
+	// Local path of the file
+	Path string
+	// ID of the uploaded photo or video, empty if the upload failed
+	PhotoId string
+	// Number of upload attempts performed
+	Attempts int
+	// Media type and duration (in seconds, videos only) as reported by getInfo
+	Media    string
+	Duration int
+	// Whether getInfo confirmed the upload matches the expectations
+	Verified bool
+	// Fields differing from the upload params when VerifyParams is set, mismatches
+	// don't make the upload fail
+	Report *VerificationReport
+	// The error that made the upload or the verification fail, if any
+	Err error
+}
+
+func (o *LargeUploadOptions) withDefaults() *LargeUploadOptions {
+	ret := LargeUploadOptions{}
+	if o != nil {
+		ret = *o
+	}
+	if ret.MaxAttempts <= 0 {
+		ret.MaxAttempts = 3
+	}
+	if ret.BaseTimeout <= 0 {
+		ret.BaseTimeout = time.Minute
+	}
+	if ret.MinThroughput <= 0 {
+		ret.MinThroughput = 128 * 1024
+	}
+	if ret.MaxSize <= 0 {
+		ret.MaxSize = MaxVideoSize
+	}
+	return &ret
+}
+
+// Given the file size, compute the timeout for the n-th attempt (starting from 0):
+// every retry doubles the time allowed to complete the upload
+func (o *LargeUploadOptions) timeout(size int64, attempt int) time.Duration {
+	transfer := time.Duration(size/o.MinThroughput) * time.Second
+	return (o.BaseTimeout + transfer) << uint(attempt)
+}
+
+// Whether an upload error is worth another attempt: network failures and
+// non-REST responses are, errors returned by the Flickr API are not
+func shouldRetry(resp *flickr.UploadResponse, err error) bool {
+	if _, ok := err.(*flickErr.Error); ok {
+		return resp != nil && resp.ErrorCode() == -1
+	}
+	return true
+}
+
+// UploadLarge uploads big files (typically videos) one after the other. Failed
+// uploads are retried from scratch with increasing timeouts, successful ones are
+// verified checking media type and duration with flickr.photos.getInfo.
+// Every file gets an UploadOutcome, in the same order as paths.
+// This call must be signed with write permissions
+func UploadLarge(client *flickr.FlickrClient, paths []string, opts *LargeUploadOptions) []*UploadOutcome {
+	opts = opts.withDefaults()
+	ret := make([]*UploadOutcome, 0, len(paths))
+	for _, path := range paths {
+		ret = append(ret, uploadLarge(client, path, opts))
+	}
+	return ret
+}
+
+func uploadLarge(client *flickr.FlickrClient, path string, opts *LargeUploadOptions) *UploadOutcome {
+	outcome := &UploadOutcome{Path: path}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		outcome.Err = err
+		return outcome
+	}
+	if stat.Size() > opts.MaxSize {
+		outcome.Err = flickErr.NewError(flickErr.FileTooLargeError,
+			fmt.Sprintf("%s is %d bytes, limit is %d", path, stat.Size(), opts.MaxSize))
+		return outcome
+	}
+
+	for outcome.Attempts < opts.MaxAttempts {
+		// the per-attempt timeout overrides whatever CallTimeout the client has
+		attempt := client.WithTimeout(opts.timeout(stat.Size(), outcome.Attempts))
+		outcome.Attempts++
+
+		var file *os.File
+		file, err = os.Open(path)
+		if err != nil {
+			break
+		}
+
+		var resp *flickr.UploadResponse
+		resp, err = flickr.UploadReader(attempt, file, file.Name(), opts.Params)
+		file.Close()
+		if err == nil {
+			outcome.PhotoId = resp.ID
+			break
+		}
+		if !shouldRetry(resp, err) {
+			break
+		}
+	}
+
+	if err != nil {
+		outcome.Err = err
+		return outcome
+	}
+
+	outcome.Err = verifyUpload(client, outcome, opts)
+	outcome.Verified = outcome.Err == nil
+	return outcome
+}
+
+// Fetch info about the uploaded file and check it matches what we expect
+func verifyUpload(client *flickr.FlickrClient, outcome *UploadOutcome, opts *LargeUploadOptions) error {
+	info, err := GetInfo(client, outcome.PhotoId, "")
+	if err != nil {
+		return err
+	}
+	if opts.VerifyParams {
+		outcome.Report = comparePhoto(&info.Photo, opts.Params)
+	}
+	media := opts.Media
+
+	outcome.Media = info.Photo.Media
+	outcome.Duration = info.Photo.Video.Duration
+
+	if media != "" && media != outcome.Media {
+		return flickErr.NewError(flickErr.VerificationError,
+			fmt.Sprintf("expected media %s, got %s", media, outcome.Media))
+	}
+
+	if outcome.Media == "video" {
+		video := info.Photo.Video
+		// a video still being processed has no duration yet
+		if video.Failed || (video.Duration <= 0 && !video.Pending) {
+			return flickErr.NewError(flickErr.VerificationError,
+				fmt.Sprintf("video %s failed processing", outcome.PhotoId))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package photos
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+const (
+	uploadOk  = `<?xml version="1.0" encoding="utf-8" ?><rsp stat="ok"><photoid>1234</photoid></rsp>`
+	videoInfo = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photo id="1234" secret="abc" server="65535" farm="66" media="video">
+    <title>A video</title>
+    <video ready="1" failed="0" pending="0" duration="42" width="1920" height="1080" />
+  </photo>
+</rsp>`
+)
+
+func tempVideo(t *testing.T) string {
+	f, err := ioutil.TempFile("", "flickr.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.WriteString("not really a video")
+	return f.Name()
+}
+
+func TestUploadLarge(t *testing.T) {
+	path := tempVideo(t)
+	defer os.Remove(path)
+
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMockMethods(200, map[string]string{
+		"upload":                uploadOk,
+		"flickr.photos.getInfo": videoInfo,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	outcomes := UploadLarge(fclient, []string{path}, &LargeUploadOptions{Media: "video"})
+	flickr.Expect(t, len(outcomes), 1)
+	o := outcomes[0]
+	flickr.Expect(t, o.Err, nil)
+	flickr.Expect(t, o.Path, path)
+	flickr.Expect(t, o.PhotoId, "1234")
+	flickr.Expect(t, o.Attempts, 1)
+	flickr.Expect(t, o.Media, "video")
+	flickr.Expect(t, o.Duration, 42)
+	flickr.Expect(t, o.Verified, true)
+	flickr.Expect(t, o.Report == nil, true)
+
+	params := &flickr.UploadParams{Title: "Not the video title"}
+	o = UploadLarge(fclient, []string{path}, &LargeUploadOptions{Params: params, VerifyParams: true})[0]
+	flickr.Expect(t, o.Verified, true)
+	flickr.Expect(t, o.Report.OK(), false)
+	flickr.Expect(t, o.Report.Mismatches[0].Field, "title")
+}
+
+func TestUploadLargeRetry(t *testing.T) {
+	path := tempVideo(t)
+	defer os.Remove(path)
+
+	uploads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "upload") {
+			uploads++
+			if uploads == 1 {
+				// simulate a dropped connection
+				panic(http.ErrAbortHandler)
+			}
+			fmt.Fprintln(w, uploadOk)
+			return
+		}
+		fmt.Fprintln(w, videoInfo)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	o := UploadLarge(fclient, []string{path}, nil)[0]
+	flickr.Expect(t, o.Err, nil)
+	flickr.Expect(t, o.Attempts, 2)
+	flickr.Expect(t, o.Verified, true)
+}
+
+func TestUploadLargeKo(t *testing.T) {
+	path := tempVideo(t)
+	defer os.Remove(path)
+
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMockMethods(200, map[string]string{
+		"upload":                uploadOk,
+		"flickr.photos.getInfo": videoInfo,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	// file too large
+	o := UploadLarge(fclient, []string{path}, &LargeUploadOptions{MaxSize: 1})[0]
+	ee, ok := o.Err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, ee.ErrorCode, flickErr.FileTooLargeError)
+	flickr.Expect(t, o.Attempts, 0)
+
+	// media mismatch
+	o = UploadLarge(fclient, []string{path}, &LargeUploadOptions{Media: "photo"})[0]
+	ee, ok = o.Err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, ee.ErrorCode, flickErr.VerificationError)
+	flickr.Expect(t, o.PhotoId, "1234")
+	flickr.Expect(t, o.Verified, false)
+
+	// API errors are not retried
+	server, client = flickr.FlickrMockMethods(200, map[string]string{})
+	defer server.Close()
+	fclient.HTTPClient = client
+	o = UploadLarge(fclient, []string{path}, nil)[0]
+	_, ok = o.Err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, o.Attempts, 1)
+	flickr.Expect(t, o.PhotoId, "")
+}
+
+func TestLargeUploadTimeout(t *testing.T) {
+	opts := (&LargeUploadOptions{MinThroughput: 10}).withDefaults()
+	flickr.Expect(t, opts.MaxAttempts, 3)
+	flickr.Expect(t, opts.timeout(100, 0), opts.BaseTimeout+10e9)
+	flickr.Expect(t, opts.timeout(100, 2), 4*(opts.BaseTimeout+10e9))
+}
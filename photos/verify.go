@@ -0,0 +1,144 @@
+package photos
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A field of an uploaded photo that doesn't hold what was requested
+type Mismatch struct {
+	// "title", "description", "tags", "is_public", "is_friend", "is_family" or
+	// "safety_level"
+	Field string
+	// For tags, Requested is empty for tags Flickr added and Stored is empty for
+	// requested tags Flickr dropped
+	Requested string
+	Stored    string
+}
+
+// Outcome of the comparison of an uploaded photo with its UploadParams
+type VerificationReport struct {
+	PhotoId    string
+	Mismatches []Mismatch
+}
+
+// Return whether the photo holds everything that was requested
+func (r *VerificationReport) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+func (r *VerificationReport) add(field, requested, stored string) {
+	r.Mismatches = append(r.Mismatches, Mismatch{Field: field, Requested: requested, Stored: stored})
+}
+
+// Return the clean value Flickr derives from a raw tag: lowercase letters and
+// digits only
+func cleanTagValue(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, tag)
+}
+
+// Compare the requested tags with the raw tags stored by Flickr. A stored tag with
+// the same clean value as a requested one is reported as its mangled version.
+func (r *VerificationReport) compareTags(requested []string, stored []Tag) {
+	unmatched := map[string]Tag{}
+	for _, t := range stored {
+		unmatched[t.Raw] = t
+	}
+	missing := []string{}
+	for _, t := range requested {
+		// same normalization as flickr.FormatTags
+		t = strings.TrimSpace(strings.Replace(t, `"`, "", -1))
+		if t == "" {
+			continue
+		}
+		if _, found := unmatched[t]; found {
+			delete(unmatched, t)
+			continue
+		}
+		missing = append(missing, t)
+	}
+
+	for _, t := range missing {
+		mangled := ""
+		for _, tag := range stored {
+			if _, found := unmatched[tag.Raw]; found && tag.Value == cleanTagValue(t) {
+				mangled = tag.Raw
+				delete(unmatched, tag.Raw)
+				break
+			}
+		}
+		r.add("tags", t, mangled)
+	}
+	for _, t := range stored {
+		if _, found := unmatched[t.Raw]; found {
+			r.add("tags", "", t.Raw)
+		}
+	}
+}
+
+// Compare a photo with the params it was uploaded with, flagging the fields that
+// differ, e.g. tags with special characters mangled by Flickr. Title and
+// description are compared ignoring surrounding spaces, friend and family flags
+// only matter for private photos.
+func comparePhoto(info *PhotoInfo, params *flickr.UploadParams) *VerificationReport {
+	report := &VerificationReport{PhotoId: info.Id, Mismatches: []Mismatch{}}
+	if params == nil {
+		return report
+	}
+
+	if title := strings.TrimSpace(params.Title); title != strings.TrimSpace(info.Title) {
+		report.add("title", params.Title, info.Title)
+	}
+	if desc := strings.TrimSpace(params.Description); desc != strings.TrimSpace(info.Description) {
+		report.add("description", params.Description, info.Description)
+	}
+	report.compareTags(params.Tags, info.Tags)
+
+	var flag = func(field string, requested, stored bool) {
+		if requested != stored {
+			report.add(field, strconv.FormatBool(requested), strconv.FormatBool(stored))
+		}
+	}
+	flag("is_public", params.IsPublic, info.Visibility.IsPublic)
+	if !params.IsPublic {
+		flag("is_friend", params.IsFriend, info.Visibility.IsFriend)
+		flag("is_family", params.IsFamily, info.Visibility.IsFamily)
+	}
+	// getInfo reports safety levels one less than uploads
+	if params.SafetyLevel >= 1 && params.SafetyLevel <= 3 && params.SafetyLevel != info.SafetyLevel+1 {
+		report.add("safety_level", strconv.Itoa(params.SafetyLevel), strconv.Itoa(info.SafetyLevel+1))
+	}
+	return report
+}
+
+// Fetch an uploaded photo with getInfo and compare its title, description, tags,
+// privacy and safety level with the params it was uploaded with.
+// This method requires authentication with 'read' permission.
+func VerifyUpload(client *flickr.FlickrClient, photoId string, params *flickr.UploadParams) (*VerificationReport, error) {
+	info, err := GetInfo(client, photoId, "")
+	if err != nil {
+		return nil, err
+	}
+	return comparePhoto(&info.Photo, params), nil
+}
+
+// Same as flickr.UploadReader, then verify the photo with VerifyUpload. Mismatches
+// don't make the call fail, the report is nil if the upload failed.
+// This call must be signed with write permissions
+func UploadVerified(client *flickr.FlickrClient, photoReader io.Reader, name string, params *flickr.UploadParams) (*flickr.UploadResponse, *VerificationReport, error) {
+	resp, err := flickr.UploadReader(client, photoReader, name, params)
+	if err != nil {
+		return resp, nil, err
+	}
+	report, err := VerifyUpload(client, resp.ID, params)
+	return resp, report, err
+}
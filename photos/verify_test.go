@@ -0,0 +1,69 @@
+package photos
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const uploadedInfo = `<rsp stat="ok">
+  <photo id="42" secret="abc" server="65535" safety_level="0" media="photo">
+    <title>Sunset </title>
+    <description>Seen from the pier</description>
+    <visibility ispublic="0" isfriend="1" isfamily="0" />
+    <tags>
+      <tag id="1" raw="sea" machine_tag="0">sea</tag>
+      <tag id="2" raw="c" machine_tag="0">c</tag>
+      <tag id="3" raw="golden hour" machine_tag="0">goldenhour</tag>
+      <tag id="4" raw="uploaded:by=app" machine_tag="1">uploaded:by=app</tag>
+    </tags>
+  </photo>
+</rsp>`
+
+func TestUploadVerified(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"upload":                `<rsp stat="ok"><photoid>42</photoid></rsp>`,
+		"flickr.photos.getInfo": uploadedInfo,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	params := flickr.NewUploadParams()
+	params.Title = "Sunset"
+	params.Description = "Seen from the pier"
+	params.Tags = []string{"sea", "golden hour", "c#", "beach"}
+	params.IsFriend = true
+	params.IsFamily = true
+
+	resp, report, err := UploadVerified(fclient, strings.NewReader("jpeg"), "sunset.jpg", params)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.ID, "42")
+	flickr.Expect(t, calls.Last("flickr.photos.getInfo").Get("photo_id"), "42")
+	flickr.Expect(t, report.PhotoId, "42")
+	flickr.Expect(t, report.OK(), false)
+	flickr.Expect(t, len(report.Mismatches), 4)
+	flickr.Expect(t, report.Mismatches[0], Mismatch{Field: "tags", Requested: "c#", Stored: "c"})
+	flickr.Expect(t, report.Mismatches[1], Mismatch{Field: "tags", Requested: "beach", Stored: ""})
+	flickr.Expect(t, report.Mismatches[2], Mismatch{Field: "tags", Requested: "", Stored: "uploaded:by=app"})
+	flickr.Expect(t, report.Mismatches[3], Mismatch{Field: "is_family", Requested: "true", Stored: "false"})
+}
+
+func TestComparePhoto(t *testing.T) {
+	info := &PhotoInfo{Id: "1", Title: "a", SafetyLevel: 1}
+	info.Visibility.IsPublic = true
+	info.Visibility.IsFamily = true
+
+	params := &flickr.UploadParams{Title: "a", IsPublic: true, SafetyLevel: 2}
+	flickr.Expect(t, comparePhoto(info, params).OK(), true)
+	flickr.Expect(t, comparePhoto(info, nil).OK(), true)
+
+	params = &flickr.UploadParams{Title: "b", IsPublic: false, SafetyLevel: 3}
+	report := comparePhoto(info, params)
+	fields := []string{}
+	for _, m := range report.Mismatches {
+		fields = append(fields, m.Field)
+	}
+	flickr.Expect(t, strings.Join(fields, ","), "title,is_public,is_family,safety_level")
+}
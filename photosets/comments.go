@@ -0,0 +1,168 @@
+package photosets
+
+import (
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/activity"
+	"gopkg.in/masci/flickr.v2/photos"
+)
+
+type CommentsListResponse struct {
+	flickr.BasicResponse
+	Comments struct {
+		PhotosetId string           `xml:"photoset_id,attr"`
+		Items      []photos.Comment `xml:"comment"`
+	} `xml:"comments"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r CommentsListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+type AddCommentResponse struct {
+	flickr.BasicResponse
+	Comment struct {
+		Id string `xml:"id,attr"`
+	} `xml:"comment"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r AddCommentResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the comments of a photoset, oldest first
+// This method does not require authentication.
+func GetComments(client *flickr.FlickrClient, photosetId string) (*CommentsListResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.photosets.comments.getList")
+	client.Args.Set("photoset_id", photosetId)
+	client.ApiSign()
+
+	response := &CommentsListResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Add a comment to a photoset
+// This method requires authentication with 'write' permission.
+func AddComment(client *flickr.FlickrClient, photosetId, text string) (*AddCommentResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photosets.comments.addComment")
+	client.Args.Set("photoset_id", photosetId)
+	client.Args.Set("comment_text", text)
+	client.OAuthSign()
+
+	response := &AddCommentResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Edit the text of a comment left on a photoset
+// This method requires authentication with 'write' permission.
+func EditComment(client *flickr.FlickrClient, commentId, text string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photosets.comments.editComment")
+	client.Args.Set("comment_id", commentId)
+	client.Args.Set("comment_text", text)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// Delete a comment left on a photoset
+// This method requires authentication with 'write' permission.
+func DeleteComment(client *flickr.FlickrClient, commentId string) (*flickr.BasicResponse, error) {
+	client.Init()
+	client.HTTPVerb = "POST"
+	client.Args.Set("method", "flickr.photosets.comments.deleteComment")
+	client.Args.Set("comment_id", commentId)
+	client.OAuthSign()
+
+	response := &flickr.BasicResponse{}
+	err := flickr.DoPost(client, response)
+	return response, err
+}
+
+// A function telling whether a comment left on a photoset is spam
+type SpamPredicate func(photosetId string, comment *photos.Comment) bool
+
+// A comment flagged by a sweep
+type FlaggedComment struct {
+	PhotosetId string
+	Comment    photos.Comment
+}
+
+// Options of SweepComments
+type SweepOptions struct {
+	// Activity timeframe to scan, e.g. "2d" or "4h", empty for the activity since
+	// the last login
+	Timeframe string
+	// Report the comments that would be deleted without deleting them
+	DryRun bool
+	// Set FailFast to stop at the first deletion failing
+	flickr.BatchOptions
+}
+
+// Outcome of SweepComments
+type CommentSweep struct {
+	// Number of new photoset comments checked
+	Scanned int
+	Flagged []FlaggedComment
+	// Outcome of the deletions by comment ID, nil in dry runs
+	Deleted *flickr.BatchResult
+}
+
+// Scan the comments recently left on the photosets of the calling user, as reported
+// by flickr.activity.userPhotos, and delete the ones spam flags. With opts.DryRun
+// nothing is deleted and the report only lists the flagged comments.
+// This method requires authentication with 'write' permission, 'read' for dry runs.
+func SweepComments(client *flickr.FlickrClient, spam SpamPredicate, opts SweepOptions) (*CommentSweep, error) {
+	sweep := &CommentSweep{Flagged: []FlaggedComment{}}
+	for page := 1; ; page++ {
+		resp, err := activity.UserPhotos(client, opts.Timeframe, page, activity.MaxUserPhotosPerPage)
+		if err != nil {
+			return sweep, err
+		}
+		for _, item := range resp.Items.Items {
+			if item.Type != activity.ItemPhotoset {
+				continue
+			}
+			for _, event := range item.Events {
+				if event.Type != activity.EventComment {
+					continue
+				}
+				sweep.Scanned++
+				comment := photos.Comment{
+					Id:         event.CommentId,
+					Author:     event.User,
+					AuthorName: event.Username,
+					DateCreate: event.DateAdded,
+					Text:       event.Text,
+				}
+				if spam(item.Id, &comment) {
+					sweep.Flagged = append(sweep.Flagged, FlaggedComment{PhotosetId: item.Id, Comment: comment})
+				}
+			}
+		}
+		if page >= resp.Items.Pages {
+			break
+		}
+	}
+
+	if opts.DryRun {
+		return sweep, nil
+	}
+	sweep.Deleted = flickr.NewBatchResult()
+	for _, flagged := range sweep.Flagged {
+		_, err := DeleteComment(client, flagged.Comment.Id)
+		if err := sweep.Deleted.Add(flagged.Comment.Id, err, opts.BatchOptions); err != nil {
+			return sweep, err
+		}
+	}
+	return sweep, nil
+}
@@ -0,0 +1,89 @@
+package photosets
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	"gopkg.in/masci/flickr.v2/photos"
+)
+
+const setComments = `<rsp stat="ok"><comments photoset_id="72157">
+  <comment id="11-72157-1" author="35468159852@N01" authorname="Dan" datecreate="1141841470">Lovely set</comment>
+</comments></rsp>`
+
+const setActivity = `<rsp stat="ok"><items page="1" pages="1" perpage="500" total="2">
+  <item type="photoset" id="72157" owner="123@N00" commentsold="1" commentsnew="2">
+    <title>Holidays</title>
+    <activity>
+      <event type="comment" commentid="11-72157-2" user="1@N00" username="spammer" dateadded="1144086424">cheap watches</event>
+      <event type="comment" commentid="11-72157-3" user="2@N00" username="friend" dateadded="1144086425">great!</event>
+    </activity>
+  </item>
+  <item type="photo" id="10289" owner="123@N00" commentsold="0" commentsnew="1">
+    <title>A photo</title>
+    <activity>
+      <event type="comment" commentid="11-10289-1" user="1@N00" username="spammer" dateadded="1144086335">cheap watches</event>
+    </activity>
+  </item>
+</items></rsp>`
+
+func TestPhotosetComments(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.comments.getList":       setComments,
+		"flickr.photosets.comments.addComment":    `<rsp stat="ok"><comment id="11-72157-9" /></rsp>`,
+		"flickr.photosets.comments.editComment":   `<rsp stat="ok"></rsp>`,
+		"flickr.photosets.comments.deleteComment": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	list, err := GetComments(fclient, "72157")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, list.Comments.PhotosetId, "72157")
+	flickr.Expect(t, list.Comments.Items[0].Text, "Lovely set")
+
+	added, err := AddComment(fclient, "72157", "Thanks!")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, added.Comment.Id, "11-72157-9")
+	flickr.Expect(t, calls.Last("flickr.photosets.comments.addComment").Get("comment_text"), "Thanks!")
+
+	_, err = EditComment(fclient, "11-72157-9", "Thanks a lot!")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photosets.comments.editComment").Get("comment_id"), "11-72157-9")
+
+	_, err = DeleteComment(fclient, "11-72157-9")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photosets.comments.deleteComment").Get("comment_id"), "11-72157-9")
+}
+
+func TestSweepComments(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.activity.userPhotos":              setActivity,
+		"flickr.photosets.comments.deleteComment": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	spam := func(photosetId string, c *photos.Comment) bool {
+		return strings.Contains(c.Text, "watches")
+	}
+
+	sweep, err := SweepComments(fclient, spam, SweepOptions{Timeframe: "1d", DryRun: true})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, sweep.Scanned, 2)
+	flickr.Expect(t, len(sweep.Flagged), 1)
+	flickr.Expect(t, sweep.Flagged[0].PhotosetId, "72157")
+	flickr.Expect(t, sweep.Flagged[0].Comment.AuthorName, "spammer")
+	flickr.Expect(t, sweep.Deleted == nil, true)
+	flickr.Expect(t, calls.Last("flickr.photosets.comments.deleteComment") == nil, true)
+	flickr.Expect(t, calls.Last("flickr.activity.userPhotos").Get("timeframe"), "1d")
+	flickr.Expect(t, calls.Last("flickr.activity.userPhotos").Get("per_page"), "50")
+
+	sweep, err = SweepComments(fclient, spam, SweepOptions{Timeframe: "1d"})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(sweep.Deleted.Succeeded), 1)
+	flickr.Expect(t, calls.Last("flickr.photosets.comments.deleteComment").Get("comment_id"), "11-72157-2")
+}
@@ -0,0 +1,221 @@
+package photosets
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Size suffixes whose URL can be built from the public secret, "o" stands for the
+// original file
+var gallerySizes = map[string]bool{
+	"s": true, "q": true, "t": true, "m": true, "n": true, "w": true,
+	"z": true, "c": true, "b": true, "o": true,
+}
+
+// Extra fields needed to build a gallery
+const galleryExtras = "description,date_taken,geo,original_format"
+
+// Location of a photo of a gallery
+type GalleryGeo struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  int     `json:"accuracy"`
+}
+
+// A photo of a gallery manifest
+type GalleryPhoto struct {
+	Id          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Date taken as reported by Flickr, in the "2006-01-02 15:04:05" layout
+	Taken string `json:"taken,omitempty"`
+	// URL of the page of the photo on Flickr
+	Page string `json:"page"`
+	// Image URLs keyed by size suffix
+	URLs map[string]string `json:"urls"`
+	// nil for photos without location
+	Geo *GalleryGeo `json:"geo,omitempty"`
+}
+
+// A static description of an album, with everything needed to render a photo
+// gallery without calling the API
+type GalleryManifest struct {
+	Id          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Owner       string    `json:"owner"`
+	Page        string    `json:"page"`
+	Cover       string    `json:"cover"`
+	Generated   time.Time `json:"generated"`
+	// Size suffixes of the image URLs, the last one is the biggest
+	Sizes  []string       `json:"sizes"`
+	Photos []GalleryPhoto `json:"photos"`
+}
+
+// Options of BuildGallery
+type GalleryOptions struct {
+	// Size suffixes of the image URLs to include, from the smallest to the biggest.
+	// Sizes bigger than "b" (1024px) need dedicated secrets and are not supported
+	// except for the original ("o"). Defaults to "q" and "b".
+	Sizes []string
+	// Sign calls with the user tokens, needed for private albums
+	Authenticate bool
+}
+
+// Build a static manifest of an album: its details along with the URLs of its photos
+// at the requested sizes, titles, descriptions and locations.
+// This method does not require authentication unless you want to access a private set
+func BuildGallery(client *flickr.FlickrClient, photosetId, ownerID string, opts GalleryOptions) (*GalleryManifest, error) {
+	sizes := opts.Sizes
+	if len(sizes) == 0 {
+		sizes = []string{"q", "b"}
+	}
+	for _, size := range sizes {
+		if !gallerySizes[size] {
+			return nil, flickErr.NewError(flickErr.InvalidParamsError, fmt.Sprintf("unsupported gallery size %q", size))
+		}
+	}
+
+	info, err := GetInfo(client, opts.Authenticate, photosetId, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	set := info.Set
+	owner := set.Owner
+	if owner == "" {
+		owner = ownerID
+	}
+	manifest := &GalleryManifest{
+		Id:          set.Id,
+		Title:       set.Title,
+		Description: set.Description,
+		Owner:       owner,
+		Page:        flickr.AlbumURL(owner, set.Id),
+		Cover:       set.Primary,
+		Generated:   time.Now().UTC(),
+		Sizes:       sizes,
+		Photos:      []GalleryPhoto{},
+	}
+
+	collect := func(p *Photo) error {
+		photo := GalleryPhoto{
+			Id:          p.Id,
+			Title:       p.Title,
+			Description: p.Description,
+			Taken:       p.DateTaken,
+			Page:        flickr.PhotoPageURL(owner, p.Id),
+			URLs:        map[string]string{},
+		}
+		for _, size := range sizes {
+			if size == "o" {
+				if u := flickr.OriginalURL(p.Server, p.Id, p.OriginalSecret, p.OriginalFormat); u != "" {
+					photo.URLs[size] = u
+				}
+				continue
+			}
+			photo.URLs[size] = flickr.PhotoURL(p.Server, p.Id, p.Secret, size)
+		}
+		if p.Latitude != 0 || p.Longitude != 0 {
+			photo.Geo = &GalleryGeo{Latitude: p.Latitude, Longitude: p.Longitude, Accuracy: p.Accuracy}
+		}
+		manifest.Photos = append(manifest.Photos, photo)
+		return nil
+	}
+	for page := 1; ; page++ {
+		list, err := StreamPhotos(client, opts.Authenticate, photosetId, ownerID, page, flickr.MaxPerPage, galleryExtras, collect)
+		if err != nil {
+			return nil, err
+		}
+		if page >= list.Pages {
+			break
+		}
+	}
+	return manifest, nil
+}
+
+// Write the manifest as indented JSON
+func (m *GalleryManifest) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Id      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Summary string     `xml:"summary,omitempty"`
+	Links   []atomLink `xml:"link"`
+	// "latitude longitude" as defined by GeoRSS Simple
+	Point string `xml:"georss:point,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	GeoRSS  string   `xml:"xmlns:georss,attr"`
+	Id      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Author  struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// Write the manifest as an Atom feed, one entry per photo linking its page and its
+// biggest image as enclosure. Locations are written as GeoRSS points.
+func (m *GalleryManifest) WriteAtom(w io.Writer) error {
+	updated := m.Generated.Format(time.RFC3339)
+	feed := atomFeed{
+		GeoRSS:  "http://www.georss.org/georss",
+		Id:      m.Page,
+		Title:   m.Title,
+		Updated: updated,
+		Link:    atomLink{Href: m.Page, Rel: "alternate"},
+		Entries: []atomEntry{},
+	}
+	feed.Author.Name = m.Owner
+
+	for _, p := range m.Photos {
+		entry := atomEntry{
+			Id:      p.Page,
+			Title:   p.Title,
+			Updated: updated,
+			Summary: p.Description,
+			Links:   []atomLink{{Href: p.Page, Rel: "alternate"}},
+		}
+		for i := len(m.Sizes) - 1; i >= 0; i-- {
+			if u, found := p.URLs[m.Sizes[i]]; found {
+				// originals keep the format they were uploaded with
+				entry.Links = append(entry.Links, atomLink{Href: u, Rel: "enclosure", Type: mime.TypeByExtension(path.Ext(u))})
+				break
+			}
+		}
+		if p.Geo != nil {
+			entry.Point = fmt.Sprintf("%g %g", p.Geo.Latitude, p.Geo.Longitude)
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(feed)
+}
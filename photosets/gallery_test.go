@@ -0,0 +1,69 @@
+package photosets
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+const (
+	galleryInfo = `<rsp stat="ok"><photoset id="72157" owner="123@N00" primary="1" secret="abc" server="2" photos="2">
+  <title>Holidays</title><description>At the beach</description></photoset></rsp>`
+	galleryPhotos = `<rsp stat="ok"><photoset id="72157" page="1" pages="1" perpage="500" total="2">
+  <photo id="1" secret="s1" server="65535" title="beach" isprimary="1" datetaken="2022-09-24 08:07:22" latitude="47.6" longitude="-122.3" accuracy="16" originalsecret="o1" originalformat="png"><description>sand</description></photo>
+  <photo id="2" secret="s2" server="65535" title="sunset" isprimary="0" latitude="0" longitude="0" accuracy="0"><description></description></photo>
+</photoset></rsp>`
+)
+
+func TestBuildGallery(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.getInfo":   galleryInfo,
+		"flickr.photosets.getPhotos": galleryPhotos,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	m, err := BuildGallery(fclient, "72157", "", GalleryOptions{Sizes: []string{"q", "o"}})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, m.Title, "Holidays")
+	flickr.Expect(t, m.Description, "At the beach")
+	flickr.Expect(t, m.Page, "https://www.flickr.com/photos/123@N00/albums/72157")
+	flickr.Expect(t, m.Cover, "1")
+	flickr.Expect(t, len(m.Photos), 2)
+
+	p := m.Photos[0]
+	flickr.Expect(t, p.Description, "sand")
+	flickr.Expect(t, p.Page, "https://www.flickr.com/photos/123@N00/1")
+	flickr.Expect(t, p.URLs["q"], "https://live.staticflickr.com/65535/1_s1_q.jpg")
+	flickr.Expect(t, p.URLs["o"], "https://live.staticflickr.com/65535/1_o1_o.png")
+	flickr.Expect(t, *p.Geo, GalleryGeo{Latitude: 47.6, Longitude: -122.3, Accuracy: 16})
+	// no original secret, no location
+	_, found := m.Photos[1].URLs["o"]
+	flickr.Expect(t, found, false)
+	flickr.Expect(t, m.Photos[1].Geo == nil, true)
+	flickr.Expect(t, calls.Last("flickr.photosets.getPhotos").Get("extras"), galleryExtras)
+
+	buf := &bytes.Buffer{}
+	flickr.Expect(t, m.WriteJSON(buf), nil)
+	decoded := &GalleryManifest{}
+	flickr.Expect(t, json.Unmarshal(buf.Bytes(), decoded), nil)
+	flickr.Expect(t, decoded.Photos[0].URLs["q"], p.URLs["q"])
+
+	buf.Reset()
+	flickr.Expect(t, m.WriteAtom(buf), nil)
+	atom := buf.String()
+	flickr.Expect(t, strings.Contains(atom, `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:georss="http://www.georss.org/georss">`), true)
+	flickr.Expect(t, strings.Contains(atom, `<link href="https://live.staticflickr.com/65535/1_o1_o.png" rel="enclosure" type="image/png"></link>`), true)
+	flickr.Expect(t, strings.Contains(atom, `<georss:point>47.6 -122.3</georss:point>`), true)
+	flickr.Expect(t, strings.Count(atom, "<georss:point>"), 1)
+}
+
+func TestBuildGalleryBadSize(t *testing.T) {
+	_, err := BuildGallery(flickr.GetTestClient(), "72157", "", GalleryOptions{Sizes: []string{"k"}})
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+}
@@ -0,0 +1,140 @@
+package photosets
+
+import (
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Return the ID of the primary photo of a list, the first photo if none is flagged
+func primaryOf(photos []Photo) string {
+	for _, p := range photos {
+		if p.IsPrimary {
+			return p.Id
+		}
+	}
+	if len(photos) == 0 {
+		return ""
+	}
+	return photos[0].Id
+}
+
+// Return the IDs of a list of photos
+func photoIds(photos []Photo) []string {
+	ids := make([]string, 0, len(photos))
+	for _, p := range photos {
+		ids = append(ids, p.Id)
+	}
+	return ids
+}
+
+// Move the photos of the source sets into the destination set and delete the
+// sources. The photos of dst keep their order and primary photo, the ones of the
+// sources follow, set after set, in their order; photos found in several sets are
+// kept once. Whatever the number of photos, the destination is written with a single
+// editPhotos call and each source deleted with a single delete call, sources are only
+// deleted once dst holds their photos so no photo is ever left out of every set.
+// Returns the photos of dst after the merge.
+// This method requires authentication with 'write' permission.
+func MergeSets(client *flickr.FlickrClient, dstId string, srcIds ...string) ([]Photo, error) {
+	merged, err := getAllPhotos(client, dstId)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, p := range merged {
+		seen[p.Id] = true
+	}
+	added := 0
+	for _, srcId := range srcIds {
+		if srcId == dstId {
+			continue
+		}
+		photos, err := getAllPhotos(client, srcId)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range photos {
+			if !seen[p.Id] {
+				seen[p.Id] = true
+				p.IsPrimary = false
+				merged = append(merged, p)
+				added++
+			}
+		}
+	}
+
+	if added > 0 {
+		if _, err := EditPhotos(client, dstId, primaryOf(merged), photoIds(merged)); err != nil {
+			return nil, err
+		}
+	}
+	for _, srcId := range srcIds {
+		if srcId == dstId {
+			continue
+		}
+		if _, err := Delete(client, srcId); err != nil {
+			return merged, err
+		}
+	}
+	return merged, nil
+}
+
+// Result of SplitSet
+type SplitResult struct {
+	// ID of the set created for the photos moved, empty when no photo matched
+	NewSetId string
+	// Photos moved to the new set and photos left in the source, in their order
+	Moved []Photo
+	Kept  []Photo
+	// Whether every photo matched: the source, which Flickr would delete anyway once
+	// empty, was deleted
+	SourceDeleted bool
+}
+
+// Move the photos of a set matching a predicate to a new set with the given title
+// and description, e.g. to split a set by year with a predicate on DateTaken. Photos
+// keep their order in both sets. The primary photo of the source stays there unless
+// it's moved, then the first photo left becomes primary. The moved photos are added
+// to the new set before they're removed from the source, the calls are: create,
+// editPhotos on the new set when more than one photo moves, then editPhotos on the
+// source or, when every photo moves, delete.
+// This method requires authentication with 'write' permission.
+func SplitSet(client *flickr.FlickrClient, srcId string, match func(Photo) bool, title, description string) (*SplitResult, error) {
+	photos, err := getAllPhotos(client, srcId)
+	if err != nil {
+		return nil, err
+	}
+	result := &SplitResult{Moved: []Photo{}, Kept: []Photo{}}
+	for _, p := range photos {
+		if match(p) {
+			result.Moved = append(result.Moved, p)
+		} else {
+			result.Kept = append(result.Kept, p)
+		}
+	}
+	if len(result.Moved) == 0 {
+		return result, nil
+	}
+
+	created, err := Create(client, title, description, result.Moved[0].Id)
+	if err != nil {
+		return nil, err
+	}
+	result.NewSetId = created.Set.Id
+	if len(result.Moved) > 1 {
+		if _, err := EditPhotos(client, result.NewSetId, result.Moved[0].Id, photoIds(result.Moved)); err != nil {
+			return result, err
+		}
+	}
+
+	if len(result.Kept) == 0 {
+		if _, err := Delete(client, srcId); err != nil {
+			return result, err
+		}
+		result.SourceDeleted = true
+		return result, nil
+	}
+	if _, err := EditPhotos(client, srcId, primaryOf(result.Kept), photoIds(result.Kept)); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package photosets
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A fake Flickr keeping the photos of the sets, the first one being primary
+type fakeSets struct {
+	sync.Mutex
+	sets    map[string][]string
+	methods []string
+	nextId  int
+}
+
+func (f *fakeSets) serve(w http.ResponseWriter, r *http.Request) {
+	r.ParseMultipartForm(1 << 20)
+	f.Lock()
+	defer f.Unlock()
+	method := r.Form.Get("method")
+	f.methods = append(f.methods, method)
+	setId := r.Form.Get("photoset_id")
+	switch method {
+	case "flickr.photosets.getPhotos":
+		fmt.Fprintf(w, `<rsp stat="ok"><photoset id="%s" page="1" pages="1" perpage="500" total="%d">`, setId, len(f.sets[setId]))
+		for i, id := range f.sets[setId] {
+			fmt.Fprintf(w, `<photo id="%s" isprimary="%t" datetaken="20%s-01-01 00:00:00"/>`, id, i == 0, id)
+		}
+		fmt.Fprint(w, `</photoset></rsp>`)
+		return
+	case "flickr.photosets.editPhotos":
+		ids := strings.Split(r.Form.Get("photo_ids"), ",")
+		// the primary photo comes first
+		list := []string{r.Form.Get("primary_photo_id")}
+		for _, id := range ids {
+			if id != list[0] {
+				list = append(list, id)
+			}
+		}
+		f.sets[setId] = list
+	case "flickr.photosets.create":
+		f.nextId++
+		setId = fmt.Sprintf("new%d", f.nextId)
+		f.sets[setId] = []string{r.Form.Get("primary_photo_id")}
+		fmt.Fprintf(w, `<rsp stat="ok"><photoset id="%s"/></rsp>`, setId)
+		return
+	case "flickr.photosets.delete":
+		delete(f.sets, setId)
+	}
+	fmt.Fprint(w, `<rsp stat="ok"></rsp>`)
+}
+
+func newFakeSets(sets map[string][]string) (*fakeSets, *httptest.Server, *flickr.FlickrClient) {
+	fake := &fakeSets{sets: sets}
+	server := httptest.NewServer(http.HandlerFunc(fake.serve))
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+	return fake, server, fclient
+}
+
+func TestMergeSets(t *testing.T) {
+	fake, server, fclient := newFakeSets(map[string][]string{
+		"a": {"10", "11"},
+		"b": {"20", "11", "21"},
+		"c": {"30"},
+	})
+	defer server.Close()
+
+	merged, err := MergeSets(fclient, "a", "b", "c")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, strings.Join(photoIds(merged), ","), "10,11,20,21,30")
+	flickr.Expect(t, strings.Join(fake.sets["a"], ","), "10,11,20,21,30")
+	_, found := fake.sets["b"]
+	flickr.Expect(t, found, false)
+	_, found = fake.sets["c"]
+	flickr.Expect(t, found, false)
+	flickr.Expect(t, strings.Join(fake.methods, ","), "flickr.photosets.getPhotos,flickr.photosets.getPhotos,flickr.photosets.getPhotos,"+
+		"flickr.photosets.editPhotos,flickr.photosets.delete,flickr.photosets.delete")
+}
+
+func TestSplitSet(t *testing.T) {
+	fake, server, fclient := newFakeSets(map[string][]string{
+		"a": {"10", "11", "20", "12", "21"},
+	})
+	defer server.Close()
+
+	in20s := func(p Photo) bool { return strings.HasPrefix(p.DateTaken, "202") }
+	result, err := SplitSet(fclient, "a", in20s, "2020s", "")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, result.NewSetId, "new1")
+	flickr.Expect(t, result.SourceDeleted, false)
+	flickr.Expect(t, strings.Join(fake.sets["new1"], ","), "20,21")
+	flickr.Expect(t, strings.Join(fake.sets["a"], ","), "10,11,12")
+
+	// the primary photo moves, every photo does
+	result, err = SplitSet(fclient, "a", func(p Photo) bool { return true }, "all", "")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, result.SourceDeleted, true)
+	flickr.Expect(t, strings.Join(fake.sets["new2"], ","), "10,11,12")
+	_, found := fake.sets["a"]
+	flickr.Expect(t, found, false)
+
+	// nothing matches, nothing changes
+	fake.methods = nil
+	result, err = SplitSet(fclient, "new1", func(p Photo) bool { return false }, "none", "")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, result.NewSetId, "")
+	flickr.Expect(t, len(fake.methods), 1)
+}
+
+func TestSplitSetPrimaryMoved(t *testing.T) {
+	fake, server, fclient := newFakeSets(map[string][]string{
+		"a": {"20", "10", "21"},
+	})
+	defer server.Close()
+
+	result, err := SplitSet(fclient, "a", func(p Photo) bool { return p.Id[0] == '2' }, "moved", "")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, strings.Join(photoIds(result.Kept), ","), "10")
+	flickr.Expect(t, strings.Join(fake.sets["a"], ","), "10")
+	flickr.Expect(t, strings.Join(fake.sets["new1"], ","), "20,21")
+}
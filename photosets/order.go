@@ -0,0 +1,95 @@
+package photosets
+
+import (
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A snapshot of the manual order of the photos of a set, see ExportOrder
+type SetOrder struct {
+	PhotosetId string   `json:"photoset_id"`
+	PrimaryId  string   `json:"primary_id"`
+	PhotoIds   []string `json:"photo_ids"`
+	// When the order was exported
+	Exported time.Time `json:"exported"`
+}
+
+// Load an order stored under key with SaveTo, nil if the key is missing
+func LoadSetOrder(store flickr.Store, key string) (*SetOrder, error) {
+	order := &SetOrder{}
+	found, err := flickr.LoadJSON(store, key, order)
+	if err != nil || !found {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Store the order as JSON under key
+func (o *SetOrder) SaveTo(store flickr.Store, key string) error {
+	return flickr.SaveJSON(store, key, o)
+}
+
+// Snapshot the order of the photos of a set and its primary photo, e.g. before a bulk
+// operation that may reorder it, see RestoreOrder.
+// This method requires authentication with 'read' permission.
+func ExportOrder(client *flickr.FlickrClient, photosetId string) (*SetOrder, error) {
+	photos, err := getAllPhotos(client, photosetId)
+	if err != nil {
+		return nil, err
+	}
+	return &SetOrder{
+		PhotosetId: photosetId,
+		PrimaryId:  primaryOf(photos),
+		PhotoIds:   photoIds(photos),
+		Exported:   time.Now(),
+	}, nil
+}
+
+// Put the photos of a set back in the order of a snapshot taken with ExportOrder,
+// along with its primary photo. Photos added to the set since the snapshot follow,
+// in their current order, photos removed since are not added back. The set is only
+// written when its order differs from the snapshot. Returns the IDs of the photos of
+// the set in their new order.
+// This method requires authentication with 'write' permission.
+func RestoreOrder(client *flickr.FlickrClient, photosetId string, order *SetOrder) ([]string, error) {
+	photos, err := getAllPhotos(client, photosetId)
+	if err != nil {
+		return nil, err
+	}
+	current := photoIds(photos)
+	inSet := map[string]bool{}
+	for _, id := range current {
+		inSet[id] = true
+	}
+
+	restored := make([]string, 0, len(current))
+	placed := map[string]bool{}
+	for _, id := range order.PhotoIds {
+		if inSet[id] && !placed[id] {
+			placed[id] = true
+			restored = append(restored, id)
+		}
+	}
+	for _, id := range current {
+		if !placed[id] {
+			restored = append(restored, id)
+		}
+	}
+	primary := primaryOf(photos)
+	if inSet[order.PrimaryId] {
+		primary = order.PrimaryId
+	}
+
+	unchanged := primary == primaryOf(photos)
+	for i := range restored {
+		unchanged = unchanged && restored[i] == current[i]
+	}
+	if unchanged || len(restored) == 0 {
+		return restored, nil
+	}
+	if _, err := ReorderPhotos(client, photosetId, primary, restored); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
@@ -0,0 +1,45 @@
+package photosets
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestExportRestoreOrder(t *testing.T) {
+	fake, server, fclient := newFakeSets(map[string][]string{"s": {"1", "2", "3", "4"}})
+	defer server.Close()
+
+	order, err := ExportOrder(fclient, "s")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, order.PrimaryId, "1")
+	flickr.Expect(t, len(order.PhotoIds), 4)
+
+	store := flickr.NewMemoryStore()
+	flickr.Expect(t, order.SaveTo(store, "order/s"), nil)
+	loaded, err := LoadSetOrder(store, "order/s")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, loaded.PhotoIds[3], "4")
+	missing, err := LoadSetOrder(store, "order/x")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, missing == nil, true)
+
+	// nothing to restore
+	ids, err := RestoreOrder(fclient, "s", loaded)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(ids), 4)
+	flickr.Expect(t, fake.methods[len(fake.methods)-1], "flickr.photosets.getPhotos")
+
+	// the set was reordered, photo 3 removed and photo 5 added meanwhile
+	fake.sets["s"] = []string{"4", "5", "2", "1"}
+	ids, err = RestoreOrder(fclient, "s", loaded)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(ids), 4)
+	flickr.Expect(t, ids[0], "1")
+	flickr.Expect(t, ids[1], "2")
+	flickr.Expect(t, ids[2], "4")
+	flickr.Expect(t, ids[3], "5")
+	flickr.Expect(t, fake.methods[len(fake.methods)-1], "flickr.photosets.editPhotos")
+	flickr.Expect(t, fake.sets["s"][0], "1")
+	flickr.Expect(t, fake.sets["s"][3], "5")
+}
@@ -0,0 +1,141 @@
+package photosets
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A function reporting whether photo a must come before photo b in a photoset
+type PhotoLess func(a, b Photo) bool
+
+// Order photos by date taken, oldest first
+func ByDateTaken(a, b Photo) bool {
+	// Flickr returns dates in MySQL datetime format, that sorts lexicographically
+	return a.DateTaken < b.DateTaken
+}
+
+// Order photos by upload date, oldest first
+func ByDateUpload(a, b Photo) bool {
+	ta, _ := strconv.ParseInt(a.DateUpload, 10, 64)
+	tb, _ := strconv.ParseInt(b.DateUpload, 10, 64)
+	return ta < tb
+}
+
+// Order photos by title using natural sort, so that "IMG 2" comes before "IMG 10"
+func ByTitle(a, b Photo) bool {
+	return NaturalLess(a.Title, b.Title)
+}
+
+// Invert the order of a PhotoLess function
+func Reverse(less PhotoLess) PhotoLess {
+	return func(a, b Photo) bool {
+		return less(b, a)
+	}
+}
+
+// Compare two strings case insensitively, treating sequences of digits as numbers
+func NaturalLess(a, b string) bool {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	i, j := 0, 0
+	for i < len(ra) && j < len(rb) {
+		if unicode.IsDigit(ra[i]) && unicode.IsDigit(rb[j]) {
+			// compare the whole numbers, ignoring leading zeros
+			si := i
+			for i < len(ra) && unicode.IsDigit(ra[i]) {
+				i++
+			}
+			sj := j
+			for j < len(rb) && unicode.IsDigit(rb[j]) {
+				j++
+			}
+			na := strings.TrimLeft(string(ra[si:i]), "0")
+			nb := strings.TrimLeft(string(rb[sj:j]), "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			continue
+		}
+		if ra[i] != rb[j] {
+			return ra[i] < rb[j]
+		}
+		i++
+		j++
+	}
+	return len(ra)-i < len(rb)-j
+}
+
+// Fetch all the photos in a set, walking every page of the results
+func getAllPhotos(client *flickr.FlickrClient, photosetId string) ([]Photo, error) {
+	ret := []Photo{}
+	for page := 1; ; page++ {
+		resp, err := getPhotos(client, true, photosetId, "", page, "date_taken,date_upload")
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, resp.Photoset.Photos...)
+		if page >= resp.Photoset.Pages {
+			break
+		}
+	}
+	return ret, nil
+}
+
+// Sort the photos of a set according to less and store the new order on Flickr,
+// the primary photo is left untouched. Returns the photos in their new order.
+// This method requires authentication with 'write' permission.
+func SortPhotos(client *flickr.FlickrClient, photosetId string, less PhotoLess) ([]Photo, error) {
+	photos, err := getAllPhotos(client, photosetId)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(photos, func(i, j int) bool {
+		return less(photos[i], photos[j])
+	})
+
+	primary := ""
+	ids := make([]string, 0, len(photos))
+	for _, p := range photos {
+		ids = append(ids, p.Id)
+		if p.IsPrimary {
+			primary = p.Id
+		}
+	}
+
+	if _, err = ReorderPhotos(client, photosetId, primary, ids); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// Set as primary the photo coming first according to less, e.g. pass
+// Reverse(ByDateUpload) to use the newest photo. Returns the ID of the new primary photo.
+// This method requires authentication with 'write' permission.
+func SetPrimaryPhotoBy(client *flickr.FlickrClient, photosetId string, less PhotoLess) (string, error) {
+	photos, err := getAllPhotos(client, photosetId)
+	if err != nil {
+		return "", err
+	}
+	if len(photos) == 0 {
+		return "", nil
+	}
+
+	first := photos[0]
+	for _, p := range photos[1:] {
+		if less(p, first) {
+			first = p
+		}
+	}
+
+	if _, err = SetPrimaryPhoto(client, photosetId, first.Id); err != nil {
+		return "", err
+	}
+	return first.Id, nil
+}
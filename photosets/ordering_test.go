@@ -0,0 +1,71 @@
+package photosets
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const setPhotos = `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photoset id="4" primary="2" owner="123@N00" page="1" pages="1" perpage="500" total="3">
+    <photo id="1" title="IMG 10" isprimary="0" datetaken="2015-03-01 10:00:00" dateupload="1500000003" />
+    <photo id="2" title="img 2" isprimary="1" datetaken="2012-01-01 10:00:00" dateupload="1500000001" />
+    <photo id="3" title="IMG 9" isprimary="0" datetaken="2014-01-01 10:00:00" dateupload="1500000002" />
+  </photoset>
+</rsp>`
+
+func TestNaturalLess(t *testing.T) {
+	flickr.Expect(t, NaturalLess("IMG 2", "IMG 10"), true)
+	flickr.Expect(t, NaturalLess("IMG 10", "IMG 2"), false)
+	flickr.Expect(t, NaturalLess("img 2", "IMG 3"), true)
+	flickr.Expect(t, NaturalLess("a", "ab"), true)
+	flickr.Expect(t, NaturalLess("ab", "a"), false)
+	flickr.Expect(t, NaturalLess("file002", "file10"), true)
+	flickr.Expect(t, NaturalLess("same", "same"), false)
+}
+
+func TestSortPhotos(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.getPhotos":  setPhotos,
+		"flickr.photosets.editPhotos": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	photos, err := SortPhotos(fclient, "4", ByTitle)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(photos), 3)
+	edit := calls.Last("flickr.photosets.editPhotos")
+	flickr.Expect(t, edit.Get("photo_ids"), "2,3,1")
+	flickr.Expect(t, edit.Get("primary_photo_id"), "2")
+	flickr.Expect(t, strings.Contains(calls.Last("flickr.photosets.getPhotos").Get("extras"), "date_taken"), true)
+
+	_, err = SortPhotos(fclient, "4", Reverse(ByDateTaken))
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photosets.editPhotos").Get("photo_ids"), "1,3,2")
+}
+
+func TestSetPrimaryPhotoBy(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.getPhotos":       setPhotos,
+		"flickr.photosets.setPrimaryPhoto": `<rsp stat="ok"></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	id, err := SetPrimaryPhotoBy(fclient, "4", Reverse(ByDateUpload))
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, id, "1")
+	flickr.Expect(t, calls.Last("flickr.photosets.setPrimaryPhoto").Get("photo_id"), "1")
+
+	// failures are reported
+	server, client = flickr.FlickrMockMethods(200, map[string]string{})
+	defer server.Close()
+	fclient.HTTPClient = client
+	_, err = SetPrimaryPhotoBy(fclient, "4", ByTitle)
+	flickr.Expect(t, err != nil, true)
+}
@@ -2,7 +2,6 @@
 package photosets
 
 import (
-	"strconv"
 	"strings"
 
 	"gopkg.in/masci/flickr.v2"
@@ -27,11 +26,30 @@ type Photoset struct {
 	Description       string `xml:"description"`
 	Url               string `xml:"url,attr"`
 	Owner             string `xml:"owner,attr"`
+	// getInfo reports the number of photos and videos in these attributes
+	CountPhotos int `xml:"count_photos,attr"`
+	CountVideos int `xml:"count_videos,attr"`
 }
 
 type Photo struct {
-	Id    string `xml:"id,attr"`
-	Title string `xml:"title,attr"`
+	Id        string `xml:"id,attr"`
+	Secret    string `xml:"secret,attr"`
+	Server    string `xml:"server,attr"`
+	Title     string `xml:"title,attr"`
+	IsPrimary bool   `xml:"isprimary,attr"`
+	// populated when extras contains "date_taken"
+	DateTaken string `xml:"datetaken,attr"`
+	// populated when extras contains "date_upload"
+	DateUpload string `xml:"dateupload,attr"`
+	// populated when extras contains "original_format"
+	OriginalFormat string `xml:"originalformat,attr"`
+	OriginalSecret string `xml:"originalsecret,attr"`
+	// populated when extras contains "geo", zero for photos without location
+	Latitude  float64 `xml:"latitude,attr"`
+	Longitude float64 `xml:"longitude,attr"`
+	Accuracy  int     `xml:"accuracy,attr"`
+	// populated when extras contains "description"
+	Description string `xml:"description"`
 }
 
 type PhotosetsListResponse struct {
@@ -45,11 +63,21 @@ type PhotosetsListResponse struct {
 	} `xml:"photosets"`
 }
 
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PhotosetsListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
 type PhotosetResponse struct {
 	flickr.BasicResponse
 	Set Photoset `xml:"photoset"`
 }
 
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PhotosetResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
 type PhotosListResponse struct {
 	flickr.BasicResponse
 	Photoset struct {
@@ -61,6 +89,11 @@ type PhotosListResponse struct {
 	} `xml:"photoset"`
 }
 
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PhotosListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
 // Return the public sets belonging to the user with userId.
 // If userId is not provided it defaults to the caller user but call needs to be authenticated.
 // This method requires authentication to retrieve private sets.
@@ -72,7 +105,7 @@ func GetList(client *flickr.FlickrClient, authenticate bool, userId string, page
 	}
 	// if not provided, flickr defaults this argument to 1
 	if page > 1 {
-		client.Args.Set("page", strconv.Itoa(page))
+		client.SetInt("page", page)
 	}
 	// perform authentication if requested
 	if authenticate {
@@ -153,6 +186,11 @@ func RemovePhoto(client *flickr.FlickrClient, photosetId, photoId string) (*flic
 // Get the photos in a set
 // This method requires authentication to retrieve photos from private sets
 func GetPhotos(client *flickr.FlickrClient, authenticate bool, photosetId, ownerID string, page int) (*PhotosListResponse, error) {
+	return getPhotos(client, authenticate, photosetId, ownerID, page, "")
+}
+
+// Same as GetPhotos, asking Flickr for the given comma separated list of extra fields
+func getPhotos(client *flickr.FlickrClient, authenticate bool, photosetId, ownerID string, page int, extras string) (*PhotosListResponse, error) {
 	client.Init()
 	client.Args.Set("method", "flickr.photosets.getPhotos")
 	client.Args.Set("photoset_id", photosetId)
@@ -162,7 +200,10 @@ func GetPhotos(client *flickr.FlickrClient, authenticate bool, photosetId, owner
 	}
 	// if not provided, flickr defaults this argument to 1
 	if page > 1 {
-		client.Args.Set("page", strconv.Itoa(page))
+		client.SetInt("page", page)
+	}
+	if extras != "" {
+		client.Args.Set("extras", extras)
 	}
 	// sign the client for authentication and authorization
 	if authenticate {
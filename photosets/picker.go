@@ -0,0 +1,103 @@
+package photosets
+
+import (
+	"sync"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A photoset shaped for album pickers in TUI/GUI frontends
+type AlbumChoice struct {
+	Id     string
+	Title  string
+	Photos int
+	Videos int
+	// URL of the primary photo thumbnail, empty if the set has no primary photo
+	ThumbnailURL string
+	Updated      time.Time
+}
+
+// Options of Picker
+type PickerOptions struct {
+	// Size suffix of the thumbnails, "q" (150px square) when empty
+	ThumbnailSize string
+	// Maximum number of pages fetched at the same time, 4 when zero
+	Concurrency int
+}
+
+// Convert a photoset to an AlbumChoice, building the thumbnail URL from the primary
+// photo details returned by getList
+func newAlbumChoice(set *Photoset, size string) AlbumChoice {
+	choice := AlbumChoice{
+		Id:      set.Id,
+		Title:   set.Title,
+		Photos:  set.Photos,
+		Videos:  set.Videos,
+		Updated: time.Unix(int64(set.DateUpdate), 0),
+	}
+	if set.Primary != "" && set.Server != "" && set.Secret != "" {
+		choice.ThumbnailURL = flickr.PhotoURL(set.Server, set.Primary, set.Secret, size)
+	}
+	return choice
+}
+
+// Return every photoset of a user along with the thumbnail of its primary photo,
+// in the order chosen by the user. The first page is fetched to know how many there
+// are, the other ones are fetched concurrently using clones of client.
+// If userId is not provided it defaults to the caller user but call needs to be authenticated.
+// This method requires authentication to retrieve private sets.
+func Picker(client *flickr.FlickrClient, authenticate bool, userId string, opts PickerOptions) ([]AlbumChoice, error) {
+	if opts.ThumbnailSize == "" {
+		opts.ThumbnailSize = "q"
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+
+	first, err := GetList(client, authenticate, userId, 1)
+	if err != nil {
+		return nil, err
+	}
+	pages := make([][]Photoset, first.Photosets.Pages)
+	if len(pages) == 0 {
+		pages = make([][]Photoset, 1)
+	}
+	pages[0] = first.Photosets.Items
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	sem := make(chan struct{}, opts.Concurrency)
+	for page := 2; page <= len(pages); page++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(page int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := GetList(client.Clone(), authenticate, userId, page)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			pages[page-1] = resp.Photosets.Items
+		}(page)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	ret := []AlbumChoice{}
+	for _, items := range pages {
+		for i := range items {
+			ret = append(ret, newAlbumChoice(&items[i], opts.ThumbnailSize))
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,58 @@
+package photosets
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestPicker(t *testing.T) {
+	// every page holds a single set named after the page
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		primary := `primary="77" secret="abc" server="65535"`
+		if page == "3" {
+			primary = ""
+		}
+		fmt.Fprintf(w, `<rsp stat="ok"><photosets page="%s" pages="3" perpage="1" total="3">
+  <photoset id="set%s" %s photos="4" videos="1" date_update="1376079704"><title>Page %s</title></photoset>
+</photosets></rsp>`, page, page, primary, page)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	albums, err := Picker(fclient, false, "123@N00", PickerOptions{Concurrency: 2})
+	flickr.Expect(t, err, nil)
+	ids := []string{}
+	for _, a := range albums {
+		ids = append(ids, a.Id)
+	}
+	flickr.Expect(t, strings.Join(ids, ","), "set1,set2,set3")
+	flickr.Expect(t, albums[1].Title, "Page 2")
+	flickr.Expect(t, albums[1].Photos, 4)
+	flickr.Expect(t, albums[1].Updated.Unix(), int64(1376079704))
+	flickr.Expect(t, albums[0].ThumbnailURL, "https://live.staticflickr.com/65535/77_abc_q.jpg")
+	flickr.Expect(t, albums[2].ThumbnailURL, "")
+}
+
+func TestPickerError(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, bodyKo, "text/xml")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	albums, err := Picker(fclient, false, "123@N00", PickerOptions{})
+	flickr.Expect(t, albums == nil, true)
+	flickr.Expect(t, err != nil, true)
+}
@@ -0,0 +1,172 @@
+package photosets
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Size suffixes of the uncropped sizes getPhotos can return URLs for, from the
+// smallest to the biggest. Squares ("sq", "q") are left out since srcset candidates
+// must share the same aspect ratio.
+var ladderSizes = []string{"t", "s", "n", "w", "m", "z", "c", "l", "h", "k", "3k", "4k", "5k", "6k", "o"}
+
+// Sizes of a slideshow when SlideshowOptions.Sizes is empty: up to 2048px, the
+// original is left out since it keeps the format and rotation it was uploaded with
+var defaultLadder = []string{"t", "s", "n", "w", "m", "z", "c", "l", "h", "k"}
+
+// A size of a photo of a slideshow
+type SizeSource struct {
+	// Size suffix, e.g. "z"
+	Size   string `json:"size"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// A photo of a slideshow along with its available sizes
+type SlideshowPhoto struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+	// From the narrowest to the widest, sizes larger than the original are not listed
+	Sources []SizeSource `json:"sources"`
+}
+
+// Return the sizes of the photo as the value of an img srcset attribute, e.g.
+// "https://.../1_a_n.jpg 320w, https://.../1_a_z.jpg 640w"
+func (p *SlideshowPhoto) SrcSet() string {
+	candidates := make([]string, 0, len(p.Sources))
+	for _, s := range p.Sources {
+		candidates = append(candidates, fmt.Sprintf("%s %dw", s.URL, s.Width))
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// Return the narrowest size at least width pixels wide, the widest one if none is
+// large enough. Returns false if the photo has no sizes.
+func (p *SlideshowPhoto) Fit(width int) (SizeSource, bool) {
+	if len(p.Sources) == 0 {
+		return SizeSource{}, false
+	}
+	for _, s := range p.Sources {
+		if s.Width >= width {
+			return s, true
+		}
+	}
+	return p.Sources[len(p.Sources)-1], true
+}
+
+// The photos of an album with the sizes needed by responsive web galleries
+type Slideshow struct {
+	Id     string           `json:"id"`
+	Photos []SlideshowPhoto `json:"photos"`
+}
+
+// Write the slideshow as indented JSON
+func (s *Slideshow) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// Options of BuildSlideshow
+type SlideshowOptions struct {
+	// Size suffixes to include, any of "t", "s", "n", "w", "m", "z", "c", "l", "h",
+	// "k", "3k", "4k", "5k", "6k" and "o". Defaults to the sizes from "t" (100px)
+	// to "k" (2048px).
+	Sizes []string
+	// Sign calls with the user tokens, needed for private albums
+	Authenticate bool
+}
+
+// A photo of getPhotos keeping the url_*, width_* and height_* extras
+type ladderPhoto struct {
+	Photo
+	Attrs []xml.Attr `xml:",any,attr"`
+}
+
+func (p *ladderPhoto) attr(name string) string {
+	for _, a := range p.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// Build the sources of a photo from its extras, skipping the sizes Flickr didn't
+// return and the ones as wide as the previous one (upscaled sizes of small photos)
+func (p *ladderPhoto) sources(sizes []string) []SizeSource {
+	ret := []SizeSource{}
+	for _, size := range sizes {
+		url := p.attr("url_" + size)
+		if url == "" {
+			continue
+		}
+		width, _ := strconv.Atoi(p.attr("width_" + size))
+		height, _ := strconv.Atoi(p.attr("height_" + size))
+		if len(ret) > 0 && width <= ret[len(ret)-1].Width {
+			continue
+		}
+		ret = append(ret, SizeSource{Size: size, URL: url, Width: width, Height: height})
+	}
+	return ret
+}
+
+// Build, for every photo of an album, the list of its available sizes with their
+// widths, suitable for srcset attributes. The URLs come from the url_* extras of
+// photosets.getPhotos, so no getSizes call is needed, even for the sizes that have
+// dedicated secrets.
+// This method does not require authentication unless you want to access a private set
+func BuildSlideshow(client *flickr.FlickrClient, photosetId, ownerID string, opts SlideshowOptions) (*Slideshow, error) {
+	sizes := defaultLadder
+	if len(opts.Sizes) > 0 {
+		// keep the ladder order whatever the order of opts.Sizes
+		requested := map[string]bool{}
+		for _, size := range opts.Sizes {
+			requested[size] = true
+		}
+		sizes = []string{}
+		for _, size := range ladderSizes {
+			if requested[size] {
+				sizes = append(sizes, size)
+				delete(requested, size)
+			}
+		}
+		for _, size := range opts.Sizes {
+			if requested[size] {
+				return nil, flickErr.NewError(flickErr.InvalidParamsError, fmt.Sprintf("unsupported slideshow size %q", size))
+			}
+		}
+	}
+	extras := make([]string, len(sizes))
+	for i, size := range sizes {
+		extras[i] = "url_" + size
+	}
+
+	show := &Slideshow{Id: photosetId, Photos: []SlideshowPhoto{}}
+	decode := func(d *xml.Decoder, start *xml.StartElement) error {
+		p := &ladderPhoto{}
+		if err := d.DecodeElement(p, start); err != nil {
+			return err
+		}
+		show.Photos = append(show.Photos, SlideshowPhoto{Id: p.Id, Title: p.Title, Sources: p.sources(sizes)})
+		return nil
+	}
+	for page := 1; ; page++ {
+		list, err := streamPhotos(client, opts.Authenticate, photosetId, ownerID, page, flickr.MaxPerPage, strings.Join(extras, ","), decode)
+		if err != nil {
+			return nil, err
+		}
+		if page >= list.Pages {
+			break
+		}
+	}
+	return show, nil
+}
@@ -0,0 +1,50 @@
+package photosets
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+const slideshowPhotos = `<rsp stat="ok"><photoset id="72157" page="1" pages="1" perpage="500" total="2">
+  <photo id="1" secret="s1" server="65535" title="beach" isprimary="1"
+    url_n="https://live.staticflickr.com/65535/1_s1_n.jpg" width_n="320" height_n="240"
+    url_z="https://live.staticflickr.com/65535/1_s1_z.jpg" width_z="640" height_z="480"
+    url_k="https://live.staticflickr.com/65535/1_k1_k.jpg" width_k="2048" height_k="1536" />
+  <photo id="2" secret="s2" server="65535" title="tiny" isprimary="0"
+    url_n="https://live.staticflickr.com/65535/2_s2_n.jpg" width_n="300" height_n="200"
+    url_z="https://live.staticflickr.com/65535/2_s2_z.jpg" width_z="300" height_z="200" />
+</photoset></rsp>`
+
+func TestBuildSlideshow(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.getPhotos": slideshowPhotos,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	show, err := BuildSlideshow(fclient, "72157", "", SlideshowOptions{Sizes: []string{"k", "n", "z"}})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.photosets.getPhotos").Get("extras"), "url_n,url_z,url_k")
+	flickr.Expect(t, len(show.Photos), 2)
+
+	p := show.Photos[0]
+	flickr.Expect(t, p.Title, "beach")
+	flickr.Expect(t, len(p.Sources), 3)
+	flickr.Expect(t, p.Sources[2].Height, 1536)
+	flickr.Expect(t, p.SrcSet(), "https://live.staticflickr.com/65535/1_s1_n.jpg 320w, "+
+		"https://live.staticflickr.com/65535/1_s1_z.jpg 640w, https://live.staticflickr.com/65535/1_k1_k.jpg 2048w")
+	s, ok := p.Fit(600)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, s.Size, "z")
+	s, _ = p.Fit(4000)
+	flickr.Expect(t, s.Size, "k")
+
+	// sizes as wide as smaller ones are left out
+	flickr.Expect(t, len(show.Photos[1].Sources), 1)
+
+	_, err = BuildSlideshow(fclient, "72157", "", SlideshowOptions{Sizes: []string{"q"}})
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+}
@@ -0,0 +1,89 @@
+package photosets
+
+import (
+	"strconv"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Layout of the dates Flickr returns in the "datetaken" attribute
+const dateTakenLayout = "2006-01-02 15:04:05"
+
+// Statistics of a photoset at a given time, for reporting purposes
+type PhotosetSnapshot struct {
+	Id       string
+	Title    string
+	Photos   int
+	Videos   int
+	Views    int
+	Comments int
+	Created  time.Time
+	Updated  time.Time
+	// Date range of the contents, zero if the set is empty or dates are unknown
+	FirstTaken  time.Time
+	LastTaken   time.Time
+	FirstUpload time.Time
+	LastUpload  time.Time
+	// When the snapshot was taken
+	Taken time.Time
+}
+
+// Extend the [first, last] range to include t, zero values are ignored
+func extendRange(first, last *time.Time, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	if first.IsZero() || t.Before(*first) {
+		*first = t
+	}
+	if last.IsZero() || t.After(*last) {
+		*last = t
+	}
+}
+
+// Build a snapshot of a photoset combining getInfo and getPhotos, the photos of the
+// set are streamed so big sets don't need to fit in memory.
+// This method does not require authentication unless you want to access a private set
+func Snapshot(client *flickr.FlickrClient, authenticate bool, photosetId, ownerID string) (*PhotosetSnapshot, error) {
+	info, err := GetInfo(client, authenticate, photosetId, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := info.Set
+	snap := &PhotosetSnapshot{
+		Id:       set.Id,
+		Title:    set.Title,
+		Photos:   set.Photos,
+		Videos:   set.Videos,
+		Views:    set.CountViews,
+		Comments: set.CountComments,
+		Created:  time.Unix(int64(set.DateCreate), 0),
+		Updated:  time.Unix(int64(set.DateUpdate), 0),
+		Taken:    time.Now(),
+	}
+	if set.CountPhotos > 0 || set.CountVideos > 0 {
+		snap.Photos, snap.Videos = set.CountPhotos, set.CountVideos
+	}
+
+	collect := func(p *Photo) error {
+		// unknown dates can't be parsed and are left out
+		taken, _ := time.Parse(dateTakenLayout, p.DateTaken)
+		extendRange(&snap.FirstTaken, &snap.LastTaken, taken)
+		if upload, err := strconv.ParseInt(p.DateUpload, 10, 64); err == nil {
+			extendRange(&snap.FirstUpload, &snap.LastUpload, time.Unix(upload, 0))
+		}
+		return nil
+	}
+	for page := 1; ; page++ {
+		list, err := StreamPhotos(client, authenticate, photosetId, ownerID, page, flickr.MaxPerPage, "date_taken,date_upload", collect)
+		if err != nil {
+			return nil, err
+		}
+		if page >= list.Pages {
+			break
+		}
+	}
+	return snap, nil
+}
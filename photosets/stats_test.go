@@ -0,0 +1,42 @@
+package photosets
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestSnapshot(t *testing.T) {
+	info := `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photoset id="4" owner="123@N00" primary="2" secret="a" server="1" farm="1" photos="3" count_views="42" count_comments="7" count_photos="2" count_videos="1" date_create="1438183533" date_update="1438183843">
+    <title>Holidays</title>
+    <description />
+  </photoset>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.getInfo":   info,
+		"flickr.photosets.getPhotos": setPhotos,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	snap, err := Snapshot(fclient, true, "4", "")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, snap.Title, "Holidays")
+	flickr.Expect(t, snap.Photos, 2)
+	flickr.Expect(t, snap.Videos, 1)
+	flickr.Expect(t, snap.Views, 42)
+	flickr.Expect(t, snap.Comments, 7)
+	flickr.Expect(t, snap.Created, time.Unix(1438183533, 0))
+	flickr.Expect(t, snap.FirstTaken, time.Date(2012, 1, 1, 10, 0, 0, 0, time.UTC))
+	flickr.Expect(t, snap.LastTaken, time.Date(2015, 3, 1, 10, 0, 0, 0, time.UTC))
+	flickr.Expect(t, snap.FirstUpload, time.Unix(1500000001, 0))
+	flickr.Expect(t, snap.LastUpload, time.Unix(1500000003, 0))
+
+	args := calls.Last("flickr.photosets.getPhotos")
+	flickr.Expect(t, args.Get("per_page"), "500")
+	flickr.Expect(t, args.Get("extras"), "date_taken,date_upload")
+}
@@ -0,0 +1,106 @@
+package photosets
+
+import (
+	"encoding/xml"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Set the paging arguments shared by list methods, zero values are left to Flickr defaults
+func setPaging(client *flickr.FlickrClient, page, perPage int) {
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+}
+
+// Same as GetList, calling fn for every set as soon as it's decoded instead of
+// building the whole list in memory. Returning an error from fn stops the decoding.
+func StreamList(client *flickr.FlickrClient, authenticate bool, userId string, page, perPage int, fn func(*Photoset) error) (*flickr.ListInfo, error) {
+	if err := flickr.ValidatePerPage(perPage); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.photosets.getList")
+	if userId != "" {
+		client.Args.Set("user_id", userId)
+	}
+	setPaging(client, page, perPage)
+	if authenticate {
+		client.OAuthSign()
+	} else {
+		client.ApiSign()
+	}
+
+	return flickr.DoGetStream(client, "photoset", func(d *xml.Decoder, start *xml.StartElement) error {
+		set := &Photoset{}
+		if err := d.DecodeElement(set, start); err != nil {
+			return err
+		}
+		return fn(set)
+	})
+}
+
+// Same as GetPhotos, calling fn for every photo as soon as it's decoded instead of
+// building the whole list in memory. extras is an optional comma separated list of
+// extra fields. Returning an error from fn stops the decoding.
+func StreamPhotos(client *flickr.FlickrClient, authenticate bool, photosetId, ownerID string, page, perPage int, extras string, fn func(*Photo) error) (*flickr.ListInfo, error) {
+	return streamPhotos(client, authenticate, photosetId, ownerID, page, perPage, extras, func(d *xml.Decoder, start *xml.StartElement) error {
+		photo := &Photo{}
+		if err := d.DecodeElement(photo, start); err != nil {
+			return err
+		}
+		return fn(photo)
+	})
+}
+
+// Same as StreamPhotos, decoding every photo element with decode
+func streamPhotos(client *flickr.FlickrClient, authenticate bool, photosetId, ownerID string, page, perPage int, extras string, decode func(*xml.Decoder, *xml.StartElement) error) (*flickr.ListInfo, error) {
+	if err := flickr.ValidatePerPage(perPage); err != nil {
+		return nil, err
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.photosets.getPhotos")
+	client.Args.Set("photoset_id", photosetId)
+	if ownerID != "" {
+		client.Args.Set("user_id", ownerID)
+	}
+	setPaging(client, page, perPage)
+	if extras != "" {
+		client.Args.Set("extras", extras)
+	}
+	if authenticate {
+		client.OAuthSign()
+	} else {
+		client.ApiSign()
+	}
+
+	return flickr.DoGetStream(client, "photo", decode)
+}
+
+// Return the IDs of the photos of the given albums of a user, e.g. to fill the
+// InAlbums and NotInAlbums sets of a photos.Filter.
+// This method does not require authentication unless you want to access private sets
+func AlbumPhotoIds(client *flickr.FlickrClient, authenticate bool, ownerID string, photosetIds []string) (map[string]bool, error) {
+	ids := map[string]bool{}
+	for _, photosetId := range photosetIds {
+		var it *flickr.PageIterator
+		it = flickr.NewPageIterator(func(page int) (*flickr.ListInfo, error) {
+			return StreamPhotos(client, authenticate, photosetId, ownerID, page, flickr.MaxPerPage, "", func(p *Photo) error {
+				it.Keep(p.Id)
+				ids[p.Id] = true
+				return nil
+			})
+		}, flickr.PageIteratorOptions{})
+		for it.Next() {
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,64 @@
+package photosets
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestStreamList(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.getList": body,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	titles := []string{}
+	info, err := StreamList(fclient, true, "", 1, 500, func(set *Photoset) error {
+		titles = append(titles, set.Title)
+		return nil
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, strings.Join(titles, ","), "A photoset,Portraits")
+	flickr.Expect(t, info.Total, 2)
+	flickr.Expect(t, calls.Last("flickr.photosets.getList").Get("per_page"), "500")
+}
+
+func TestStreamPhotos(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.getPhotos": setPhotos,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	ids := []string{}
+	info, err := StreamPhotos(fclient, false, "4", "", 2, 0, "date_taken", func(p *Photo) error {
+		ids = append(ids, p.Id)
+		return nil
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, strings.Join(ids, ","), "1,2,3")
+	flickr.Expect(t, info.PerPage, 500)
+	args := calls.Last("flickr.photosets.getPhotos")
+	flickr.Expect(t, args.Get("page"), "2")
+	flickr.Expect(t, args.Get("per_page"), "")
+	flickr.Expect(t, args.Get("extras"), "date_taken")
+}
+
+func TestAlbumPhotoIds(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.photosets.getPhotos": setPhotos,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	ids, err := AlbumPhotoIds(fclient, false, "me", []string{"4", "5"})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(ids), 3)
+	flickr.Expect(t, ids["2"], true)
+	flickr.Expect(t, calls.Last("flickr.photosets.getPhotos").Get("photoset_id"), "5")
+}
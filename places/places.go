@@ -0,0 +1,82 @@
+// Package implementing methods: flickr.places.*
+package places
+
+import (
+	"strings"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// A level of the hierarchy a place belongs to, e.g. its region
+type PlacePart struct {
+	PlaceId string `xml:"place_id,attr"`
+	WoeId   string `xml:"woeid,attr"`
+	Name    string `xml:",chardata"`
+}
+
+// A place of Flickr Places
+type Place struct {
+	PlaceId   string  `xml:"place_id,attr"`
+	WoeId     string  `xml:"woeid,attr"`
+	Latitude  float64 `xml:"latitude,attr"`
+	Longitude float64 `xml:"longitude,attr"`
+	PlaceUrl  string  `xml:"place_url,attr"`
+	// e.g. "neighbourhood", "locality", "region" or "country"
+	PlaceType string `xml:"place_type,attr"`
+	Timezone  string `xml:"timezone,attr"`
+	// Full name, e.g. "Montreal, Quebec, Canada", not reported by every method
+	FullName      string    `xml:"name,attr"`
+	Neighbourhood PlacePart `xml:"neighbourhood"`
+	Locality      PlacePart `xml:"locality"`
+	County        PlacePart `xml:"county"`
+	Region        PlacePart `xml:"region"`
+	Country       PlacePart `xml:"country"`
+}
+
+// Return the full name of the place, built from its hierarchy when not reported
+func (p *Place) Name() string {
+	if p.FullName != "" {
+		return p.FullName
+	}
+	parts := []string{}
+	for _, part := range []PlacePart{p.Neighbourhood, p.Locality, p.Region, p.Country} {
+		if name := strings.TrimSpace(part.Name); name != "" {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+type PlaceInfoResponse struct {
+	flickr.BasicResponse
+	Place Place `xml:"place"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r PlaceInfoResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the details of a place, identified by its Flickr Places ID or by its
+// Yahoo WOE ID, one of them must be set
+// This method does not require authentication.
+func GetInfo(client *flickr.FlickrClient, placeId, woeId string) (*PlaceInfoResponse, error) {
+	if placeId == "" && woeId == "" {
+		return nil, flickErr.NewError(flickErr.InvalidParamsError, "either place_id or woe_id must be set")
+	}
+
+	client.Init()
+	client.Args.Set("method", "flickr.places.getInfo")
+	if placeId != "" {
+		client.Args.Set("place_id", placeId)
+	}
+	if woeId != "" {
+		client.Args.Set("woe_id", woeId)
+	}
+	client.ApiSign()
+
+	response := &PlaceInfoResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
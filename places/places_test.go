@@ -0,0 +1,39 @@
+package places
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestGetInfo(t *testing.T) {
+	body := `<rsp stat="ok">
+  <place place_id="4hLQygSaBJ92" woeid="3534" latitude="45.512" longitude="-73.554" place_url="/Canada/Quebec/Montreal" place_type="locality" timezone="America/Toronto">
+    <locality place_id="4hLQygSaBJ92" woeid="3534">Montreal</locality>
+    <county place_id="cFBi9x6bCJ8D5rba1g" woeid="29375198">Montréal</county>
+    <region place_id="CrZUvXebApjI0.72" woeid="2344924">Quebec</region>
+    <country place_id="EESRy8qbApgaeIkbsA" woeid="23424775">Canada</country>
+  </place>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.places.getInfo": body,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetInfo(fclient, "", "3534")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Place.PlaceType, "locality")
+	flickr.Expect(t, resp.Place.Latitude, 45.512)
+	flickr.Expect(t, resp.Place.Region.WoeId, "2344924")
+	flickr.Expect(t, resp.Place.Name(), "Montreal, Quebec, Canada")
+	flickr.Expect(t, calls.Last("flickr.places.getInfo").Get("woe_id"), "3534")
+	flickr.Expect(t, calls.Last("flickr.places.getInfo").Get("place_id"), "")
+
+	flickr.Expect(t, (&Place{FullName: "Paris, France"}).Name(), "Paris, France")
+
+	_, err = GetInfo(fclient, "", "")
+	flickr.Expect(t, err.(*flickErr.Error).ErrorCode, flickErr.InvalidParamsError)
+}
@@ -0,0 +1,230 @@
+// Package publish composes uploads, photosets and group pools into the most common
+// end-to-end workflow: upload a batch of files, collect them in an album and share it.
+//
+//	report, err := publish.Publish(client, []string{"a.jpg", "b.jpg"}, publish.Options{
+//		Title:  "Holidays",
+//		Groups: map[string][]string{"34427469792@N01": {"a.jpg"}},
+//	})
+//	fmt.Println(report.AlbumURL)
+package publish
+
+import (
+	"fmt"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+	"gopkg.in/masci/flickr.v2/groups"
+	"gopkg.in/masci/flickr.v2/photosets"
+	"gopkg.in/masci/flickr.v2/test"
+)
+
+// Throttle mode of groups without posting limits
+const throttleNone = "none"
+
+// Throttle mode of groups where posting is not allowed
+const throttleDisabled = "disabled"
+
+// Options of Publish
+type Options struct {
+	// Title and description of the album
+	Title       string
+	Description string
+	// Add the photos to an existing album of the calling user with the same title
+	// instead of creating a new one
+	ReuseAlbum bool
+	// Path of the file to use as album cover, the first uploaded file if empty
+	Cover string
+	// Params applied to every upload, nil for the defaults of flickr.NewUploadParams
+	Upload *flickr.UploadParams
+	// Files to submit to group pools, keyed by group ID
+	Groups map[string][]string
+	// Optional ledger to avoid submitting a photo to the same group twice
+	Ledger *groups.PostingLedger
+	flickr.BatchOptions
+}
+
+// Outcome of Publish
+type Report struct {
+	// IDs of the uploaded photos and the URL of their page, keyed by file path
+	PhotoIds  map[string]string
+	PhotoURLs map[string]string
+	AlbumId   string
+	// Whether the album was created, false if an existing one was reused
+	AlbumCreated bool
+	// Public URL of the album, ready to be shared
+	AlbumURL string
+	// Outcome of the uploads, items are file paths
+	Uploads *flickr.BatchResult
+	// Outcome of the group submissions, items are "groupId/photoId"
+	Submissions *flickr.BatchResult
+}
+
+// Return the NSID of the calling user, asking Flickr when the client doesn't know it
+func callingUser(client *flickr.FlickrClient) (string, error) {
+	if client.Id != "" {
+		return client.Id, nil
+	}
+	resp, err := test.Login(client)
+	if err != nil {
+		return "", err
+	}
+	return resp.User.ID, nil
+}
+
+// Return the first album of the calling user titled title, nil if there is none
+func findAlbum(client *flickr.FlickrClient, title string) (*photosets.Photoset, error) {
+	for page := 1; ; page++ {
+		resp, err := photosets.GetList(client, true, "", page)
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.Photosets.Items {
+			if resp.Photosets.Items[i].Title == title {
+				return &resp.Photosets.Items[i], nil
+			}
+		}
+		if page >= resp.Photosets.Pages {
+			return nil, nil
+		}
+	}
+}
+
+// Upload files, collect them in an album, set its cover and submit some of them to
+// group pools, returning a report with the IDs and URLs of everything created.
+// Files failing to upload are recorded in the report and left out of the album,
+// failures of the album calls stop the pipeline. Group submissions are throttle
+// aware: the photos exceeding the posts a group still allows are not submitted and
+// a warning is recorded instead.
+// With FailFast set the pipeline stops at the first failing upload or submission,
+// the report is returned along with the error in any case.
+// This method requires authentication with 'write' permission.
+func Publish(client *flickr.FlickrClient, files []string, opts Options) (*Report, error) {
+	report := &Report{
+		PhotoIds:    map[string]string{},
+		PhotoURLs:   map[string]string{},
+		Uploads:     flickr.NewBatchResult(),
+		Submissions: flickr.NewBatchResult(),
+	}
+	if opts.Title == "" {
+		return report, flickErr.NewError(flickErr.InvalidParamsError, "album title is required")
+	}
+
+	userId, err := callingUser(client)
+	if err != nil {
+		return report, err
+	}
+
+	params := opts.Upload
+	if params == nil {
+		params = flickr.NewUploadParams()
+	}
+	uploaded := []string{}
+	for _, path := range files {
+		resp, err := flickr.UploadFile(client, path, params)
+		if err == nil {
+			report.PhotoIds[path] = resp.ID
+			report.PhotoURLs[path] = flickr.PhotoPageURL(userId, resp.ID)
+			uploaded = append(uploaded, resp.ID)
+		}
+		if err := report.Uploads.Add(path, err, opts.BatchOptions); err != nil {
+			return report, err
+		}
+	}
+	if len(uploaded) == 0 {
+		return report, report.Uploads.Err()
+	}
+
+	cover := uploaded[0]
+	if id, found := report.PhotoIds[opts.Cover]; found {
+		cover = id
+	}
+	if err := fillAlbum(client, report, uploaded, cover, opts); err != nil {
+		return report, err
+	}
+	report.AlbumURL = flickr.AlbumURL(userId, report.AlbumId)
+
+	for groupId, paths := range opts.Groups {
+		if err := submit(client, report, groupId, paths, opts); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// Create or reuse the album and add the uploaded photos to it
+func fillAlbum(client *flickr.FlickrClient, report *Report, uploaded []string, cover string, opts Options) error {
+	var set *photosets.Photoset
+	if opts.ReuseAlbum {
+		found, err := findAlbum(client, opts.Title)
+		if err != nil {
+			return err
+		}
+		set = found
+	}
+
+	added := map[string]bool{}
+	if set == nil {
+		resp, err := photosets.Create(client, opts.Title, opts.Description, cover)
+		if err != nil {
+			return err
+		}
+		set = &resp.Set
+		report.AlbumCreated = true
+		added[cover] = true
+	}
+	report.AlbumId = set.Id
+
+	for _, id := range uploaded {
+		if added[id] {
+			continue
+		}
+		if _, err := photosets.AddPhoto(client, set.Id, id); err != nil {
+			return err
+		}
+		added[id] = true
+	}
+	if !report.AlbumCreated {
+		if _, err := photosets.SetPrimaryPhoto(client, set.Id, cover); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Submit the uploaded photos among paths to a group pool, within the posts the group
+// still allows
+func submit(client *flickr.FlickrClient, report *Report, groupId string, paths []string, opts Options) error {
+	info, err := groups.GetInfo(client, groupId)
+	if err != nil {
+		return err
+	}
+	throttle := info.Group.Throttle
+
+	for _, path := range paths {
+		photoId, found := report.PhotoIds[path]
+		if !found {
+			report.Submissions.Warn("%s was not uploaded, not submitted to %s", path, groupId)
+			continue
+		}
+		item := groupId + "/" + photoId
+
+		posted := true
+		if throttle.Mode == throttleDisabled {
+			err = flickErr.NewError(flickErr.ApiError, fmt.Sprintf("posting to %s is disabled", groupId))
+		} else if throttle.Mode != "" && throttle.Mode != throttleNone && throttle.Remaining <= 0 {
+			report.Submissions.Warn("%s: throttle limit of %s reached, %s not submitted", groupId, info.Group.Name, photoId)
+			continue
+		} else if opts.Ledger != nil {
+			posted, err = opts.Ledger.Post(client, groupId, photoId)
+		} else {
+			_, err = groups.AddPhoto(client, groupId, photoId)
+		}
+		if err == nil && posted {
+			throttle.Remaining--
+		}
+		if err := report.Submissions.Add(item, err, opts.BatchOptions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package publish
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const (
+	uploaded  = `<rsp stat="ok"><photoid>42</photoid></rsp>`
+	created   = `<rsp stat="ok"><photoset id="72157" url="https://www.flickr.com/photos/bees/sets/72157/" /></rsp>`
+	ok        = `<rsp stat="ok"></rsp>`
+	groupOpen = `<rsp stat="ok"><group id="34427469792@N01"><name>FlickrCentral</name>
+  <throttle count="2" mode="day" remaining="1" /></group></rsp>`
+	groupFull = `<rsp stat="ok"><group id="34427469792@N01"><name>FlickrCentral</name>
+  <throttle count="2" mode="day" remaining="0" /></group></rsp>`
+	setList = `<rsp stat="ok"><photosets page="1" pages="1" perpage="500" total="1">
+  <photoset id="72999" primary="1" secret="abc" server="2" photos="2" videos="0"><title>Holidays</title></photoset>
+</photosets></rsp>`
+)
+
+func tempFile(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "publish")
+	flickr.Expect(t, err, nil)
+	path := filepath.Join(dir, "beach.jpg")
+	flickr.Expect(t, ioutil.WriteFile(path, []byte("jpeg"), 0644), nil)
+	return path
+}
+
+func TestPublish(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	fclient.Id = "123@N00"
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"upload":                    uploaded,
+		"flickr.photosets.create":   created,
+		"flickr.groups.getInfo":     groupOpen,
+		"flickr.groups.pools.add":   ok,
+		"flickr.photosets.addPhoto": ok,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	path := tempFile(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	missing := filepath.Join(filepath.Dir(path), "missing.jpg")
+
+	report, err := Publish(fclient, []string{missing, path}, Options{
+		Title:  "Holidays",
+		Groups: map[string][]string{"34427469792@N01": {missing, path}},
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(report.Uploads.Succeeded), 1)
+	flickr.Expect(t, report.Uploads.Succeeded[0], path)
+	flickr.Expect(t, len(report.Uploads.Failed), 1)
+	flickr.Expect(t, len(report.PhotoIds), 1)
+	flickr.Expect(t, report.PhotoIds[path], "42")
+	flickr.Expect(t, report.PhotoURLs[path], "https://www.flickr.com/photos/123@N00/42")
+	flickr.Expect(t, report.AlbumId, "72157")
+	flickr.Expect(t, report.AlbumCreated, true)
+	flickr.Expect(t, report.AlbumURL, "https://www.flickr.com/photos/123@N00/albums/72157")
+	flickr.Expect(t, len(report.Submissions.Succeeded), 1)
+	flickr.Expect(t, report.Submissions.Succeeded[0], "34427469792@N01/42")
+	flickr.Expect(t, len(report.Submissions.Warnings), 1)
+
+	args := calls.Last("flickr.photosets.create")
+	flickr.Expect(t, args.Get("title"), "Holidays")
+	flickr.Expect(t, args.Get("primary_photo_id"), "42")
+	// the cover is added by create
+	flickr.Expect(t, calls.Last("flickr.photosets.addPhoto") == nil, true)
+}
+
+func TestPublishReuseThrottled(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	fclient.Id = "123@N00"
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"upload":                           uploaded,
+		"flickr.photosets.getList":         setList,
+		"flickr.photosets.addPhoto":        ok,
+		"flickr.photosets.setPrimaryPhoto": ok,
+		"flickr.groups.getInfo":            groupFull,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+	signatures := &bytes.Buffer{}
+	fclient.SignatureDebug = signatures
+
+	path := tempFile(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	report, err := Publish(fclient, []string{path}, Options{
+		Title:      "Holidays",
+		ReuseAlbum: true,
+		Cover:      path,
+		Groups:     map[string][]string{"34427469792@N01": {path}},
+	})
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, report.AlbumId, "72999")
+	flickr.Expect(t, report.AlbumCreated, false)
+	// the album is looked up with a GET following the upload POST
+	flickr.Expect(t, strings.Contains(signatures.String(), "signature of GET flickr.photosets.getList"), true)
+	flickr.Expect(t, calls.Last("flickr.photosets.addPhoto").Get("photo_id"), "42")
+	flickr.Expect(t, calls.Last("flickr.photosets.setPrimaryPhoto").Get("photo_id"), "42")
+	flickr.Expect(t, calls.Last("flickr.groups.pools.add") == nil, true)
+	flickr.Expect(t, len(report.Submissions.Succeeded), 0)
+	flickr.Expect(t, len(report.Submissions.Warnings), 1)
+}
+
+func TestPublishNoTitle(t *testing.T) {
+	_, err := Publish(flickr.GetTestClient(), []string{"beach.jpg"}, Options{})
+	flickr.Expect(t, err != nil, true)
+}
@@ -0,0 +1,137 @@
+package flickr
+
+import (
+	"sync"
+	"time"
+)
+
+// Number of calls per hour Flickr allows for an api key
+const DefaultHourlyQuota = 3600
+
+// QuotaTracker counts the requests performed in the last hour to estimate how much
+// of the Flickr quota is left, so that daemons can slow down before Flickr starts
+// rejecting calls. Every HTTP request counts, retries included.
+// The estimate only covers requests performed by the clients sharing the tracker:
+// share it among all the clients using the same api key.
+// A QuotaTracker is safe for concurrent use.
+type QuotaTracker struct {
+	// Calls allowed per hour
+	Limit int
+	// Fractions of Limit triggering OnThreshold when crossed, e.g. 0.75
+	Thresholds []float64
+	// Optional callback invoked once when usage crosses one of the Thresholds, it's
+	// invoked again if usage goes below the threshold and crosses it once more
+	OnThreshold func(threshold float64, used, limit int)
+
+	mu    sync.Mutex
+	calls []time.Time
+	// thresholds already notified
+	crossed map[float64]bool
+	// replaced in tests
+	now func() time.Time
+}
+
+// Create a tracker for the default quota, notifying usage at 75% and 90%
+func NewQuotaTracker() *QuotaTracker {
+	return &QuotaTracker{
+		Limit:      DefaultHourlyQuota,
+		Thresholds: []float64{0.75, 0.9},
+	}
+}
+
+// Track the requests performed by the client with tracker
+func WithQuotaTracker(tracker *QuotaTracker) ClientOption {
+	return func(c *FlickrClient) {
+		c.QuotaTracker = tracker
+	}
+}
+
+func (q *QuotaTracker) clock() time.Time {
+	if q.now != nil {
+		return q.now()
+	}
+	return time.Now()
+}
+
+// Drop the calls older than one hour. Must be called with mu held
+func (q *QuotaTracker) prune(now time.Time) {
+	start := now.Add(-time.Hour)
+	i := 0
+	for i < len(q.calls) && !q.calls[i].After(start) {
+		i++
+	}
+	q.calls = q.calls[i:]
+}
+
+// Return the number of calls performed in the last hour
+func (q *QuotaTracker) Used() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.prune(q.clock())
+	return len(q.calls)
+}
+
+// Return the number of calls left in the last hour, never negative
+func (q *QuotaTracker) Remaining() int {
+	used := q.Used()
+	if used >= q.Limit {
+		return 0
+	}
+	return q.Limit - used
+}
+
+// Return how long until a call leaves the hour window when the quota is exhausted,
+// zero while calls are left
+func (q *QuotaTracker) freeIn() time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := q.clock()
+	q.prune(now)
+	if q.Limit <= 0 || len(q.calls) < q.Limit {
+		return 0
+	}
+	return q.calls[len(q.calls)-q.Limit].Add(time.Hour).Sub(now)
+}
+
+// Record a call, notifying the thresholds crossed
+func (q *QuotaTracker) record() {
+	q.mu.Lock()
+	now := q.clock()
+	q.prune(now)
+	q.calls = append(q.calls, now)
+	used := len(q.calls)
+
+	notify := []float64{}
+	if q.Limit > 0 {
+		if q.crossed == nil {
+			q.crossed = map[float64]bool{}
+		}
+		usage := float64(used) / float64(q.Limit)
+		for _, threshold := range q.Thresholds {
+			switch {
+			case usage >= threshold && !q.crossed[threshold]:
+				q.crossed[threshold] = true
+				notify = append(notify, threshold)
+			case usage < threshold:
+				q.crossed[threshold] = false
+			}
+		}
+	}
+	onThreshold := q.OnThreshold
+	q.mu.Unlock()
+
+	if onThreshold != nil {
+		for _, threshold := range notify {
+			onThreshold(threshold, used, q.Limit)
+		}
+	}
+}
+
+// Return the estimated number of calls the client can still perform in the current
+// hour according to its QuotaTracker, -1 if the client has no tracker
+func (c *FlickrClient) EstimatedRemainingQuota() int {
+	if c.QuotaTracker == nil {
+		return -1
+	}
+	return c.QuotaTracker.Remaining()
+}
@@ -0,0 +1,56 @@
+package flickr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuotaTracker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	q := NewQuotaTracker()
+	q.Limit = 10
+	q.now = func() time.Time { return now }
+	notified := []float64{}
+	q.OnThreshold = func(threshold float64, used, limit int) {
+		notified = append(notified, threshold)
+		Expect(t, limit, 10)
+	}
+
+	for i := 0; i < 8; i++ {
+		q.record()
+		now = now.Add(time.Minute)
+	}
+	Expect(t, q.Used(), 8)
+	Expect(t, q.Remaining(), 2)
+	Expect(t, len(notified), 1)
+	Expect(t, notified[0], 0.75)
+
+	q.record()
+	q.record()
+	q.record()
+	Expect(t, q.Remaining(), 0)
+	Expect(t, len(notified), 2)
+	Expect(t, notified[1], 0.9)
+
+	// calls older than one hour are forgotten and thresholds are re-armed
+	now = now.Add(time.Hour)
+	Expect(t, q.Used(), 0)
+	for i := 0; i < 8; i++ {
+		q.record()
+	}
+	Expect(t, len(notified), 3)
+}
+
+func TestEstimatedRemainingQuota(t *testing.T) {
+	fclient := GetTestClient()
+	Expect(t, fclient.EstimatedRemainingQuota(), -1)
+
+	server, client := FlickrMock(200, `<rsp stat="ok"></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+	WithQuotaTracker(NewQuotaTracker())(fclient)
+
+	Expect(t, DoGet(fclient, &FooResponse{}), nil)
+	Expect(t, DoGet(fclient, &FooResponse{}), nil)
+	Expect(t, fclient.EstimatedRemainingQuota(), DefaultHourlyQuota-2)
+}
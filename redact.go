@@ -0,0 +1,53 @@
+package flickr
+
+import (
+	"net/url"
+	"regexp"
+)
+
+// Replacement of the values masked by Redact
+const Redacted = "REDACTED"
+
+// Params whose values must never end up in logs or error messages
+var secretParams = []string{"api_secret", "oauth_token_secret", "oauth_signature", "api_sig"}
+
+// Match a secret param followed by its value, either as in a query string
+// (oauth_signature=abc), possibly URL encoded, or as in an OAuth Authorization header
+// (oauth_signature="abc"). The param name must not be the tail of another name.
+var secretPattern = regexp.MustCompile(`(^|[^A-Za-z0-9_]|%3[Ff]|%26)(api_secret|oauth_token_secret|oauth_signature|api_sig)(=|%3D)("[^"]*"|[^&\s"',;]*)`)
+
+// Mask the values of secrets (api_secret, oauth_token_secret, oauth_signature and
+// api_sig) found in s, e.g. a URL, a query string or a log line. The client runs
+// error messages and traced params through it, apps can use it for their own logs.
+func Redact(s string) string {
+	return secretPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := secretPattern.FindStringSubmatch(match)
+		if len(parts[4]) > 0 && parts[4][0] == '"' {
+			return parts[1] + parts[2] + parts[3] + `"` + Redacted + `"`
+		}
+		return parts[1] + parts[2] + parts[3] + Redacted
+	})
+}
+
+// Return a copy of params with the values of secrets masked, see Redact
+func RedactValues(params url.Values) url.Values {
+	ret := url.Values{}
+	for k, v := range params {
+		ret[k] = v
+	}
+	for _, name := range secretParams {
+		if _, found := ret[name]; found {
+			ret[name] = []string{Redacted}
+		}
+	}
+	return ret
+}
+
+// Mask the secrets in the URL reported by the errors of http.Client, GET requests
+// carry the signature in the query string
+func redactError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		urlErr.URL = Redact(urlErr.URL)
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package flickr
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	Expect(t, Redact("https://api.flickr.com/services/rest?oauth_signature=abc%3D&oauth_token=tok&photo_id=1"),
+		"https://api.flickr.com/services/rest?oauth_signature=REDACTED&oauth_token=tok&photo_id=1")
+	Expect(t, Redact(`OAuth oauth_token="tok", oauth_signature="abc%3D"`), `OAuth oauth_token="tok", oauth_signature="REDACTED"`)
+	Expect(t, Redact("oauth_token=tok&oauth_token_secret=s3cr3t"), "oauth_token=tok&oauth_token_secret=REDACTED")
+	Expect(t, Redact("api_secret=s3cr3t api_sig=123"), "api_secret=REDACTED api_sig=REDACTED")
+	Expect(t, Redact("callback%3Fapi_secret%3Ds3cr3t"), "callback%3Fapi_secret%3DREDACTED")
+	Expect(t, Redact("my_api_sig=123"), "my_api_sig=123")
+	Expect(t, Redact("nothing to hide"), "nothing to hide")
+}
+
+func TestRedactValues(t *testing.T) {
+	params := url.Values{"oauth_signature": {"abc"}, "photo_id": {"1"}}
+	redacted := RedactValues(params)
+	Expect(t, redacted.Get("oauth_signature"), Redacted)
+	Expect(t, redacted.Get("photo_id"), "1")
+	_, found := redacted["api_sig"]
+	Expect(t, found, false)
+	// the original params are untouched
+	Expect(t, params.Get("oauth_signature"), "abc")
+}
+
+func TestRedactNetError(t *testing.T) {
+	client := unreachableClient()
+	client.Args.Set("method", "flickr.test.null")
+	client.OAuthSign()
+	signature := client.Args.Get("oauth_signature")
+
+	err := DoGet(client, &FooResponse{})
+	Expect(t, err != nil, true)
+	Expect(t, strings.Contains(err.Error(), url.QueryEscape(signature)), false)
+	Expect(t, strings.Contains(err.Error(), "oauth_signature="+Redacted), true)
+}
+
+func TestTraceHookRedacted(t *testing.T) {
+	server, httpClient := FlickrMock(200, `<rsp stat="ok"></rsp>`, "")
+	defer server.Close()
+
+	var traced url.Values
+	client := GetTestClient()
+	client.HTTPClient = httpClient
+	client.TraceHook = &TraceHook{Start: func(ctx context.Context, info *CallInfo) context.Context {
+		traced = info.Args
+		return ctx
+	}}
+	client.Init()
+	client.Args.Set("method", "flickr.test.null")
+	client.OAuthSign()
+
+	Expect(t, DoGet(client, &FooResponse{}), nil)
+	Expect(t, traced.Get("oauth_signature"), Redacted)
+	Expect(t, traced.Get("method"), "flickr.test.null")
+	Expect(t, client.Args.Get("oauth_signature") != Redacted, true)
+}
@@ -1,6 +1,7 @@
 package flickr
 
 import (
+	"bytes"
 	"encoding/xml"
 	"io/ioutil"
 	"net/http"
@@ -29,6 +30,27 @@ type BasicResponse struct {
 		Message string `xml:"msg,attr"`
 	} `xml:"err"`
 	Extra string `xml:",innerxml"`
+	// Status code, size and headers of the HTTP response, filled in by DoGet, DoPost
+	// and the upload functions even when the call fails
+	HTTP ResponseMeta `xml:"-"`
+}
+
+// HTTP details of the response a call was answered with, e.g. to tell a proxy or a
+// CDN challenge page from a Flickr failure
+type ResponseMeta struct {
+	StatusCode int
+	// Size of the body announced by the server, -1 when unknown
+	ContentLength int64
+	Header        http.Header
+}
+
+// Implemented by the responses embedding BasicResponse
+type responseMetaSetter interface {
+	setResponseMeta(res *http.Response)
+}
+
+func (r *BasicResponse) setResponseMeta(res *http.Response) {
+	r.HTTP = ResponseMeta{StatusCode: res.StatusCode, ContentLength: res.ContentLength, Header: res.Header}
 }
 
 // Return whether a response contains errors
@@ -66,15 +88,28 @@ func (r *BasicResponse) SetErrorMsg(msg string) {
 }
 
 // Given an http.Response retrieved from Flickr, unmarshal results
-// into a FlickrResponse struct.
-func parseApiResponse(res *http.Response, r FlickrResponse) error {
+// into a FlickrResponse struct. Bad encodings in user generated content are
+// tolerated, see newResponseDecoder. The HTTP details of res are recorded in r
+// when it embeds BasicResponse.
+func parseApiResponse(res *http.Response, r FlickrResponse, charsetReader CharsetReader) error {
+	return parseResponseWith(res, r, func(body []byte, r FlickrResponse) error {
+		return newResponseDecoder(bytes.NewReader(body), charsetReader).Decode(r)
+	})
+}
+
+// Same as parseApiResponse, decoding the body with decode
+func parseResponseWith(res *http.Response, r FlickrResponse, decode ResponseDecoder) error {
 	defer res.Body.Close()
+	if setter, ok := r.(responseMetaSetter); ok {
+		setter.setResponseMeta(res)
+	}
 	responseBody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return err
 	}
 
-	err = xml.Unmarshal(responseBody, r)
+	var oauthErr *OAuthError
+	err = decode(responseBody, r)
 	if err != nil {
 		// In case of OAuth errors (signature, parameters, etc) Flicker does not
 		// return a REST response but raw text (!), so the unmarshalling could fail.
@@ -83,10 +118,18 @@ func parseApiResponse(res *http.Response, r FlickrResponse) error {
 		r.SetErrorStatus(true)
 		r.SetErrorCode(-1)
 		r.SetErrorMsg(string(responseBody))
+		if oauthErr = parseOAuthProblemBody(responseBody); oauthErr != nil {
+			oauthErr.ServerTime = serverTime(res)
+		}
 	}
 
 	if r.HasErrors() {
-		return flickErr.NewError(flickErr.ApiError, r.ErrorMsg())
+		err := flickErr.NewError(flickErr.ApiError, r.ErrorMsg())
+		err.ApiCode = r.ErrorCode()
+		if oauthErr != nil {
+			err.Cause = oauthErr
+		}
+		return err
 	}
 
 	return nil
@@ -72,7 +72,7 @@ func TestParseResponse(t *testing.T) {
 	response := &http.Response{}
 	response.Body = NewFakeBody(bodyStr)
 
-	err := parseApiResponse(response, flickrResp)
+	err := parseApiResponse(response, flickrResp, nil)
 
 	Expect(t, err, nil)
 	Expect(t, flickrResp.Foo, "Foo!")
@@ -80,14 +80,14 @@ func TestParseResponse(t *testing.T) {
 	response = &http.Response{}
 	response.Body = NewFakeBody("a_non_rest_format_error")
 
-	err = parseApiResponse(response, flickrResp)
+	err = parseApiResponse(response, flickrResp, nil)
 	ferr, ok := err.(*flickErr.Error)
 	Expect(t, ok, true)
 	Expect(t, ferr.ErrorCode, 10)
 
 	response = &http.Response{}
 	response.Body = NewFakeBody(`<?xml version="1.0" encoding="utf-8" ?><rsp stat="fail"></rsp>`)
-	err = parseApiResponse(response, flickrResp)
+	err = parseApiResponse(response, flickrResp, nil)
 	//ferr, ok := err.(*flickErr.Error)
 	//Expect(t, ok, true)
 	//Expect(t, ferr.ErrorCode, 10)
@@ -111,7 +111,7 @@ func TestExtra(t *testing.T) {
 	response := &http.Response{}
 	response.Body = NewFakeBody(bodyStr)
 
-	err := parseApiResponse(response, flickrResp)
+	err := parseApiResponse(response, flickrResp, nil)
 
 	Expect(t, err, nil)
 	Expect(t, flickrResp.Extra != "", true)
@@ -0,0 +1,234 @@
+package flickr
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Flickr errors signalling a temporary failure on their side
+const (
+	ServiceUnavailable   = 105
+	WriteOperationFailed = 106
+)
+
+// RetryPolicy retries API calls failing because of temporary problems on the Flickr
+// side (errors 105 and 106, HTTP 429 and 5xx) or connections that couldn't be
+// established, with a capped exponential backoff honoring the Retry-After header
+// when the server sends one.
+// A circuit breaker per API method stops calling Flickr after too many consecutive
+// failures: calls fail immediately with a CircuitOpenError until the cooldown expires,
+// then a single call is let through to probe the service.
+// Retries are signed again, with a fresh OAuth nonce and timestamp. Requests whose
+// body can't be replayed (e.g. streamed uploads) are never retried.
+// A RetryPolicy is safe for concurrent use and can be shared by many clients.
+type RetryPolicy struct {
+	// Maximum number of attempts per call, including the first one
+	MaxAttempts int
+	// Delay before the first retry, doubled at every attempt
+	BaseDelay time.Duration
+	// Upper bound of the delay between attempts
+	MaxDelay time.Duration
+	// Consecutive failures of a method opening its breaker
+	BreakerThreshold int
+	// How long a breaker stays open
+	BreakerCooldown time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+	// replaced in tests
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+type breaker struct {
+	failures  int
+	openUntil time.Time
+	// whether the call probing the service after the cooldown is in flight
+	probing bool
+}
+
+// State of the circuit breaker of an API method
+type BreakerState struct {
+	// Whether calls are currently suspended
+	Open bool
+	// Number of consecutive failures
+	Failures int
+	// When calls will be allowed again, zero if the breaker is closed
+	OpenUntil time.Time
+}
+
+// Create a RetryPolicy with default settings: 4 attempts, delays from 2 seconds up
+// to 1 minute, breakers opening after 5 consecutive failures for 5 minutes
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:      4,
+		BaseDelay:        2 * time.Second,
+		MaxDelay:         time.Minute,
+		BreakerThreshold: 5,
+		BreakerCooldown:  5 * time.Minute,
+	}
+}
+
+// Retry API calls failing because of temporary problems according to policy
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *FlickrClient) {
+		c.RetryPolicy = policy
+	}
+}
+
+func (p *RetryPolicy) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
+// Return the state of the breaker of an API method
+func (p *RetryPolicy) State(method string) BreakerState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b, found := p.breakers[method]
+	if !found {
+		return BreakerState{}
+	}
+	state := BreakerState{Failures: b.failures, OpenUntil: b.openUntil}
+	state.Open = p.clock().Before(b.openUntil) || b.probing
+	return state
+}
+
+// Close the breaker of an API method, forgetting its failures
+func (p *RetryPolicy) Reset(method string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.breakers, method)
+}
+
+// Return an error if the breaker of method is open. Once the cooldown expires the
+// breaker is half open: the first caller is let through to probe the service, the
+// others fail until the probe succeeds, closing the breaker, or fails, opening it
+// again.
+func (p *RetryPolicy) allow(method string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b, found := p.breakers[method]
+	if !found || b.openUntil.IsZero() {
+		return nil
+	}
+	if p.clock().Before(b.openUntil) {
+		msg := fmt.Sprintf("%s failed %d times in a row, retry after %s", method, b.failures, b.openUntil.Format(time.RFC3339))
+		return flickErr.NewError(flickErr.CircuitOpenError, msg)
+	}
+	if b.probing {
+		msg := fmt.Sprintf("%s failed %d times in a row, a call is probing the service", method, b.failures)
+		return flickErr.NewError(flickErr.CircuitOpenError, msg)
+	}
+	b.probing = true
+	return nil
+}
+
+// Record the outcome of an attempt in the breaker of method
+func (p *RetryPolicy) record(method string, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !failed {
+		delete(p.breakers, method)
+		return
+	}
+	if p.breakers == nil {
+		p.breakers = map[string]*breaker{}
+	}
+	b, found := p.breakers[method]
+	if !found {
+		b = &breaker{}
+		p.breakers[method] = b
+	}
+	b.failures++
+	b.probing = false
+	if p.BreakerThreshold > 0 && b.failures >= p.BreakerThreshold {
+		b.openUntil = p.clock().Add(p.BreakerCooldown)
+	}
+}
+
+// Compute the delay before the n-th retry (starting from 0), retryAfter is the
+// delay requested by the server, if any
+func (p *RetryPolicy) delay(retry int, retryAfter time.Duration) time.Duration {
+	d := p.BaseDelay << uint(retry)
+	if retryAfter > d {
+		d = retryAfter
+	}
+	if p.MaxDelay > 0 && (d > p.MaxDelay || d <= 0) {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// Whether an attempt failed because of a temporary problem
+func temporaryFailure(res *http.Response, err error) bool {
+	if e, ok := err.(*flickErr.Error); ok && e.ErrorCode == flickErr.ApiError {
+		if e.ApiCode == ServiceUnavailable || e.ApiCode == WriteOperationFailed {
+			return true
+		}
+	}
+	if res != nil && (res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500) {
+		return true
+	}
+	// the request never reached Flickr
+	if netErr := AsNetError(err); netErr != nil && netErr.Kind == NetConnect {
+		return true
+	}
+	return false
+}
+
+// Parse the Retry-After header, only the delay in seconds form is supported
+func retryAfter(res *http.Response) time.Duration {
+	if res == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Perform a request with attempt, retrying temporary failures with the requests
+// returned by next
+func (p *RetryPolicy) run(method string, req *http.Request, attempt func(*http.Request) (*http.Response, error), next func() (*http.Request, error)) error {
+	if err := p.allow(method); err != nil {
+		return err
+	}
+
+	ctx := req.Context()
+	for retry := 0; ; retry++ {
+		res, err := attempt(req)
+		failed := temporaryFailure(res, err)
+		p.record(method, failed)
+		if !failed || retry+1 >= p.MaxAttempts || req.GetBody == nil && req.Body != nil {
+			return err
+		}
+		if err := p.allow(method); err != nil {
+			return err
+		}
+
+		wait := p.delay(retry, retryAfter(res))
+		if p.sleep != nil {
+			p.sleep(wait)
+		} else {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if req, err = next(); err != nil {
+			return err
+		}
+		req = req.WithContext(ctx)
+	}
+}
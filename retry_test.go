@@ -0,0 +1,194 @@
+package flickr
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Mock the Flickr API failing with the given responses before succeeding
+func flakyMock(failures []func(w http.ResponseWriter)) (*httptest.Server, *http.Client, *[]string) {
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) <= len(failures) {
+			failures[len(bodies)-1](w)
+			return
+		}
+		fmt.Fprintln(w, `<rsp stat="ok"></rsp>`)
+	}))
+	u, _ := url.Parse(server.URL)
+	return server, &http.Client{Transport: RewriteTransport{URL: u}}, &bodies
+}
+
+func flickrError(code int) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		fmt.Fprintf(w, `<rsp stat="fail"><err code="%d" msg="Service currently unavailable" /></rsp>`, code)
+	}
+}
+
+func testPolicy(delays *[]time.Duration) *RetryPolicy {
+	p := NewRetryPolicy()
+	p.sleep = func(d time.Duration) { *delays = append(*delays, d) }
+	return p
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := NewRetryPolicy()
+	Expect(t, p.delay(0, 0), 2*time.Second)
+	Expect(t, p.delay(2, 0), 8*time.Second)
+	Expect(t, p.delay(0, 10*time.Second), 10*time.Second)
+	Expect(t, p.delay(10, 0), time.Minute)
+	Expect(t, p.delay(0, time.Hour), time.Minute)
+}
+
+func TestRetryPolicyRetries(t *testing.T) {
+	tooMany := func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	server, client, bodies := flakyMock([]func(http.ResponseWriter){flickrError(105), tooMany, flickrError(106)})
+	defer server.Close()
+
+	delays := []time.Duration{}
+	fclient := GetTestClient()
+	fclient.HTTPClient = client
+	WithRetryPolicy(testPolicy(&delays))(fclient)
+	fclient.Args.Set("method", "flickr.photos.setMeta")
+
+	err := DoPostBody(fclient, bytes.NewBufferString("payload"), "text/plain", &FooResponse{})
+	Expect(t, err, nil)
+	Expect(t, len(*bodies), 4)
+	// the body is sent again at every attempt
+	Expect(t, (*bodies)[3], "payload")
+	Expect(t, len(delays), 3)
+	Expect(t, delays[0], 2*time.Second)
+	Expect(t, delays[1], 7*time.Second)
+	Expect(t, delays[2], 8*time.Second)
+	Expect(t, fclient.RetryPolicy.State("flickr.photos.setMeta").Failures, 0)
+}
+
+func TestRetryPolicyPermanentError(t *testing.T) {
+	server, client, bodies := flakyMock([]func(http.ResponseWriter){flickrError(1)})
+	defer server.Close()
+
+	delays := []time.Duration{}
+	fclient := GetTestClient()
+	fclient.HTTPClient = client
+	fclient.RetryPolicy = testPolicy(&delays)
+
+	err := DoGet(fclient, &FooResponse{})
+	ee, ok := err.(*flickErr.Error)
+	Expect(t, ok, true)
+	Expect(t, ee.ApiCode, 1)
+	Expect(t, len(*bodies), 1)
+	Expect(t, len(delays), 0)
+}
+
+func TestRetryPolicyBreaker(t *testing.T) {
+	failures := []func(http.ResponseWriter){}
+	for i := 0; i < 10; i++ {
+		failures = append(failures, flickrError(105))
+	}
+	server, client, bodies := flakyMock(failures)
+	defer server.Close()
+
+	now := time.Unix(1000, 0)
+	delays := []time.Duration{}
+	policy := testPolicy(&delays)
+	policy.BreakerThreshold = 3
+	policy.now = func() time.Time { return now }
+
+	fclient := GetTestClient()
+	fclient.HTTPClient = client
+	fclient.RetryPolicy = policy
+	fclient.Args.Set("method", "flickr.photos.getInfo")
+
+	// the breaker opens after the third attempt
+	err := DoGet(fclient, &FooResponse{})
+	ee, ok := err.(*flickErr.Error)
+	Expect(t, ok, true)
+	Expect(t, ee.ErrorCode, flickErr.CircuitOpenError)
+	Expect(t, len(*bodies), 3)
+	state := policy.State("flickr.photos.getInfo")
+	Expect(t, state.Open, true)
+	Expect(t, state.Failures, 3)
+	Expect(t, state.OpenUntil, now.Add(5*time.Minute))
+	// other methods are not affected
+	Expect(t, policy.State("flickr.photos.delete").Open, false)
+
+	// calls fail without hitting the API while the breaker is open
+	err = DoGet(fclient, &FooResponse{})
+	ee, _ = err.(*flickErr.Error)
+	Expect(t, ee.ErrorCode, flickErr.CircuitOpenError)
+	Expect(t, len(*bodies), 3)
+
+	// after the cooldown a call goes through, failing it opens the breaker again
+	now = now.Add(6 * time.Minute)
+	policy.MaxAttempts = 1
+	DoGet(fclient, &FooResponse{})
+	Expect(t, len(*bodies), 4)
+	Expect(t, policy.State("flickr.photos.getInfo").OpenUntil, now.Add(5*time.Minute))
+
+	// a single call probes the service while the breaker is half open
+	now = now.Add(6 * time.Minute)
+	Expect(t, policy.allow("flickr.photos.getInfo"), nil)
+	Expect(t, policy.State("flickr.photos.getInfo").Open, true)
+	ee, _ = policy.allow("flickr.photos.getInfo").(*flickErr.Error)
+	Expect(t, ee.ErrorCode, flickErr.CircuitOpenError)
+	policy.record("flickr.photos.getInfo", false)
+	Expect(t, policy.allow("flickr.photos.getInfo"), nil)
+	Expect(t, policy.State("flickr.photos.getInfo").Open, false)
+
+	policy.Reset("flickr.photos.getInfo")
+	Expect(t, policy.State("flickr.photos.getInfo"), BreakerState{})
+}
+
+func TestRetryPolicyResigns(t *testing.T) {
+	nonces, keys := []string{}, []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, r.FormValue("oauth_nonce"))
+		keys = append(keys, r.FormValue("api_key"))
+		if len(nonces) == 1 || len(nonces) == 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprintln(w, `<rsp stat="ok"></rsp>`)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	delays := []time.Duration{}
+	fclient := GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+	fclient.RetryPolicy = testPolicy(&delays)
+
+	// OAuth calls get a fresh nonce and a valid signature at every attempt
+	fclient.Init()
+	fclient.HTTPVerb = "POST"
+	fclient.Args.Set("method", "flickr.photos.setMeta")
+	fclient.OAuthSign()
+	Expect(t, DoPost(fclient, &FooResponse{}), nil)
+	Expect(t, len(nonces), 2)
+	Expect(t, nonces[0] != nonces[1], true)
+	signature := fclient.Args.Get("oauth_signature")
+	fclient.Sign(fclient.OAuthTokenSecret)
+	Expect(t, fclient.Args.Get("oauth_signature"), signature)
+
+	// calls signed with the api key switch key when the KeyRing puts one aside
+	WithKeyRing(NewKeyRing(ApiKey{"k1", "s1"}, ApiKey{"k2", "s2"}))(fclient)
+	fclient.Init()
+	fclient.Args.Set("method", "flickr.photos.getInfo")
+	fclient.ApiSign()
+	Expect(t, DoGet(fclient, &FooResponse{}), nil)
+	Expect(t, keys[2], "k1")
+	Expect(t, keys[3], "k2")
+}
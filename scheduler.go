@@ -0,0 +1,209 @@
+package flickr
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Counters of a Scheduler, for all the calls or for those of one subsystem
+type SchedulerStats struct {
+	// Calls currently waiting for their turn
+	Queued int
+	// Calls let through
+	Calls int
+	// Calls that had to wait for their turn
+	Waited int
+	// Calls whose context was done while they were waiting
+	Cancelled int
+	// Time spent waiting by the calls let through
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// Return the average time the calls let through waited
+func (s SchedulerStats) AverageWait() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalWait / time.Duration(s.Calls)
+}
+
+// Scheduler paces the HTTP requests of every client sharing it, so that the calls
+// of the subsystems of a process running concurrently, e.g. an uploader, a group
+// poster and a batch of metadata edits, stay within the same hourly rate as a whole.
+// Calls are let through in the order they arrive, once spaced by 1/Rate of an hour,
+// and held back while the Quota tracker estimates the hourly quota is exhausted.
+// Retries are paced like any other request.
+// Calls are accounted to the Subsystem of their client in the metrics, see
+// FlickrClient.ForSubsystem.
+// A Scheduler is safe for concurrent use.
+type Scheduler struct {
+	// Calls allowed per hour, DefaultHourlyQuota when zero
+	Rate int
+	// Calls let through back to back after an idle period, 1 when zero
+	Burst int
+	// Optional tracker holding calls back once it estimates the quota is exhausted,
+	// it should be the QuotaTracker of the clients
+	Quota *QuotaTracker
+
+	mu sync.Mutex
+	// theoretical arrival time of the next call
+	next       time.Time
+	total      SchedulerStats
+	subsystems map[string]*SchedulerStats
+}
+
+// Create a scheduler spreading the default quota over the hour and holding calls
+// back when quota says it's exhausted, quota can be nil
+func NewScheduler(quota *QuotaTracker) *Scheduler {
+	return &Scheduler{Rate: DefaultHourlyQuota, Quota: quota}
+}
+
+// Pace the requests of the client with scheduler, share it among all the clients
+// of the process
+func WithScheduler(scheduler *Scheduler) ClientOption {
+	return func(c *FlickrClient) {
+		c.Scheduler = scheduler
+	}
+}
+
+// Return a copy of the client whose calls are accounted to subsystem in the
+// metrics of its Scheduler, e.g. "uploads" or "groups"
+func (c *FlickrClient) ForSubsystem(subsystem string) *FlickrClient {
+	ret := c.Clone()
+	ret.Subsystem = subsystem
+	return ret
+}
+
+// Return the interval between two calls and the burst. Must be called with mu held.
+func (s *Scheduler) pace() (time.Duration, int) {
+	rate := s.Rate
+	if rate <= 0 {
+		rate = DefaultHourlyQuota
+	}
+	burst := s.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	return time.Hour / time.Duration(rate), burst
+}
+
+// Return the counters of a subsystem. Must be called with mu held.
+func (s *Scheduler) statsOf(subsystem string) *SchedulerStats {
+	if s.subsystems == nil {
+		s.subsystems = map[string]*SchedulerStats{}
+	}
+	st, found := s.subsystems[subsystem]
+	if !found {
+		st = &SchedulerStats{}
+		s.subsystems[subsystem] = st
+	}
+	return st
+}
+
+// Sleep for d, returns early with the error of ctx once it's done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Wait until a call of subsystem may be performed, returns the error of ctx if it's
+// done meanwhile. The clients having the scheduler call it before every request.
+func (s *Scheduler) Wait(ctx context.Context, subsystem string) error {
+	start := time.Now()
+	s.mu.Lock()
+	s.total.Queued++
+	s.statsOf(subsystem).Queued++
+	s.mu.Unlock()
+
+	waited, err := s.wait(ctx)
+
+	elapsed := time.Since(start)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range []*SchedulerStats{&s.total, s.statsOf(subsystem)} {
+		st.Queued--
+		if err != nil {
+			st.Cancelled++
+			continue
+		}
+		st.Calls++
+		if waited {
+			st.Waited++
+			st.TotalWait += elapsed
+			if elapsed > st.MaxWait {
+				st.MaxWait = elapsed
+			}
+		}
+	}
+	return err
+}
+
+// Wait for the quota, then for a slot, returns whether the call had to wait
+func (s *Scheduler) wait(ctx context.Context) (bool, error) {
+	waited := false
+	for s.Quota != nil {
+		d := s.Quota.freeIn()
+		if d <= 0 {
+			break
+		}
+		waited = true
+		if err := sleepContext(ctx, d); err != nil {
+			return waited, err
+		}
+	}
+
+	s.mu.Lock()
+	interval, burst := s.pace()
+	now := time.Now()
+	slot := s.next
+	if slot.Before(now) {
+		slot = now
+	}
+	at := slot.Add(-time.Duration(burst-1) * interval)
+	s.next = slot.Add(interval)
+	s.mu.Unlock()
+
+	if !at.After(now) {
+		return waited, ctx.Err()
+	}
+	if err := sleepContext(ctx, at.Sub(now)); err != nil {
+		s.mu.Lock()
+		// give the slot back unless a later call reserved the next one already
+		if s.next.Equal(slot.Add(interval)) {
+			s.next = slot
+		}
+		s.mu.Unlock()
+		return true, err
+	}
+	return true, nil
+}
+
+// Return the counters of all the calls
+func (s *Scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+// Return the counters of every subsystem, calls of clients without a subsystem are
+// accounted to ""
+func (s *Scheduler) SubsystemStats() map[string]SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := map[string]SchedulerStats{}
+	for name, st := range s.subsystems {
+		ret[name] = *st
+	}
+	return ret
+}
@@ -0,0 +1,87 @@
+package flickr
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	// one call every 10ms
+	s := &Scheduler{Rate: 360000, Burst: 2}
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		subsystem := "uploads"
+		if i%2 == 0 {
+			subsystem = "groups"
+		}
+		go func() {
+			defer wg.Done()
+			Expect(t, s.Wait(context.Background(), subsystem), nil)
+		}()
+	}
+	wg.Wait()
+	// the burst goes through right away, the others are spaced
+	Expect(t, time.Since(start) >= 35*time.Millisecond, true)
+
+	stats := s.Stats()
+	Expect(t, stats.Calls, 6)
+	Expect(t, stats.Queued, 0)
+	Expect(t, stats.Waited > 0, true)
+	Expect(t, stats.MaxWait > 0, true)
+	Expect(t, stats.AverageWait() > 0, true)
+	subsystems := s.SubsystemStats()
+	Expect(t, subsystems["groups"].Calls, 3)
+	Expect(t, subsystems["uploads"].Calls, 3)
+
+	// a cancelled call gives its slot back
+	s = &Scheduler{Rate: 36}
+	Expect(t, s.Wait(context.Background(), ""), nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	Expect(t, s.Wait(ctx, ""), context.DeadlineExceeded)
+	Expect(t, s.Stats().Cancelled, 1)
+	Expect(t, s.next.Sub(time.Now()) < 100*time.Second, true)
+}
+
+func TestSchedulerQuota(t *testing.T) {
+	now := time.Now()
+	q := NewQuotaTracker()
+	q.Limit = 2
+	q.now = func() time.Time { return now }
+	q.record()
+	q.record()
+
+	s := NewScheduler(q)
+	s.Rate = 360000
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	Expect(t, s.Wait(ctx, "batch"), context.DeadlineExceeded)
+
+	now = now.Add(time.Hour)
+	Expect(t, s.Wait(context.Background(), "batch"), nil)
+	Expect(t, s.SubsystemStats()["batch"].Cancelled, 1)
+	Expect(t, s.SubsystemStats()["batch"].Calls, 1)
+}
+
+func TestWithScheduler(t *testing.T) {
+	server, client := FlickrMock(200, `<rsp stat="ok"></rsp>`, "")
+	defer server.Close()
+
+	s := &Scheduler{Rate: 360000}
+	fclient := NewFlickrClient("key", "secret", WithScheduler(s))
+	fclient.HTTPClient = client
+	uploads := fclient.ForSubsystem("uploads")
+	Expect(t, fclient.Subsystem, "")
+
+	fclient.Args.Set("method", "flickr.test.echo")
+	Expect(t, DoGet(fclient, &BasicResponse{}), nil)
+	uploads.Args.Set("method", "flickr.test.echo")
+	Expect(t, DoGet(uploads, &BasicResponse{}), nil)
+	Expect(t, s.Stats().Calls, 2)
+	Expect(t, s.SubsystemStats()["uploads"].Calls, 1)
+	Expect(t, s.SubsystemStats()[""].Calls, 1)
+}
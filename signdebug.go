@@ -0,0 +1,44 @@
+package flickr
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Serialize the dumps of clones sharing the same writer
+var signatureDebugMu sync.Mutex
+
+// Write to w, for every request signed by the client, the exact base string, the
+// shape of the signing key (lengths of the secrets only, never their values), the
+// resulting signature and the time spent computing it. Compare the dump with the
+// output of Flickr's OAuth test tool to diagnose "invalid signature" errors (codes
+// 95 and 96). Base strings contain the request params, including the access token,
+// so don't enable it in production logs.
+func WithSignatureDebug(w io.Writer) ClientOption {
+	return func(c *FlickrClient) {
+		c.SignatureDebug = w
+	}
+}
+
+// Write a signature record to the client SignatureDebug writer, if any. Write errors
+// are ignored, debugging must not break requests.
+func (c *FlickrClient) dumpSignature(kind, baseString, key, signature string, elapsed time.Duration) {
+	if c.SignatureDebug == nil {
+		return
+	}
+	method := c.Args.Get("method")
+	if method == "" {
+		method = c.EndpointUrl
+	}
+	signatureDebugMu.Lock()
+	defer signatureDebugMu.Unlock()
+	fmt.Fprintf(c.SignatureDebug, "--- %s signature of %s %s\nbase string: %s\nsigning key: %s\nsignature: %s\nelapsed: %s\n",
+		kind, c.HTTPVerb, method, baseString, key, signature, elapsed)
+}
+
+// Describe a secret by its length
+func secretShape(name, secret string) string {
+	return fmt.Sprintf("<%s, %d chars>", name, len(secret))
+}
@@ -0,0 +1,115 @@
+package flickr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Number of bytes SniffMediaFormat looks at
+const sniffLen = 512
+
+// Brands of ISO base media files (major brand of the "ftyp" box) that are not mp4
+var ftypBrands = map[string]string{
+	"qt  ": "mov",
+	"M4V ": "m4v",
+	"M4VH": "m4v",
+	"M4VP": "m4v",
+	"heic": "heic",
+	"heix": "heic",
+	"heim": "heic",
+	"heis": "heic",
+	"hevc": "heic",
+	"mif1": "heic",
+	"msf1": "heic",
+}
+
+// Return the format string of the file starting with head, empty if unknown
+func sniffFormat(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\xFF\xD8\xFF")):
+		return "jpg"
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1A\n")):
+		return "png"
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		return "gif"
+	case bytes.HasPrefix(head, []byte("II*\x00")), bytes.HasPrefix(head, []byte("MM\x00*")):
+		return "tiff"
+	case len(head) >= 12 && bytes.HasPrefix(head, []byte("RIFF")) && string(head[8:12]) == "WEBP":
+		return "webp"
+	case len(head) >= 12 && bytes.HasPrefix(head, []byte("RIFF")) && string(head[8:12]) == "AVI ":
+		return "avi"
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		brand := string(head[8:12])
+		if format, found := ftypBrands[brand]; found {
+			return format
+		}
+		if brand[:3] == "3gp" || brand[:3] == "3g2" {
+			return "3gp"
+		}
+		return "mp4"
+	case bytes.HasPrefix(head, []byte("\x30\x26\xB2\x75\x8E\x66\xCF\x11")):
+		return "wmv"
+	case bytes.HasPrefix(head, []byte("\x00\x00\x01\xBA")), bytes.HasPrefix(head, []byte("\x00\x00\x01\xB3")):
+		return "mpg"
+	case bytes.HasPrefix(head, []byte("OggS")):
+		return "ogv"
+	// MPEG transport streams: 188 bytes packets starting with a sync byte, prefixed
+	// with a 4 bytes timecode in M2TS files
+	case len(head) > 188 && head[0] == 0x47 && head[188] == 0x47:
+		return "mts"
+	case len(head) > 196 && head[4] == 0x47 && head[196] == 0x47:
+		return "m2ts"
+	}
+	return ""
+}
+
+// Detect the format of a media file from its first bytes, at least 512 bytes should
+// be given when available. Only the formats Flickr accepts are recognized: JPEG,
+// PNG, GIF, TIFF, WebP, HEIC and the common video containers, see GetMediaFormat.
+// Returns false for anything else.
+func SniffMediaFormat(head []byte) (MediaFormat, bool) {
+	format := sniffFormat(head)
+	if format == "" {
+		return MediaFormat{}, false
+	}
+	return GetMediaFormat(format)
+}
+
+// Read the first bytes of r to detect its format with SniffMediaFormat. Returns a
+// reader yielding the whole content of r, sniffed bytes included. Files in a
+// format Flickr doesn't accept get a flickErr.Error with code UnsupportedMediaError.
+func SniffMedia(r io.Reader) (MediaFormat, io.Reader, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return MediaFormat{}, nil, err
+	}
+	head = head[:n]
+	replay := io.MultiReader(bytes.NewReader(head), r)
+
+	format, ok := SniffMediaFormat(head)
+	if !ok {
+		return MediaFormat{}, replay, flickErr.NewError(flickErr.UnsupportedMediaError, describeHead(head))
+	}
+	return format, replay, nil
+}
+
+// Describe unrecognized content for error messages
+func describeHead(head []byte) string {
+	if len(head) == 0 {
+		return "empty file"
+	}
+	if len(head) > 8 {
+		head = head[:8]
+	}
+	return fmt.Sprintf("unknown content starting with % x", head)
+}
+
+// Return whether err reports a file in a format Flickr doesn't accept
+func IsUnsupportedMedia(err error) bool {
+	e, ok := err.(*flickErr.Error)
+	return ok && e.ErrorCode == flickErr.UnsupportedMediaError
+}
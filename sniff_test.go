@@ -0,0 +1,71 @@
+package flickr
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestSniffMediaFormat(t *testing.T) {
+	ts := make([]byte, 200)
+	ts[0], ts[188] = 0x47, 0x47
+	cases := map[string]string{
+		"\xFF\xD8\xFF\xE0\x00\x10JFIF":             "image/jpeg",
+		"\x89PNG\r\n\x1A\n\x00\x00\x00\x0DIHDR":    "image/png",
+		"GIF89a\x01\x00":                           "image/gif",
+		"MM\x00*\x00\x00\x00\x08":                  "image/tiff",
+		"RIFF\x24\x00\x00\x00WEBPVP8 ":             "image/webp",
+		"\x00\x00\x00\x18ftypheic\x00\x00\x00\x00": "image/heic",
+		"\x00\x00\x00\x14ftypqt  \x00\x00\x00\x00": "video/quicktime",
+		"\x00\x00\x00\x18ftypisom\x00\x00\x02\x00": "video/mp4",
+		"\x00\x00\x00\x14ftyp3gp4\x00\x00\x00\x00": "video/3gpp",
+		"RIFF\x24\x00\x00\x00AVI LIST":             "video/x-msvideo",
+		"\x30\x26\xB2\x75\x8E\x66\xCF\x11\xA6\xD9": "video/x-ms-wmv",
+		"\x00\x00\x01\xBA\x44\x00":                 "video/mpeg",
+		string(ts):                                 "video/mp2t",
+	}
+	for head, mimeType := range cases {
+		f, ok := SniffMediaFormat([]byte(head))
+		Expect(t, ok, true)
+		Expect(t, f.MimeType, mimeType)
+	}
+
+	for _, head := range []string{"", "%PDF-1.4", "<html>", "RIFF\x24\x00\x00\x00WAVEfmt "} {
+		_, ok := SniffMediaFormat([]byte(head))
+		Expect(t, ok, false)
+	}
+}
+
+func TestSniffMedia(t *testing.T) {
+	content := "\xFF\xD8\xFF\xE0" + strings.Repeat("x", 1000)
+	f, r, err := SniffMedia(strings.NewReader(content))
+	Expect(t, err, nil)
+	Expect(t, f.Extension, ".jpg")
+	data, _ := ioutil.ReadAll(r)
+	Expect(t, string(data), content)
+
+	_, r, err = SniffMedia(strings.NewReader("%PDF-1.4 document"))
+	Expect(t, IsUnsupportedMedia(err), true)
+	Expect(t, strings.HasSuffix(err.Error(), "unknown content starting with 25 50 44 46 2d 31 2e 34"), true)
+	data, _ = ioutil.ReadAll(r)
+	Expect(t, string(data), "%PDF-1.4 document")
+}
+
+func TestUploadCheckMedia(t *testing.T) {
+	fclient := GetTestClient()
+	server, client, calls := FlickrMockRecorder(200, map[string]string{
+		"upload": `<rsp stat="ok"><photoid>1234</photoid></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	params := &UploadParams{CheckMedia: true}
+	_, err := UploadReader(fclient, bytes.NewBufferString("<html>not a photo</html>"), "a.jpg", params)
+	Expect(t, IsUnsupportedMedia(err), true)
+	Expect(t, len(calls.Methods()), 0)
+
+	resp, err := UploadReader(fclient, bytes.NewBufferString("GIF89a tiny"), "a.gif", params)
+	Expect(t, err, nil)
+	Expect(t, resp.ID, "1234")
+}
@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// Views from a domain over the period of a ReferrerReport
+type DomainTotal struct {
+	Name  string `xml:"name"`
+	Views int    `xml:"views"`
+}
+
+// Views from a page over the period of a ReferrerReport
+type ReferrerTotal struct {
+	Domain      string `xml:"domain"`
+	Url         string `xml:"url"`
+	SearchTerms string `xml:"searchterms"`
+	Views       int    `xml:"views"`
+}
+
+// Orders of a ReferrerReport
+type ReportOrder int
+
+const (
+	// Most views first, ties broken by name
+	ByViews ReportOrder = iota
+	// Alphabetical order of the domains, then of the referrer URLs
+	ByName
+)
+
+// Views of a scope rolled up by domain and referrer over a range of days
+type ReferrerReport struct {
+	Scope Scope
+	// First and last day of the report
+	From time.Time
+	To   time.Time
+	// Total views over the period
+	Views     int
+	Domains   []DomainTotal
+	Referrers []ReferrerTotal
+}
+
+// Sort the domains and the referrers of the report
+func (r *ReferrerReport) Sort(order ReportOrder) {
+	sort.SliceStable(r.Domains, func(i, j int) bool {
+		a, b := r.Domains[i], r.Domains[j]
+		if order == ByViews && a.Views != b.Views {
+			return a.Views > b.Views
+		}
+		return a.Name < b.Name
+	})
+	sort.SliceStable(r.Referrers, func(i, j int) bool {
+		a, b := r.Referrers[i], r.Referrers[j]
+		if order == ByViews && a.Views != b.Views {
+			return a.Views > b.Views
+		}
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		if a.Url != b.Url {
+			return a.Url < b.Url
+		}
+		return a.SearchTerms < b.SearchTerms
+	})
+}
+
+// Write the referrers of the report as CSV, see flickr.WriteCSV
+func (r *ReferrerReport) WriteCSV(w io.Writer) error {
+	return flickr.WriteCSV(w, r.Referrers)
+}
+
+// Views of a referrer, referrers with the same URL but different search terms are
+// told apart
+type referrerKey struct {
+	domain, url, searchTerms string
+}
+
+// Roll up the referrers of a scope between two days, both included, into a report
+// sorted ByViews. Every page of domains and referrers of every day is fetched, so
+// keep the range short: Flickr only keeps daily stats for the last 28 days.
+// This method requires authentication with 'read' permission.
+func GetReferrerReport(client *flickr.FlickrClient, scope Scope, from, to time.Time) (*ReferrerReport, error) {
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour)
+	report := &ReferrerReport{Scope: scope, From: from, To: to}
+
+	domains := map[string]int{}
+	referrers := map[referrerKey]int{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for page := 1; ; page++ {
+			resp, err := GetDomains(client, scope, day, page, MaxStatsPerPage)
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range resp.Domains.Items {
+				domains[d.Name] += d.Views
+				report.Views += d.Views
+				if err := addReferrers(client, scope, day, d.Name, referrers); err != nil {
+					return nil, err
+				}
+			}
+			if page >= resp.Domains.Pages {
+				break
+			}
+		}
+	}
+
+	report.Domains = make([]DomainTotal, 0, len(domains))
+	for name, views := range domains {
+		report.Domains = append(report.Domains, DomainTotal{Name: name, Views: views})
+	}
+	report.Referrers = make([]ReferrerTotal, 0, len(referrers))
+	for k, views := range referrers {
+		report.Referrers = append(report.Referrers, ReferrerTotal{Domain: k.domain, Url: k.url, SearchTerms: k.searchTerms, Views: views})
+	}
+	report.Sort(ByViews)
+	return report, nil
+}
+
+// Add the views of every referrer of a domain on a day to totals
+func addReferrers(client *flickr.FlickrClient, scope Scope, day time.Time, domain string, totals map[referrerKey]int) error {
+	for page := 1; ; page++ {
+		resp, err := GetReferrers(client, scope, day, domain, page, MaxStatsPerPage)
+		if err != nil {
+			return err
+		}
+		for _, r := range resp.Domain.Items {
+			totals[referrerKey{domain, r.Url, r.SearchTerms}] += r.Views
+		}
+		if page >= resp.Domain.Pages {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestGetReferrerReport(t *testing.T) {
+	// flickr.com is seen both days, example.com only on the second one and
+	// its referrers span two pages
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		date := r.FormValue("date")
+		switch r.FormValue("method") {
+		case "flickr.stats.getPhotosetDomains":
+			domains := `<domain name="flickr.com" views="10" />`
+			if date == "2026-10-02" {
+				domains += `<domain name="example.com" views="5" />`
+			}
+			fmt.Fprintf(w, `<rsp stat="ok"><domains page="1" pages="1">%s</domains></rsp>`, domains)
+		case "flickr.stats.getPhotosetReferrers":
+			switch r.FormValue("domain") + " " + r.FormValue("page") {
+			case "flickr.com ":
+				fmt.Fprintln(w, `<rsp stat="ok"><domain page="1" pages="1"><referrer url="https://flickr.com/a" views="10" /></domain></rsp>`)
+			case "example.com ":
+				fmt.Fprintln(w, `<rsp stat="ok"><domain page="1" pages="2"><referrer url="https://example.com/x" views="1" /></domain></rsp>`)
+			case "example.com 2":
+				fmt.Fprintln(w, `<rsp stat="ok"><domain page="2" pages="2"><referrer url="https://example.com/y" views="4" /></domain></rsp>`)
+			}
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	fclient := flickr.GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: flickr.RewriteTransport{URL: u}}
+
+	from := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	report, err := GetReferrerReport(fclient, Scope{Kind: ScopePhotoset, Id: "72157"}, from, from.AddDate(0, 0, 1))
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, report.From, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	flickr.Expect(t, report.Views, 25)
+	flickr.Expect(t, len(report.Domains), 2)
+	flickr.Expect(t, report.Domains[0], DomainTotal{Name: "flickr.com", Views: 20})
+	flickr.Expect(t, report.Domains[1], DomainTotal{Name: "example.com", Views: 5})
+	flickr.Expect(t, len(report.Referrers), 3)
+	flickr.Expect(t, report.Referrers[0].Url, "https://flickr.com/a")
+	flickr.Expect(t, report.Referrers[0].Views, 20)
+	flickr.Expect(t, report.Referrers[1].Url, "https://example.com/y")
+
+	report.Sort(ByName)
+	flickr.Expect(t, report.Domains[0].Name, "example.com")
+	flickr.Expect(t, report.Referrers[0].Url, "https://example.com/x")
+
+	buf := &bytes.Buffer{}
+	flickr.Expect(t, report.WriteCSV(buf), nil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	flickr.Expect(t, len(lines), 4)
+	flickr.Expect(t, lines[0], "domain,url,searchterms,views")
+	flickr.Expect(t, lines[1], "example.com,https://example.com/x,,1")
+}
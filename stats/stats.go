@@ -0,0 +1,195 @@
+// Package implementing methods: flickr.stats.*
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Maximum number of domains or referrers per page of the stats methods
+const MaxStatsPerPage = 100
+
+// Kinds of Scope
+const (
+	ScopePhoto       = "photo"
+	ScopePhotoset    = "photoset"
+	ScopeCollection  = "collection"
+	ScopePhotostream = "photostream"
+)
+
+// What stats are about: a photo, photoset or collection of the calling user, all of
+// them when Id is empty, or the whole photostream
+type Scope struct {
+	// ScopePhoto, ScopePhotoset, ScopeCollection or ScopePhotostream
+	Kind string
+	// Not used for ScopePhotostream
+	Id string
+}
+
+// A domain photos were viewed from
+type Domain struct {
+	Name  string `xml:"name,attr"`
+	Views int    `xml:"views,attr"`
+}
+
+// A page photos were viewed from
+type Referrer struct {
+	Url string `xml:"url,attr"`
+	// Terms searched for when the referrer is a search engine
+	SearchTerms string `xml:"searchterms,attr"`
+	Views       int    `xml:"views,attr"`
+}
+
+type DomainsResponse struct {
+	flickr.BasicResponse
+	Domains struct {
+		Page    int      `xml:"page,attr"`
+		Pages   int      `xml:"pages,attr"`
+		PerPage int      `xml:"perpage,attr"`
+		Total   int      `xml:"total,attr"`
+		Items   []Domain `xml:"domain"`
+	} `xml:"domains"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r DomainsResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+type ReferrersResponse struct {
+	flickr.BasicResponse
+	Domain struct {
+		Name    string     `xml:"name,attr"`
+		Page    int        `xml:"page,attr"`
+		Pages   int        `xml:"pages,attr"`
+		PerPage int        `xml:"perpage,attr"`
+		Total   int        `xml:"total,attr"`
+		Items   []Referrer `xml:"referrer"`
+	} `xml:"domain"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r ReferrersResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Name of the scopes in the stats methods, e.g. flickr.stats.getPhotosetDomains
+var scopeMethods = map[string]string{
+	ScopePhoto:       "Photo",
+	ScopePhotoset:    "Photoset",
+	ScopeCollection:  "Collection",
+	ScopePhotostream: "Photostream",
+}
+
+// Set the method and the params shared by the domains and referrers methods,
+// suffix is either "Domains" or "Referrers"
+func setStatsArgs(client *flickr.FlickrClient, suffix string, scope Scope, date time.Time, page, perPage int) error {
+	name, found := scopeMethods[scope.Kind]
+	if !found {
+		return flickErr.NewError(flickErr.InvalidParamsError, fmt.Sprintf("unknown stats scope %q", scope.Kind))
+	}
+	if perPage < 0 || perPage > MaxStatsPerPage {
+		return flickErr.NewError(flickErr.InvalidParamsError,
+			fmt.Sprintf("per_page must be between 0 (Flickr default) and %d, got %d", MaxStatsPerPage, perPage))
+	}
+	client.Args.Set("method", "flickr.stats.get"+name+suffix)
+	// stats are daily, Flickr takes the day as YYYY-MM-DD in UTC
+	client.Args.Set("date", date.UTC().Format("2006-01-02"))
+	if scope.Kind != ScopePhotostream && scope.Id != "" {
+		client.Args.Set(scope.Kind+"_id", scope.Id)
+	}
+	if page > 1 {
+		client.SetInt("page", page)
+	}
+	if perPage > 0 {
+		client.SetInt("per_page", perPage)
+	}
+	return nil
+}
+
+// Return the domains the photos of a scope were viewed from on a given day
+// This method requires authentication with 'read' permission.
+func GetDomains(client *flickr.FlickrClient, scope Scope, date time.Time, page, perPage int) (*DomainsResponse, error) {
+	client.Init()
+	if err := setStatsArgs(client, "Domains", scope, date, page, perPage); err != nil {
+		return nil, err
+	}
+	client.OAuthSign()
+
+	response := &DomainsResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Return the pages of a domain the photos of a scope were viewed from on a given day
+// This method requires authentication with 'read' permission.
+func GetReferrers(client *flickr.FlickrClient, scope Scope, date time.Time, domain string, page, perPage int) (*ReferrersResponse, error) {
+	client.Init()
+	if err := setStatsArgs(client, "Referrers", scope, date, page, perPage); err != nil {
+		return nil, err
+	}
+	client.Args.Set("domain", domain)
+	client.OAuthSign()
+
+	response := &ReferrersResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Return the domains a photo was viewed from on a given day, all the photos of the
+// calling user when photoId is empty
+// This method requires authentication with 'read' permission.
+func GetPhotoDomains(client *flickr.FlickrClient, date time.Time, photoId string, page, perPage int) (*DomainsResponse, error) {
+	return GetDomains(client, Scope{Kind: ScopePhoto, Id: photoId}, date, page, perPage)
+}
+
+// Return the domains a photoset was viewed from on a given day, all the photosets of
+// the calling user when photosetId is empty
+// This method requires authentication with 'read' permission.
+func GetPhotosetDomains(client *flickr.FlickrClient, date time.Time, photosetId string, page, perPage int) (*DomainsResponse, error) {
+	return GetDomains(client, Scope{Kind: ScopePhotoset, Id: photosetId}, date, page, perPage)
+}
+
+// Return the domains a collection was viewed from on a given day, all the
+// collections of the calling user when collectionId is empty
+// This method requires authentication with 'read' permission.
+func GetCollectionDomains(client *flickr.FlickrClient, date time.Time, collectionId string, page, perPage int) (*DomainsResponse, error) {
+	return GetDomains(client, Scope{Kind: ScopeCollection, Id: collectionId}, date, page, perPage)
+}
+
+// Return the domains the photostream of the calling user was viewed from on a given day
+// This method requires authentication with 'read' permission.
+func GetPhotostreamDomains(client *flickr.FlickrClient, date time.Time, page, perPage int) (*DomainsResponse, error) {
+	return GetDomains(client, Scope{Kind: ScopePhotostream}, date, page, perPage)
+}
+
+// Return the pages of a domain a photo was viewed from on a given day, all the
+// photos of the calling user when photoId is empty
+// This method requires authentication with 'read' permission.
+func GetPhotoReferrers(client *flickr.FlickrClient, date time.Time, domain, photoId string, page, perPage int) (*ReferrersResponse, error) {
+	return GetReferrers(client, Scope{Kind: ScopePhoto, Id: photoId}, date, domain, page, perPage)
+}
+
+// Return the pages of a domain a photoset was viewed from on a given day, all the
+// photosets of the calling user when photosetId is empty
+// This method requires authentication with 'read' permission.
+func GetPhotosetReferrers(client *flickr.FlickrClient, date time.Time, domain, photosetId string, page, perPage int) (*ReferrersResponse, error) {
+	return GetReferrers(client, Scope{Kind: ScopePhotoset, Id: photosetId}, date, domain, page, perPage)
+}
+
+// Return the pages of a domain a collection was viewed from on a given day, all the
+// collections of the calling user when collectionId is empty
+// This method requires authentication with 'read' permission.
+func GetCollectionReferrers(client *flickr.FlickrClient, date time.Time, domain, collectionId string, page, perPage int) (*ReferrersResponse, error) {
+	return GetReferrers(client, Scope{Kind: ScopeCollection, Id: collectionId}, date, domain, page, perPage)
+}
+
+// Return the pages of a domain the photostream of the calling user was viewed from
+// on a given day
+// This method requires authentication with 'read' permission.
+func GetPhotostreamReferrers(client *flickr.FlickrClient, date time.Time, domain string, page, perPage int) (*ReferrersResponse, error) {
+	return GetReferrers(client, Scope{Kind: ScopePhotostream}, date, domain, page, perPage)
+}
@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+const (
+	photoDomains = `<rsp stat="ok"><domains page="1" perpage="25" pages="1" total="2">
+  <domain name="images.search.yahoo.com" views="127" />
+  <domain name="flickr.com" views="122" />
+</domains></rsp>`
+	photoReferrers = `<rsp stat="ok"><domain page="1" perpage="25" pages="1" total="2" name="images.search.yahoo.com">
+  <referrer url="http://images.search.yahoo.com/search/images" searchterms="flickr api" views="127" />
+  <referrer url="http://images.search.yahoo.com/search/images?p=clouds" views="3" />
+</domain></rsp>`
+)
+
+func TestGetDomains(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.stats.getPhotoDomains":       photoDomains,
+		"flickr.stats.getPhotostreamDomains": photoDomains,
+		"flickr.stats.getPhotosetDomains":    photoDomains,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+	date := time.Date(2026, 10, 1, 23, 0, 0, 0, time.FixedZone("CEST", -2*3600))
+
+	resp, err := GetPhotoDomains(fclient, date, "42", 2, 25)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(resp.Domains.Items), 2)
+	flickr.Expect(t, resp.Domains.Items[0].Name, "images.search.yahoo.com")
+	flickr.Expect(t, resp.Domains.Items[1].Views, 122)
+	args := calls.Last("flickr.stats.getPhotoDomains")
+	flickr.Expect(t, args.Get("date"), "2026-10-02")
+	flickr.Expect(t, args.Get("photo_id"), "42")
+	flickr.Expect(t, args.Get("page"), "2")
+	flickr.Expect(t, args.Get("per_page"), "25")
+
+	_, err = GetPhotosetDomains(fclient, date, "", 1, 0)
+	flickr.Expect(t, err, nil)
+	args = calls.Last("flickr.stats.getPhotosetDomains")
+	_, found := args["photoset_id"]
+	flickr.Expect(t, found, false)
+
+	_, err = GetPhotostreamDomains(fclient, date, 1, 0)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.stats.getPhotostreamDomains") != nil, true)
+
+	_, err = GetDomains(fclient, Scope{Kind: "gallery"}, date, 1, 0)
+	ee, ok := err.(*flickErr.Error)
+	flickr.Expect(t, ok, true)
+	flickr.Expect(t, ee.ErrorCode, flickErr.InvalidParamsError)
+
+	_, err = GetPhotoDomains(fclient, date, "", 1, MaxStatsPerPage+1)
+	flickr.Expect(t, err != nil, true)
+}
+
+func TestGetReferrers(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.stats.getCollectionReferrers": photoReferrers,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetCollectionReferrers(fclient, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), "images.search.yahoo.com", "12", 1, 0)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Domain.Name, "images.search.yahoo.com")
+	flickr.Expect(t, len(resp.Domain.Items), 2)
+	flickr.Expect(t, resp.Domain.Items[0].SearchTerms, "flickr api")
+	flickr.Expect(t, resp.Domain.Items[1].Views, 3)
+	args := calls.Last("flickr.stats.getCollectionReferrers")
+	flickr.Expect(t, args.Get("domain"), "images.search.yahoo.com")
+	flickr.Expect(t, args.Get("collection_id"), "12")
+	flickr.Expect(t, args.Get("date"), "2026-10-01")
+}
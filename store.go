@@ -0,0 +1,171 @@
+package flickr
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Storage of the state of the stateful helpers: sync cursors, posting ledgers,
+// library snapshots, OAuth tokens, thumbnails. Values are opaque bytes, usually
+// JSON documents, keys are slash separated paths like "cursors/nightly".
+// Implement it to back every helper with your own database, MemoryStore and
+// FileStore are provided. Implementations must be safe for concurrent use.
+type Store interface {
+	// Return the value stored under key and whether it was found
+	Get(key string) ([]byte, bool, error)
+	// Store value under key, replacing any previous one
+	Put(key string, value []byte) error
+	// Remove key, deleting a missing key is not an error
+	Delete(key string) error
+}
+
+// A Store keeping values in memory
+type MemoryStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: map[string][]byte{}}
+}
+
+// Implement Store
+func (s *MemoryStore) Get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, found := s.values[key]
+	return value, found, nil
+}
+
+// Implement Store, value is copied
+func (s *MemoryStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Implement Store
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+// A Store keeping every value in a file named after its key under Dir, the
+// directories of slash separated keys are created as needed
+type FileStore struct {
+	Dir string
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+// Return the file of a key, keys escaping Dir are refused
+func (s *FileStore) path(key string) (string, error) {
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", invalidParams("invalid store key %q", key)
+		}
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
+
+// Implement Store
+func (s *FileStore) Get(key string) ([]byte, bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Implement Store, the file is written under a temporary name and renamed so that
+// readers never see partial values
+func (s *FileStore) Put(key string, value []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// Implement Store
+func (s *FileStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Decode the JSON value stored under key into v. Returns false, leaving v untouched,
+// if the key is missing.
+func LoadJSON(store Store, key string, v interface{}) (bool, error) {
+	data, found, err := store.Get(key)
+	if err != nil || !found {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+// Store v as JSON under key
+func SaveJSON(store Store, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return store.Put(key, data)
+}
+
+// Return the access token stored under key with SaveOAuthToken, nil if missing
+func LoadOAuthToken(store Store, key string) (*OAuthToken, error) {
+	tok := &OAuthToken{}
+	found, err := LoadJSON(store, key, tok)
+	if err != nil || !found {
+		return nil, err
+	}
+	return tok, nil
+}
+
+// Store an access token under key, so that it can be reused by the next runs
+// instead of authorizing the application again
+func SaveOAuthToken(store Store, key string, tok *OAuthToken) error {
+	return SaveJSON(store, key, tok)
+}
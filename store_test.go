@@ -0,0 +1,62 @@
+package flickr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testStore(t *testing.T, s Store) {
+	_, found, err := s.Get("a/b")
+	Expect(t, err, nil)
+	Expect(t, found, false)
+
+	Expect(t, s.Put("a/b", []byte("one")), nil)
+	Expect(t, s.Put("a/b", []byte("two")), nil)
+	value, found, err := s.Get("a/b")
+	Expect(t, err, nil)
+	Expect(t, found, true)
+	Expect(t, string(value), "two")
+
+	Expect(t, s.Delete("a/b"), nil)
+	Expect(t, s.Delete("a/b"), nil)
+	_, found, _ = s.Get("a/b")
+	Expect(t, found, false)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+	s := NewFileStore(dir)
+	testStore(t, s)
+
+	Expect(t, s.Put("cursors/nightly", []byte("{}")), nil)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "cursors", "nightly"))
+	Expect(t, err, nil)
+	Expect(t, string(data), "{}")
+
+	for _, key := range []string{"", "../x", "a//b", "a/./b"} {
+		if err := s.Put(key, nil); err == nil {
+			t.Errorf("key %q accepted", key)
+		}
+	}
+}
+
+func TestOAuthTokenStore(t *testing.T) {
+	s := NewMemoryStore()
+	tok, err := LoadOAuthToken(s, "token")
+	Expect(t, err, nil)
+	Expect(t, tok == nil, true)
+
+	saved := &OAuthToken{OAuthToken: "tok", OAuthTokenSecret: "secret", UserNsid: "123@N01", Username: "bob"}
+	Expect(t, SaveOAuthToken(s, "token", saved), nil)
+	tok, err = LoadOAuthToken(s, "token")
+	Expect(t, err, nil)
+	Expect(t, *tok, *saved)
+}
@@ -0,0 +1,103 @@
+package flickr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Pagination details of a list response, as found in the attributes of the element
+// wrapping the list items (e.g. <photos page="1" pages="10" perpage="500" total="4990">)
+type ListInfo struct {
+	Page    int
+	Pages   int
+	PerPage int
+	Total   int
+}
+
+// Fill in the pagination details from the attributes of a list element
+func (l *ListInfo) fill(attrs []xml.Attr) {
+	for _, a := range attrs {
+		v, _ := strconv.Atoi(a.Value)
+		switch a.Name.Local {
+		case "page":
+			l.Page = v
+		case "pages":
+			l.Pages = v
+		case "perpage", "per_page":
+			l.PerPage = v
+		case "total":
+			l.Total = v
+		}
+	}
+}
+
+// A function called for every list item found in a streamed response. The item must
+// be consumed with d.DecodeElement(&value, start), returning an error stops the stream
+// and the error is returned to the caller.
+type StreamFunc func(d *xml.Decoder, start *xml.StartElement) error
+
+// Perform a GET request like DoGet but instead of unmarshalling the whole response,
+// decode it incrementally calling fn for every element named item. Use it for methods
+// returning thousands of entries, only one item at a time is kept in memory.
+// Returns the pagination details of the list.
+func DoGetStream(client *FlickrClient, item string, fn StreamFunc) (*ListInfo, error) {
+	var info *ListInfo
+	err := client.getAndParse(func(res *http.Response) error {
+		defer res.Body.Close()
+		var err error
+		info, err = parseApiStream(res.Body, item, fn, client.CharsetReader)
+		return err
+	})
+	return info, err
+}
+
+// Decode a Flickr response token by token, see DoGetStream
+func parseApiStream(body io.Reader, item string, fn StreamFunc, charsetReader CharsetReader) (*ListInfo, error) {
+	reader := bufio.NewReader(body)
+	// In case of OAuth errors Flickr returns raw text instead of a REST response,
+	// see parseApiResponse
+	head, _ := reader.Peek(512)
+	if !bytes.Contains(head, []byte("<rsp")) {
+		text, _ := ioutil.ReadAll(reader)
+		return nil, flickErr.NewError(flickErr.ApiError, string(text))
+	}
+
+	info := &ListInfo{}
+	decoder := newResponseDecoder(reader, charsetReader)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return info, nil
+		}
+		if err != nil {
+			return info, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case item:
+			if err := fn(decoder, &start); err != nil {
+				return info, err
+			}
+		case "err":
+			resp := &BasicResponse{}
+			decoder.DecodeElement(&resp.Error, &start)
+			err := flickErr.NewError(flickErr.ApiError, resp.ErrorMsg())
+			err.ApiCode = resp.ErrorCode()
+			return info, err
+		case "rsp":
+		default:
+			info.fill(start.Attr)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package flickr
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+type streamItem struct {
+	Id string `xml:"id,attr"`
+}
+
+func TestDoGetStream(t *testing.T) {
+	bodyStr := `<?xml version="1.0" encoding="utf-8" ?>
+<rsp stat="ok">
+  <photos page="2" pages="10" perpage="3" total="30">
+    <photo id="1" /><photo id="2" /><photo id="3" />
+  </photos>
+</rsp>`
+
+	fclient := GetTestClient()
+	server, client := FlickrMock(200, bodyStr, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	ids := []string{}
+	info, err := DoGetStream(fclient, "photo", func(d *xml.Decoder, start *xml.StartElement) error {
+		item := streamItem{}
+		if err := d.DecodeElement(&item, start); err != nil {
+			return err
+		}
+		ids = append(ids, item.Id)
+		return nil
+	})
+	Expect(t, err, nil)
+	Expect(t, strings.Join(ids, ","), "1,2,3")
+	Expect(t, *info, ListInfo{Page: 2, Pages: 10, PerPage: 3, Total: 30})
+
+	// stop at the first item
+	stop := errors.New("stop")
+	calls := 0
+	_, err = DoGetStream(fclient, "photo", func(d *xml.Decoder, start *xml.StartElement) error {
+		calls++
+		return stop
+	})
+	Expect(t, err, stop)
+	Expect(t, calls, 1)
+}
+
+func TestDoGetStreamErrors(t *testing.T) {
+	fclient := GetTestClient()
+	server, client := FlickrMock(200, `<rsp stat="fail"><err code="1" msg="User not found" /></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	noop := func(d *xml.Decoder, start *xml.StartElement) error { return nil }
+	_, err := DoGetStream(fclient, "photo", noop)
+	ee, ok := err.(*flickErr.Error)
+	Expect(t, ok, true)
+	Expect(t, ee.ErrorCode, flickErr.ApiError)
+	Expect(t, ee.Message, "Flickr API returned an error: User not found")
+
+	server, client = FlickrMock(200, "oauth_problem=signature_invalid", "")
+	defer server.Close()
+	fclient.HTTPClient = client
+	_, err = DoGetStream(fclient, "photo", noop)
+	ee, ok = err.(*flickErr.Error)
+	Expect(t, ok, true)
+	Expect(t, ee.ErrorCode, flickErr.ApiError)
+}
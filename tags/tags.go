@@ -0,0 +1,120 @@
+// Package implementing methods: flickr.tags.*
+package tags
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A tag along with the number of times it was used
+type Tag struct {
+	Count int    `xml:"count,attr"`
+	Name  string `xml:",chardata"`
+}
+
+type TagsListResponse struct {
+	flickr.BasicResponse
+	Who struct {
+		Id   string `xml:"id,attr"`
+		Tags []Tag  `xml:"tags>tag"`
+	} `xml:"who"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r TagsListResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the tags used most often by the calling user
+// This method requires authentication with 'read' permission.
+func GetMostFrequentlyUsed(client *flickr.FlickrClient) (*TagsListResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.tags.getMostFrequentlyUsed")
+	client.OAuthSign()
+
+	response := &TagsListResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// TagSuggester provides tag completions for upload UIs, ranking the tags used most
+// often by the calling user first. Tags are fetched on the first call and kept for
+// TTL, calls performed within it don't hit the API.
+// A TagSuggester is safe for concurrent use.
+type TagSuggester struct {
+	TTL time.Duration
+
+	client  *flickr.FlickrClient
+	mu      sync.Mutex
+	tags    []Tag
+	fetched time.Time
+	// replaced in tests
+	now func() time.Time
+}
+
+// Create a suggester fetching tags with a clone of client, keeping them for ttl
+func NewTagSuggester(client *flickr.FlickrClient, ttl time.Duration) *TagSuggester {
+	return &TagSuggester{TTL: ttl, client: client.Clone()}
+}
+
+func (s *TagSuggester) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// Return the tags of the user, most used first, fetching them if the cache expired
+func (s *TagSuggester) Tags() ([]Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock()
+	if s.tags != nil && now.Sub(s.fetched) < s.TTL {
+		return s.tags, nil
+	}
+	resp, err := GetMostFrequentlyUsed(s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := append([]Tag{}, resp.Who.Tags...)
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].Count > tags[j].Count
+	})
+	s.tags = tags
+	s.fetched = now
+	return tags, nil
+}
+
+// Return at most limit tags starting with prefix (case insensitive), most used first.
+// A zero limit returns all the matching tags.
+func (s *TagSuggester) Suggest(prefix string, limit int) ([]Tag, error) {
+	tags, err := s.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	ret := []Tag{}
+	for _, t := range tags {
+		if strings.HasPrefix(strings.ToLower(t.Name), prefix) {
+			ret = append(ret, t)
+			if limit > 0 && len(ret) == limit {
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+// Drop the cached tags
+func (s *TagSuggester) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tags = nil
+}
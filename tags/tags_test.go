@@ -0,0 +1,63 @@
+package tags
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+const frequentTags = `<rsp stat="ok"><who id="12037949754@N01"><tags>
+  <tag count="3">beach</tag>
+  <tag count="10">sunset</tag>
+  <tag count="7">Bees</tag>
+  <tag count="5">sun</tag>
+</tags></who></rsp>`
+
+func TestGetMostFrequentlyUsed(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client := flickr.FlickrMock(200, frequentTags, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetMostFrequentlyUsed(fclient)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, resp.Who.Id, "12037949754@N01")
+	flickr.Expect(t, len(resp.Who.Tags), 4)
+	flickr.Expect(t, resp.Who.Tags[1], Tag{Count: 10, Name: "sunset"})
+}
+
+func TestTagSuggester(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.tags.getMostFrequentlyUsed": frequentTags,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	now := time.Unix(1000, 0)
+	s := NewTagSuggester(fclient, time.Hour)
+	s.now = func() time.Time { return now }
+
+	tags, err := s.Suggest("su", 0)
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, len(tags), 2)
+	flickr.Expect(t, tags[0].Name, "sunset")
+	flickr.Expect(t, tags[1].Name, "sun")
+
+	tags, _ = s.Suggest("b", 1)
+	flickr.Expect(t, len(tags), 1)
+	flickr.Expect(t, tags[0].Name, "Bees")
+
+	tags, _ = s.Suggest("", 0)
+	flickr.Expect(t, len(tags), 4)
+	flickr.Expect(t, len(calls.Methods()), 1)
+
+	// tags are fetched again once the TTL expires or the cache is invalidated
+	now = now.Add(2 * time.Hour)
+	s.Suggest("", 0)
+	flickr.Expect(t, len(calls.Methods()), 2)
+	s.Invalidate()
+	s.Suggest("", 0)
+	flickr.Expect(t, len(calls.Methods()), 3)
+}
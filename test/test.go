@@ -17,6 +17,11 @@ type LoginResponse struct {
 	} `xml:"user"`
 }
 
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r LoginResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
 // Response type used by Echo function
 type EchoResponse struct {
 	flickr.BasicResponse
@@ -28,6 +33,11 @@ type EchoResponse struct {
 	Format string `xml:"format"`
 }
 
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r EchoResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
 // A testing method which checks if the caller is logged in then returns their username.
 // This method requires authentication with 'read' permission.
 func Login(client *flickr.FlickrClient) (*LoginResponse, error) {
@@ -10,6 +10,7 @@ import (
 	"path"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -71,6 +72,81 @@ func FlickrMock(code int, body string, contentType string) (*httptest.Server, *h
 	return server, &http.Client{Transport: RewriteTransport{URL: u}}
 }
 
+// Mock the Flickr API returning a different body depending on the "method" param of
+// the request. Requests to the upload and replace endpoints are answered with the
+// bodies stored under the "upload" and "replace" keys, requests without a method
+// (static files) with the body stored under the URL path, any other request gets a
+// failure response.
+func FlickrMockMethods(code int, bodies map[string]string) (*httptest.Server, *http.Client) {
+	server, client, _ := FlickrMockRecorder(code, bodies)
+	return server, client
+}
+
+// Params of the requests received by a mocked Flickr API, in order of arrival
+type MockCalls struct {
+	sync.Mutex
+	Calls []url.Values
+}
+
+// Return the "method" param of every request received, uploads are reported as "upload"
+func (m *MockCalls) Methods() []string {
+	m.Lock()
+	defer m.Unlock()
+	ret := []string{}
+	for _, c := range m.Calls {
+		ret = append(ret, c.Get("method"))
+	}
+	return ret
+}
+
+// Return the params of the last request for the given method, nil if not found
+func (m *MockCalls) Last(method string) url.Values {
+	m.Lock()
+	defer m.Unlock()
+	for i := len(m.Calls) - 1; i >= 0; i-- {
+		if m.Calls[i].Get("method") == method {
+			return m.Calls[i]
+		}
+	}
+	return nil
+}
+
+// Same as FlickrMockMethods, recording the params of every request received
+func FlickrMockRecorder(code int, bodies map[string]string) (*httptest.Server, *http.Client, *MockCalls) {
+	calls := &MockCalls{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		params := url.Values{}
+		for k, v := range r.Form {
+			params[k] = v
+		}
+		if strings.Contains(r.URL.Path, "upload") {
+			params.Set("method", "upload")
+		} else if strings.Contains(r.URL.Path, "services/replace") {
+			params.Set("method", "replace")
+		} else if params.Get("method") == "" {
+			// downloads of static files are keyed by the URL path
+			params.Set("method", r.URL.Path)
+		}
+		calls.Lock()
+		calls.Calls = append(calls.Calls, params)
+		calls.Unlock()
+
+		body, found := bodies[params.Get("method")]
+		if !found {
+			body = `<?xml version="1.0" encoding="utf-8" ?><rsp stat="fail"><err code="112" msg="Method not found" /></rsp>`
+		}
+
+		w.Header().Set("content-type", "text/xml")
+		w.WriteHeader(code)
+		fmt.Fprintln(w, body)
+	}))
+
+	u, _ := url.Parse(server.URL)
+
+	return server, &http.Client{Transport: RewriteTransport{URL: u}}, calls
+}
+
 // A ReaderCloser to fake http.Response Body field
 type FakeBody struct {
 	content *bytes.Buffer
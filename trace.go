@@ -0,0 +1,77 @@
+package flickr
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Request params worth reporting as tracing attributes
+var traceAttributes = []string{"photo_id", "photoset_id", "group_id", "user_id"}
+
+// Details of an API call, passed to TraceHook callbacks
+type CallInfo struct {
+	// Flickr API method, e.g. "flickr.photos.getInfo". Empty for uploads and
+	// OAuth token requests, see Endpoint
+	Method string
+	// GET or POST
+	HTTPVerb string
+	// The URL the request is sent to, without query params
+	Endpoint string
+	// Request params with secrets masked, see RedactValues
+	Args url.Values
+}
+
+// Return the attributes describing the call: the method name along with the IDs
+// of the objects involved (photo_id, photoset_id, group_id, user_id) if present
+func (i *CallInfo) Attributes() map[string]string {
+	ret := map[string]string{"method": i.Method}
+	for _, name := range traceAttributes {
+		if v := i.Args.Get(name); v != "" {
+			ret[name] = v
+		}
+	}
+	return ret
+}
+
+// A pair of callbacks invoked around every request performed by a FlickrClient, used
+// to plug in a tracing system (e.g. OpenTelemetry) without depending on it.
+// Start is called before the request is sent, the context it returns is attached to
+// the HTTP request so that instrumented transports can propagate it. End is called
+// with the same context once the response has been parsed, err is nil on success.
+// Either callback can be nil.
+type TraceHook struct {
+	Start func(ctx context.Context, info *CallInfo) context.Context
+	End   func(ctx context.Context, info *CallInfo, err error)
+}
+
+// Invoke the client TraceHook around the given request
+func WithTraceHook(hook *TraceHook) ClientOption {
+	return func(c *FlickrClient) {
+		c.TraceHook = hook
+	}
+}
+
+// Notify the TraceHook a call is starting, returning the context to attach to the
+// request and the function to call when the call is over
+func (c *FlickrClient) startTrace(ctx context.Context, req *http.Request) (context.Context, func(error)) {
+	hook := c.TraceHook
+	if hook == nil {
+		return ctx, func(error) {}
+	}
+
+	info := &CallInfo{
+		Method:   c.Args.Get("method"),
+		HTTPVerb: req.Method,
+		Endpoint: c.EndpointUrl,
+		Args:     RedactValues(c.Args),
+	}
+	if hook.Start != nil {
+		ctx = hook.Start(ctx, info)
+	}
+	return ctx, func(err error) {
+		if hook.End != nil {
+			hook.End(ctx, info, err)
+		}
+	}
+}
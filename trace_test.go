@@ -0,0 +1,54 @@
+package flickr
+
+import (
+	"context"
+	"testing"
+)
+
+type traceKey struct{}
+
+func TestTraceHook(t *testing.T) {
+	started := []*CallInfo{}
+	var ended error
+	var endCtx context.Context
+	hook := &TraceHook{
+		Start: func(ctx context.Context, info *CallInfo) context.Context {
+			started = append(started, info)
+			return context.WithValue(ctx, traceKey{}, "span")
+		},
+		End: func(ctx context.Context, info *CallInfo, err error) {
+			endCtx = ctx
+			ended = err
+		},
+	}
+
+	fclient := GetTestClient()
+	WithTraceHook(hook)(fclient)
+	server, client := FlickrMock(200, `<rsp stat="fail"><err code="1" msg="Photo not found" /></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	fclient.Args.Set("method", "flickr.photos.getInfo")
+	fclient.Args.Set("photo_id", "123")
+	err := DoGet(fclient, &FooResponse{})
+
+	Expect(t, len(started), 1)
+	Expect(t, started[0].Method, "flickr.photos.getInfo")
+	Expect(t, started[0].HTTPVerb, "GET")
+	attrs := started[0].Attributes()
+	Expect(t, attrs["photo_id"], "123")
+	Expect(t, len(attrs), 2)
+	Expect(t, ended, err)
+	Expect(t, endCtx.Value(traceKey{}), "span")
+}
+
+func TestTraceHookPartial(t *testing.T) {
+	// a nil callback is simply skipped
+	fclient := GetTestClient()
+	fclient.TraceHook = &TraceHook{}
+	server, client := FlickrMock(200, `<rsp stat="ok"></rsp>`, "")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	Expect(t, DoGet(fclient, &FooResponse{}), nil)
+}
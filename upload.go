@@ -1,17 +1,17 @@
 package flickr
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/tls"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
+	"time"
 )
 
 // generate a random multipart boundary string,
@@ -25,26 +25,22 @@ func randomBoundary() string {
 	return fmt.Sprintf("%x", buf[:])
 }
 
-// Encode the file and request parameters in a multipart body.
-// File contents are streamed into the request using an io.Pipe in a separated goroutine
-func streamUploadBody(client *FlickrClient, photo io.Reader, body *io.PipeWriter, fileName string, boundary string) {
+// Encode the file and request parameters in a multipart body written to w
+func writeUploadBody(client *FlickrClient, photo io.Reader, w io.Writer, fileName string, boundary string) error {
 	// multipart writer to fill the body
-	defer body.Close()
-	writer := multipart.NewWriter(body)
+	writer := multipart.NewWriter(w)
 	writer.SetBoundary(boundary)
 
 	// create the "photo" field
 	part, err := writer.CreateFormFile("photo", filepath.Base(fileName))
 	if err != nil {
-		log.Fatal(err)
-		return
+		return err
 	}
 
 	// fill the photo field
 	_, err = io.Copy(part, photo)
 	if err != nil {
-		log.Fatal(err)
-		return
+		return err
 	}
 
 	// dump other params
@@ -53,11 +49,18 @@ func streamUploadBody(client *FlickrClient, photo io.Reader, body *io.PipeWriter
 	}
 
 	// close the form writer
-	err = writer.Close()
-	if err != nil {
-		log.Fatal(err)
+	return writer.Close()
+}
+
+// Encode the file and request parameters in a multipart body.
+// File contents are streamed into the request using an io.Pipe in a separated goroutine,
+// any error is propagated to the reading side of the pipe so the request fails.
+func streamUploadBody(client *FlickrClient, photo io.Reader, body *io.PipeWriter, fileName string, boundary string) {
+	if err := writeUploadBody(client, photo, body, fileName, boundary); err != nil {
+		body.CloseWithError(err)
 		return
 	}
+	body.Close()
 }
 
 // UploadParams is a convenience struct wrapping all optional upload parameters
@@ -66,15 +69,33 @@ type UploadParams struct {
 	Tags                         []string
 	IsPublic, IsFamily, IsFriend bool
 	ContentType                  int
-	Hidden                       int
-	SafetyLevel                  int
+	// VisibleInSearch or HiddenFromSearch
+	Hidden      int
+	SafetyLevel int
+	// Transformations applied in order to the file before it's sent, see UploadHook
+	Hooks []UploadHook
+	// Cap on the bandwidth used by this upload, overriding the UploadLimiter of
+	// the client
+	Limiter *BandwidthLimiter
+	// Sniff the format of the file before sending it, files Flickr doesn't accept
+	// fail locally with an UnsupportedMediaError, see SniffMedia
+	CheckMedia bool
+	// Generate title, description and tags from the file name and EXIF data, see
+	// UploadTemplate
+	Template *UploadTemplate
 }
 
+// Values of the hidden flag, controlling whether a photo shows up in public searches
+const (
+	VisibleInSearch  = 1
+	HiddenFromSearch = 2
+)
+
 // NewUploadParams provides meaningful default values
 func NewUploadParams() *UploadParams {
 	ret := &UploadParams{}
 	ret.ContentType = 1 // photo
-	ret.Hidden = 2      // hidden from public searchesi
+	ret.Hidden = HiddenFromSearch
 	ret.SafetyLevel = 1 // safe
 	return ret
 }
@@ -85,6 +106,28 @@ type UploadResponse struct {
 	ID string `xml:"photoid"`
 }
 
+// Implement json.Marshaler, see MarshalResponse
+func (r UploadResponse) MarshalJSON() ([]byte, error) {
+	return MarshalResponse(r)
+}
+
+// Format a list of raw tags the way Flickr expects them in the "tags" param:
+// space separated, with multi-word tags wrapped in double quotes
+func FormatTags(tags []string) string {
+	ret := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(strings.Replace(t, `"`, "", -1))
+		if t == "" {
+			continue
+		}
+		if strings.ContainsAny(t, " \t") {
+			t = `"` + t + `"`
+		}
+		ret = append(ret, t)
+	}
+	return strings.Join(ret, " ")
+}
+
 // Set client query arguments based on the contents of the UploadParams struct
 func fillArgsWithParams(client *FlickrClient, params *UploadParams) {
 	if params.Title != "" {
@@ -96,32 +139,96 @@ func fillArgsWithParams(client *FlickrClient, params *UploadParams) {
 	}
 
 	if len(params.Tags) > 0 {
-		client.Args.Set("tags", strings.Join(params.Tags, " "))
+		client.Args.Set("tags", FormatTags(params.Tags))
 	}
 
-	var boolString = func(b bool) string {
-		if b {
-			return "1"
-		}
-		return "0"
-	}
-	client.Args.Set("is_public", boolString(params.IsPublic))
-	client.Args.Set("is_friend", boolString(params.IsFriend))
-	client.Args.Set("is_family", boolString(params.IsFamily))
+	client.SetBool("is_public", params.IsPublic)
+	client.SetBool("is_friend", params.IsFriend)
+	client.SetBool("is_family", params.IsFamily)
 
 	if params.ContentType >= 1 && params.ContentType <= 3 {
-		client.Args.Set("content_type", strconv.Itoa(params.ContentType))
+		client.SetInt("content_type", params.ContentType)
 	}
 
 	if params.Hidden >= 1 && params.Hidden <= 2 {
-		client.Args.Set("hidden", strconv.Itoa(params.Hidden))
+		client.SetInt("hidden", params.Hidden)
 	}
 
 	if params.SafetyLevel >= 1 && params.SafetyLevel <= 3 {
-		client.Args.Set("safety_level", strconv.Itoa(params.SafetyLevel))
+		client.SetInt("safety_level", params.SafetyLevel)
 	}
 }
 
+// Params never included in the signature of an upload: the file itself and the
+// signatures of other signing modes
+var uploadUnsignedArgs = []string{"photo", "api_sig", "oauth_signature"}
+
+// Sign an upload request. Uploads follow their own rules: the file is sent as the
+// "photo" multipart field and must not be signed, every other param is sent as a
+// multipart field and must be signed with OAuth exactly as it's sent. Since fields
+// only carry one value, params with many values are reduced to the first one.
+// OAuth defaults (nonce, timestamp, etc) already set are kept.
+// Only the normalization is performed when the client has an AuthProvider.
+func (c *FlickrClient) UploadSign() {
+	for _, name := range uploadUnsignedArgs {
+		c.Args.Del(name)
+	}
+	for name, values := range c.Args {
+		if len(values) > 1 {
+			c.Args[name] = values[:1]
+		}
+	}
+	if c.AuthProvider != nil {
+		return
+	}
+
+	defaults := map[string]string{
+		"oauth_version":          "1.0",
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_nonce":            generateNonce(),
+		"oauth_timestamp":        fmt.Sprintf("%d", c.now().Unix()),
+	}
+	for name, value := range defaults {
+		if c.Args.Get(name) == "" {
+			c.Args.Set(name, value)
+		}
+	}
+	c.Args.Set("oauth_token", c.OAuthToken)
+	c.Args.Set("oauth_consumer_key", c.ApiKey)
+	c.Args.Set("api_key", c.ApiKey)
+
+	c.Sign(c.OAuthTokenSecret)
+}
+
+// Sign an upload again with a fresh nonce and timestamp, for a new attempt
+func (c *FlickrClient) resignUpload() {
+	c.Args.Del("oauth_nonce")
+	c.Args.Del("oauth_timestamp")
+	c.UploadSign()
+}
+
+// NewUploadHTTPClient returns the HTTP client used to perform uploads, a zero
+// timeout means no timeout at all. When the FlickrClient was configured with a
+// custom Transport that one is reused, otherwise the client is forced to use http1.1
+func NewUploadHTTPClient(client *FlickrClient, timeout time.Duration) *http.Client {
+	if client.HTTPClient != nil && client.HTTPClient.Transport != nil {
+		return &http.Client{Transport: client.HTTPClient.Transport, Timeout: timeout}
+	}
+
+	// Create a Transport to explicitly use the http1.1 client
+	// TODO: for some reason, when we use the http2 client flickr API responds
+	// with HTTP: 411 (No Content Length : POST) whereas it should be ok to
+	// upload using chunks. Explicitly setting `req.Header.Set("transfer-encoding", "chunked")`
+	// does not help and try to compute the request size isn't the right thing to do IMHO.
+	// We should investigate why this happens instead of forcing the downgrade to http1.1.
+	tr := &http.Transport{
+		TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
+	}
+
+	// instance an HTTP client
+	return &http.Client{Transport: tr, Timeout: timeout}
+}
+
 // UploadFile performs a file upload using the Flickr API. If optionalParams is nil,
 // no parameters will be added to the request and Flickr will set User's
 // default preferences.
@@ -143,53 +250,155 @@ func UploadReader(client *FlickrClient, photoReader io.Reader, name string, opti
 
 // UploadReaderWithClient does same as UploadReader but allows passing a custom httpClient
 func UploadReaderWithClient(client *FlickrClient, photoReader io.Reader, name string, optionalParams *UploadParams, httpClient *http.Client) (*UploadResponse, error) {
+	return sendFile(client, UPLOAD_ENDPOINT, photoReader, name, optionalParams, httpClient, nil)
+}
+
+// ReplaceFile replaces the file of an existing photo, keeping its ID, metadata,
+// comments and favorites. Flickr gives the photo a new secret, it's invalidated in
+// the PhotoCaches of the client.
+// This call must be signed with write permissions
+func ReplaceFile(client *FlickrClient, photoId, path string) (*UploadResponse, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReplaceReader(client, photoId, file, file.Name())
+}
+
+// ReplaceReader does same as ReplaceFile but the file is passed as an io.Reader
+func ReplaceReader(client *FlickrClient, photoId string, photoReader io.Reader, name string) (*UploadResponse, error) {
+	resp, err := sendFile(client, REPLACE_ENDPOINT, photoReader, name, nil, nil, func() {
+		client.Args.Set("photo_id", photoId)
+	})
+	// the file may have been replaced even if the response was lost
+	client.InvalidatePhoto(photoId)
+	return resp, err
+}
+
+// Send a file to the upload or replace endpoint, setArgs sets the params specific to
+// the endpoint if not nil
+func sendFile(client *FlickrClient, endpoint string, photoReader io.Reader, name string, optionalParams *UploadParams, httpClient *http.Client, setArgs func()) (*UploadResponse, error) {
+	// hooks may change the size of the file, it's only known once they ran
+	if client.UploadLimits != nil && (optionalParams == nil || len(optionalParams.Hooks) == 0) {
+		var err error
+		if photoReader, err = client.UploadLimits.check(photoReader, name); err != nil {
+			return nil, err
+		}
+	}
+	if optionalParams != nil && optionalParams.CheckMedia {
+		var err error
+		if _, photoReader, err = SniffMedia(photoReader); err != nil {
+			return nil, err
+		}
+	}
+	if optionalParams != nil && optionalParams.Template != nil {
+		var err error
+		if optionalParams, photoReader, err = applyUploadTemplate(optionalParams, photoReader, name); err != nil {
+			return nil, err
+		}
+	}
+
 	client.Init()
-	client.EndpointUrl = UPLOAD_ENDPOINT
+	client.EndpointUrl = endpoint
 	client.HTTPVerb = "POST"
 
 	if optionalParams != nil {
 		fillArgsWithParams(client, optionalParams)
 	}
+	if setArgs != nil {
+		setArgs()
+	}
 
-	client.OAuthSign()
+	client.UploadSign()
 
-	// write request body in a Pipe
-	boundary := randomBoundary()
-	r, w := io.Pipe()
-	go streamUploadBody(client, photoReader, w, name, boundary)
+	limiter := client.UploadLimiter
+	if optionalParams != nil && optionalParams.Limiter != nil {
+		limiter = optionalParams.Limiter
+	}
+	if httpClient == nil {
+		httpClient = NewUploadHTTPClient(client, 0)
+	}
 
-	// create an HTTP Request
-	req, err := http.NewRequest("POST", client.EndpointUrl, r)
-	if err != nil {
-		return nil, err
+	// the transformed file is kept in memory, so that retries can encode it again
+	// along with freshly signed params
+	var transformed []byte
+	if optionalParams != nil && len(optionalParams.Hooks) > 0 {
+		var err error
+		if transformed, err = applyUploadHooks(photoReader, name, optionalParams.Hooks); err != nil {
+			return nil, err
+		}
 	}
 
-	// set content-type
-	req.Header.Set("content-type", "multipart/form-data; boundary="+boundary)
-	req.ContentLength = -1 // unknown
+	build := func(retry bool) (*http.Request, error) {
+		boundary := randomBoundary()
+		var body io.Reader
+		if transformed != nil {
+			if retry {
+				client.resignUpload()
+			}
+			// the size of the transformed file can't be known in advance, build the
+			// whole body so the request has a proper content length
+			buf := &bytes.Buffer{}
+			if err := writeUploadBody(client, bytes.NewReader(transformed), buf, name, boundary); err != nil {
+				return nil, err
+			}
+			body = buf
+		} else {
+			// write request body in a Pipe, such a body can't be sent twice
+			r, w := io.Pipe()
+			go streamUploadBody(client, photoReader, w, name, boundary)
+			body = r
+		}
 
-	if (httpClient == nil) {
-		// Create a Transport to explicitly use the http1.1 client
-		// TODO: for some reason, when we use the http2 client flickr API responds
-		// with HTTP: 411 (No Content Length : POST) whereas it should be ok to
-		// upload using chunks. Explicitly setting `req.Header.Set("transfer-encoding", "chunked")`
-		// does not help and try to compute the request size isn't the right thing to do IMHO.
-		// We should investigate why this happens instead of forcing the downgrade to http1.1.
-		tr := &http.Transport{
-			TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
+		// create an HTTP Request, the content length is known for buffered bodies
+		req, err := http.NewRequest("POST", client.EndpointUrl, body)
+		if err != nil {
+			return nil, err
 		}
 
-		// instance an HTTP client
-		httpClient = &http.Client{Transport: tr}
+		// set content-type
+		req.Header.Set("content-type", "multipart/form-data; boundary="+boundary)
+		if _, buffered := body.(*bytes.Buffer); !buffered {
+			req.ContentLength = -1 // unknown
+		}
+		if limiter != nil {
+			// throttle the encoded body
+			req.Body = limiter.readCloser(req.Body)
+		}
+		return req, nil
 	}
 
-	// perform upload request streaming the file
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, err
+	// a streamed file can only be sent again when it can be rewound
+	seeker, seekable := photoReader.(io.Seeker)
+	var start int64
+	if transformed == nil && seekable {
+		var err error
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			seekable = false
+		}
+	}
+	resign := func() bool {
+		if transformed == nil {
+			if !seekable {
+				return false
+			}
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return false
+			}
+		}
+		client.resignUpload()
+		return true
 	}
 
-	apiResp := &UploadResponse{}
-	err = parseApiResponse(resp, apiResp)
+	// perform upload request streaming the file
+	var apiResp *UploadResponse
+	err := client.retryOnSkewWith(resign, func() error {
+		return client.roundTrip(httpClient, build, func(res *http.Response) error {
+			apiResp = &UploadResponse{}
+			return client.parseResponse(res, apiResp)
+		})
+	})
 	return apiResp, err
 }
@@ -0,0 +1,54 @@
+package flickr
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+)
+
+// A function transforming a file before it's uploaded, e.g. to resize it, strip
+// EXIF GPS data or add a watermark. It receives the contents of the file along with
+// its name and returns the transformed contents.
+type UploadHook func(r io.Reader, name string) (io.Reader, error)
+
+// Apply the hooks in order, returning the transformed file
+func applyUploadHooks(photo io.Reader, name string, hooks []UploadHook) ([]byte, error) {
+	var err error
+	for _, hook := range hooks {
+		photo, err = hook(photo, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ioutil.ReadAll(photo)
+}
+
+// Return an UploadHook decoding the file as an image, applying fn and encoding the
+// result in the same format for PNG files, as JPEG otherwise. Since only the
+// pixels are kept, metadata like EXIF GPS coordinates are dropped in the process.
+// quality is used for JPEG files, zero means jpeg.DefaultQuality.
+func ImageHook(fn func(image.Image) image.Image, quality int) UploadHook {
+	if quality <= 0 {
+		quality = jpeg.DefaultQuality
+	}
+	return func(r io.Reader, name string) (io.Reader, error) {
+		img, format, err := image.Decode(r)
+		if err != nil {
+			return nil, err
+		}
+		if fn != nil {
+			img = fn(img)
+		}
+
+		out := &bytes.Buffer{}
+		if format == "png" {
+			err = png.Encode(out, img)
+		} else {
+			err = jpeg.Encode(out, img, &jpeg.Options{Quality: quality})
+		}
+		return out, err
+	}
+}
@@ -0,0 +1,79 @@
+package flickr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestUploadHooks(t *testing.T) {
+	var contentLength int64
+	var photo string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		r.ParseMultipartForm(1 << 20)
+		f, _, _ := r.FormFile("photo")
+		data, _ := ioutil.ReadAll(f)
+		photo = string(data)
+		fmt.Fprintln(w, `<?xml version="1.0" encoding="utf-8" ?><rsp stat="ok"><photoid>1234</photoid></rsp>`)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	fclient := GetTestClient()
+	fclient.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+
+	upper := func(r io.Reader, name string) (io.Reader, error) {
+		data, err := ioutil.ReadAll(r)
+		return bytes.NewReader(bytes.ToUpper(data)), err
+	}
+	suffix := func(r io.Reader, name string) (io.Reader, error) {
+		return io.MultiReader(r, strings.NewReader(" "+name)), nil
+	}
+	params := NewUploadParams()
+	params.Hooks = []UploadHook{upper, suffix}
+
+	resp, err := UploadReader(fclient, strings.NewReader("photo bytes"), "a.jpg", params)
+	Expect(t, err, nil)
+	Expect(t, resp.ID, "1234")
+	Expect(t, photo, "PHOTO BYTES a.jpg")
+	Expect(t, contentLength > 0, true)
+
+	// a failing hook aborts the upload
+	boom := errors.New("boom")
+	params.Hooks = []UploadHook{func(r io.Reader, name string) (io.Reader, error) { return nil, boom }}
+	_, err = UploadReader(fclient, strings.NewReader("photo bytes"), "a.jpg", params)
+	Expect(t, err, boom)
+}
+
+func TestImageHook(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	buf := &bytes.Buffer{}
+	png.Encode(buf, src)
+
+	mark := func(img image.Image) image.Image {
+		out := image.NewRGBA(img.Bounds())
+		out.Set(0, 0, color.RGBA{255, 0, 0, 255})
+		return out
+	}
+	r, err := ImageHook(mark, 0)(buf, "a.png")
+	Expect(t, err, nil)
+	img, format, err := image.Decode(r)
+	Expect(t, err, nil)
+	Expect(t, format, "png")
+	red, _, _, _ := img.At(0, 0).RGBA()
+	Expect(t, red, uint32(0xffff))
+
+	_, err = ImageHook(nil, 0)(strings.NewReader("not an image"), "a.jpg")
+	Expect(t, err, image.ErrFormat)
+}
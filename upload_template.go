@@ -0,0 +1,137 @@
+package flickr
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Maximum number of bytes buffered to read the metadata of an uploaded file, JPEG
+// metadata segments come first and are at most 64KB each
+const templateSniffLen = 1 << 20
+
+// Values available to the templates of an UploadTemplate
+type TemplateData struct {
+	// Name of the file without directory and extension, e.g. "DSC_0001"
+	Filename string
+	// Name of the directory containing the file, e.g. "2024-06 Lisbon", empty when
+	// the upload name has no directory
+	Folder string
+	// When the photo was taken according to EXIF, zero if unknown, see FileMetadata
+	Taken       time.Time
+	CameraMake  string
+	CameraModel string
+}
+
+// Return the template data of the file uploaded as name, the EXIF fields come from meta
+func NewTemplateData(name string, meta *FileMetadata) *TemplateData {
+	base := filepath.Base(name)
+	data := &TemplateData{Filename: strings.TrimSuffix(base, filepath.Ext(base))}
+	if dir := filepath.Dir(name); dir != "." && dir != string(filepath.Separator) {
+		data.Folder = filepath.Base(dir)
+	}
+	if meta != nil {
+		data.Taken = meta.Taken
+		data.CameraMake = meta.CameraMake
+		data.CameraModel = meta.CameraModel
+	}
+	return data
+}
+
+// Functions available to upload templates
+var templateFuncs = template.FuncMap{
+	// Format a time with a Go layout, zero times yield an empty string:
+	// {{date "2006-01-02" .Taken}}
+	"date": func(layout string, t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	},
+}
+
+// Go templates (text/template) generating the title, description and tags of
+// uploads from a TemplateData, so that bulk uploads of camera files get meaningful
+// metadata instead of "DSC_0001":
+//
+//	params.Template = &flickr.UploadTemplate{
+//		Title: `{{.Folder}} {{date "2006-01-02" .Taken}}`,
+//		Tags:  []string{"{{.CameraModel}}", "{{.Folder}}"},
+//	}
+//
+// Besides the builtin functions, date formats a time and yields an empty string
+// for zero times.
+type UploadTemplate struct {
+	// Templates of the title and the description, empty ones leave the values of the
+	// UploadParams untouched
+	Title       string
+	Description string
+	// Templates of tags added to the ones of the UploadParams, a tag rendering to an
+	// empty string is dropped
+	Tags []string
+}
+
+// Render a template, errors are reported as InvalidParamsError
+func renderTemplate(text string, data *TemplateData) (string, error) {
+	tmpl, err := template.New("upload").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", flickErr.NewError(flickErr.InvalidParamsError, err.Error())
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", flickErr.NewError(flickErr.InvalidParamsError, err.Error())
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Render the templates with data and set the results in params
+func (t *UploadTemplate) Apply(params *UploadParams, data *TemplateData) error {
+	var err error
+	if t.Title != "" {
+		if params.Title, err = renderTemplate(t.Title, data); err != nil {
+			return err
+		}
+	}
+	if t.Description != "" {
+		if params.Description, err = renderTemplate(t.Description, data); err != nil {
+			return err
+		}
+	}
+	if len(t.Tags) > 0 {
+		tags := append([]string(nil), params.Tags...)
+		for _, text := range t.Tags {
+			tag, err := renderTemplate(text, data)
+			if err != nil {
+				return err
+			}
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		params.Tags = tags
+	}
+	return nil
+}
+
+// Render the template of params for the file uploaded as name, reading its EXIF
+// metadata from the head of r. Returns a copy of params and a reader yielding the
+// whole content of r.
+func applyUploadTemplate(params *UploadParams, r io.Reader, name string) (*UploadParams, io.Reader, error) {
+	var head bytes.Buffer
+	meta, err := readJPEGMetadata(io.TeeReader(io.LimitReader(r, templateSniffLen), &head))
+	if err != nil {
+		return nil, nil, err
+	}
+	replay := io.MultiReader(&head, r)
+
+	rendered := *params
+	if err := params.Template.Apply(&rendered, NewTemplateData(name, meta)); err != nil {
+		return nil, nil, err
+	}
+	return &rendered, replay, nil
+}
@@ -0,0 +1,55 @@
+package flickr
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestUploadTemplate(t *testing.T) {
+	tmpl := &UploadTemplate{
+		Title:       `{{.Folder}} {{date "2006-01-02" .Taken}}`,
+		Description: `Shot with {{.CameraMake}} {{.CameraModel}}, {{.Filename}}`,
+		Tags:        []string{"{{.CameraModel}}", `{{date "2006" .Taken}}`},
+	}
+	params := &UploadParams{Title: "ignored", Tags: []string{"holidays"}}
+	data := NewTemplateData("photos/Lisbon/DSC_0001.JPG", &FileMetadata{
+		Taken:       time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC),
+		CameraMake:  "Canon",
+		CameraModel: "EOS R5",
+	})
+	Expect(t, tmpl.Apply(params, data), nil)
+	Expect(t, params.Title, "Lisbon 2024-06-01")
+	Expect(t, params.Description, "Shot with Canon EOS R5, DSC_0001")
+	Expect(t, FormatTags(params.Tags), `holidays "EOS R5" 2024`)
+
+	// unknown date, the year tag is dropped
+	params = &UploadParams{}
+	Expect(t, tmpl.Apply(params, NewTemplateData("DSC_0002.JPG", nil)), nil)
+	Expect(t, params.Title, "")
+	Expect(t, len(params.Tags), 0)
+
+	err := (&UploadTemplate{Title: "{{.Nope}}"}).Apply(params, data)
+	Expect(t, err != nil, true)
+}
+
+func TestUploadWithTemplate(t *testing.T) {
+	fclient := GetTestClient()
+	server, client, calls := FlickrMockRecorder(200, map[string]string{
+		"upload": `<rsp stat="ok"><photoid>1234</photoid></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	jpeg := []byte{0xFF, jpegSOI}
+	jpeg = append(jpeg, jpegSegment(jpegAPP1, testExif("Canon", "EOS R5", "2024:06:01 10:30:00"))...)
+	jpeg = append(jpeg, 0xFF, jpegSOS, 0, 2)
+
+	params := NewUploadParams()
+	params.Template = &UploadTemplate{Title: `{{.Filename}} {{date "Jan 2006" .Taken}}`}
+	_, err := UploadReader(fclient, bytes.NewReader(jpeg), "DSC_0001.JPG", params)
+	Expect(t, err, nil)
+	upload := calls.Last("upload")
+	Expect(t, upload.Get("title"), "DSC_0001 Jun 2024")
+	Expect(t, params.Title, "")
+}
@@ -1,8 +1,11 @@
 package flickr
 
 import (
+	"bytes"
 	"io/ioutil"
+	"mime/multipart"
 	"os"
+	"strings"
 	"testing"
 
 	flickErr "gopkg.in/masci/flickr.v2/error"
@@ -84,3 +87,72 @@ func TestUploadFileKo(t *testing.T) {
 	Expect(t, ok, true)
 	Expect(t, resp.HasErrors(), true)
 }
+
+func TestFormatTags(t *testing.T) {
+	Expect(t, FormatTags([]string{"a", "b c", " d ", "", `e"f`}), `a "b c" d ef`)
+	Expect(t, FormatTags(nil), "")
+}
+
+func TestUploadSign(t *testing.T) {
+	c := NewFlickrClient("653e7a6ecc1d528c516cc8f92cf98611", "3d6b2f2c0d5b7c27")
+	c.OAuthToken = "72157626737672178-022bbd2f4c2f3432"
+	c.OAuthTokenSecret = "tokensecret123"
+	c.EndpointUrl = UPLOAD_ENDPOINT
+	c.HTTPVerb = "POST"
+	c.Args.Set("oauth_nonce", "95613465")
+	c.Args.Set("oauth_timestamp", "1305586162")
+	fillArgsWithParams(c, &UploadParams{Title: "My * photo", Tags: []string{"sea", "sunset beach"}, IsPublic: true, Hidden: HiddenFromSearch})
+	c.Args.Del("is_friend")
+	c.Args.Del("is_family")
+	// params that must not be signed or sent twice
+	c.Args.Set("photo", "data")
+	c.Args.Set("api_sig", "stale")
+	c.Args.Add("hidden", "1")
+
+	c.UploadSign()
+	// computed with an independent OAuth 1.0a implementation
+	Expect(t, c.Args.Get("oauth_signature"), "kNvLM3AcZC9XIGf/XAMyk6HgOns=")
+	Expect(t, c.Args.Get("oauth_nonce"), "95613465")
+	Expect(t, len(c.Args["hidden"]), 1)
+	Expect(t, c.Args.Get("photo"), "")
+	Expect(t, c.Args.Get("api_sig"), "")
+
+	// every signed param is sent as a multipart field, along with the photo
+	body := &bytes.Buffer{}
+	Expect(t, writeUploadBody(c, strings.NewReader("jpeg"), body, "a.jpg", "xyz"), nil)
+	form, err := multipart.NewReader(body, "xyz").ReadForm(1024)
+	Expect(t, err, nil)
+	Expect(t, len(form.File["photo"]), 1)
+	Expect(t, len(form.Value), len(c.Args))
+	for name, values := range c.Args {
+		Expect(t, form.Value[name][0], values[0])
+	}
+}
+
+// A PhotoCache recording the invalidated photos
+type recordingPhotoCache []string
+
+func (c *recordingPhotoCache) InvalidatePhoto(photoId string) {
+	*c = append(*c, photoId)
+}
+
+func TestReplaceReader(t *testing.T) {
+	cache := &recordingPhotoCache{}
+	fclient := GetTestClient()
+	WithPhotoCache(cache)(fclient)
+	server, client, calls := FlickrMockRecorder(200, map[string]string{
+		"replace": `<rsp stat="ok"><photoid secret="abc" originalsecret="def">123</photoid></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := ReplaceReader(fclient, "123", strings.NewReader("new file"), "new.jpg")
+	Expect(t, err, nil)
+	Expect(t, resp.ID, "123")
+	Expect(t, fclient.EndpointUrl, REPLACE_ENDPOINT)
+	args := calls.Last("replace")
+	Expect(t, args.Get("photo_id"), "123")
+	Expect(t, args.Get("title"), "")
+	Expect(t, len(*cache), 1)
+	Expect(t, (*cache)[0], "123")
+}
@@ -0,0 +1,106 @@
+package flickr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Size limits of the files Flickr accepts
+const (
+	MaxPhotoUploadSize = 200 << 20
+	MaxVideoUploadSize = 1 << 30
+)
+
+// A reader failing with a FileTooLargeError once more than max bytes were read
+type sizeLimitReader struct {
+	r    io.Reader
+	name string
+	read int64
+	max  int64
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return n, flickErr.NewError(flickErr.FileTooLargeError,
+			fmt.Sprintf("%s is more than %d bytes", l.name, l.max))
+	}
+	return n, err
+}
+
+// Return the name of the file at u, used as the name of the uploaded file
+func remoteFileName(u *url.URL, contentType string) string {
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = "upload"
+	}
+	if path.Ext(name) == "" {
+		// Flickr guesses the type of the file from its extension, the system MIME
+		// tables may list unusual ones first, e.g. .jfif for image/jpeg
+		if ext := mimeTypeExtension(contentType); ext != "" {
+			name += ext
+		} else if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+			name += exts[0]
+		}
+	}
+	return name
+}
+
+// Upload the file found at rawURL, e.g. when migrating from another photo service.
+// The remote file is streamed straight into the upload without touching the disk.
+// It's fetched with the client HTTPClient, without the client credentials, and must
+// be an image or a video within the Flickr size limits: a Content-Type other than
+// image/* or video/* is refused with an InvalidParamsError, a size above the limits
+// with a FileTooLargeError, either announced by Content-Length or detected while
+// streaming. Canceling ctx aborts the download and the upload streaming it.
+// params are the same as UploadFile.
+// This call must be signed with write permissions
+func UploadFromURL(ctx context.Context, client *FlickrClient, rawURL string, params *UploadParams) (*UploadResponse, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// not client.do: the AuthProvider must not see requests to other services
+	start := time.Now()
+	res, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, diagnoseNetError(u.Host, err, time.Since(start))
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, flickErr.NewError(flickErr.DownloadError, fmt.Sprintf("%s returned %s", Redact(rawURL), res.Status))
+	}
+	contentType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	var limit int64
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		limit = MaxPhotoUploadSize
+	case strings.HasPrefix(contentType, "video/"):
+		limit = MaxVideoUploadSize
+	default:
+		return nil, flickErr.NewError(flickErr.InvalidParamsError,
+			fmt.Sprintf("%s is %q, not a photo or a video", Redact(rawURL), contentType))
+	}
+	if res.ContentLength > limit {
+		return nil, flickErr.NewError(flickErr.FileTooLargeError,
+			fmt.Sprintf("%s is %d bytes, limit is %d", Redact(rawURL), res.ContentLength, limit))
+	}
+
+	body := &sizeLimitReader{r: res.Body, name: Redact(rawURL), max: limit}
+	return UploadReader(client, body, remoteFileName(u, contentType), params)
+}
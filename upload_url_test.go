@@ -0,0 +1,103 @@
+package flickr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Serve remote files under /files/ with the given content type and size, and the
+// upload endpoint recording the name and contents of the uploaded file. Files bigger
+// than the upload limits are announced but not sent.
+func remoteUploadClient(t *testing.T, contentType string, size int, uploaded *string) (*httptest.Server, *FlickrClient) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/files/") {
+			Expect(t, r.Header.Get("X-Gateway-Token"), "")
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Length", fmt.Sprint(size))
+			if size <= MaxPhotoUploadSize {
+				w.Write([]byte(strings.Repeat("x", size)))
+			}
+			return
+		}
+		file, header, err := r.FormFile("photo")
+		if err != nil {
+			fmt.Fprintln(w, `<rsp stat="fail"><err code="2" msg="No photo specified" /></rsp>`)
+			return
+		}
+		data, _ := ioutil.ReadAll(file)
+		*uploaded = header.Filename + ":" + string(data)
+		fmt.Fprintln(w, `<rsp stat="ok"><photoid>42</photoid></rsp>`)
+	}))
+	u, _ := url.Parse(server.URL)
+	client := GetTestClient()
+	client.AuthProvider = &HeaderAuthProvider{Header: http.Header{"X-Gateway-Token": {"secret"}}}
+	client.HTTPClient = &http.Client{Transport: RewriteTransport{URL: u}}
+	return server, client
+}
+
+func TestUploadFromURL(t *testing.T) {
+	uploaded := ""
+	server, client := remoteUploadClient(t, "image/jpeg", 5, &uploaded)
+	defer server.Close()
+
+	resp, err := UploadFromURL(context.Background(), client, "https://photos.example.com/files/sunset.jpg", nil)
+	Expect(t, err, nil)
+	Expect(t, resp.ID, "42")
+	Expect(t, uploaded, "sunset.jpg:xxxxx")
+
+	// the extension is guessed from the content type
+	_, err = UploadFromURL(context.Background(), client, "https://photos.example.com/files/1234", nil)
+	Expect(t, err, nil)
+	Expect(t, uploaded, "1234.jpg:xxxxx")
+}
+
+func TestUploadFromURLValidation(t *testing.T) {
+	uploaded := ""
+	server, client := remoteUploadClient(t, "text/html; charset=utf-8", 5, &uploaded)
+	defer server.Close()
+
+	_, err := UploadFromURL(context.Background(), client, "https://photos.example.com/files/page", nil)
+	ee, ok := err.(*flickErr.Error)
+	Expect(t, ok, true)
+	Expect(t, ee.ErrorCode, flickErr.InvalidParamsError)
+	Expect(t, uploaded, "")
+
+	server, client = remoteUploadClient(t, "image/png", MaxPhotoUploadSize+1, &uploaded)
+	defer server.Close()
+	_, err = UploadFromURL(context.Background(), client, "https://photos.example.com/files/huge.png", nil)
+	ee, ok = err.(*flickErr.Error)
+	Expect(t, ok, true)
+	Expect(t, ee.ErrorCode, flickErr.FileTooLargeError)
+
+	Expect(t, uploaded, "")
+}
+
+func TestSizeLimitReader(t *testing.T) {
+	// without Content-Length the size is checked while streaming
+	_, err := ioutil.ReadAll(&sizeLimitReader{r: strings.NewReader("xxxxx"), name: "a.jpg", max: 5})
+	Expect(t, err, nil)
+	_, err = ioutil.ReadAll(&sizeLimitReader{r: strings.NewReader("xxxxxx"), name: "a.jpg", max: 5})
+	var ee *flickErr.Error
+	Expect(t, errors.As(err, &ee), true)
+	Expect(t, ee.ErrorCode, flickErr.FileTooLargeError)
+}
+
+func TestUploadFromURLCanceled(t *testing.T) {
+	uploaded := ""
+	server, client := remoteUploadClient(t, "image/jpeg", 5, &uploaded)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := UploadFromURL(ctx, client, "https://photos.example.com/files/sunset.jpg", nil)
+	Expect(t, AsNetError(err).Kind, NetCanceled)
+}
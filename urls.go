@@ -0,0 +1,87 @@
+package flickr
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Base URL of the Flickr static servers hosting photo files
+const STATIC_ENDPOINT = "https://live.staticflickr.com"
+
+// Base URL of the pages of the photos and albums on the Flickr website
+const WEBSITE_ENDPOINT = "https://www.flickr.com/photos"
+
+// Return the URL of the page of a photo on the Flickr website
+func PhotoPageURL(userId, photoId string) string {
+	return fmt.Sprintf("%s/%s/%s", WEBSITE_ENDPOINT, userId, photoId)
+}
+
+// Return the URL of the page of an album on the Flickr website
+func AlbumURL(userId, photosetId string) string {
+	return fmt.Sprintf("%s/%s/albums/%s", WEBSITE_ENDPOINT, userId, photosetId)
+}
+
+// Return the URL of a photo file at the given size suffix (e.g. "m", "b", "k"),
+// an empty size returns the default 500px version. Sizes up to 1024px can be built
+// from the public secret, bigger ones might need a dedicated secret.
+func PhotoURL(server, id, secret, size string) string {
+	if size == "" {
+		return fmt.Sprintf("%s/%s/%s_%s.jpg", STATIC_ENDPOINT, server, id, secret)
+	}
+	return fmt.Sprintf("%s/%s/%s_%s_%s.jpg", STATIC_ENDPOINT, server, id, secret, size)
+}
+
+// Return the URL of the original file of a photo. Unlike the other sizes, the
+// original has its own secret and keeps the format it was uploaded with.
+// Returns an empty string if originalSecret is empty.
+func OriginalURL(server, id, originalSecret, originalFormat string) string {
+	if originalSecret == "" {
+		return ""
+	}
+	if originalFormat == "" {
+		originalFormat = "jpg"
+	}
+	// the URL uses the format as reported by Flickr, not FormatExtension
+	return fmt.Sprintf("%s/%s/%s_%s_o.%s", STATIC_ENDPOINT, server, id, originalSecret, originalFormat)
+}
+
+// URL of the icon of the users and groups that didn't upload one
+const DEFAULT_BUDDYICON_URL = "https://www.flickr.com/images/buddyicon.gif"
+
+// Return the URL of the buddy icon of a user, or the icon of a group, given the
+// iconserver and iconfarm attributes Flickr reports along with its NSID. Users
+// without an icon have iconserver "0" (or no such attribute) and get the default
+// icon. The farm host is used when iconfarm is known, the farmless static host
+// otherwise.
+func BuddyIconURL(nsid, iconServer, iconFarm string) string {
+	if server, err := strconv.Atoi(iconServer); err != nil || server <= 0 || nsid == "" {
+		return DEFAULT_BUDDYICON_URL
+	}
+	if farm, err := strconv.Atoi(iconFarm); err == nil && farm > 0 {
+		return fmt.Sprintf("https://farm%d.staticflickr.com/%s/buddyicons/%s.jpg", farm, iconServer, nsid)
+	}
+	return fmt.Sprintf("%s/%s/buddyicons/%s.jpg", STATIC_ENDPOINT, iconServer, nsid)
+}
+
+// Download the file at url into w, using the client HTTPClient and CallTimeout.
+// Returns the number of bytes written.
+func Download(client *FlickrClient, url string, w io.Writer) (int64, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := client.do(client.HTTPClient, req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, flickErr.NewError(flickErr.DownloadError, fmt.Sprintf("%s returned %s", url, res.Status))
+	}
+	return io.Copy(w, res.Body)
+}
@@ -0,0 +1,147 @@
+// Package implementing methods: flickr.urls.*
+package urls
+
+import (
+	"gopkg.in/masci/flickr.v2"
+)
+
+// A group along with the URL of its page
+type GroupURL struct {
+	Nsid string `xml:"nsid,attr"`
+	URL  string `xml:"url,attr"`
+}
+
+type GroupURLResponse struct {
+	flickr.BasicResponse
+	Group GroupURL `xml:"group"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r GroupURLResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// A user along with the URL of one of their pages
+type UserURL struct {
+	Nsid string `xml:"nsid,attr"`
+	URL  string `xml:"url,attr"`
+}
+
+type UserURLResponse struct {
+	flickr.BasicResponse
+	User UserURL `xml:"user"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r UserURLResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+type LookupUserResponse struct {
+	flickr.BasicResponse
+	User struct {
+		Id       string `xml:"id,attr"`
+		Username string `xml:"username"`
+	} `xml:"user"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r LookupUserResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// A gallery as returned by flickr.urls.lookupGallery
+type Gallery struct {
+	Id             string `xml:"id,attr"`
+	URL            string `xml:"url,attr"`
+	Owner          string `xml:"owner,attr"`
+	PrimaryPhotoId string `xml:"primary_photo_id,attr"`
+	// Unix timestamps
+	DateCreate  string `xml:"date_create,attr"`
+	DateUpdate  string `xml:"date_update,attr"`
+	CountPhotos int    `xml:"count_photos,attr"`
+	CountVideos int    `xml:"count_videos,attr"`
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+}
+
+type GalleryResponse struct {
+	flickr.BasicResponse
+	Gallery Gallery `xml:"gallery"`
+}
+
+// Implement json.Marshaler, see flickr.MarshalResponse
+func (r GalleryResponse) MarshalJSON() ([]byte, error) {
+	return flickr.MarshalResponse(r)
+}
+
+// Return the URL of the page of a group, e.g. "https://www.flickr.com/groups/flickrcentral/"
+// This method does not require authentication.
+func GetGroup(client *flickr.FlickrClient, groupId string) (*GroupURLResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.urls.getGroup")
+	client.Args.Set("group_id", groupId)
+	client.ApiSign()
+
+	response := &GroupURLResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Call a flickr.urls.getUser* method, the calling user is used when userId is empty
+func getUser(client *flickr.FlickrClient, method, userId string) (*UserURLResponse, error) {
+	client.Init()
+	client.Args.Set("method", method)
+	if userId != "" {
+		client.Args.Set("user_id", userId)
+		client.ApiSign()
+	} else {
+		client.OAuthSign()
+	}
+
+	response := &UserURLResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Return the URL of the photostream of a user, e.g. "https://www.flickr.com/photos/bees/"
+// This method does not require authentication unless userId is empty, the calling
+// user is used then.
+func GetUserPhotos(client *flickr.FlickrClient, userId string) (*UserURLResponse, error) {
+	return getUser(client, "flickr.urls.getUserPhotos", userId)
+}
+
+// Return the URL of the profile of a user, e.g. "https://www.flickr.com/people/bees/"
+// This method does not require authentication unless userId is empty, the calling
+// user is used then.
+func GetUserProfile(client *flickr.FlickrClient, userId string) (*UserURLResponse, error) {
+	return getUser(client, "flickr.urls.getUserProfile", userId)
+}
+
+// Return the NSID and the username of a user given the URL of their photostream or
+// profile.
+// This method does not require authentication.
+func LookupUser(client *flickr.FlickrClient, userUrl string) (*LookupUserResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.urls.lookupUser")
+	client.Args.Set("url", userUrl)
+	client.ApiSign()
+
+	response := &LookupUserResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
+
+// Return a gallery given the URL of its page, e.g.
+// "https://www.flickr.com/photos/straup/galleries/72157617483228192"
+// This method does not require authentication.
+func LookupGallery(client *flickr.FlickrClient, galleryUrl string) (*GalleryResponse, error) {
+	client.Init()
+	client.Args.Set("method", "flickr.urls.lookupGallery")
+	client.Args.Set("url", galleryUrl)
+	client.ApiSign()
+
+	response := &GalleryResponse{}
+	err := flickr.DoGet(client, response)
+	return response, err
+}
@@ -0,0 +1,87 @@
+package urls
+
+import (
+	"testing"
+
+	"gopkg.in/masci/flickr.v2"
+)
+
+func TestGetGroup(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.urls.getGroup": `<rsp stat="ok"><group nsid="34427469792@N01" url="https://www.flickr.com/groups/flickrcentral/"/></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetGroup(fclient, "34427469792@N01")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.urls.getGroup").Get("group_id"), "34427469792@N01")
+	flickr.Expect(t, resp.Group.URL, "https://www.flickr.com/groups/flickrcentral/")
+}
+
+func TestGetUser(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.urls.getUserPhotos":  `<rsp stat="ok"><user nsid="12037949754@N01" url="https://www.flickr.com/photos/bees/"/></rsp>`,
+		"flickr.urls.getUserProfile": `<rsp stat="ok"><user nsid="12037949754@N01" url="https://www.flickr.com/people/bees/"/></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := GetUserPhotos(fclient, "12037949754@N01")
+	flickr.Expect(t, err, nil)
+	args := calls.Last("flickr.urls.getUserPhotos")
+	flickr.Expect(t, args.Get("user_id"), "12037949754@N01")
+	flickr.Expect(t, args.Get("oauth_token"), "")
+	flickr.Expect(t, resp.User.URL, "https://www.flickr.com/photos/bees/")
+
+	// the calling user
+	fclient.OAuthToken = "token"
+	resp, err = GetUserProfile(fclient, "")
+	flickr.Expect(t, err, nil)
+	args = calls.Last("flickr.urls.getUserProfile")
+	_, found := args["user_id"]
+	flickr.Expect(t, found, false)
+	flickr.Expect(t, args.Get("oauth_token"), "token")
+	flickr.Expect(t, resp.User.Nsid, "12037949754@N01")
+	flickr.Expect(t, resp.User.URL, "https://www.flickr.com/people/bees/")
+}
+
+func TestLookupUser(t *testing.T) {
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.urls.lookupUser": `<rsp stat="ok"><user id="12037949754@N01"><username>Bees</username></user></rsp>`,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := LookupUser(fclient, "https://www.flickr.com/photos/bees/")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.urls.lookupUser").Get("url"), "https://www.flickr.com/photos/bees/")
+	flickr.Expect(t, resp.User.Id, "12037949754@N01")
+	flickr.Expect(t, resp.User.Username, "Bees")
+}
+
+func TestLookupGallery(t *testing.T) {
+	body := `<rsp stat="ok">
+  <gallery id="6065-72157617483228192" url="https://www.flickr.com/photos/straup/galleries/72157617483228192" owner="35034348999@N01" primary_photo_id="292882708" date_create="1241028772" date_update="1270111667" count_photos="17" count_videos="0">
+    <title>Cat Pictures I've Sent To Kevin Collins</title>
+    <description/>
+  </gallery>
+</rsp>`
+	fclient := flickr.GetTestClient()
+	server, client, calls := flickr.FlickrMockRecorder(200, map[string]string{
+		"flickr.urls.lookupGallery": body,
+	})
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	resp, err := LookupGallery(fclient, "https://www.flickr.com/photos/straup/galleries/72157617483228192")
+	flickr.Expect(t, err, nil)
+	flickr.Expect(t, calls.Last("flickr.urls.lookupGallery").Get("url"), "https://www.flickr.com/photos/straup/galleries/72157617483228192")
+	flickr.Expect(t, resp.Gallery.Id, "6065-72157617483228192")
+	flickr.Expect(t, resp.Gallery.Owner, "35034348999@N01")
+	flickr.Expect(t, resp.Gallery.CountPhotos, 17)
+	flickr.Expect(t, resp.Gallery.Title, "Cat Pictures I've Sent To Kevin Collins")
+}
@@ -0,0 +1,52 @@
+package flickr
+
+import (
+	"bytes"
+	"testing"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func TestPhotoURL(t *testing.T) {
+	Expect(t, PhotoURL("65535", "123", "abc", ""), "https://live.staticflickr.com/65535/123_abc.jpg")
+	Expect(t, PhotoURL("65535", "123", "abc", "b"), "https://live.staticflickr.com/65535/123_abc_b.jpg")
+}
+
+func TestPageURLs(t *testing.T) {
+	Expect(t, PhotoPageURL("123@N00", "42"), "https://www.flickr.com/photos/123@N00/42")
+	Expect(t, AlbumURL("123@N00", "72157"), "https://www.flickr.com/photos/123@N00/albums/72157")
+}
+
+func TestOriginalURL(t *testing.T) {
+	Expect(t, OriginalURL("65535", "123", "def", "png"), "https://live.staticflickr.com/65535/123_def_o.png")
+	Expect(t, OriginalURL("65535", "123", "def", ""), "https://live.staticflickr.com/65535/123_def_o.jpg")
+	Expect(t, OriginalURL("65535", "123", "", "png"), "")
+}
+
+func TestBuddyIconURL(t *testing.T) {
+	Expect(t, BuddyIconURL("123@N00", "65535", "66"), "https://farm66.staticflickr.com/65535/buddyicons/123@N00.jpg")
+	Expect(t, BuddyIconURL("123@N00", "65535", ""), "https://live.staticflickr.com/65535/buddyicons/123@N00.jpg")
+	Expect(t, BuddyIconURL("123@N00", "0", "0"), DEFAULT_BUDDYICON_URL)
+	Expect(t, BuddyIconURL("123@N00", "", ""), DEFAULT_BUDDYICON_URL)
+}
+
+func TestDownload(t *testing.T) {
+	fclient := GetTestClient()
+	server, client := FlickrMock(200, "image data", "image/jpeg")
+	defer server.Close()
+	fclient.HTTPClient = client
+
+	buf := &bytes.Buffer{}
+	n, err := Download(fclient, PhotoURL("1", "2", "3", ""), buf)
+	Expect(t, err, nil)
+	Expect(t, n, int64(11))
+	Expect(t, buf.String(), "image data\n")
+
+	server, client = FlickrMock(404, "not found", "")
+	defer server.Close()
+	fclient.HTTPClient = client
+	_, err = Download(fclient, PhotoURL("1", "2", "3", ""), buf)
+	ee, ok := err.(*flickErr.Error)
+	Expect(t, ok, true)
+	Expect(t, ee.ErrorCode, flickErr.DownloadError)
+}
@@ -0,0 +1,101 @@
+package flickr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+// Limits enforced by the Flickr API, checking them locally saves an API call
+const (
+	// Maximum number of items returned per page by list methods
+	MaxPerPage = 500
+	// Maximum number of tags a photo can have
+	MaxTagsPerPhoto = 75
+)
+
+// Build an error with code InvalidParamsError
+func invalidParams(format string, a ...interface{}) error {
+	return flickErr.NewError(flickErr.InvalidParamsError, fmt.Sprintf(format, a...))
+}
+
+// Check a per_page value, zero means Flickr default and is accepted
+func ValidatePerPage(perPage int) error {
+	if perPage < 0 || perPage > MaxPerPage {
+		return invalidParams("per_page must be between 0 (Flickr default) and %d, got %d", MaxPerPage, perPage)
+	}
+	return nil
+}
+
+// Check the number of tags to be set on a photo
+func ValidateTagCount(tags []string) error {
+	if len(tags) > MaxTagsPerPhoto {
+		return invalidParams("a photo can have at most %d tags, got %d", MaxTagsPerPhoto, len(tags))
+	}
+	return nil
+}
+
+// Check the rotation of a photo, Flickr only supports 90, 180 and 270 degrees
+func ValidateRotation(degrees int) error {
+	switch degrees {
+	case 90, 180, 270:
+		return nil
+	}
+	return invalidParams("degrees must be one of 90, 180 or 270, got %d", degrees)
+}
+
+// Check a bounding box in the "min_lon,min_lat,max_lon,max_lat" format used by
+// geo searches, an empty string is accepted
+func ValidateBBox(bbox string) error {
+	if bbox == "" {
+		return nil
+	}
+
+	parts := strings.Split(bbox, ",")
+	if len(parts) != 4 {
+		return invalidParams("bbox must be \"min_lon,min_lat,max_lon,max_lat\", got %q", bbox)
+	}
+	coords := make([]float64, 4)
+	for i, p := range parts {
+		c, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return invalidParams("bbox coordinate %q is not a number", p)
+		}
+		coords[i] = c
+	}
+
+	minLon, minLat, maxLon, maxLat := coords[0], coords[1], coords[2], coords[3]
+	if minLon < -180 || maxLon > 180 || minLat < -90 || maxLat > 90 {
+		return invalidParams("bbox %q is out of range", bbox)
+	}
+	if minLon >= maxLon || minLat >= maxLat {
+		return invalidParams("bbox %q minimum values must be lower than maximum values", bbox)
+	}
+	return nil
+}
+
+// Check a safety level as accepted by uploads and flickr.photos.setSafetyLevel:
+// 1 for safe, 2 for moderate, 3 for restricted
+func ValidateSafetyLevel(level int) error {
+	if level < 1 || level > 3 {
+		return invalidParams("safety_level must be between 1 and 3, got %d", level)
+	}
+	return nil
+}
+
+// Check a location as used by geo methods: latitude and longitude in decimal degrees
+// and accuracy from 1 (world level) to 16 (street level)
+func ValidateLocation(lat, lon float64, accuracy int) error {
+	if lat < -90 || lat > 90 {
+		return invalidParams("latitude must be between -90 and 90, got %g", lat)
+	}
+	if lon < -180 || lon > 180 {
+		return invalidParams("longitude must be between -180 and 180, got %g", lon)
+	}
+	if accuracy < 1 || accuracy > 16 {
+		return invalidParams("accuracy must be between 1 and 16, got %d", accuracy)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package flickr
+
+import (
+	"strings"
+	"testing"
+
+	flickErr "gopkg.in/masci/flickr.v2/error"
+)
+
+func expectInvalidParams(t *testing.T, err error) {
+	e, ok := err.(*flickErr.Error)
+	Expect(t, ok, true)
+	Expect(t, e.ErrorCode, flickErr.InvalidParamsError)
+}
+
+func TestValidatePerPage(t *testing.T) {
+	Expect(t, ValidatePerPage(0), nil)
+	Expect(t, ValidatePerPage(500), nil)
+	expectInvalidParams(t, ValidatePerPage(501))
+	expectInvalidParams(t, ValidatePerPage(-1))
+	Expect(t, ValidatePerPage(501).Error(), "Invalid request parameters: per_page must be between 0 (Flickr default) and 500, got 501")
+}
+
+func TestValidateTagCount(t *testing.T) {
+	Expect(t, ValidateTagCount(nil), nil)
+	Expect(t, ValidateTagCount(make([]string, MaxTagsPerPhoto)), nil)
+	expectInvalidParams(t, ValidateTagCount(make([]string, MaxTagsPerPhoto+1)))
+}
+
+func TestValidateRotation(t *testing.T) {
+	Expect(t, ValidateRotation(90), nil)
+	Expect(t, ValidateRotation(270), nil)
+	expectInvalidParams(t, ValidateRotation(0))
+	expectInvalidParams(t, ValidateRotation(45))
+}
+
+func TestValidateBBox(t *testing.T) {
+	Expect(t, ValidateBBox(""), nil)
+	Expect(t, ValidateBBox("-122.5,37.7,-122.3,37.8"), nil)
+	expectInvalidParams(t, ValidateBBox("-122.5,37.7,-122.3"))
+	expectInvalidParams(t, ValidateBBox("a,37.7,-122.3,37.8"))
+	expectInvalidParams(t, ValidateBBox("-190,37.7,-122.3,37.8"))
+	expectInvalidParams(t, ValidateBBox("-122.3,37.7,-122.5,37.8"))
+
+	err := ValidateBBox("1,2,3")
+	Expect(t, strings.Contains(err.Error(), "min_lon,min_lat,max_lon,max_lat"), true)
+}